@@ -19,8 +19,19 @@ type customRiskRule string
 func main() {
 	getInfo := flag.Bool("get-info", false, "get rule info")
 	generateRisks := flag.Bool("generate-risks", false, "generate risks")
+	serve := flag.Bool("serve", false, "keep running and answer calls on stdin")
 	flag.Parse()
 
+	if *serve {
+		serveError := model.ServePlugin(new(customRiskRule), os.Stdin, os.Stdout)
+		if serveError != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to serve risk rule: %v\n", serveError)
+			os.Exit(-2)
+		}
+
+		os.Exit(0)
+	}
+
 	if *getInfo {
 		rule := new(customRiskRule)
 		riskData, marshalError := json.Marshal(new(model.CustomRiskCategory).Init(rule.Category(), rule.SupportedTags()))
@@ -30,7 +41,7 @@ func main() {
 			os.Exit(-2)
 		}
 
-		_, _ = fmt.Fprint(os.Stdout, riskData)
+		_, _ = os.Stdout.Write(riskData)
 		os.Exit(0)
 	}
 
@@ -61,7 +72,7 @@ func main() {
 			os.Exit(-2)
 		}
 
-		_, _ = fmt.Fprint(os.Stdout, outData)
+		_, _ = os.Stdout.Write(outData)
 		os.Exit(0)
 	}
 
@@ -18,10 +18,11 @@ func (what *Threagile) initAnalyze() *Threagile {
 			commands := what.readCommands()
 			progressReporter := common.DefaultProgressReporter{Verbose: cfg.Verbose}
 
-			r, err := model.ReadAndAnalyzeModel(cfg, progressReporter)
+			r, err := model.ReadAndAnalyzeModel(cmd.Context(), cfg, progressReporter)
 			if err != nil {
 				return fmt.Errorf("failed to read and analyze model: %v", err)
 			}
+			defer r.Close()
 
 			err = report.Generate(cfg, r, commands, progressReporter)
 			if err != nil {
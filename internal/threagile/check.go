@@ -0,0 +1,64 @@
+package threagile
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/model"
+)
+
+func (what *Threagile) initCheck() *Threagile {
+	check := &cobra.Command{
+		Use:   common.CheckCommand,
+		Short: "Check the model and its risks against a policy",
+		Long: "Analyzes the model and checks it against a policy file: the maximum number of risks per severity and " +
+			"status (max_risks), the risk categories no unmitigated risk may be of (forbidden_risk_categories), the " +
+			"risk tracking fields required per status (required_tracking_fields) and the maximum age of risk tracking " +
+			"entries per status (max_tracking_age_days). Violations are printed and make the command fail, so it can " +
+			"block merges in a CI pipeline.",
+		Args: cobra.NoArgs,
+		RunE: what.check,
+	}
+
+	check.Flags().StringVar(&what.flags.policyFlag, policyFlagName, common.PolicyFilename, "policy file")
+
+	what.rootCmd.AddCommand(check)
+
+	return what
+}
+
+func (what *Threagile) check(cmd *cobra.Command, _ []string) error {
+	cfg := what.readConfig(cmd, what.buildTimestamp)
+	progressReporter := common.DefaultProgressReporter{Verbose: cfg.Verbose}
+
+	policy := new(model.Policy)
+	loadError := policy.Load(cfg.PolicyFilename)
+	if loadError != nil {
+		return loadError
+	}
+
+	result, readError := model.ReadAndAnalyzeModel(cmd.Context(), cfg, progressReporter)
+	if readError != nil {
+		return fmt.Errorf("failed to read and analyze model: %v", readError)
+	}
+	defer result.Close()
+
+	categoryError := policy.CheckRiskCategories(result.ParsedModel)
+	if categoryError != nil {
+		return fmt.Errorf("invalid policy file %q: %v", cfg.PolicyFilename, categoryError)
+	}
+
+	violations := policy.Check(result.ParsedModel, time.Now())
+	for _, violation := range violations {
+		cmd.Println(violation.String())
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("found %d policy violations", len(violations))
+	}
+
+	cmd.Printf("no policy violations found in %v\n", cfg.InputFile)
+	return nil
+}
@@ -0,0 +1,55 @@
+package threagile
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/report"
+)
+
+func (what *Threagile) initDiff() *Threagile {
+	diff := &cobra.Command{
+		Use:   common.DiffCommand + " [old model] [new model]",
+		Short: "Show what changed between two versions of a model, and its risks",
+		Long: "Analyzes both models and lists the technical assets, communication links, data assets and trust boundaries " +
+			"added, removed or changed, as well as the risks new, resolved or of changed severity (by their synthetic id). " +
+			"The diff is written to standard output as text, JSON or Markdown (for pull request comments).",
+		Args:       cobra.ExactArgs(2),
+		ArgAliases: []string{"old", "new"},
+		RunE:       what.diff,
+	}
+
+	diff.Flags().StringVar(&what.flags.diffFormatFlag, diffFormatFlagName, report.DiffFormatText, "output format: "+report.DiffFormatText+", "+report.DiffFormatJSON+" or "+report.DiffFormatMarkdown)
+
+	what.rootCmd.AddCommand(diff)
+
+	return what
+}
+
+func (what *Threagile) diff(cmd *cobra.Command, args []string) error {
+	cfg := what.readConfig(cmd, what.buildTimestamp)
+	progressReporter := common.DefaultProgressReporter{Verbose: cfg.Verbose}
+
+	results := make([]*model.ReadResult, 0)
+	for _, filename := range args {
+		modelConfig := *cfg
+		modelConfig.InputFile = modelConfig.CleanPath(filename)
+
+		result, readError := model.ReadAndAnalyzeModel(cmd.Context(), &modelConfig, progressReporter)
+		if readError != nil {
+			return fmt.Errorf("failed to read and analyze model %q: %v", filename, readError)
+		}
+		defer result.Close()
+		results = append(results, result)
+	}
+
+	content, formatError := report.FormatDiff(model.DiffModels(results[0].ParsedModel, results[1].ParsedModel), what.flags.diffFormatFlag)
+	if formatError != nil {
+		return formatError
+	}
+
+	_, writeError := fmt.Fprintln(cmd.OutOrStdout(), string(content))
+	return writeError
+}
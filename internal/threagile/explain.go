@@ -9,7 +9,6 @@ import (
 	"github.com/threagile/threagile/pkg/model"
 	"github.com/threagile/threagile/pkg/security/risks"
 	"github.com/threagile/threagile/pkg/security/types"
-	"strings"
 )
 
 func (what *Threagile) initExplain() *Threagile {
@@ -51,22 +50,37 @@ func (what *Threagile) initExplainNew() *Threagile {
 	return what
 }
 
-func (what *Threagile) explainRisk(cmd *cobra.Command, _ []string) error {
+func (what *Threagile) explainRisk(cmd *cobra.Command, args []string) error {
 	cfg := what.readConfig(cmd, what.buildTimestamp)
 	progressReporter := common.DefaultProgressReporter{Verbose: cfg.Verbose}
 
 	// todo: reuse model if already loaded
 
-	result, runError := model.ReadAndAnalyzeModel(cfg, progressReporter)
+	result, runError := model.ReadAndAnalyzeModel(cmd.Context(), cfg, progressReporter)
 	if runError != nil {
 		cmd.Printf("Failed to read and analyze model: %v", runError)
 		return runError
 	}
+	defer result.Close()
 
-	// todo: implement this
+	for n, riskId := range args {
+		explanation, explainError := result.ExplainRisk(riskId)
+		if explainError != nil {
+			return explainError
+		}
+
+		if n > 0 {
+			cmd.Println()
+			cmd.Println("--------------------")
+			cmd.Println()
+		}
 
-	_ = result
-	return fmt.Errorf("not implemented yet")
+		for _, line := range explanation {
+			cmd.Println(line)
+		}
+	}
+
+	return nil
 }
 
 func (what *Threagile) explainRules(cmd *cobra.Command, _ []string) error {
@@ -76,7 +90,10 @@ func (what *Threagile) explainRules(cmd *cobra.Command, _ []string) error {
 	cmd.Println("----------------------")
 	cmd.Println("Custom risk rules:")
 	cmd.Println("----------------------")
-	customRiskRules := model.LoadCustomRiskRules(strings.Split(what.flags.customRiskRulesPluginFlag, ","), common.DefaultProgressReporter{Verbose: what.flags.verboseFlag})
+	cfg := what.readConfig(cmd, what.buildTimestamp)
+	customRiskRules := model.LoadCustomRiskRules(cmd.Context(), cfg, common.DefaultProgressReporter{Verbose: cfg.Verbose})
+	defer model.CloseCustomRiskRules(customRiskRules)
+
 	for _, rule := range customRiskRules {
 		cmd.Printf("%v: %v\n", rule.Category().ID, rule.Category().Description)
 	}
@@ -85,7 +102,7 @@ func (what *Threagile) explainRules(cmd *cobra.Command, _ []string) error {
 	cmd.Println("Built-in risk rules:")
 	cmd.Println("--------------------")
 	cmd.Println()
-	for _, rule := range risks.GetBuiltInRiskRules() {
+	for _, rule := range risks.GetBuiltInRiskRules(cfg, common.DefaultProgressReporter{Verbose: cfg.Verbose}) {
 		cmd.Printf("%v: %v\n", rule.Category().ID, rule.Category().Description)
 	}
 	cmd.Println()
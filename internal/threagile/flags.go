@@ -25,19 +25,37 @@ const (
 	raaPluginFlagName = "raa-run"
 
 	customRiskRulesPluginFlagName      = "custom-risk-rules-plugin"
+	riskRulesScriptDirsFlagName        = "risk-rules-script-dirs"
+	includeBuiltinRulesFlagName        = "include-builtin-rules"
 	diagramDpiFlagName                 = "diagram-dpi"
+	diagramRendererFlagName            = "diagram-renderer"
 	skipRiskRulesFlagName              = "skip-risk-rules"
 	ignoreOrphanedRiskTrackingFlagName = "ignore-orphaned-risk-tracking"
 	templateFileNameFlagName           = "background"
+	shapeTechnologiesFlagName          = "shape-technologies"
+	diffFormatFlagName                 = "format"
+	policyFlagName                     = "policy"
+	trackerFlagName                    = "tracker"
+	dryRunFlagName                     = "dry-run"
 
 	generateDataFlowDiagramFlagName     = "generate-data-flow-diagram"
 	generateDataAssetDiagramFlagName    = "generate-data-asset-diagram"
 	generateRisksJSONFlagName           = "generate-risks-json"
+	generateRisksSARIFFlagName          = "generate-risks-sarif"
 	generateTechnicalAssetsJSONFlagName = "generate-technical-assets-json"
 	generateStatsJSONFlagName           = "generate-stats-json"
+	generateAttackPathsJSONFlagName     = "generate-attack-paths-json"
+	generateAttackPathDiagramFlagName   = "generate-attack-path-diagram"
+	generateDiagramSVGFlagName          = "generate-diagram-svg"
+	generateDiagramViewerFlagName       = "generate-diagram-viewer"
+	generateDiagramMermaidFlagName      = "generate-diagram-mermaid"
+	generateDiagramPlantUMLFlagName     = "generate-diagram-plantuml"
+	generateDiagramDrawIOFlagName       = "generate-diagram-drawio"
 	generateRisksExcelFlagName          = "generate-risks-excel"
 	generateTagsExcelFlagName           = "generate-tags-excel"
 	generateReportPDFFlagName           = "generate-report-pdf"
+	generateReportMarkdownFlagName      = "generate-report-markdown"
+	generateReportHTMLFlagName          = "generate-report-html"
 )
 
 type Flags struct {
@@ -55,16 +73,34 @@ type Flags struct {
 
 	skipRiskRulesFlag              string
 	customRiskRulesPluginFlag      string
+	riskRulesScriptDirsFlag        string
+	includeBuiltinRulesFlag        bool
 	ignoreOrphanedRiskTrackingFlag bool
 	templateFileNameFlag           string
 	diagramDpiFlag                 int
+	diagramRendererFlag            string
+	shapeTechnologiesFlag          string
+	diffFormatFlag                 string
+	policyFlag                     string
+	trackerFlag                    string
+	dryRunFlag                     bool
 
 	generateDataFlowDiagramFlag     bool
 	generateDataAssetDiagramFlag    bool
 	generateRisksJSONFlag           bool
+	generateRisksSARIFFlag          bool
 	generateTechnicalAssetsJSONFlag bool
 	generateStatsJSONFlag           bool
+	generateAttackPathsJSONFlag     bool
+	generateAttackPathDiagramFlag   bool
+	generateDiagramSVGFlag          bool
+	generateDiagramViewerFlag       bool
+	generateDiagramMermaidFlag      bool
+	generateDiagramPlantUMLFlag     bool
+	generateDiagramDrawIOFlag       bool
 	generateRisksExcelFlag          bool
 	generateTagsExcelFlag           bool
 	generateReportPDFFlag           bool
+	generateReportMarkdownFlag      bool
+	generateReportHTMLFlag          bool
 }
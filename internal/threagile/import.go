@@ -0,0 +1,168 @@
+package threagile
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/docs"
+	"github.com/threagile/threagile/pkg/importer"
+)
+
+func (what *Threagile) initImport() *Threagile {
+	importCmd := &cobra.Command{
+		Use:   common.ImportCommand,
+		Short: "Import a model from another format",
+	}
+
+	importDrawIO := &cobra.Command{
+		Use:   common.DrawIOItem + " [draw.io file]",
+		Short: "Import a stub model from a draw.io (diagrams.net) file",
+		Long: "\n" + docs.Logo + "\n\n" + fmt.Sprintf(docs.VersionText, what.buildTimestamp) + "\n\n" +
+			"Imports the first page of a draw.io file into a stub model named " + common.ImportedModelFilename + " in the output directory: " +
+			"shapes become technical assets, containers become trust boundaries and edges become communication links. " +
+			"The technology of a technical asset is taken from its shape (see --" + shapeTechnologiesFlagName + " to map more shapes) or its label, " +
+			"the protocol of a communication link from its label. What could not be inferred, like the CIA ratings and the data assets, is marked with TODO comments.",
+		Args:       cobra.ExactArgs(1),
+		ArgAliases: []string{"file"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return what.importModel(cmd, args, importer.ImportDrawIO)
+		},
+	}
+	importDrawIO.Flags().StringVar(&what.flags.shapeTechnologiesFlag, shapeTechnologiesFlagName, "", "YAML file mapping draw.io shapes to technologies, in addition to the built-in ones")
+
+	importTM7 := &cobra.Command{
+		Use:   common.TM7Item + " [tm7 file]",
+		Short: "Import a stub model from a Microsoft Threat Modeling Tool (.tm7) file",
+		Long: "\n" + docs.Logo + "\n\n" + fmt.Sprintf(docs.VersionText, what.buildTimestamp) + "\n\n" +
+			"Imports all diagrams of a Microsoft Threat Modeling Tool file into a stub model named " + common.ImportedModelFilename + " in the output directory: " +
+			"processes, external interactors and data stores become technical assets, border boundaries become trust boundaries and data flows become communication links. " +
+			"Threats become risks of custom risk categories, one per threat type, with their state kept as risk tracking. " +
+			"The technology of a technical asset is taken from its stencil (see --" + shapeTechnologiesFlagName + " to map more stencils) or its name. " +
+			"What could not be inferred is marked with TODO comments, what could not be imported at all is listed in " + common.ImportLogFilename + ".",
+		Args:       cobra.ExactArgs(1),
+		ArgAliases: []string{"file"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return what.importModel(cmd, args, importer.ImportTM7)
+		},
+	}
+	importTM7.Flags().StringVar(&what.flags.shapeTechnologiesFlag, shapeTechnologiesFlagName, "", "YAML file mapping stencil type ids to technologies, in addition to the built-in ones")
+
+	importThreatDragon := &cobra.Command{
+		Use:   common.ThreatDragonItem + " [json file]",
+		Short: "Import a stub model from a Threat Dragon (version 1 or 2) file",
+		Long: "\n" + docs.Logo + "\n\n" + fmt.Sprintf(docs.VersionText, what.buildTimestamp) + "\n\n" +
+			"Imports all diagrams of a Threat Dragon file into a stub model named " + common.ImportedModelFilename + " in the output directory: " +
+			"processes, actors and stores become technical assets, trust boundary boxes become trust boundaries and data flows become communication links. " +
+			"Threats become risks of custom risk categories, one per threat title, with their status kept as risk tracking. " +
+			"The technology of a technical asset is taken from its name or its shape (see --" + shapeTechnologiesFlagName + " to map shapes). " +
+			"What could not be inferred is marked with TODO comments, what could not be imported at all is listed in " + common.ImportLogFilename + ".",
+		Args:       cobra.ExactArgs(1),
+		ArgAliases: []string{"file"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return what.importModel(cmd, args, importer.ImportThreatDragon)
+		},
+	}
+	importThreatDragon.Flags().StringVar(&what.flags.shapeTechnologiesFlag, shapeTechnologiesFlagName, "", "YAML file mapping Threat Dragon shapes to technologies, in addition to the built-in ones")
+
+	importKubernetes := &cobra.Command{
+		Use:   common.KubernetesItem + " [manifest directory or file]",
+		Short: "Import a model from Kubernetes manifests, including Helm-rendered ones",
+		Long: "\n" + docs.Logo + "\n\n" + fmt.Sprintf(docs.VersionText, what.buildTimestamp) + "\n\n" +
+			"Imports the Kubernetes manifests of a directory (or file) into a model named " + common.KubernetesModelFilename + " in the output directory: " +
+			"deployments and stateful sets become technical assets tagged k8s running in the container platform, namespaces become trust boundaries, " +
+			"and the services the workloads address, ingresses and network policies become communication links. " +
+			"This model is regenerated on every import. It is included by a stub model named " + common.ImportedModelFilename + ", which is only written by the first import: " +
+			"add what only the owners know, like CIA ratings and data assets, there to keep it on the next import. " +
+			"What could not be imported, like new technical assets not rated yet, is listed in " + common.ImportLogFilename + ".",
+		Args:       cobra.ExactArgs(1),
+		ArgAliases: []string{"path"},
+		RunE:       what.importKubernetes,
+	}
+	importKubernetes.Flags().StringVar(&what.flags.shapeTechnologiesFlag, shapeTechnologiesFlagName, "", "YAML file mapping container images to technologies, in addition to the built-in ones")
+
+	importCompose := &cobra.Command{
+		Use:   common.ComposeItem + " [compose file or directory]",
+		Short: "Import a stub model from a docker-compose file",
+		Long: "\n" + docs.Logo + "\n\n" + fmt.Sprintf(docs.VersionText, what.buildTimestamp) + "\n\n" +
+			"Imports a compose file (or the compose.yaml or docker-compose.yml of a directory) into a stub model named " + common.ImportedModelFilename + " in the output directory: " +
+			"services become technical assets, networks become trust boundaries and named volumes become datastores. " +
+			"Dependencies, links, the service addresses in the environment and commands as well as the ports published to external clients become communication links. " +
+			"The technology of a technical asset is taken from its image name, as technology alias (see --" + shapeTechnologiesFlagName + " to map more images), or its service name. " +
+			"What could not be inferred is marked with TODO comments, what could not be imported at all is listed in " + common.ImportLogFilename + ".",
+		Args:       cobra.ExactArgs(1),
+		ArgAliases: []string{"path"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return what.importModel(cmd, args, importer.ImportCompose)
+		},
+	}
+	importCompose.Flags().StringVar(&what.flags.shapeTechnologiesFlag, shapeTechnologiesFlagName, "", "YAML file mapping container images to technologies, in addition to the built-in ones")
+
+	importCmd.AddCommand(importDrawIO, importTM7, importThreatDragon, importKubernetes, importCompose)
+	what.rootCmd.AddCommand(importCmd)
+
+	return what
+}
+
+func (what *Threagile) importModel(cmd *cobra.Command, args []string, importFile func(filename string, shapeTechnologies *importer.ShapeTechnologies) (*importer.StubModel, error)) error {
+	cfg := what.readConfig(cmd, what.buildTimestamp)
+
+	shapeTechnologies := new(importer.ShapeTechnologies)
+	loadError := shapeTechnologies.LoadWithConfig(cfg)
+	if loadError != nil {
+		return fmt.Errorf("failed to load shape technologies: %v", loadError)
+	}
+
+	stub, importError := importFile(args[0], shapeTechnologies)
+	if importError != nil {
+		return importError
+	}
+
+	filename := filepath.Join(cfg.OutputFolder, common.ImportedModelFilename)
+	logFilename := filepath.Join(cfg.OutputFolder, common.ImportLogFilename)
+	writeError := stub.Write(filename, logFilename)
+	if writeError != nil {
+		return writeError
+	}
+
+	cmd.Printf("A stub model with %d technical assets and %d trust boundaries was imported into %q, see its TODO comments for what is left to model.\n",
+		len(stub.Model.TechnicalAssets), len(stub.Model.TrustBoundaries), filename)
+	if len(stub.Log) > 0 {
+		cmd.Printf("See %q for %d notes on what could not be imported as it is.\n", logFilename, len(stub.Log))
+	}
+	return nil
+}
+
+func (what *Threagile) importKubernetes(cmd *cobra.Command, args []string) error {
+	cfg := what.readConfig(cmd, what.buildTimestamp)
+
+	shapeTechnologies := new(importer.ShapeTechnologies)
+	loadError := shapeTechnologies.LoadWithConfig(cfg)
+	if loadError != nil {
+		return fmt.Errorf("failed to load shape technologies: %v", loadError)
+	}
+
+	imported, importError := importer.ImportKubernetes(args[0], shapeTechnologies)
+	if importError != nil {
+		return importError
+	}
+
+	clusterFilename := filepath.Join(cfg.OutputFolder, common.KubernetesModelFilename)
+	filename := filepath.Join(cfg.OutputFolder, common.ImportedModelFilename)
+	logFilename := filepath.Join(cfg.OutputFolder, common.ImportLogFilename)
+	stubWritten, writeError := imported.Write(clusterFilename, filename, logFilename)
+	if writeError != nil {
+		return writeError
+	}
+
+	cmd.Printf("A model with %d technical assets and %d trust boundaries was imported into %q.\n",
+		len(imported.Cluster.TechnicalAssets), len(imported.Cluster.TrustBoundaries), clusterFilename)
+	if stubWritten {
+		cmd.Printf("It is included by the stub model %q, see its TODO comments for what is left to model.\n", filename)
+	}
+	if len(imported.Stub.Log) > 0 {
+		cmd.Printf("See %q for %d notes on what could not be imported as it is.\n", logFilename, len(imported.Stub.Log))
+	}
+	return nil
+}
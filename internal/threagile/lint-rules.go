@@ -0,0 +1,69 @@
+package threagile
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/risks"
+)
+
+func (what *Threagile) initLintRules() *Threagile {
+	lintRules := &cobra.Command{
+		Use:   common.LintRiskRulesCommand + " [script file or folder]...",
+		Short: "Check script risk rules for errors without running them",
+		Long: "Parses script risk rules and reports unknown keywords, calls of undefined methods, wrong parameter " +
+			"counts, unknown model fields, invalid enum values for 'as' casts and unreachable statements, with file " +
+			"and line positions. Without arguments, the configured risk rule script folders and the plugin folder " +
+			"are checked.",
+		RunE: what.lintRules,
+	}
+
+	lintRules.Flags().BoolVar(&what.flags.includeBuiltinRulesFlag, includeBuiltinRulesFlagName, false, "also lint the built-in script risk rules")
+
+	what.rootCmd.AddCommand(lintRules)
+
+	return what
+}
+
+func (what *Threagile) lintRules(cmd *cobra.Command, args []string) error {
+	cfg := what.readConfig(cmd, what.buildTimestamp)
+
+	paths := args
+	if len(paths) == 0 {
+		paths = append(paths, cfg.RiskRulesScriptFolders...)
+		if info, statError := os.Stat(cfg.PluginFolder); len(cfg.PluginFolder) > 0 && statError == nil && info.IsDir() {
+			paths = append(paths, cfg.PluginFolder)
+		}
+	}
+
+	issues, files, lintError := risks.LintRiskRules(paths)
+	if lintError != nil {
+		return fmt.Errorf("failed to lint risk rules: %v", lintError)
+	}
+
+	if what.flags.includeBuiltinRulesFlag {
+		builtinIssues, builtinError := risks.LintEmbeddedRiskRules()
+		if builtinError != nil {
+			return fmt.Errorf("failed to lint built-in risk rules: %v", builtinError)
+		}
+
+		issues = append(builtinIssues, issues...)
+	}
+
+	if files == 0 && !what.flags.includeBuiltinRulesFlag {
+		return fmt.Errorf("no script risk rules found to lint")
+	}
+
+	for _, issue := range issues {
+		cmd.Println(issue.String())
+	}
+
+	if len(issues) > 0 {
+		return fmt.Errorf("found %d problems in risk rules", len(issues))
+	}
+
+	cmd.Printf("no problems found in %d risk rule files\n", files)
+	return nil
+}
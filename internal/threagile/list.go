@@ -2,6 +2,9 @@ package threagile
 
 import (
 	"fmt"
+	"sort"
+	"time"
+
 	"github.com/spf13/cobra"
 	"github.com/threagile/threagile/pkg/common"
 	"github.com/threagile/threagile/pkg/docs"
@@ -9,7 +12,6 @@ import (
 	"github.com/threagile/threagile/pkg/model"
 	"github.com/threagile/threagile/pkg/security/risks"
 	"github.com/threagile/threagile/pkg/security/types"
-	"strings"
 )
 
 func (what *Threagile) initList() *Threagile {
@@ -23,7 +25,10 @@ func (what *Threagile) initList() *Threagile {
 			cmd.Println("----------------------")
 			cmd.Println("Custom risk rules:")
 			cmd.Println("----------------------")
-			customRiskRules := model.LoadCustomRiskRules(strings.Split(what.flags.customRiskRulesPluginFlag, ","), common.DefaultProgressReporter{Verbose: what.flags.verboseFlag})
+			cfg := what.readConfig(cmd, what.buildTimestamp)
+			customRiskRules := model.LoadCustomRiskRules(cmd.Context(), cfg, common.DefaultProgressReporter{Verbose: cfg.Verbose})
+			defer model.CloseCustomRiskRules(customRiskRules)
+
 			for id, customRule := range customRiskRules {
 				cmd.Println(id, "-->", customRule.Category().Title, "--> with tags:", customRule.SupportedTags())
 			}
@@ -32,7 +37,7 @@ func (what *Threagile) initList() *Threagile {
 			cmd.Println("Built-in risk rules:")
 			cmd.Println("--------------------")
 			cmd.Println()
-			for _, rule := range risks.GetBuiltInRiskRules() {
+			for _, rule := range risks.GetBuiltInRiskRules(cfg, common.DefaultProgressReporter{Verbose: cfg.Verbose}) {
 				cmd.Println(rule.Category().ID, "-->", rule.Category().Title, "--> with tags:", rule.SupportedTags())
 			}
 
@@ -81,5 +86,75 @@ func (what *Threagile) initList() *Threagile {
 		},
 	})
 
+	what.rootCmd.AddCommand(&cobra.Command{
+		Use:   common.ListOverdueTrackingCommand,
+		Short: "Print overdue risk tracking per owner",
+		Long: "Analyzes the model and prints the risk tracking entries that expired (reverting acceptances to unchecked) " +
+			"or should have been reviewed by now, grouped by their owner.",
+		Args: cobra.NoArgs,
+		RunE: what.listOverdueRiskTracking,
+	})
+
 	return what
 }
+
+func (what *Threagile) listOverdueRiskTracking(cmd *cobra.Command, _ []string) error {
+	cfg := what.readConfig(cmd, what.buildTimestamp)
+	result, readError := model.ReadAndAnalyzeModel(cmd.Context(), cfg, common.DefaultProgressReporter{Verbose: cfg.Verbose})
+	if readError != nil {
+		return fmt.Errorf("failed to read and analyze model: %v", readError)
+	}
+	defer result.Close()
+
+	today := time.Now()
+	byOwner := make(map[string][]*types.RiskTracking)
+	for _, tracking := range result.ParsedModel.RiskTrackingEntries() {
+		if tracking.IsOverdue(today) {
+			byOwner[tracking.Owner] = append(byOwner[tracking.Owner], tracking)
+		}
+	}
+
+	if len(byOwner) == 0 {
+		cmd.Println("no overdue risk tracking")
+		return nil
+	}
+
+	owners := make([]string, 0)
+	for owner := range byOwner {
+		owners = append(owners, owner)
+	}
+	sort.Slice(owners, func(i, j int) bool {
+		if len(owners[i]) == 0 || len(owners[j]) == 0 {
+			return len(owners[j]) == 0 && len(owners[i]) > 0
+		}
+		return owners[i] < owners[j]
+	})
+
+	for _, owner := range owners {
+		entries := byOwner[owner]
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].DueDate().Before(entries[j].DueDate()) })
+
+		title := owner
+		if len(title) == 0 {
+			title = "(no owner)"
+		}
+		cmd.Printf("%v (%d):\n", title, len(entries))
+		for _, tracking := range entries {
+			state := "review overdue"
+			if tracking.IsExpired(today) {
+				state = "expired"
+			}
+
+			line := fmt.Sprintf("  %v  %-14v  %v", tracking.DueDate().Format("2006-01-02"), state, tracking.SyntheticRiskId)
+			if len(tracking.Ticket) > 0 {
+				line += "  " + tracking.Ticket
+			}
+			if tracking.Position.IsKnown() {
+				line += "  (" + tracking.Position.String() + ")"
+			}
+			cmd.Println(line)
+		}
+	}
+
+	return nil
+}
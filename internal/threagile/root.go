@@ -63,7 +63,7 @@ func (what *Threagile) initRoot() *Threagile {
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.tempDirFlag, tempDirFlagName, defaultConfig.TempFolder, "temporary folder location")
 
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.inputFileFlag, inputFileFlagName, defaultConfig.InputFile, "input model yaml file")
-	what.rootCmd.PersistentFlags().StringVar(&what.flags.raaPluginFlag, raaPluginFlagName, defaultConfig.RAAPlugin, "RAA calculation run file name")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.raaPluginFlag, raaPluginFlagName, defaultConfig.RAAPlugin, "RAA algorithm (default or exposure) or calculation run file name in the plugin folder")
 
 	what.rootCmd.PersistentFlags().BoolVarP(&what.flags.interactiveFlag, interactiveFlagName, interactiveFlagShorthand, defaultConfig.Interactive, "interactive mode")
 	what.rootCmd.PersistentFlags().BoolVarP(&what.flags.verboseFlag, verboseFlagName, verboseFlagShorthand, defaultConfig.Verbose, "verbose output")
@@ -71,7 +71,9 @@ func (what *Threagile) initRoot() *Threagile {
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.configFlag, configFlagName, "", "config file")
 
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.customRiskRulesPluginFlag, customRiskRulesPluginFlagName, strings.Join(defaultConfig.RiskRulesPlugins, ","), "comma-separated list of plugins file names with custom risk rules to load")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.riskRulesScriptDirsFlag, riskRulesScriptDirsFlagName, strings.Join(defaultConfig.RiskRulesScriptFolders, ","), "comma-separated list of folders with script risk rules (YAML) to load")
 	what.rootCmd.PersistentFlags().IntVar(&what.flags.diagramDpiFlag, diagramDpiFlagName, defaultConfig.DiagramDPI, "DPI used to render: maximum is "+fmt.Sprintf("%d", common.MaxGraphvizDPI)+"")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.diagramRendererFlag, diagramRendererFlagName, defaultConfig.DiagramRenderer, "diagram renderer: "+common.DiagramRendererAuto+" (graphviz if installed), "+common.DiagramRendererGraphviz+" or "+common.DiagramRendererBuiltin)
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.skipRiskRulesFlag, skipRiskRulesFlagName, strings.Join(defaultConfig.SkipRiskRules, ","), "comma-separated list of risk rules (by their ID) to skip")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.ignoreOrphanedRiskTrackingFlag, ignoreOrphanedRiskTrackingFlagName, defaultConfig.IgnoreOrphanedRiskTracking, "ignore orphaned risk tracking (just log them) not matching a concrete risk")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.templateFileNameFlag, templateFileNameFlagName, defaultConfig.TemplateFilename, "background pdf file")
@@ -79,11 +81,21 @@ func (what *Threagile) initRoot() *Threagile {
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateDataFlowDiagramFlag, generateDataFlowDiagramFlagName, true, "generate data flow diagram")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateDataAssetDiagramFlag, generateDataAssetDiagramFlagName, true, "generate data asset diagram")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateRisksJSONFlag, generateRisksJSONFlagName, true, "generate risks json")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateRisksSARIFFlag, generateRisksSARIFFlagName, false, "generate risks sarif for code scanning")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateTechnicalAssetsJSONFlag, generateTechnicalAssetsJSONFlagName, true, "generate technical assets json")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateStatsJSONFlag, generateStatsJSONFlagName, true, "generate stats json")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateAttackPathsJSONFlag, generateAttackPathsJSONFlagName, false, "generate attack paths json")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateAttackPathDiagramFlag, generateAttackPathDiagramFlagName, false, "generate attack path diagram")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateDiagramSVGFlag, generateDiagramSVGFlagName, false, "render the diagrams as svg too, with assets linking to the html report")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateDiagramViewerFlag, generateDiagramViewerFlagName, false, "generate interactive diagram viewer, filtering by trust boundary, tag or severity")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateDiagramMermaidFlag, generateDiagramMermaidFlagName, false, "export data flow diagram as mermaid flowchart")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateDiagramPlantUMLFlag, generateDiagramPlantUMLFlagName, false, "export data flow diagram as plantuml deployment diagram")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateDiagramDrawIOFlag, generateDiagramDrawIOFlagName, false, "export data flow diagram as draw.io file")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateRisksExcelFlag, generateRisksExcelFlagName, true, "generate risks excel")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateTagsExcelFlag, generateTagsExcelFlagName, true, "generate tags excel")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateReportPDFFlag, generateReportPDFFlagName, true, "generate report pdf, including diagrams")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateReportMarkdownFlag, generateReportMarkdownFlagName, false, "generate report markdown, including svg diagrams")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateReportHTMLFlag, generateReportHTMLFlagName, false, "generate report html, including svg diagrams")
 
 	return what
 }
@@ -213,11 +225,21 @@ func (what *Threagile) readCommands() *report.GenerateCommands {
 	commands.DataFlowDiagram = what.flags.generateDataFlowDiagramFlag
 	commands.DataAssetDiagram = what.flags.generateDataAssetDiagramFlag
 	commands.RisksJSON = what.flags.generateRisksJSONFlag
+	commands.RisksSARIF = what.flags.generateRisksSARIFFlag
 	commands.StatsJSON = what.flags.generateStatsJSONFlag
 	commands.TechnicalAssetsJSON = what.flags.generateTechnicalAssetsJSONFlag
+	commands.AttackPathsJSON = what.flags.generateAttackPathsJSONFlag
+	commands.AttackPathDiagram = what.flags.generateAttackPathDiagramFlag
+	commands.DiagramSVG = what.flags.generateDiagramSVGFlag
+	commands.DiagramViewer = what.flags.generateDiagramViewerFlag
+	commands.DiagramMermaid = what.flags.generateDiagramMermaidFlag
+	commands.DiagramPlantUML = what.flags.generateDiagramPlantUMLFlag
+	commands.DiagramDrawIO = what.flags.generateDiagramDrawIOFlag
 	commands.RisksExcel = what.flags.generateRisksExcelFlag
 	commands.TagsExcel = what.flags.generateTagsExcelFlag
 	commands.ReportPDF = what.flags.generateReportPDFFlag
+	commands.ReportMarkdown = what.flags.generateReportMarkdownFlag
+	commands.ReportHTML = what.flags.generateReportHTMLFlag
 	return commands
 }
 
@@ -263,6 +285,14 @@ func (what *Threagile) readConfig(cmd *cobra.Command, buildTimestamp string) *co
 	if isFlagOverridden(flags, customRiskRulesPluginFlagName) {
 		cfg.RiskRulesPlugins = strings.Split(what.flags.customRiskRulesPluginFlag, ",")
 	}
+	if isFlagOverridden(flags, riskRulesScriptDirsFlagName) {
+		cfg.RiskRulesScriptFolders = make([]string, 0)
+		for _, folder := range strings.Split(what.flags.riskRulesScriptDirsFlag, ",") {
+			if len(folder) > 0 {
+				cfg.RiskRulesScriptFolders = append(cfg.RiskRulesScriptFolders, cfg.CleanPath(folder))
+			}
+		}
+	}
 	if isFlagOverridden(flags, skipRiskRulesFlagName) {
 		cfg.SkipRiskRules = strings.Split(what.flags.skipRiskRulesFlag, ",")
 	}
@@ -272,9 +302,21 @@ func (what *Threagile) readConfig(cmd *cobra.Command, buildTimestamp string) *co
 	if isFlagOverridden(flags, diagramDpiFlagName) {
 		cfg.DiagramDPI = what.flags.diagramDpiFlag
 	}
+	if isFlagOverridden(flags, diagramRendererFlagName) {
+		cfg.DiagramRenderer = what.flags.diagramRendererFlag
+	}
 	if isFlagOverridden(flags, templateFileNameFlagName) {
 		cfg.TemplateFilename = what.flags.templateFileNameFlag
 	}
+	if isFlagOverridden(flags, shapeTechnologiesFlagName) {
+		cfg.ShapeTechnologyFilename = cfg.CleanPath(what.flags.shapeTechnologiesFlag)
+	}
+	if isFlagOverridden(flags, policyFlagName) {
+		cfg.PolicyFilename = cfg.CleanPath(what.flags.policyFlag)
+	}
+	if isFlagOverridden(flags, trackerFlagName) {
+		cfg.TrackerFilename = cfg.CleanPath(what.flags.trackerFlag)
+	}
 	return cfg
 }
 
@@ -0,0 +1,81 @@
+package threagile
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/risks"
+)
+
+func (what *Threagile) initTestRules() *Threagile {
+	testRules := &cobra.Command{
+		Use:   common.TestRiskRulesCommand + " [fixture folder or file]...",
+		Short: "Test risk rules against fixture models with expected risks",
+		Long: "Runs the script risk rules (and optionally the built-in risk rules) against fixture models and compares " +
+			"the generated risks with the expected synthetic IDs, severities and titles listed in a sidecar file " +
+			"next to each model (e.g. my-model.expected.yaml for my-model.yaml). A sidecar file may name the model " +
+			"to use ('model') and restrict the rules to run ('rules').",
+		Args: cobra.MinimumNArgs(1),
+		RunE: what.testRules,
+	}
+
+	testRules.Flags().BoolVar(&what.flags.includeBuiltinRulesFlag, includeBuiltinRulesFlagName, false, "also test the built-in risk rules")
+
+	what.rootCmd.AddCommand(testRules)
+
+	return what
+}
+
+func (what *Threagile) testRules(cmd *cobra.Command, args []string) error {
+	cfg := what.readConfig(cmd, what.buildTimestamp)
+	progressReporter := common.DefaultProgressReporter{Verbose: cfg.Verbose}
+
+	fixtures, loadError := model.LoadRuleFixtures(args)
+	if loadError != nil {
+		return fmt.Errorf("failed to load rule fixtures: %v", loadError)
+	}
+
+	if len(fixtures) == 0 {
+		return fmt.Errorf("no rule fixtures found in %v", args)
+	}
+
+	customRiskRules := model.LoadCustomRiskRules(cmd.Context(), cfg, progressReporter)
+	defer model.CloseCustomRiskRules(customRiskRules)
+
+	availableRules := risks.GetBuiltInRiskRules(cfg, progressReporter).Merge(customRiskRules)
+	rulesUnderTest := model.ScriptRiskRules(availableRules)
+	if what.flags.includeBuiltinRulesFlag {
+		rulesUnderTest = availableRules
+	}
+
+	failed := 0
+	for _, fixture := range fixtures {
+		result, runError := fixture.Run(cmd.Context(), cfg, rulesUnderTest, availableRules, progressReporter)
+		if runError != nil {
+			failed++
+			cmd.Printf("ERROR %v: %v\n", fixture.Name, runError)
+			continue
+		}
+
+		if result.Passed() {
+			cmd.Printf("PASS  %v\n", fixture.Name)
+			continue
+		}
+
+		failed++
+		cmd.Printf("FAIL  %v\n", fixture.Name)
+		for _, difference := range result.Differences {
+			cmd.Printf("      - %v\n", difference)
+		}
+	}
+
+	cmd.Println()
+	cmd.Printf("%d of %d rule fixtures passed\n", len(fixtures)-failed, len(fixtures))
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d rule fixtures failed", failed, len(fixtures))
+	}
+
+	return nil
+}
@@ -1,7 +1,10 @@
 package threagile
 
 import (
+	"context"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/spf13/cobra"
 )
@@ -12,8 +15,12 @@ type Threagile struct {
 	buildTimestamp string
 }
 
+// Execute runs the command line until it ends or is interrupted, which also stops all plugins started by it
+
 func (what *Threagile) Execute() {
-	err := what.rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := what.rootCmd.ExecuteContext(ctx)
+	stop()
 	if err != nil {
 		what.rootCmd.Println(err)
 		os.Exit(1)
@@ -22,5 +29,5 @@ func (what *Threagile) Execute() {
 
 func (what *Threagile) Init(buildTimestamp string) *Threagile {
 	what.buildTimestamp = buildTimestamp
-	return what.initRoot().initAnalyze().initCreate().initExecute().initExplain().initList().initPrint().initQuit().initServer().initVersion()
+	return what.initRoot().initAnalyze().initCheck().initCreate().initDiff().initExecute().initExplain().initImport().initList().initPrint().initQuit().initServer().initTestRules().initTracking().initLintRules().initVersion()
 }
@@ -0,0 +1,82 @@
+package threagile
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/tracking"
+)
+
+func (what *Threagile) initTracking() *Threagile {
+	trackingCmd := &cobra.Command{
+		Use:   common.TrackingCommand,
+		Short: "Sync risk tracking with an issue tracker",
+	}
+
+	syncCmd := &cobra.Command{
+		Use:   common.SyncItem,
+		Short: "Create tickets for unchecked risks and take over the status of tickets into the risk tracking",
+		Long: "Analyzes the model, pulls the status of the tickets of its risk tracking entries from the issue tracker " +
+			"configured in the tracker file, and creates tickets for unchecked risks without one. Statuses and new " +
+			"tickets are written back into the risk tracking of the model files, leaving the rest of them untouched. " +
+			"Expired risk tracking entries are not updated from their tickets.",
+		Args: cobra.NoArgs,
+		RunE: what.syncTracking,
+	}
+
+	syncCmd.Flags().StringVar(&what.flags.trackerFlag, trackerFlagName, common.TrackerFilename, "issue tracker connector configuration file")
+	syncCmd.Flags().BoolVar(&what.flags.dryRunFlag, dryRunFlagName, false, "print the changes only, without creating tickets or writing the model")
+
+	trackingCmd.AddCommand(syncCmd)
+	what.rootCmd.AddCommand(trackingCmd)
+
+	return what
+}
+
+func (what *Threagile) syncTracking(cmd *cobra.Command, _ []string) error {
+	cfg := what.readConfig(cmd, what.buildTimestamp)
+	progressReporter := common.DefaultProgressReporter{Verbose: cfg.Verbose}
+
+	connector, loadError := tracking.LoadConnector(cfg.TrackerFilename)
+	if loadError != nil {
+		return loadError
+	}
+
+	result, readError := model.ReadAndAnalyzeModel(cmd.Context(), cfg, progressReporter)
+	if readError != nil {
+		return fmt.Errorf("failed to read and analyze model: %v", readError)
+	}
+	defer result.Close()
+
+	editor := new(input.ModelEditor)
+	editError := editor.Load(cfg.InputFile)
+	if editError != nil {
+		return fmt.Errorf("unable to edit model: %v", editError)
+	}
+
+	changes, syncError := tracking.Sync(cmd.Context(), result.ParsedModel, editor, connector, time.Now(), what.flags.dryRunFlag)
+	for _, change := range changes {
+		cmd.Println(change.String())
+	}
+
+	if !what.flags.dryRunFlag {
+		// tickets created before a failure are written as well, so they are not created again
+		saveError := tracking.Save(editor, changes)
+		if saveError != nil {
+			return saveError
+		}
+	}
+
+	if syncError != nil {
+		return syncError
+	}
+
+	if len(changes) == 0 {
+		cmd.Printf("risk tracking of %v is in sync\n", cfg.InputFile)
+	}
+	return nil
+}
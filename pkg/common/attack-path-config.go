@@ -0,0 +1,34 @@
+package common
+
+// AttackPathConfig limits the attack path analysis to the MaxPaths highest scoring paths of at most MaxHops
+// communication links each, found within MaxSteps steps of the search; zero values fall back to the defaults
+
+type AttackPathConfig struct {
+	MaxPaths int
+	MaxHops  int
+	MaxSteps int
+}
+
+func (what AttackPathConfig) PathLimit() int {
+	if what.MaxPaths <= 0 {
+		return DefaultAttackPathMaxPaths
+	}
+
+	return what.MaxPaths
+}
+
+func (what AttackPathConfig) HopLimit() int {
+	if what.MaxHops <= 0 {
+		return DefaultAttackPathMaxHops
+	}
+
+	return what.MaxHops
+}
+
+func (what AttackPathConfig) StepLimit() int {
+	if what.MaxSteps <= 0 {
+		return DefaultAttackPathMaxSteps
+	}
+
+	return what.MaxSteps
+}
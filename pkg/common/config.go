@@ -23,28 +23,49 @@ type Config struct {
 	TempFolder   string
 	KeyFolder    string
 
-	InputFile                   string
-	DataFlowDiagramFilenamePNG  string
-	DataAssetDiagramFilenamePNG string
-	DataFlowDiagramFilenameDOT  string
-	DataAssetDiagramFilenameDOT string
-	ReportFilename              string
-	ExcelRisksFilename          string
-	ExcelTagsFilename           string
-	JsonRisksFilename           string
-	JsonTechnicalAssetsFilename string
-	JsonStatsFilename           string
-	TemplateFilename            string
-	TechnologyFilename          string
-
-	RAAPlugin         string
-	RiskRulesPlugins  []string
-	SkipRiskRules     []string
-	ExecuteModelMacro string
-	RiskExcel         RiskExcelConfig
+	InputFile                       string
+	DataFlowDiagramFilenamePNG      string
+	DataAssetDiagramFilenamePNG     string
+	DataFlowDiagramFilenameDOT      string
+	DataAssetDiagramFilenameDOT     string
+	AttackPathDiagramFilenamePNG    string
+	AttackPathDiagramFilenameDOT    string
+	DataFlowDiagramFilenameSVG      string
+	DataAssetDiagramFilenameSVG     string
+	AttackPathDiagramFilenameSVG    string
+	DiagramViewerFilename           string
+	DataFlowDiagramFilenameMermaid  string
+	DataFlowDiagramFilenamePlantUML string
+	DataFlowDiagramFilenameDrawIO   string
+	ReportFilename                  string
+	ReportMarkdownFilename          string
+	ReportHTMLFilename              string
+	ReportTemplateFolder            string
+	ExcelRisksFilename              string
+	ExcelTagsFilename               string
+	JsonRisksFilename               string
+	JsonTechnicalAssetsFilename     string
+	JsonStatsFilename               string
+	JsonAttackPathsFilename         string
+	SarifRisksFilename              string
+	TemplateFilename                string
+	TechnologyFilename              string
+	ShapeTechnologyFilename         string
+	PolicyFilename                  string
+	TrackerFilename                 string
+
+	RAAPlugin              string
+	RiskRulesPlugins       []string
+	RiskRulesScriptFolders []string
+	SkipRiskRules          []string
+	ExecuteModelMacro      string
+	RiskExcel              RiskExcelConfig
+	Plugins                PluginConfig
+	AttackPaths            AttackPathConfig
 
 	ServerMode               bool
 	DiagramDPI               int
+	DiagramRenderer          string
 	ServerPort               int
 	GraphvizDPI              int
 	MaxGraphvizDPI           int
@@ -77,31 +98,63 @@ func (c *Config) Defaults(buildTimestamp string) *Config {
 		TempFolder:   TempDir,
 		KeyFolder:    KeyDir,
 
-		InputFile:                   InputFile,
-		DataFlowDiagramFilenamePNG:  DataFlowDiagramFilenamePNG,
-		DataAssetDiagramFilenamePNG: DataAssetDiagramFilenamePNG,
-		DataFlowDiagramFilenameDOT:  DataFlowDiagramFilenameDOT,
-		DataAssetDiagramFilenameDOT: DataAssetDiagramFilenameDOT,
-		ReportFilename:              ReportFilename,
-		ExcelRisksFilename:          ExcelRisksFilename,
-		ExcelTagsFilename:           ExcelTagsFilename,
-		JsonRisksFilename:           JsonRisksFilename,
-		JsonTechnicalAssetsFilename: JsonTechnicalAssetsFilename,
-		JsonStatsFilename:           JsonStatsFilename,
-		TemplateFilename:            TemplateFilename,
-		TechnologyFilename:          "",
-
-		RAAPlugin:         RAAPluginName,
-		RiskRulesPlugins:  make([]string, 0),
-		SkipRiskRules:     make([]string, 0),
-		ExecuteModelMacro: "",
+		InputFile:                       InputFile,
+		DataFlowDiagramFilenamePNG:      DataFlowDiagramFilenamePNG,
+		DataAssetDiagramFilenamePNG:     DataAssetDiagramFilenamePNG,
+		DataFlowDiagramFilenameDOT:      DataFlowDiagramFilenameDOT,
+		DataAssetDiagramFilenameDOT:     DataAssetDiagramFilenameDOT,
+		AttackPathDiagramFilenamePNG:    AttackPathDiagramFilenamePNG,
+		AttackPathDiagramFilenameDOT:    AttackPathDiagramFilenameDOT,
+		DataFlowDiagramFilenameSVG:      DataFlowDiagramFilenameSVG,
+		DataAssetDiagramFilenameSVG:     DataAssetDiagramFilenameSVG,
+		AttackPathDiagramFilenameSVG:    AttackPathDiagramFilenameSVG,
+		DiagramViewerFilename:           DiagramViewerFilename,
+		DataFlowDiagramFilenameMermaid:  DataFlowDiagramFilenameMermaid,
+		DataFlowDiagramFilenamePlantUML: DataFlowDiagramFilenamePlantUML,
+		DataFlowDiagramFilenameDrawIO:   DataFlowDiagramFilenameDrawIO,
+		ReportFilename:                  ReportFilename,
+		ReportMarkdownFilename:          ReportMarkdownFilename,
+		ReportHTMLFilename:              ReportHTMLFilename,
+		ReportTemplateFolder:            "",
+		ExcelRisksFilename:              ExcelRisksFilename,
+		ExcelTagsFilename:               ExcelTagsFilename,
+		JsonRisksFilename:               JsonRisksFilename,
+		JsonTechnicalAssetsFilename:     JsonTechnicalAssetsFilename,
+		JsonStatsFilename:               JsonStatsFilename,
+		JsonAttackPathsFilename:         JsonAttackPathsFilename,
+		SarifRisksFilename:              SarifRisksFilename,
+		TemplateFilename:                TemplateFilename,
+		TechnologyFilename:              "",
+		ShapeTechnologyFilename:         "",
+		PolicyFilename:                  PolicyFilename,
+		TrackerFilename:                 TrackerFilename,
+
+		RAAPlugin:              RAAPluginName,
+		RiskRulesPlugins:       make([]string, 0),
+		RiskRulesScriptFolders: make([]string, 0),
+		SkipRiskRules:          make([]string, 0),
+		ExecuteModelMacro:      "",
 		RiskExcel: RiskExcelConfig{
 			HideColumns:   make([]string, 0),
 			SortByColumns: make([]string, 0),
 		},
+		Plugins: PluginConfig{
+			PluginLimits: PluginLimits{
+				TimeoutSeconds: DefaultPluginTimeoutSeconds,
+				MaxOutputBytes: DefaultPluginMaxOutputBytes,
+				Sandbox:        false,
+			},
+			PerPlugin: make(map[string]PerPluginLimits),
+		},
+		AttackPaths: AttackPathConfig{
+			MaxPaths: DefaultAttackPathMaxPaths,
+			MaxHops:  DefaultAttackPathMaxHops,
+			MaxSteps: DefaultAttackPathMaxSteps,
+		},
 
 		ServerMode:               false,
 		DiagramDPI:               DefaultDiagramDPI,
+		DiagramRenderer:          DiagramRendererAuto,
 		ServerPort:               DefaultServerPort,
 		GraphvizDPI:              DefaultGraphvizDPI,
 		MaxGraphvizDPI:           MaxGraphvizDPI,
@@ -190,6 +243,25 @@ func (c *Config) Load(configFilename string) error {
 	}
 
 	c.TechnologyFilename = c.CleanPath(c.TechnologyFilename)
+	if len(c.ShapeTechnologyFilename) > 0 {
+		c.ShapeTechnologyFilename = c.CleanPath(c.ShapeTechnologyFilename)
+	}
+	c.PolicyFilename = c.CleanPath(c.PolicyFilename)
+	c.TrackerFilename = c.CleanPath(c.TrackerFilename)
+
+	if len(c.ReportTemplateFolder) > 0 {
+		c.ReportTemplateFolder = c.CleanPath(c.ReportTemplateFolder)
+		templateDirError := c.checkDir(c.ReportTemplateFolder, "report template")
+		if templateDirError != nil {
+			errorList = append(errorList, templateDirError)
+		}
+	}
+
+	switch c.DiagramRenderer {
+	case DiagramRendererAuto, DiagramRendererGraphviz, DiagramRendererBuiltin:
+	default:
+		errorList = append(errorList, fmt.Errorf("unknown diagram renderer %q (%v, %v, %v)", c.DiagramRenderer, DiagramRendererAuto, DiagramRendererGraphviz, DiagramRendererBuiltin))
+	}
 
 	serverFolderError := c.CheckServerFolder()
 	if serverFolderError != nil {
@@ -262,9 +334,45 @@ func (c *Config) Merge(config Config, values map[string]any) {
 		case strings.ToLower("DataAssetDiagramFilenameDOT"):
 			c.DataAssetDiagramFilenameDOT = config.DataAssetDiagramFilenameDOT
 
+		case strings.ToLower("AttackPathDiagramFilenamePNG"):
+			c.AttackPathDiagramFilenamePNG = config.AttackPathDiagramFilenamePNG
+
+		case strings.ToLower("AttackPathDiagramFilenameDOT"):
+			c.AttackPathDiagramFilenameDOT = config.AttackPathDiagramFilenameDOT
+
+		case strings.ToLower("DataFlowDiagramFilenameSVG"):
+			c.DataFlowDiagramFilenameSVG = config.DataFlowDiagramFilenameSVG
+
+		case strings.ToLower("DataAssetDiagramFilenameSVG"):
+			c.DataAssetDiagramFilenameSVG = config.DataAssetDiagramFilenameSVG
+
+		case strings.ToLower("AttackPathDiagramFilenameSVG"):
+			c.AttackPathDiagramFilenameSVG = config.AttackPathDiagramFilenameSVG
+
+		case strings.ToLower("DiagramViewerFilename"):
+			c.DiagramViewerFilename = config.DiagramViewerFilename
+
+		case strings.ToLower("DataFlowDiagramFilenameMermaid"):
+			c.DataFlowDiagramFilenameMermaid = config.DataFlowDiagramFilenameMermaid
+
+		case strings.ToLower("DataFlowDiagramFilenamePlantUML"):
+			c.DataFlowDiagramFilenamePlantUML = config.DataFlowDiagramFilenamePlantUML
+
+		case strings.ToLower("DataFlowDiagramFilenameDrawIO"):
+			c.DataFlowDiagramFilenameDrawIO = config.DataFlowDiagramFilenameDrawIO
+
 		case strings.ToLower("ReportFilename"):
 			c.ReportFilename = config.ReportFilename
 
+		case strings.ToLower("ReportMarkdownFilename"):
+			c.ReportMarkdownFilename = config.ReportMarkdownFilename
+
+		case strings.ToLower("ReportHTMLFilename"):
+			c.ReportHTMLFilename = config.ReportHTMLFilename
+
+		case strings.ToLower("ReportTemplateFolder"):
+			c.ReportTemplateFolder = config.ReportTemplateFolder
+
 		case strings.ToLower("ExcelRisksFilename"):
 			c.ExcelRisksFilename = config.ExcelRisksFilename
 
@@ -280,18 +388,36 @@ func (c *Config) Merge(config Config, values map[string]any) {
 		case strings.ToLower("JsonStatsFilename"):
 			c.JsonStatsFilename = config.JsonStatsFilename
 
+		case strings.ToLower("JsonAttackPathsFilename"):
+			c.JsonAttackPathsFilename = config.JsonAttackPathsFilename
+
+		case strings.ToLower("SarifRisksFilename"):
+			c.SarifRisksFilename = config.SarifRisksFilename
+
 		case strings.ToLower("TemplateFilename"):
 			c.TemplateFilename = config.TemplateFilename
 
 		case strings.ToLower("TechnologyFilename"):
 			c.TechnologyFilename = config.TechnologyFilename
 
+		case strings.ToLower("ShapeTechnologyFilename"):
+			c.ShapeTechnologyFilename = config.ShapeTechnologyFilename
+
+		case strings.ToLower("PolicyFilename"):
+			c.PolicyFilename = config.PolicyFilename
+
+		case strings.ToLower("TrackerFilename"):
+			c.TrackerFilename = config.TrackerFilename
+
 		case strings.ToLower("RAAPlugin"):
 			c.RAAPlugin = config.RAAPlugin
 
 		case strings.ToLower("RiskRulesPlugins"):
 			c.RiskRulesPlugins = config.RiskRulesPlugins
 
+		case strings.ToLower("RiskRulesScriptFolders"):
+			c.RiskRulesScriptFolders = config.RiskRulesScriptFolders
+
 		case strings.ToLower("RiskExcel"):
 			configMap, mapOk := values[key].(map[string]any)
 			if !mapOk {
@@ -317,6 +443,53 @@ func (c *Config) Merge(config Config, values map[string]any) {
 				}
 			}
 
+		case strings.ToLower("Plugins"):
+			configMap, mapOk := values[key].(map[string]any)
+			if !mapOk {
+				continue
+			}
+
+			for valueName := range configMap {
+				switch strings.ToLower(valueName) {
+				case strings.ToLower("TimeoutSeconds"):
+					c.Plugins.TimeoutSeconds = config.Plugins.TimeoutSeconds
+
+				case strings.ToLower("MaxOutputBytes"):
+					c.Plugins.MaxOutputBytes = config.Plugins.MaxOutputBytes
+
+				case strings.ToLower("Sandbox"):
+					c.Plugins.Sandbox = config.Plugins.Sandbox
+
+				case strings.ToLower("PerPlugin"):
+					if c.Plugins.PerPlugin == nil {
+						c.Plugins.PerPlugin = make(map[string]PerPluginLimits)
+					}
+
+					for name, limits := range config.Plugins.PerPlugin {
+						c.Plugins.PerPlugin[name] = limits
+					}
+				}
+			}
+
+		case strings.ToLower("AttackPaths"):
+			configMap, mapOk := values[key].(map[string]any)
+			if !mapOk {
+				continue
+			}
+
+			for valueName := range configMap {
+				switch strings.ToLower(valueName) {
+				case strings.ToLower("MaxPaths"):
+					c.AttackPaths.MaxPaths = config.AttackPaths.MaxPaths
+
+				case strings.ToLower("MaxHops"):
+					c.AttackPaths.MaxHops = config.AttackPaths.MaxHops
+
+				case strings.ToLower("MaxSteps"):
+					c.AttackPaths.MaxSteps = config.AttackPaths.MaxSteps
+				}
+			}
+
 		case strings.ToLower("SkipRiskRules"):
 			c.SkipRiskRules = config.SkipRiskRules
 
@@ -326,6 +499,9 @@ func (c *Config) Merge(config Config, values map[string]any) {
 		case strings.ToLower("DiagramDPI"):
 			c.DiagramDPI = config.DiagramDPI
 
+		case strings.ToLower("DiagramRenderer"):
+			c.DiagramRenderer = config.DiagramRenderer
+
 		case strings.ToLower("ServerPort"):
 			c.ServerPort = config.ServerPort
 
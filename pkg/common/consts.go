@@ -11,26 +11,55 @@ const (
 
 	DefaultServerPort = 8080
 
-	InputFile                   = "threagile.yaml"
-	ReportFilename              = "report.pdf"
-	ExcelRisksFilename          = "risks.xlsx"
-	ExcelTagsFilename           = "tags.xlsx"
-	JsonRisksFilename           = "risks.json"
-	JsonTechnicalAssetsFilename = "technical-assets.json"
-	JsonStatsFilename           = "stats.json"
-	TemplateFilename            = "background.pdf"
-	DataFlowDiagramFilenameDOT  = "data-flow-diagram.gv"
-	DataFlowDiagramFilenamePNG  = "data-flow-diagram.png"
-	DataAssetDiagramFilenameDOT = "data-asset-diagram.gv"
-	DataAssetDiagramFilenamePNG = "data-asset-diagram.png"
+	InputFile                       = "threagile.yaml"
+	ReportFilename                  = "report.pdf"
+	ExcelRisksFilename              = "risks.xlsx"
+	ExcelTagsFilename               = "tags.xlsx"
+	JsonRisksFilename               = "risks.json"
+	JsonTechnicalAssetsFilename     = "technical-assets.json"
+	JsonStatsFilename               = "stats.json"
+	SarifRisksFilename              = "risks.sarif"
+	TemplateFilename                = "background.pdf"
+	DataFlowDiagramFilenameDOT      = "data-flow-diagram.gv"
+	DataFlowDiagramFilenamePNG      = "data-flow-diagram.png"
+	DataAssetDiagramFilenameDOT     = "data-asset-diagram.gv"
+	DataAssetDiagramFilenamePNG     = "data-asset-diagram.png"
+	AttackPathDiagramFilenameDOT    = "attack-path-diagram.gv"
+	AttackPathDiagramFilenamePNG    = "attack-path-diagram.png"
+	JsonAttackPathsFilename         = "attack-paths.json"
+	ReportMarkdownFilename          = "report.md"
+	ReportHTMLFilename              = "report.html"
+	DataFlowDiagramFilenameSVG      = "data-flow-diagram.svg"
+	DataAssetDiagramFilenameSVG     = "data-asset-diagram.svg"
+	AttackPathDiagramFilenameSVG    = "attack-path-diagram.svg"
+	DiagramViewerFilename           = "diagrams.html"
+	DataFlowDiagramFilenameMermaid  = "data-flow-diagram.mmd"
+	DataFlowDiagramFilenamePlantUML = "data-flow-diagram.puml"
+	DataFlowDiagramFilenameDrawIO   = "data-flow-diagram.drawio"
+	ImportedModelFilename           = "threagile-imported-model.yaml"
+	ImportLogFilename               = "threagile-import.log"
+	KubernetesModelFilename         = "threagile-kubernetes-model.yaml"
+	PolicyFilename                  = "threagile-policy.yaml"
+	TrackerFilename                 = "threagile-tracker.yaml"
 
-	RAAPluginName = "raa_calc"
+	RAAPluginName = "default"
 
 	DefaultDiagramDPI               = 100
 	DefaultGraphvizDPI              = 120
 	MinGraphvizDPI                  = 20
 	MaxGraphvizDPI                  = 300
 	DefaultBackupHistoryFilesToKeep = 50
+
+	DiagramRendererAuto     = "auto"     // graphviz if its dot command is installed, built-in otherwise
+	DiagramRendererGraphviz = "graphviz" // always graphviz
+	DiagramRendererBuiltin  = "builtin"  // always the built-in layout and rendering
+
+	DefaultPluginTimeoutSeconds = 120
+	DefaultPluginMaxOutputBytes = 64 << 20
+
+	DefaultAttackPathMaxPaths = 10
+	DefaultAttackPathMaxHops  = 6
+	DefaultAttackPathMaxSteps = 100000
 )
 
 const (
@@ -41,26 +70,39 @@ const (
 	ListTypesCommand            = "list-types"
 	ListRiskRulesCommand        = "list-risk-rules"
 	ListModelMacrosCommand      = "list-model-macros"
+	ListOverdueTrackingCommand  = "list-overdue-risk-tracking"
 	Print3rdPartyCommand        = "print-3rd-party-licenses"
 	PrintLicenseCommand         = "print-license"
+	TestRiskRulesCommand        = "test-rules"
+	LintRiskRulesCommand        = "lint-rules"
 
+	CheckCommand        = "check"
 	CreateCommand       = "create"
+	DiffCommand         = "diff"
 	ExplainCommand      = "explain"
+	ImportCommand       = "import"
 	ListCommand         = "list"
 	PrintCommand        = "print"
 	QuitCommand         = "quit"
 	RunCommand          = "run"
+	TrackingCommand     = "tracking"
 	PrintVersionCommand = "version"
 )
 
 const (
+	ComposeItem        = "compose"
+	DrawIOItem         = "drawio"
 	EditingSupportItem = "editing-support"
 	ExampleItem        = "example"
+	KubernetesItem     = "kubernetes"
 	LicenseItem        = "license"
 	MacrosItem         = "macros"
 	ModelItem          = "model"
 	RiskItem           = "risk"
 	RulesItem          = "rules"
 	StubItem           = "stub"
+	SyncItem           = "sync"
+	ThreatDragonItem   = "threat-dragon"
+	TM7Item            = "tm7"
 	TypesItem          = "types"
 )
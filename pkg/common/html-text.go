@@ -0,0 +1,11 @@
+package common
+
+import "regexp"
+
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// StripHTMLTags removes the HTML tags from a text, like those the risk rules format their texts with
+
+func StripHTMLTags(text string) string {
+	return htmlTagPattern.ReplaceAllString(text, "")
+}
@@ -0,0 +1,68 @@
+package common
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// PluginLimits restricts how long a RAA or custom risk rule plugin may run, how much it may write to stdout and what
+// it inherits from threagile; a sandboxed plugin gets no environment variables and runs in its own folder below
+// TempFolder
+
+type PluginLimits struct {
+	TimeoutSeconds int
+	MaxOutputBytes int
+	Sandbox        bool
+}
+
+// PerPluginLimits overrides the default plugin limits for an individual plugin; zero values and an unset Sandbox keep
+// the defaults, while an explicit Sandbox of false lifts a default sandbox
+
+type PerPluginLimits struct {
+	TimeoutSeconds int
+	MaxOutputBytes int
+	Sandbox        *bool
+}
+
+// PluginConfig holds the default plugin limits and the limits of individual plugins, keyed by their file name
+
+type PluginConfig struct {
+	PluginLimits
+	PerPlugin map[string]PerPluginLimits
+}
+
+func (what PluginLimits) Timeout() time.Duration {
+	return time.Duration(what.TimeoutSeconds) * time.Second
+}
+
+func (what PluginConfig) Limits(pluginFile string) PluginLimits {
+	limits := what.PluginLimits
+	if limits.TimeoutSeconds <= 0 {
+		limits.TimeoutSeconds = DefaultPluginTimeoutSeconds
+	}
+
+	if limits.MaxOutputBytes <= 0 {
+		limits.MaxOutputBytes = DefaultPluginMaxOutputBytes
+	}
+
+	for name, pluginLimits := range what.PerPlugin {
+		if !strings.EqualFold(name, filepath.Base(pluginFile)) && name != pluginFile {
+			continue
+		}
+
+		if pluginLimits.TimeoutSeconds > 0 {
+			limits.TimeoutSeconds = pluginLimits.TimeoutSeconds
+		}
+
+		if pluginLimits.MaxOutputBytes > 0 {
+			limits.MaxOutputBytes = pluginLimits.MaxOutputBytes
+		}
+
+		if pluginLimits.Sandbox != nil {
+			limits.Sandbox = *pluginLimits.Sandbox
+		}
+	}
+
+	return limits
+}
@@ -0,0 +1,436 @@
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+	"gopkg.in/yaml.v3"
+)
+
+// composeFilenames are the names of the compose files looked for in a directory, in order of precedence
+
+var composeFilenames = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+
+// composeFile and friends are the parts of the compose file format read by ImportCompose, with the values that come
+// in a short (string or list) and a long (mapping) syntax kept as nodes
+
+type composeFile struct {
+	Name     string                     `yaml:"name"`
+	Services map[string]*composeService `yaml:"services"`
+	Networks map[string]yaml.Node       `yaml:"networks"`
+	Volumes  map[string]yaml.Node       `yaml:"volumes"`
+}
+
+type composeService struct {
+	Image       string      `yaml:"image"`
+	Build       yaml.Node   `yaml:"build"`
+	Command     yaml.Node   `yaml:"command"`
+	Environment yaml.Node   `yaml:"environment"`
+	Ports       []yaml.Node `yaml:"ports"`
+	Expose      []yaml.Node `yaml:"expose"`
+	DependsOn   yaml.Node   `yaml:"depends_on"`
+	Links       []string    `yaml:"links"`
+	Networks    yaml.Node   `yaml:"networks"`
+	NetworkMode string      `yaml:"network_mode"`
+	Volumes     []yaml.Node `yaml:"volumes"`
+	Deploy      struct {
+		Replicas *int `yaml:"replicas"`
+	} `yaml:"deploy"`
+}
+
+// composePort is a port of a service, published on the host or only exposed to the other services
+
+type composePort struct {
+	target    int
+	published bool
+}
+
+// composeMount is a volume or bind mount of a service
+
+type composeMount struct {
+	kind     string
+	source   string
+	readOnly bool
+}
+
+// composeNames returns the names listed by a node in short (list) or long (mapping) syntax, like the networks and
+// dependencies of a service
+
+func composeNames(node *yaml.Node) []string {
+	names := make([]string, 0)
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			names = append(names, item.Value)
+		}
+	case yaml.MappingNode:
+		for n := 0; n+1 < len(node.Content); n += 2 {
+			names = append(names, node.Content[n].Value)
+		}
+	case yaml.ScalarNode:
+		if len(node.Value) > 0 {
+			names = append(names, node.Value)
+		}
+	}
+	return names
+}
+
+// composeEnvironment returns the values of the environment variables of a service given as list (NAME=value) or
+// mapping, by name
+
+func composeEnvironment(node *yaml.Node) map[string]string {
+	environment := make(map[string]string)
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			name, value, _ := strings.Cut(item.Value, "=")
+			environment[name] = value
+		}
+	case yaml.MappingNode:
+		for n := 0; n+1 < len(node.Content); n += 2 {
+			environment[node.Content[n].Value] = node.Content[n+1].Value
+		}
+	}
+	return environment
+}
+
+// composePortOf parses a port given as number, as text (like 127.0.0.1:8080:80/tcp) or as mapping; the target port
+// of a port range is its first one
+
+func composePortOf(node *yaml.Node, published bool) composePort {
+	if node.Kind == yaml.MappingNode {
+		var port struct {
+			Target    int       `yaml:"target"`
+			Published yaml.Node `yaml:"published"`
+		}
+		_ = node.Decode(&port)
+		return composePort{target: port.Target, published: published && len(port.Published.Value) > 0}
+	}
+
+	value, _, _ := strings.Cut(node.Value, "/")
+	parts := strings.Split(value, ":")
+	first, _, _ := strings.Cut(parts[len(parts)-1], "-")
+	target, _ := strconv.Atoi(first)
+	return composePort{target: target, published: published && len(parts) > 1}
+}
+
+// composeMountOf parses a mount given as text (like data:/var/lib/data:ro) or as mapping
+
+func composeMountOf(node *yaml.Node) composeMount {
+	if node.Kind == yaml.MappingNode {
+		var mount struct {
+			Type     string `yaml:"type"`
+			Source   string `yaml:"source"`
+			ReadOnly bool   `yaml:"read_only"`
+		}
+		_ = node.Decode(&mount)
+		return composeMount{kind: mount.Type, source: mount.Source, readOnly: mount.ReadOnly}
+	}
+
+	parts := strings.Split(node.Value, ":")
+	if len(parts) == 1 {
+		return composeMount{kind: "volume"}
+	}
+
+	mount := composeMount{kind: "volume", source: parts[0]}
+	if strings.ContainsAny(mount.source, "/.~$") {
+		mount.kind = "bind"
+	}
+	if len(parts) > 2 {
+		for _, option := range strings.Split(parts[2], ",") {
+			mount.readOnly = mount.readOnly || option == "ro"
+		}
+	}
+	return mount
+}
+
+// ImportCompose imports the given compose file, or the compose file of the given directory, into a stub model:
+// services become technical assets of the technology their image is known as, networks become trust boundaries,
+// named volumes become datastores, and dependencies, links, the addresses in the services' environment and commands
+// as well as the published ports become communication links
+
+func ImportCompose(path string, shapeTechnologies *ShapeTechnologies) (*StubModel, error) {
+	filename := filepath.Clean(path)
+	if info, statError := os.Stat(filename); statError == nil && info.IsDir() {
+		for _, name := range composeFilenames {
+			if _, existsError := os.Stat(filepath.Join(filename, name)); existsError == nil {
+				filename = filepath.Join(filename, name)
+				break
+			}
+		}
+	}
+
+	data, readError := os.ReadFile(filename)
+	if readError != nil {
+		return nil, fmt.Errorf("error reading %s: %v", path, readError)
+	}
+
+	file := new(composeFile)
+	parseError := yaml.Unmarshal(data, file)
+	if parseError != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", filename, parseError)
+	}
+	if len(file.Services) == 0 {
+		return nil, fmt.Errorf("no services found in %s", filename)
+	}
+
+	title := file.Name
+	if len(title) == 0 {
+		title = filepath.Base(filepath.Dir(filename))
+	}
+
+	compose := &composeImporter{
+		stub:              new(StubModel).Init(title),
+		shapeTechnologies: shapeTechnologies,
+		file:              file,
+		titles:            make(map[string]string),
+		links:             make(map[string]string),
+	}
+
+	names := make([]string, 0)
+	for name, service := range file.Services {
+		if service == nil {
+			file.Services[name] = new(composeService)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		compose.addService(name)
+	}
+	for _, name := range names {
+		compose.addVolumes(name)
+		compose.addServiceLinks(name)
+	}
+	for _, name := range names {
+		compose.addPortLinks(name)
+	}
+	compose.addNetworks(names)
+
+	return compose.stub, nil
+}
+
+type composeImporter struct {
+	stub              *StubModel
+	shapeTechnologies *ShapeTechnologies
+	file              *composeFile
+	titles            map[string]string
+	links             map[string]string
+}
+
+// addService adds the service as technical asset of the technology of its image, or the one its name names;
+// services of a well-known image that is not built are no custom development, those storing data are datastores
+
+func (what *composeImporter) addService(name string) {
+	service := what.file.Services[name]
+	technology := what.shapeTechnologies.Image(service.Image)
+	offTheShelf := technology != nil && service.Build.IsZero()
+	guessed := false
+	if technology == nil {
+		technology, guessed = what.shapeTechnologies.Find(nil, []string{name})
+	}
+
+	assetType := types.Process
+	if isStorageTechnology(technology) {
+		assetType = types.Datastore
+	} else if technology.Name == types.UnknownTechnology {
+		for _, volume := range service.Volumes {
+			if mount := composeMountOf(&volume); mount.kind == "volume" && len(mount.source) > 0 {
+				assetType = types.Datastore
+			}
+		}
+	}
+
+	title := what.stub.AddTechnicalAsset(name, technology, guessed, assetType)
+	asset := what.stub.Model.TechnicalAssets[title]
+	asset.Size = types.Service.String()
+	asset.Machine = types.Container.String()
+	asset.CustomDevelopedParts = !offTheShelf
+	asset.Redundant = service.Deploy.Replicas != nil && *service.Deploy.Replicas > 1
+	what.stub.Model.TechnicalAssets[title] = asset
+	what.titles[name] = title
+}
+
+// addVolumes adds links from the service to the named volumes it mounts, adding each volume as datastore on first
+// use; bind mounts of host paths are left out
+
+func (what *composeImporter) addVolumes(name string) {
+	for _, volume := range what.file.Services[name].Volumes {
+		mount := composeMountOf(&volume)
+		switch {
+		case mount.kind == "bind":
+			what.stub.Logf("skipped the bind mount of %s into service %v", mount.source, name)
+			continue
+		case mount.kind != "volume" || len(mount.source) == 0:
+			continue
+		}
+
+		volumeTitle, ok := what.titles["volume:"+mount.source]
+		if !ok {
+			if _, declared := what.file.Volumes[mount.source]; !declared {
+				what.stub.Logf("volume %v of service %v is not declared", mount.source, name)
+			}
+
+			volumeTitle = what.stub.AddTechnicalAsset("Volume "+mount.source, what.shapeTechnologies.Label(types.LocalFileSystem), false, types.Datastore)
+			what.titles["volume:"+mount.source] = volumeTitle
+		}
+
+		linkTitle := what.addLink(what.titles[name], volumeTitle, types.LocalFileAccess)
+		if mount.readOnly {
+			source := what.stub.Model.TechnicalAssets[what.titles[name]]
+			link := source.CommunicationLinks[linkTitle]
+			link.Readonly = true
+			source.CommunicationLinks[linkTitle] = link
+		}
+	}
+}
+
+// addServiceLinks adds links to the services the service depends on, is linked to, or addresses in its environment
+// variables or command
+
+func (what *composeImporter) addServiceLinks(name string) {
+	service := what.file.Services[name]
+	targets := composeNames(&service.DependsOn)
+	for _, link := range service.Links {
+		target, _, _ := strings.Cut(link, ":")
+		targets = append(targets, target)
+	}
+	for _, target := range targets {
+		if _, ok := what.file.Services[target]; !ok {
+			what.stub.Logf("skipped the dependency of service %v on %v, which is no service", name, target)
+			continue
+		}
+		what.addLink(what.titles[name], what.titles[target], what.servicePortProtocol(target, 0, ""))
+	}
+
+	values := composeNames(&service.Command)
+	if service.Command.Kind == yaml.ScalarNode {
+		values = strings.Fields(service.Command.Value)
+	}
+	for variable, value := range composeEnvironment(&service.Environment) {
+		if serviceAddressVariable.MatchString(variable) || strings.Contains(value, ":") {
+			values = append(values, value)
+		}
+	}
+	sort.Strings(values)
+
+	for _, value := range values {
+		for _, match := range serviceAddress.FindAllStringSubmatch(strings.ToLower(value), -1) {
+			scheme, host, port := match[1], match[2], match[3]
+			if _, ok := what.file.Services[host]; !ok || host == name || (len(scheme) == 0 && len(port) == 0 && value != host) {
+				continue
+			}
+
+			number, _ := strconv.Atoi(port)
+			what.addLink(what.titles[name], what.titles[host], what.servicePortProtocol(host, number, scheme))
+		}
+	}
+}
+
+// addPortLinks adds links from an external client to the services publishing ports on the host, adding the client
+// on first use
+
+func (what *composeImporter) addPortLinks(name string) {
+	for _, port := range what.file.Services[name].Ports {
+		if !composePortOf(&port, true).published {
+			continue
+		}
+
+		clientTitle, ok := what.titles["client"]
+		if !ok {
+			clientTitle = what.stub.AddTechnicalAsset("External Client", what.shapeTechnologies.Label(types.ClientSystem), false, types.ExternalEntity)
+			what.titles["client"] = clientTitle
+		}
+		what.addLink(clientTitle, what.titles[name], what.servicePortProtocol(name, composePortOf(&port, true).target, ""))
+	}
+}
+
+// addNetworks adds a trust boundary around the services of each network, which services without networks are in
+// the default network of; services in several networks are put into the first one only, as trust boundaries must
+// not overlap
+
+func (what *composeImporter) addNetworks(names []string) {
+	networks := make(map[string][]string)
+	for _, name := range names {
+		service := what.file.Services[name]
+		if len(service.NetworkMode) > 0 {
+			what.stub.Logf("skipped the network mode %v of service %v", service.NetworkMode, name)
+			continue
+		}
+
+		serviceNetworks := composeNames(&service.Networks)
+		if len(serviceNetworks) == 0 {
+			serviceNetworks = []string{"default"}
+		}
+		sort.Strings(serviceNetworks)
+		for _, network := range serviceNetworks {
+			if _, declared := what.file.Networks[network]; !declared && network != "default" {
+				what.stub.Logf("network %v of service %v is not declared", network, name)
+			}
+		}
+		if len(serviceNetworks) > 1 {
+			what.stub.Logf("service %v is in the networks %v, but put into the trust boundary of %v only", name, strings.Join(serviceNetworks, ", "), serviceNetworks[0])
+		}
+
+		network := serviceNetworks[0]
+		networks[network] = append(networks[network], what.stub.Model.TechnicalAssets[what.titles[name]].ID)
+	}
+
+	networkNames := make([]string, 0)
+	for network := range networks {
+		networkNames = append(networkNames, network)
+	}
+	sort.Strings(networkNames)
+
+	for _, network := range networkNames {
+		title := "Network " + network
+		what.stub.AddTrustBoundary(title, what.stub.UniqueId(title), types.NetworkVirtualLAN.String(), networks[network], nil)
+	}
+}
+
+// addLink adds a communication link between the technical assets with the given titles, unless there already is
+// one; a link of unknown protocol takes the protocol of a later one; it returns the title of the link
+
+func (what *composeImporter) addLink(sourceTitle string, targetTitle string, protocol types.Protocol) string {
+	if title, ok := what.links[sourceTitle+">"+targetTitle]; ok {
+		source := what.stub.Model.TechnicalAssets[sourceTitle]
+		link := source.CommunicationLinks[title]
+		if link.Protocol == types.UnknownProtocol.String() && protocol != types.UnknownProtocol {
+			link.Protocol = protocol.String()
+			source.CommunicationLinks[title] = link
+		}
+		return title
+	}
+
+	title := what.stub.AddCommunicationLink(sourceTitle, targetTitle, "Traffic to "+targetTitle, protocol)
+	what.links[sourceTitle+">"+targetTitle] = title
+	return title
+}
+
+// servicePortProtocol returns the protocol of the service's port with the given number (or the first port it
+// publishes or exposes, if 0), as named by the scheme or the service's image and name
+
+func (what *composeImporter) servicePortProtocol(name string, number int, scheme string) types.Protocol {
+	service := what.file.Services[name]
+	image := service.Image[strings.LastIndex(service.Image, "/")+1:]
+	image, _, _ = strings.Cut(image, ":")
+
+	if protocol, ok := wellKnownProtocol(number, scheme, image, name); ok {
+		return protocol
+	}
+	if number == 0 {
+		for _, port := range append(append(make([]yaml.Node, 0), service.Ports...), service.Expose...) {
+			if protocol, ok := wellKnownProtocol(composePortOf(&port, false).target); ok {
+				return protocol
+			}
+		}
+	}
+
+	return types.UnknownProtocol
+}
@@ -0,0 +1,131 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
+	"gopkg.in/yaml.v3"
+)
+
+const composeTestFile = `name: shop
+services:
+  proxy:
+    image: docker.io/library/nginx:1.25
+    ports: ["443:443", "127.0.0.1:8080:80/tcp"]
+    depends_on: [web]
+    networks: [front]
+    volumes:
+      - ./nginx.conf:/etc/nginx/nginx.conf:ro
+  web:
+    build: .
+    environment:
+      - DATABASE_URL=postgres://app:secret@db:5432/app
+      - CACHE_HOST=cache
+      - LOG_LEVEL=info
+    expose: ["8080"]
+    networks: [back]
+    deploy: {replicas: 2}
+  db:
+    image: postgres:16
+    volumes: ["db-data:/var/lib/postgresql/data"]
+    networks: [back]
+  cache:
+    image: redis
+    networks: [back]
+  worker:
+    image: example/worker
+    command: ["--queue", "amqp://queue"]
+    depends_on:
+      db: {condition: service_healthy}
+      missing: {condition: service_started}
+    volumes:
+      - type: volume
+        source: db-data
+        target: /backup
+        read_only: true
+  queue:
+    image: rabbitmq:3
+volumes:
+  db-data:
+networks:
+  front:
+  back: {internal: true}
+`
+
+func TestImportCompose(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docker-compose.yml"), []byte(composeTestFile), 0600))
+
+	stub, err := ImportCompose(dir, loadTestShapeTechnologies(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, "shop", stub.Model.Title)
+
+	assets := stub.Model.TechnicalAssets
+	require.Len(t, assets, 8)
+	assert.Equal(t, "web-server", assets["proxy"].Technology)
+	assert.False(t, assets["proxy"].CustomDevelopedParts)
+	assert.Equal(t, "container", assets["proxy"].Machine)
+	assert.Equal(t, "unknown-technology", assets["web"].Technology)
+	assert.True(t, assets["web"].CustomDevelopedParts)
+	assert.True(t, assets["web"].Redundant)
+	assert.Equal(t, "database", assets["db"].Technology)
+	assert.Equal(t, "datastore", assets["db"].Type)
+	assert.Equal(t, "database", assets["cache"].Technology)
+	assert.Equal(t, "message-queue", assets["queue"].Technology)
+	assert.Equal(t, "local-file-system", assets["Volume db-data"].Technology)
+	assert.Equal(t, "datastore", assets["Volume db-data"].Type)
+	assert.Equal(t, "external-entity", assets["External Client"].Type)
+
+	assert.Equal(t, "https", assets["External Client"].CommunicationLinks["Traffic to proxy"].Protocol)
+	assert.Equal(t, "http", assets["proxy"].CommunicationLinks["Traffic to web"].Protocol)
+	assert.Equal(t, "sql-access-protocol", assets["web"].CommunicationLinks["Traffic to db"].Protocol)
+	assert.Equal(t, "nosql-access-protocol", assets["web"].CommunicationLinks["Traffic to cache"].Protocol)
+	assert.Equal(t, "binary", assets["worker"].CommunicationLinks["Traffic to queue"].Protocol)
+	assert.Equal(t, "local-file-access", assets["db"].CommunicationLinks["Traffic to Volume db-data"].Protocol)
+	assert.True(t, assets["worker"].CommunicationLinks["Traffic to Volume db-data"].Readonly)
+	assert.Len(t, assets["web"].CommunicationLinks, 2)
+
+	require.Len(t, stub.Model.TrustBoundaries, 3)
+	assert.Equal(t, "network-virtual-lan", stub.Model.TrustBoundaries["Network back"].Type)
+	assert.Equal(t, []string{"cache", "db", "web"}, stub.Model.TrustBoundaries["Network back"].TechnicalAssetsInside)
+	assert.Equal(t, []string{"proxy"}, stub.Model.TrustBoundaries["Network front"].TechnicalAssetsInside)
+	assert.Equal(t, []string{"queue", "worker"}, stub.Model.TrustBoundaries["Network default"].TechnicalAssetsInside)
+
+	require.Len(t, stub.Log, 2)
+	assert.Contains(t, stub.Log[0], "nginx.conf")
+	assert.Contains(t, stub.Log[1], "missing")
+}
+
+func TestImportComposeModelParses(t *testing.T) {
+	filename := writeTestFile(t, "compose.yaml", composeTestFile)
+
+	stub, err := ImportCompose(filename, loadTestShapeTechnologies(t))
+	require.NoError(t, err)
+
+	content, err := stub.Marshal()
+	require.NoError(t, err)
+
+	modelInput := new(input.Model).Defaults()
+	require.NoError(t, yaml.Unmarshal(content, modelInput))
+
+	parsedModel, err := model.ParseModel(&common.Config{}, modelInput, make(types.RiskRules), make(types.RiskRules))
+	require.NoError(t, err)
+	assert.Len(t, parsedModel.TechnicalAssets, 8)
+	assert.Len(t, parsedModel.TrustBoundaries, 3)
+}
+
+func TestImportComposeWithoutServices(t *testing.T) {
+	_, err := ImportCompose(writeTestFile(t, "compose.yaml", "volumes:\n  data:\n"), loadTestShapeTechnologies(t))
+	assert.Error(t, err)
+
+	_, err = ImportCompose(t.TempDir(), loadTestShapeTechnologies(t))
+	assert.Error(t, err)
+}
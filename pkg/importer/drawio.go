@@ -0,0 +1,553 @@
+package importer
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// drawIOFile and friends are the parts of the draw.io (mxGraph) file format read by ImportDrawIO, with the cells
+// kept in file order whether they are plain or wrapped into objects carrying custom properties
+
+type drawIOFile struct {
+	XMLName  xml.Name
+	Diagrams []*drawIODiagram `xml:"diagram"`
+	Root     *drawIORoot      `xml:"root"`
+}
+
+type drawIODiagram struct {
+	Name       string            `xml:"name,attr"`
+	Model      *drawIOGraphModel `xml:"mxGraphModel"`
+	Compressed string            `xml:",chardata"`
+}
+
+type drawIOGraphModel struct {
+	Root *drawIORoot `xml:"root"`
+}
+
+type drawIORoot struct {
+	Elements []*drawIOElement `xml:",any"`
+}
+
+type drawIOElement struct {
+	XMLName    xml.Name
+	Attributes []xml.Attr     `xml:",any,attr"`
+	Cell       *drawIOElement `xml:"mxCell"`
+	Geometry   *struct {
+		X      float64 `xml:"x,attr"`
+		Y      float64 `xml:"y,attr"`
+		Width  float64 `xml:"width,attr"`
+		Height float64 `xml:"height,attr"`
+	} `xml:"mxGeometry"`
+}
+
+func (what *drawIOElement) attribute(name string) string {
+	for _, attribute := range what.Attributes {
+		if attribute.Name.Local == name {
+			return attribute.Value
+		}
+	}
+	return ""
+}
+
+// drawIOCell is a shape or edge of the diagram, with its label split into lines and its style into shape names
+
+type drawIOCell struct {
+	id     string
+	parent string
+	source string
+	target string
+	vertex bool
+	edge   bool
+	lines  []string
+	title  string
+	style  map[string]string
+	shapes []string
+	x, y   float64
+	width  float64
+	height float64
+}
+
+func (what *drawIOCell) is(style string) bool {
+	_, ok := what.style[style]
+	return ok
+}
+
+func (what *drawIOCell) contains(other *drawIOCell) bool {
+	return what.width*what.height > other.width*other.height &&
+		what.x <= other.x && what.y <= other.y &&
+		what.x+what.width >= other.x+other.width && what.y+what.height >= other.y+other.height
+}
+
+// ImportDrawIO reads the first page of a draw.io file into a stub model: shapes become technical assets of the
+// technology their shape or label names, containers (and shapes drawn around others) become trust boundaries or
+// shared runtimes, and edges become communication links of the protocol their label names
+
+func ImportDrawIO(filename string, shapeTechnologies *ShapeTechnologies) (*StubModel, error) {
+	data, readError := os.ReadFile(filepath.Clean(filename))
+	if readError != nil {
+		return nil, fmt.Errorf("error reading %s: %v", filename, readError)
+	}
+
+	title, cells, parseError := parseDrawIO(data)
+	if parseError != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", filename, parseError)
+	}
+	if len(title) == 0 {
+		title = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	}
+
+	stub := new(StubModel).Init(title)
+	importError := importDrawIOCells(stub, cells, shapeTechnologies)
+	if importError != nil {
+		return nil, fmt.Errorf("error importing %s: %v", filename, importError)
+	}
+
+	return stub, nil
+}
+
+func parseDrawIO(data []byte) (string, []*drawIOCell, error) {
+	file := new(drawIOFile)
+	unmarshalError := xml.Unmarshal(data, file)
+	if unmarshalError != nil {
+		return "", nil, unmarshalError
+	}
+
+	title := ""
+	root := file.Root
+	if file.XMLName.Local == "mxfile" {
+		if len(file.Diagrams) == 0 {
+			return "", nil, fmt.Errorf("no diagram found")
+		}
+
+		diagram := file.Diagrams[0]
+		title = diagram.Name
+		if diagram.Model == nil {
+			model, decodeError := decodeDrawIODiagram(diagram.Compressed)
+			if decodeError != nil {
+				return "", nil, fmt.Errorf("unable to decode diagram %q: %v", diagram.Name, decodeError)
+			}
+			diagram.Model = model
+		}
+		root = diagram.Model.Root
+	} else if file.XMLName.Local != "mxGraphModel" {
+		return "", nil, fmt.Errorf("neither a draw.io file nor a graph model: %v", file.XMLName.Local)
+	}
+
+	if root == nil {
+		return "", nil, fmt.Errorf("no cells found")
+	}
+
+	cells := make([]*drawIOCell, 0)
+	for _, element := range root.Elements {
+		cell := element
+		label := element.attribute("value")
+		if element.XMLName.Local != "mxCell" {
+			if element.Cell == nil {
+				continue
+			}
+			cell = element.Cell
+			label = element.attribute("label")
+		}
+
+		lines, title := drawIOLabel(label)
+		style, shapes := drawIOStyle(cell.attribute("style"))
+		imported := &drawIOCell{
+			id:     element.attribute("id"),
+			parent: cell.attribute("parent"),
+			source: cell.attribute("source"),
+			target: cell.attribute("target"),
+			vertex: cell.attribute("vertex") == "1",
+			edge:   cell.attribute("edge") == "1",
+			lines:  lines,
+			title:  title,
+			style:  style,
+			shapes: shapes,
+		}
+		if cell.Geometry != nil {
+			imported.x, imported.y = cell.Geometry.X, cell.Geometry.Y
+			imported.width, imported.height = cell.Geometry.Width, cell.Geometry.Height
+		}
+		cells = append(cells, imported)
+	}
+
+	return title, cells, nil
+}
+
+// decodeDrawIODiagram decodes the compressed diagram format: URL encoded, deflated and then base64 encoded
+
+func decodeDrawIODiagram(compressed string) (*drawIOGraphModel, error) {
+	deflated, decodeError := base64.StdEncoding.DecodeString(strings.TrimSpace(compressed))
+	if decodeError != nil {
+		return nil, decodeError
+	}
+
+	encoded, inflateError := io.ReadAll(flate.NewReader(bytes.NewReader(deflated)))
+	if inflateError != nil {
+		return nil, inflateError
+	}
+
+	decoded, unescapeError := url.PathUnescape(string(encoded))
+	if unescapeError != nil {
+		return nil, unescapeError
+	}
+
+	model := new(drawIOGraphModel)
+	unmarshalError := xml.Unmarshal([]byte(decoded), model)
+	if unmarshalError != nil {
+		return nil, unmarshalError
+	}
+	return model, nil
+}
+
+var (
+	drawIOLineBreak = regexp.MustCompile(`(?i)<br\s*/?>|</?div[^>]*>|</?p[^>]*>|\n`)
+	drawIOBold      = regexp.MustCompile(`(?i)<b>|<strong>|font-weight:\s*(bold|[6-9]00)`)
+)
+
+// drawIOLabel splits a (possibly HTML) label into its lines of text and picks the first bold line as title, or the
+// first line if there is none
+
+func drawIOLabel(label string) ([]string, string) {
+	lines := make([]string, 0)
+	title := ""
+	for _, line := range drawIOLineBreak.Split(label, -1) {
+		text := strings.Join(strings.Fields(html.UnescapeString(common.StripHTMLTags(line))), " ")
+		if len(text) == 0 {
+			continue
+		}
+		if len(title) == 0 && drawIOBold.MatchString(line) {
+			title = text
+		}
+		lines = append(lines, text)
+	}
+
+	if len(title) == 0 && len(lines) > 0 {
+		title = lines[0]
+	}
+	return lines, title
+}
+
+// drawIOStyle splits a style into its entries and the shape names it uses, most specific first: the icon of
+// resource shapes, then the shape and finally the bare style names like ellipse
+
+func drawIOStyle(style string) (map[string]string, []string) {
+	entries := make(map[string]string)
+	bare := make([]string, 0)
+	for _, entry := range strings.Split(style, ";") {
+		key, value, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if len(key) == 0 {
+			continue
+		}
+		entries[key] = value
+		if !found {
+			bare = append(bare, key)
+		}
+	}
+
+	shapes := make([]string, 0)
+	for _, key := range []string{"resIcon", "prIcon", "shape"} {
+		if value, ok := entries[key]; ok && len(value) > 0 {
+			shapes = append(shapes, value)
+		}
+	}
+	return entries, append(shapes, bare...)
+}
+
+var drawIOTrustBoundaryKeywords = []struct {
+	keyword      string
+	boundaryType types.TrustBoundaryType
+}{
+	{"security group", types.NetworkCloudSecurityGroup},
+	{"namespace", types.NetworkPolicyNamespaceIsolation},
+	{"vlan", types.NetworkVirtualLAN},
+	{"virtual lan", types.NetworkVirtualLAN},
+	{"cloud", types.NetworkCloudProvider},
+	{"hoster", types.NetworkDedicatedHoster},
+	{"execution environment", types.ExecutionEnvironment},
+}
+
+const drawIOSharedRuntimeSuffix = " (shared runtime)"
+
+func importDrawIOCells(stub *StubModel, cells []*drawIOCell, shapeTechnologies *ShapeTechnologies) error {
+	cellsById := make(map[string]*drawIOCell)
+	for _, cell := range cells {
+		cellsById[cell.id] = cell
+	}
+
+	// edge labels are vertices placed on their edge, and text is no shape at all
+
+	vertices := make([]*drawIOCell, 0)
+	edges := make([]*drawIOCell, 0)
+	for _, cell := range cells {
+		if parent, ok := cellsById[cell.parent]; ok && parent.edge {
+			parent.lines = append(parent.lines, cell.lines...)
+			if len(parent.title) == 0 {
+				parent.title = cell.title
+			}
+			continue
+		}
+
+		switch {
+		case cell.edge:
+			edges = append(edges, cell)
+		case cell.vertex && !cell.is("text") && !cell.is("edgeLabel"):
+			vertices = append(vertices, cell)
+		}
+	}
+
+	// shapes nested into another one (the way containers and groups work) are positioned relative to it, and groups
+	// are transparent for finding the container
+
+	isVertex := make(map[string]bool)
+	for _, vertex := range vertices {
+		isVertex[vertex.id] = true
+	}
+
+	parentOf := func(cell *drawIOCell) *drawIOCell {
+		if parent, ok := cellsById[cell.parent]; ok && isVertex[parent.id] {
+			return parent
+		}
+		return nil
+	}
+
+	positioned := make(map[*drawIOCell]bool)
+	var position func(cell *drawIOCell, depth int)
+	position = func(cell *drawIOCell, depth int) {
+		if positioned[cell] {
+			return
+		}
+		positioned[cell] = true
+		if parent := parentOf(cell); parent != nil && depth < len(cells) {
+			position(parent, depth+1)
+			cell.x += parent.x
+			cell.y += parent.y
+		}
+	}
+	for _, vertex := range vertices {
+		position(vertex, 0)
+	}
+
+	containerOf := make(map[*drawIOCell]*drawIOCell)
+	for _, vertex := range vertices {
+		parent := parentOf(vertex)
+		for parent != nil && parent.is("group") {
+			parent = parentOf(parent)
+		}
+
+		if parent != nil {
+			containerOf[vertex] = parent
+			continue
+		}
+
+		// a shape drawn around others without being their container in draw.io is meant to be one
+
+		for _, candidate := range vertices {
+			if candidate == vertex || candidate.is("group") || !candidate.contains(vertex) {
+				continue
+			}
+			if current, ok := containerOf[vertex]; !ok || current.contains(candidate) {
+				containerOf[vertex] = candidate
+			}
+		}
+	}
+
+	isContainer := make(map[*drawIOCell]bool)
+	for _, vertex := range vertices {
+		if vertex.is("swimlane") || vertex.style["container"] == "1" {
+			isContainer[vertex] = true
+		}
+	}
+	for vertex, container := range containerOf {
+		if !vertex.is("group") {
+			isContainer[container] = true
+		}
+	}
+
+	// technical assets first, as the trust boundaries reference them
+
+	assetTitles := make(map[*drawIOCell]string)
+	for _, vertex := range vertices {
+		if isContainer[vertex] || vertex.is("group") {
+			continue
+		}
+		if len(vertex.title) == 0 {
+			stub.Logf("skipped shape %v, which has no label to name a technical asset by", vertex.id)
+			continue
+		}
+
+		technology, guessed := shapeTechnologies.Find(vertex.shapes, vertex.lines)
+		title := stub.AddTechnicalAsset(vertex.title, technology, guessed, drawIOTechnicalAssetType(vertex, technology))
+		for _, line := range vertex.lines {
+			if size, parseError := types.ParseTechnicalAssetSize(line); parseError == nil && line != vertex.title {
+				asset := stub.Model.TechnicalAssets[title]
+				asset.Size = size.String()
+				stub.Model.TechnicalAssets[title] = asset
+				break
+			}
+		}
+		assetTitles[vertex] = title
+	}
+
+	if len(assetTitles) == 0 {
+		return fmt.Errorf("no shapes found to import as technical assets")
+	}
+
+	for _, edge := range edges {
+		source, target := cellsById[edge.source], cellsById[edge.target]
+		sourceTitle, sourceOk := assetTitles[source]
+		targetTitle, targetOk := assetTitles[target]
+		if !sourceOk || !targetOk {
+			stub.Logf("skipped edge %v %q, which does not connect two technical assets", edge.id, edge.title)
+			continue
+		}
+
+		// the label names the protocol, the link or both
+
+		protocol := types.UnknownProtocol
+		title := ""
+		for _, line := range edge.lines {
+			if parsed, parseError := types.ParseProtocol(strings.ToLower(line)); parseError == nil && protocol == types.UnknownProtocol {
+				protocol = parsed
+			} else if len(title) == 0 {
+				title = line
+			}
+		}
+		if len(title) == 0 {
+			title = "Traffic to " + targetTitle
+		}
+		stub.AddCommunicationLink(sourceTitle, targetTitle, title, protocol)
+	}
+
+	// trust boundaries and shared runtimes, in document order so that the titles made unique follow the drawing
+
+	containers := make([]*drawIOCell, 0)
+	for _, vertex := range vertices {
+		if isContainer[vertex] {
+			containers = append(containers, vertex)
+		}
+	}
+
+	type containerInfo struct {
+		title         string
+		id            string
+		boundaryType  string
+		guessed       bool
+		sharedRuntime bool
+	}
+
+	infos := make(map[*drawIOCell]*containerInfo)
+	for n, container := range containers {
+		info := &containerInfo{title: container.title}
+		if strings.HasSuffix(container.title, drawIOSharedRuntimeSuffix) {
+			info.title, info.sharedRuntime = strings.TrimSuffix(container.title, drawIOSharedRuntimeSuffix), true
+		} else {
+			info.title, info.boundaryType, info.guessed = drawIOTrustBoundaryType(container)
+		}
+		if len(info.title) == 0 {
+			info.title = "Trust Boundary " + strconv.Itoa(n+1)
+		}
+		info.id = stub.UniqueId(info.title)
+		infos[container] = info
+	}
+
+	// shared runtimes are no trust boundaries, so what is inside of them is inside of the trust boundary around them
+
+	trustBoundaryOf := func(vertex *drawIOCell) *drawIOCell {
+		container := containerOf[vertex]
+		for depth := 0; container != nil && infos[container].sharedRuntime && depth < len(vertices); depth++ {
+			container = containerOf[container]
+		}
+		return container
+	}
+
+	for _, container := range containers {
+		info := infos[container]
+		assetIds := make([]string, 0)
+		nestedIds := make([]string, 0)
+		for _, vertex := range vertices {
+			member := trustBoundaryOf(vertex) == container
+			if info.sharedRuntime {
+				member = containerOf[vertex] == container
+			}
+			if !member {
+				continue
+			}
+			if title, ok := assetTitles[vertex]; ok {
+				assetIds = append(assetIds, stub.Model.TechnicalAssets[title].ID)
+			} else if nested, ok := infos[vertex]; ok && !nested.sharedRuntime {
+				nestedIds = append(nestedIds, nested.id)
+			}
+		}
+		sort.Strings(assetIds)
+		sort.Strings(nestedIds)
+
+		if info.sharedRuntime {
+			stub.AddSharedRuntime(info.title, info.id, assetIds)
+			continue
+		}
+
+		title := stub.AddTrustBoundary(info.title, info.id, info.boundaryType, assetIds, nestedIds)
+		if info.guessed {
+			stub.Todo("check the trust boundary type guessed from the label", "trust_boundaries", title, "type")
+		}
+	}
+
+	return nil
+}
+
+// drawIOTechnicalAssetType derives the type from the shape (cylinders store, actors are external) and otherwise from
+// the technology: clients are external entities and storage technologies are datastores
+
+func drawIOTechnicalAssetType(vertex *drawIOCell, technology *types.Technology) types.TechnicalAssetType {
+	for _, shape := range vertex.shapes {
+		switch shape {
+		case "cylinder", "cylinder2", "cylinder3", "datastore":
+			return types.Datastore
+		case "actor", "umlActor", "mxgraph.basic.person":
+			return types.ExternalEntity
+		}
+	}
+
+	switch {
+	case technology.GetAttribute("client"):
+		return types.ExternalEntity
+	case isStorageTechnology(technology):
+		return types.Datastore
+	}
+	return types.Process
+}
+
+// drawIOTrustBoundaryType takes the type from a label ending with it in parentheses (as exported by threagile) or
+// guesses it from keywords of the label; the type is empty if neither works
+
+func drawIOTrustBoundaryType(container *drawIOCell) (string, string, bool) {
+	title := container.title
+	if open := strings.LastIndex(title, " ("); open >= 0 && strings.HasSuffix(title, ")") {
+		if boundaryType, parseError := types.ParseTrustBoundary(title[open+2 : len(title)-1]); parseError == nil {
+			return title[:open], boundaryType.String(), false
+		}
+	}
+
+	label := strings.ToLower(strings.Join(container.lines, " "))
+	for _, keyword := range drawIOTrustBoundaryKeywords {
+		if strings.Contains(label, keyword.keyword) {
+			return title, keyword.boundaryType.String(), true
+		}
+	}
+	return title, "", false
+}
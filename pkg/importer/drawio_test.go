@@ -0,0 +1,208 @@
+package importer
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
+	"gopkg.in/yaml.v3"
+)
+
+const drawIOTestGraphModel = `<mxGraphModel><root>
+<mxCell id="0"/>
+<mxCell id="1" parent="0"/>
+<mxCell id="dmz" value="Web DMZ (network-cloud-security-group)" style="swimlane;container=1" vertex="1" parent="1">
+  <mxGeometry x="100" y="100" width="400" height="200" as="geometry"/>
+</mxCell>
+<mxCell id="web" value="&lt;font&gt;web-server&lt;/font&gt;&lt;br&gt;&lt;b&gt;Apache&amp;nbsp;Webserver&lt;/b&gt;" style="rounded=0;html=1" vertex="1" parent="dmz">
+  <mxGeometry x="20" y="40" width="120" height="60" as="geometry"/>
+</mxCell>
+<object label="Customer DB" owner="someone" id="db">
+  <mxCell style="shape=cylinder3;whiteSpace=wrap" vertex="1" parent="1">
+    <mxGeometry x="600" y="100" width="80" height="80" as="geometry"/>
+  </mxCell>
+</object>
+<mxCell id="user" value="Customer" style="shape=umlActor" vertex="1" parent="1">
+  <mxGeometry x="0" y="0" width="30" height="60" as="geometry"/>
+</mxCell>
+<mxCell id="cloud" value="Company Cloud" style="rounded=1;dashed=1" vertex="1" parent="1">
+  <mxGeometry x="580" y="80" width="200" height="200" as="geometry"/>
+</mxCell>
+<mxCell id="tool" value="Some Thing" style="ellipse" vertex="1" parent="1">
+  <mxGeometry x="700" y="150" width="60" height="40" as="geometry"/>
+</mxCell>
+<mxCell id="note" value="Just a note" style="text;html=1" vertex="1" parent="1">
+  <mxGeometry x="0" y="400" width="60" height="20" as="geometry"/>
+</mxCell>
+<mxCell id="e1" value="HTTPS" style="edgeStyle=orthogonalEdgeStyle" edge="1" parent="1" source="user" target="web">
+  <mxGeometry relative="1" as="geometry"/>
+</mxCell>
+<mxCell id="e2" style="" edge="1" parent="1" source="web" target="db">
+  <mxGeometry relative="1" as="geometry"/>
+</mxCell>
+<mxCell id="e2label" value="Contract Lookup&lt;br&gt;jdbc" style="edgeLabel;html=1" vertex="1" connectable="0" parent="e2">
+  <mxGeometry x="-0.1" relative="1" as="geometry"/>
+</mxCell>
+<mxCell id="e4" value="Nightly Sync" edge="1" parent="1" source="tool" target="db">
+  <mxGeometry relative="1" as="geometry"/>
+</mxCell>
+<mxCell id="e3" value="whatever" edge="1" parent="1" source="note" target="db">
+  <mxGeometry relative="1" as="geometry"/>
+</mxCell>
+</root></mxGraphModel>`
+
+func loadTestShapeTechnologies(t *testing.T) *ShapeTechnologies {
+	shapeTechnologies := new(ShapeTechnologies)
+	require.NoError(t, shapeTechnologies.LoadWithConfig(&common.Config{}))
+	return shapeTechnologies
+}
+
+func writeTestFile(t *testing.T, name string, content string) string {
+	filename := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(filename, []byte(content), 0600))
+	return filename
+}
+
+func assertDrawIOTestModel(t *testing.T, stub *StubModel) {
+	assets := stub.Model.TechnicalAssets
+	require.Len(t, assets, 4)
+
+	assert.Equal(t, "web-server", assets["Apache Webserver"].Technology)
+	assert.Equal(t, "process", assets["Apache Webserver"].Type)
+	assert.Equal(t, "database", assets["Customer DB"].Technology)
+	assert.Equal(t, "datastore", assets["Customer DB"].Type)
+	assert.Equal(t, "browser", assets["Customer"].Technology)
+	assert.Equal(t, "external-entity", assets["Customer"].Type)
+	assert.True(t, assets["Customer"].UsedAsClientByHuman)
+	assert.Equal(t, types.UnknownTechnology, assets["Some Thing"].Technology)
+
+	assert.Equal(t, map[string]input.CommunicationLink{"Traffic to Apache Webserver": {
+		Target:         "apache-webserver",
+		Description:    "Traffic to Apache Webserver",
+		Protocol:       "https",
+		Authentication: "none",
+		Authorization:  "none",
+		Usage:          "business",
+	}}, assets["Customer"].CommunicationLinks)
+	assert.Equal(t, "jdbc", assets["Apache Webserver"].CommunicationLinks["Contract Lookup"].Protocol)
+	assert.Equal(t, "unknown-protocol", assets["Some Thing"].CommunicationLinks["Nightly Sync"].Protocol)
+	assert.Len(t, assets["Customer DB"].CommunicationLinks, 0)
+
+	boundaries := stub.Model.TrustBoundaries
+	require.Len(t, boundaries, 2)
+	assert.Equal(t, input.TrustBoundary{
+		ID:                    "web-dmz",
+		Description:           "Web DMZ",
+		Type:                  "network-cloud-security-group",
+		TechnicalAssetsInside: []string{"apache-webserver"},
+		TrustBoundariesNested: []string{},
+	}, boundaries["Web DMZ"])
+	assert.Equal(t, "network-cloud-provider", boundaries["Company Cloud"].Type)
+	assert.Equal(t, []string{"customer-db", "some-thing"}, boundaries["Company Cloud"].TechnicalAssetsInside)
+}
+
+func TestImportDrawIO(t *testing.T) {
+	filename := writeTestFile(t, "diagram.drawio", `<mxfile><diagram name="Some Diagram">`+drawIOTestGraphModel+`</diagram></mxfile>`)
+
+	stub, err := ImportDrawIO(filename, loadTestShapeTechnologies(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Some Diagram", stub.Model.Title)
+	assertDrawIOTestModel(t, stub)
+}
+
+func TestImportDrawIOCompressed(t *testing.T) {
+	var deflated bytes.Buffer
+	writer, err := flate.NewWriter(&deflated, flate.BestCompression)
+	require.NoError(t, err)
+	_, err = writer.Write([]byte(url.PathEscape(drawIOTestGraphModel)))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	filename := writeTestFile(t, "compressed.drawio", `<mxfile><diagram name="Page-1">`+base64.StdEncoding.EncodeToString(deflated.Bytes())+`</diagram></mxfile>`)
+
+	stub, err := ImportDrawIO(filename, loadTestShapeTechnologies(t))
+	require.NoError(t, err)
+	assertDrawIOTestModel(t, stub)
+}
+
+func TestImportDrawIOGraphModel(t *testing.T) {
+	filename := writeTestFile(t, "my-architecture.xml", drawIOTestGraphModel)
+
+	stub, err := ImportDrawIO(filename, loadTestShapeTechnologies(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, "my-architecture", stub.Model.Title)
+	assertDrawIOTestModel(t, stub)
+}
+
+func TestImportDrawIOWithoutShapes(t *testing.T) {
+	filename := writeTestFile(t, "empty.drawio", `<mxfile><diagram name="Empty"><mxGraphModel><root><mxCell id="0"/></root></mxGraphModel></diagram></mxfile>`)
+
+	_, err := ImportDrawIO(filename, loadTestShapeTechnologies(t))
+	assert.Error(t, err)
+}
+
+func TestImportDrawIOModelParses(t *testing.T) {
+	filename := writeTestFile(t, "diagram.drawio", `<mxfile><diagram name="Some Diagram">`+drawIOTestGraphModel+`</diagram></mxfile>`)
+
+	stub, err := ImportDrawIO(filename, loadTestShapeTechnologies(t))
+	require.NoError(t, err)
+
+	content, err := stub.Marshal()
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "confidentiality: confidential # TODO: rate the confidentiality")
+	assert.Contains(t, string(content), "data_assets_processed: [] # TODO: reference the data assets processed")
+	assert.Contains(t, string(content), "technology: unknown-technology # TODO: set the technology")
+	assert.Contains(t, string(content), "protocol: unknown-protocol # TODO: set the protocol")
+	assert.True(t, strings.HasPrefix(string(content), "threagile_version: "))
+
+	modelInput := new(input.Model).Defaults()
+	require.NoError(t, yaml.Unmarshal(content, modelInput))
+
+	parsedModel, err := model.ParseModel(&common.Config{}, modelInput, make(types.RiskRules), make(types.RiskRules))
+	require.NoError(t, err)
+	assert.Len(t, parsedModel.TechnicalAssets, 4)
+	assert.Len(t, parsedModel.TrustBoundaries, 2)
+}
+
+func TestShapeTechnologiesUnknownTechnology(t *testing.T) {
+	config := &common.Config{ShapeTechnologyFilename: writeTestFile(t, "shapes.yaml", "mxgraph.custom.thing: no-such-technology\n")}
+
+	err := new(ShapeTechnologies).LoadWithConfig(config)
+	assert.ErrorContains(t, err, "no-such-technology")
+}
+
+func TestShapeTechnologiesFromConfig(t *testing.T) {
+	config := &common.Config{ShapeTechnologyFilename: writeTestFile(t, "shapes.yaml", "cylinder3: file-server\nmxgraph.custom.thing: vault\n")}
+
+	shapeTechnologies := new(ShapeTechnologies)
+	require.NoError(t, shapeTechnologies.LoadWithConfig(config))
+
+	assert.Equal(t, "file-server", shapeTechnologies.Shape("cylinder3").Name)
+	assert.Equal(t, "vault", shapeTechnologies.Shape("rounded", "mxgraph.custom.thing").Name)
+	assert.Equal(t, "database", shapeTechnologies.Shape("mxgraph.aws4.rds").Name)
+	assert.Nil(t, shapeTechnologies.Shape("rounded"))
+}
+
+func TestShapeTechnologiesLabels(t *testing.T) {
+	shapeTechnologies := loadTestShapeTechnologies(t)
+
+	assert.Equal(t, "ldap-server", shapeTechnologies.Label("LDAP").Name)
+	assert.Equal(t, "web-service-rest", shapeTechnologies.Label("Some Service", "REST API").Name)
+	assert.Nil(t, shapeTechnologies.Label("Customer DB"))
+	assert.Equal(t, "database", shapeTechnologies.Mentioned("Customer DB").Name)
+	assert.Equal(t, "web-application", shapeTechnologies.Mentioned("Customer Web App").Name)
+	assert.Nil(t, shapeTechnologies.Mentioned("Something Else"))
+}
@@ -0,0 +1,810 @@
+package importer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/docs"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+	"gopkg.in/yaml.v3"
+)
+
+// KubernetesTag is the tag of everything imported from Kubernetes manifests
+
+const KubernetesTag = "k8s"
+
+// kubernetesObject and friends are the parts of the Kubernetes resources read by ImportKubernetes
+
+type kubernetesObject struct {
+	Kind     string `yaml:"kind"`
+	Metadata struct {
+		Name      string            `yaml:"name"`
+		Namespace string            `yaml:"namespace"`
+		Labels    map[string]string `yaml:"labels"`
+	} `yaml:"metadata"`
+	Spec  yaml.Node   `yaml:"spec"`
+	Items []yaml.Node `yaml:"items"`
+}
+
+type kubernetesWorkloadSpec struct {
+	Replicas             *int        `yaml:"replicas"`
+	VolumeClaimTemplates []yaml.Node `yaml:"volumeClaimTemplates"`
+	Template             struct {
+		Metadata struct {
+			Labels map[string]string `yaml:"labels"`
+		} `yaml:"metadata"`
+		Spec struct {
+			Containers []kubernetesContainer `yaml:"containers"`
+		} `yaml:"spec"`
+	} `yaml:"template"`
+}
+
+type kubernetesContainer struct {
+	Image string `yaml:"image"`
+	Env   []struct {
+		Name  string `yaml:"name"`
+		Value string `yaml:"value"`
+	} `yaml:"env"`
+	Args    []string `yaml:"args"`
+	Command []string `yaml:"command"`
+}
+
+type kubernetesServiceSpec struct {
+	Type         string            `yaml:"type"`
+	ExternalName string            `yaml:"externalName"`
+	Selector     map[string]string `yaml:"selector"`
+	Ports        []kubernetesPort  `yaml:"ports"`
+}
+
+type kubernetesPort struct {
+	Name        string    `yaml:"name"`
+	Port        yaml.Node `yaml:"port"`
+	AppProtocol string    `yaml:"appProtocol"`
+}
+
+type kubernetesIngressSpec struct {
+	DefaultBackend *kubernetesIngressBackend `yaml:"defaultBackend"`
+	Backend        *kubernetesIngressBackend `yaml:"backend"`
+	Rules          []struct {
+		HTTP struct {
+			Paths []struct {
+				Backend kubernetesIngressBackend `yaml:"backend"`
+			} `yaml:"paths"`
+		} `yaml:"http"`
+	} `yaml:"rules"`
+}
+
+// kubernetesIngressBackend is the backend of an ingress rule, with the service in networking.k8s.io/v1 and the
+// service name and port in the older extensions/v1beta1
+
+type kubernetesIngressBackend struct {
+	Service struct {
+		Name string         `yaml:"name"`
+		Port kubernetesPort `yaml:"port"`
+	} `yaml:"service"`
+	ServiceName string    `yaml:"serviceName"`
+	ServicePort yaml.Node `yaml:"servicePort"`
+}
+
+type kubernetesNetworkPolicySpec struct {
+	PodSelector kubernetesSelector            `yaml:"podSelector"`
+	Ingress     []kubernetesNetworkPolicyRule `yaml:"ingress"`
+	Egress      []kubernetesNetworkPolicyRule `yaml:"egress"`
+}
+
+type kubernetesNetworkPolicyRule struct {
+	From  []kubernetesNetworkPolicyPeer `yaml:"from"`
+	To    []kubernetesNetworkPolicyPeer `yaml:"to"`
+	Ports []kubernetesPort              `yaml:"ports"`
+}
+
+type kubernetesNetworkPolicyPeer struct {
+	PodSelector       *kubernetesSelector `yaml:"podSelector"`
+	NamespaceSelector *kubernetesSelector `yaml:"namespaceSelector"`
+	IPBlock           *struct {
+		CIDR string `yaml:"cidr"`
+	} `yaml:"ipBlock"`
+}
+
+type kubernetesSelector struct {
+	MatchLabels      map[string]string `yaml:"matchLabels"`
+	MatchExpressions []struct {
+		Key      string   `yaml:"key"`
+		Operator string   `yaml:"operator"`
+		Values   []string `yaml:"values"`
+	} `yaml:"matchExpressions"`
+}
+
+func (what *kubernetesSelector) matches(labels map[string]string) bool {
+	for key, value := range what.MatchLabels {
+		if labels[key] != value {
+			return false
+		}
+	}
+
+	for _, expression := range what.MatchExpressions {
+		value, exists := labels[expression.Key]
+		listed := false
+		for _, candidate := range expression.Values {
+			listed = listed || exists && candidate == value
+		}
+
+		switch expression.Operator {
+		case "In":
+			if !listed {
+				return false
+			}
+		case "NotIn":
+			if listed {
+				return false
+			}
+		case "Exists":
+			if !exists {
+				return false
+			}
+		case "DoesNotExist":
+			if exists {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// name returns the port's name, or its number as text
+
+func (what *kubernetesPort) name() string {
+	if len(what.Name) > 0 {
+		return what.Name
+	}
+	return what.Port.Value
+}
+
+// number returns the port's number, or 0 for a named port
+
+func (what *kubernetesPort) number() int {
+	number, _ := strconv.Atoi(what.Port.Value)
+	return number
+}
+
+type kubernetesWorkload struct {
+	namespace string
+	name      string
+	title     string
+	labels    map[string]string
+	spec      kubernetesWorkloadSpec
+}
+
+type kubernetesService struct {
+	namespace string
+	name      string
+	spec      kubernetesServiceSpec
+}
+
+// KubernetesModel is a model imported from Kubernetes manifests: the cluster model holds what the manifests tell
+// and is meant to be regenerated on every import, the stub includes it and holds what only the owners of the
+// technical assets know, marked as TODO, with the conversion log of everything that could not be imported
+
+type KubernetesModel struct {
+	Cluster *input.Model
+	Stub    *StubModel
+
+	shapeTechnologies *ShapeTechnologies
+	ids               map[string]bool
+	namespaces        map[string]map[string]string
+	workloads         []*kubernetesWorkload
+	services          []*kubernetesService
+	links             map[string]string
+}
+
+// ImportKubernetes reads the Kubernetes manifests of the given file or directory (including Helm-rendered ones with
+// many documents per file): deployments and stateful sets become technical assets tagged k8s, running in the
+// container platform's shared runtime and inside a trust boundary per namespace; services (as addressed by the
+// workloads' environment and arguments), ingresses and network policies become communication links
+
+func ImportKubernetes(path string, shapeTechnologies *ShapeTechnologies) (*KubernetesModel, error) {
+	title := strings.TrimSuffix(filepath.Base(filepath.Clean(path)), filepath.Ext(path))
+
+	model := &KubernetesModel{
+		Cluster:           new(input.Model).Defaults(),
+		Stub:              new(StubModel).Init(title),
+		shapeTechnologies: shapeTechnologies,
+		ids:               make(map[string]bool),
+		namespaces:        make(map[string]map[string]string),
+		workloads:         make([]*kubernetesWorkload, 0),
+		services:          make([]*kubernetesService, 0),
+		links:             make(map[string]string),
+	}
+	model.Cluster.TagsAvailable = []string{KubernetesTag}
+
+	objects, readError := model.read(path)
+	if readError != nil {
+		return nil, readError
+	}
+
+	ingresses := make([]*kubernetesObject, 0)
+	networkPolicies := make([]*kubernetesObject, 0)
+	for _, object := range objects {
+		namespace := object.Metadata.Namespace
+		if len(namespace) == 0 {
+			namespace = "default"
+		}
+
+		var decodeError error
+		switch object.Kind {
+		case "Namespace":
+			model.namespace(object.Metadata.Name)
+			for key, value := range object.Metadata.Labels {
+				model.namespaces[object.Metadata.Name][key] = value
+			}
+
+		case "Deployment", "StatefulSet":
+			workload := &kubernetesWorkload{namespace: namespace, name: object.Metadata.Name}
+			decodeError = object.Spec.Decode(&workload.spec)
+			workload.labels = workload.spec.Template.Metadata.Labels
+			model.namespace(namespace)
+			model.workloads = append(model.workloads, workload)
+
+		case "Service":
+			service := &kubernetesService{namespace: namespace, name: object.Metadata.Name}
+			decodeError = object.Spec.Decode(&service.spec)
+			model.services = append(model.services, service)
+			switch service.spec.Type {
+			case "ExternalName":
+				model.Stub.Logf("skipped service %v/%v for %v outside the cluster, add it as technical asset by hand", namespace, service.name, service.spec.ExternalName)
+			case "LoadBalancer", "NodePort":
+				model.Stub.Logf("service %v/%v is reachable from outside the cluster (%v), add the communication links of its clients by hand", namespace, service.name, service.spec.Type)
+			}
+
+		case "Ingress":
+			ingresses = append(ingresses, object)
+
+		case "NetworkPolicy":
+			networkPolicies = append(networkPolicies, object)
+
+		case "DaemonSet", "ReplicaSet", "Pod", "Job", "CronJob":
+			model.Stub.Logf("skipped %v %v/%v, only deployments and stateful sets are imported as technical assets", object.Kind, namespace, object.Metadata.Name)
+		}
+		if decodeError != nil {
+			return nil, fmt.Errorf("error decoding %v %v/%v: %v", object.Kind, namespace, object.Metadata.Name, decodeError)
+		}
+	}
+
+	if len(model.workloads) == 0 {
+		return nil, fmt.Errorf("error importing %s: no deployments or stateful sets found", path)
+	}
+
+	for _, workload := range model.workloads {
+		model.addWorkload(workload)
+	}
+	model.addNamespaces()
+
+	for _, workload := range model.workloads {
+		model.addServiceLinks(workload)
+	}
+	for _, ingress := range ingresses {
+		decodeError := model.addIngressLinks(ingress)
+		if decodeError != nil {
+			return nil, fmt.Errorf("error decoding ingress %v/%v: %v", ingress.Metadata.Namespace, ingress.Metadata.Name, decodeError)
+		}
+	}
+	for _, networkPolicy := range networkPolicies {
+		decodeError := model.addNetworkPolicyLinks(networkPolicy)
+		if decodeError != nil {
+			return nil, fmt.Errorf("error decoding network policy %v/%v: %v", networkPolicy.Metadata.Namespace, networkPolicy.Metadata.Name, decodeError)
+		}
+	}
+	model.addPlatform()
+
+	return model, nil
+}
+
+// read reads the Kubernetes objects of all YAML and JSON files at the given path, skipping those that are no valid
+// YAML, like Helm templates not rendered yet
+
+func (what *KubernetesModel) read(path string) ([]*kubernetesObject, error) {
+	filenames := make([]string, 0)
+	walkError := filepath.WalkDir(path, func(filename string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		switch strings.ToLower(filepath.Ext(filename)) {
+		case ".yaml", ".yml", ".json":
+			if !entry.IsDir() {
+				filenames = append(filenames, filename)
+			}
+		}
+		return nil
+	})
+	if walkError != nil {
+		return nil, fmt.Errorf("error reading %s: %v", path, walkError)
+	}
+
+	objects := make([]*kubernetesObject, 0)
+	for _, filename := range filenames {
+		data, readError := os.ReadFile(filepath.Clean(filename))
+		if readError != nil {
+			return nil, fmt.Errorf("error reading %s: %v", filename, readError)
+		}
+
+		fileObjects := make([]*kubernetesObject, 0)
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		for {
+			object := new(kubernetesObject)
+			decodeError := decoder.Decode(object)
+			if errors.Is(decodeError, io.EOF) {
+				break
+			}
+			if decodeError != nil {
+				what.Stub.Logf("skipped %s, which is no valid YAML (a Helm template not rendered yet?): %v", filename, decodeError)
+				fileObjects = nil
+				break
+			}
+
+			fileObjects = append(fileObjects, object)
+			for _, item := range object.Items {
+				listed := new(kubernetesObject)
+				if item.Decode(listed) == nil {
+					fileObjects = append(fileObjects, listed)
+				}
+			}
+		}
+		objects = append(objects, fileObjects...)
+	}
+
+	return objects, nil
+}
+
+func (what *KubernetesModel) namespace(name string) {
+	if _, ok := what.namespaces[name]; !ok {
+		what.namespaces[name] = map[string]string{"kubernetes.io/metadata.name": name}
+	}
+}
+
+func (what *KubernetesModel) uniqueId(title string) string {
+	base := slug(title)
+	id := base
+	for n := 2; what.ids[id]; n++ {
+		id = base + "-" + strconv.Itoa(n)
+	}
+	what.ids[id] = true
+
+	return id
+}
+
+// addAsset adds a technical asset to the cluster model and its ratings to the stub, the technology going into the
+// stub instead if it is a guess
+
+func (what *KubernetesModel) addAsset(title string, asset input.TechnicalAsset, technology *types.Technology, guessed bool) {
+	asset.ID = what.uniqueId(title)
+	asset.Description = title
+	asset.Usage = types.Business.String()
+	asset.Tags = []string{KubernetesTag}
+	asset.CommunicationLinks = make(map[string]input.CommunicationLink)
+
+	if technology.Name != types.UnknownTechnology && !guessed {
+		asset.Technology = technology.Name
+		technology = nil
+	}
+
+	what.Cluster.TechnicalAssets[title] = asset
+	what.Stub.AddTechnicalAssetRatings(title, technology, guessed)
+}
+
+// addPlatform adds the container platform, along with its shared runtime running all containers imported
+
+func (what *KubernetesModel) addPlatform() {
+	platform := what.shapeTechnologies.Label(types.ContainerPlatform)
+	what.addAsset("Kubernetes Container Platform", input.TechnicalAsset{
+		Type:    types.Process.String(),
+		Size:    types.System.String(),
+		Machine: types.Virtual.String(),
+	}, platform, false)
+
+	running := make([]string, 0)
+	for _, asset := range what.Cluster.TechnicalAssets {
+		if asset.Machine == types.Container.String() {
+			running = append(running, asset.ID)
+		}
+	}
+	sort.Strings(running)
+
+	what.Cluster.SharedRuntimes["Kubernetes Runtime"] = input.SharedRuntime{
+		ID:                     "kubernetes-container-runtime",
+		Description:            "Kubernetes Runtime",
+		Tags:                   []string{KubernetesTag},
+		TechnicalAssetsRunning: running,
+	}
+}
+
+// addWorkload adds the workload as technical asset of the technology of its first well-known container image, or
+// the one its name names; off-the-shelf images are no custom development, stateful sets with volumes are datastores
+
+func (what *KubernetesModel) addWorkload(workload *kubernetesWorkload) {
+	workload.title = workload.namespace + "/" + workload.name
+
+	images := make([]string, 0)
+	for _, container := range workload.spec.Template.Spec.Containers {
+		images = append(images, container.Image)
+	}
+
+	technology := what.shapeTechnologies.Image(images...)
+	offTheShelf := technology != nil
+	guessed := false
+	if technology == nil {
+		technology, guessed = what.shapeTechnologies.Find(nil, []string{workload.name})
+	}
+
+	assetType := types.Process
+	if isStorageTechnology(technology) || technology.Name == types.UnknownTechnology && len(workload.spec.VolumeClaimTemplates) > 0 {
+		assetType = types.Datastore
+	}
+
+	what.addAsset(workload.title, input.TechnicalAsset{
+		Type:                 assetType.String(),
+		Size:                 types.Service.String(),
+		Machine:              types.Container.String(),
+		CustomDevelopedParts: !offTheShelf,
+		Redundant:            workload.spec.Replicas != nil && *workload.spec.Replicas > 1,
+	}, technology, guessed)
+}
+
+// addNamespaces adds a network policy namespace isolation trust boundary around the workloads of each namespace
+
+func (what *KubernetesModel) addNamespaces() {
+	for namespace := range what.namespaces {
+		inside := make([]string, 0)
+		for _, workload := range what.workloads {
+			if workload.namespace == namespace {
+				inside = append(inside, what.Cluster.TechnicalAssets[workload.title].ID)
+			}
+		}
+		if len(inside) == 0 {
+			continue
+		}
+		sort.Strings(inside)
+
+		title := "Namespace " + namespace
+		what.Cluster.TrustBoundaries[title] = input.TrustBoundary{
+			ID:                    what.uniqueId(title),
+			Description:           title,
+			Type:                  types.NetworkPolicyNamespaceIsolation.String(),
+			Tags:                  []string{KubernetesTag},
+			TechnicalAssetsInside: inside,
+		}
+	}
+}
+
+// addLink adds a communication link between the technical assets with the given titles to the cluster model and its
+// ratings to the stub, unless there already is one; a link of unknown protocol takes the protocol of a later one
+
+func (what *KubernetesModel) addLink(sourceTitle string, targetTitle string, protocol types.Protocol) {
+	source := what.Cluster.TechnicalAssets[sourceTitle]
+	if title, ok := what.links[sourceTitle+">"+targetTitle]; ok {
+		link := source.CommunicationLinks[title]
+		if link.Protocol == types.UnknownProtocol.String() {
+			link.Protocol = protocol.String()
+			source.CommunicationLinks[title] = link
+		}
+		return
+	}
+
+	title := "Traffic to " + targetTitle
+	what.links[sourceTitle+">"+targetTitle] = title
+	source.CommunicationLinks[title] = input.CommunicationLink{
+		Target:      what.Cluster.TechnicalAssets[targetTitle].ID,
+		Description: title,
+		Protocol:    protocol.String(),
+		Usage:       types.Business.String(),
+	}
+	what.Stub.AddCommunicationLinkRatings(sourceTitle, title)
+}
+
+// servedBy returns the workloads the service selects pods of
+
+func (what *KubernetesModel) servedBy(service *kubernetesService) []*kubernetesWorkload {
+	workloads := make([]*kubernetesWorkload, 0)
+	if len(service.spec.Selector) == 0 {
+		return workloads
+	}
+
+	selector := kubernetesSelector{MatchLabels: service.spec.Selector}
+	for _, workload := range what.workloads {
+		if workload.namespace == service.namespace && selector.matches(workload.labels) {
+			workloads = append(workloads, workload)
+		}
+	}
+	return workloads
+}
+
+// service returns the service addressed by the given host name from the given namespace, like orders, orders.shop
+// or orders.shop.svc.cluster.local
+
+func (what *KubernetesModel) service(namespace string, host string) *kubernetesService {
+	for _, service := range what.services {
+		switch {
+		case service.namespace == namespace && host == service.name,
+			host == service.name+"."+service.namespace,
+			strings.HasPrefix(host, service.name+"."+service.namespace+".svc"):
+			return service
+		}
+	}
+	return nil
+}
+
+// servicePortProtocol returns the protocol of the service's port with the given number (or the only port, if 0)
+
+func (what *KubernetesModel) servicePortProtocol(service *kubernetesService, number int, scheme string) types.Protocol {
+	for _, port := range service.spec.Ports {
+		if number == 0 && len(service.spec.Ports) == 1 || port.number() == number {
+			number = port.number()
+			if protocol, ok := wellKnownProtocol(number, scheme, port.AppProtocol, port.Name); ok {
+				return protocol
+			}
+		}
+	}
+
+	protocol, _ := wellKnownProtocol(number, scheme)
+	return protocol
+}
+
+// addServiceLinks adds links to the workloads behind the services the workload's containers address in their
+// environment variables or arguments
+
+func (what *KubernetesModel) addServiceLinks(workload *kubernetesWorkload) {
+	for _, container := range workload.spec.Template.Spec.Containers {
+		values := append(append(make([]string, 0), container.Args...), container.Command...)
+		for _, env := range container.Env {
+			if serviceAddressVariable.MatchString(env.Name) || strings.Contains(env.Value, ":") {
+				values = append(values, env.Value)
+			}
+		}
+
+		for _, value := range values {
+			for _, match := range serviceAddress.FindAllStringSubmatch(strings.ToLower(value), -1) {
+				scheme, host, port := match[1], match[2], match[3]
+				service := what.service(workload.namespace, host)
+				if service == nil || (len(scheme) == 0 && len(port) == 0 && value != host && !strings.Contains(host, ".")) {
+					continue
+				}
+
+				number, _ := strconv.Atoi(port)
+				protocol := what.servicePortProtocol(service, number, scheme)
+				for _, target := range what.servedBy(service) {
+					if target != workload {
+						what.addLink(workload.title, target.title, protocol)
+					}
+				}
+			}
+		}
+	}
+}
+
+// addIngressLinks adds links from the ingress controller to the workloads behind the services of the ingress' rules,
+// adding the ingress controller on first use
+
+func (what *KubernetesModel) addIngressLinks(ingress *kubernetesObject) error {
+	spec := new(kubernetesIngressSpec)
+	decodeError := ingress.Spec.Decode(spec)
+	if decodeError != nil {
+		return decodeError
+	}
+
+	namespace := ingress.Metadata.Namespace
+	if len(namespace) == 0 {
+		namespace = "default"
+	}
+
+	backends := make([]*kubernetesIngressBackend, 0)
+	for _, backend := range []*kubernetesIngressBackend{spec.DefaultBackend, spec.Backend} {
+		if backend != nil {
+			backends = append(backends, backend)
+		}
+	}
+	for _, rule := range spec.Rules {
+		for n := range rule.HTTP.Paths {
+			backends = append(backends, &rule.HTTP.Paths[n].Backend)
+		}
+	}
+
+	const controllerTitle = "Ingress Controller"
+	for _, backend := range backends {
+		name, port := backend.Service.Name, backend.Service.Port
+		if len(name) == 0 {
+			name, port = backend.ServiceName, kubernetesPort{Port: backend.ServicePort}
+		}
+
+		service := what.service(namespace, name)
+		if service == nil {
+			what.Stub.Logf("skipped the backend %v of ingress %v/%v, which is no imported service", name, namespace, ingress.Metadata.Name)
+			continue
+		}
+
+		if _, ok := what.Cluster.TechnicalAssets[controllerTitle]; !ok {
+			what.addAsset(controllerTitle, input.TechnicalAsset{
+				Type:    types.Process.String(),
+				Size:    types.Component.String(),
+				Machine: types.Container.String(),
+			}, what.shapeTechnologies.Label(types.ReverseProxy), false)
+		}
+
+		protocol := what.servicePortProtocol(service, port.number(), "")
+		if protocol == types.UnknownProtocol {
+			protocol = types.HTTP
+		}
+		for _, target := range what.servedBy(service) {
+			what.addLink(controllerTitle, target.title, protocol)
+		}
+	}
+
+	return nil
+}
+
+// addNetworkPolicyLinks adds links between the workloads the network policy allows to communicate, skipping rules
+// allowing everything or address blocks
+
+func (what *KubernetesModel) addNetworkPolicyLinks(networkPolicy *kubernetesObject) error {
+	spec := new(kubernetesNetworkPolicySpec)
+	decodeError := networkPolicy.Spec.Decode(spec)
+	if decodeError != nil {
+		return decodeError
+	}
+
+	namespace := networkPolicy.Metadata.Namespace
+	if len(namespace) == 0 {
+		namespace = "default"
+	}
+	name := namespace + "/" + networkPolicy.Metadata.Name
+
+	selected := make([]*kubernetesWorkload, 0)
+	for _, workload := range what.workloads {
+		if workload.namespace == namespace && spec.PodSelector.matches(workload.labels) {
+			selected = append(selected, workload)
+		}
+	}
+
+	for _, rule := range spec.Ingress {
+		for _, source := range what.peers(name, namespace, rule.From) {
+			for _, target := range selected {
+				what.addPolicyLink(source, target, rule.Ports)
+			}
+		}
+	}
+	for _, rule := range spec.Egress {
+		for _, target := range what.peers(name, namespace, rule.To) {
+			for _, source := range selected {
+				what.addPolicyLink(source, target, rule.Ports)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (what *KubernetesModel) addPolicyLink(source *kubernetesWorkload, target *kubernetesWorkload, ports []kubernetesPort) {
+	if source == target {
+		return
+	}
+
+	protocol := types.UnknownProtocol
+	for _, port := range ports {
+		if protocol == types.UnknownProtocol {
+			for _, service := range what.services {
+				for _, served := range what.servedBy(service) {
+					if served == target && protocol == types.UnknownProtocol {
+						protocol = what.servicePortProtocol(service, port.number(), "")
+					}
+				}
+			}
+		}
+		if protocol == types.UnknownProtocol {
+			protocol, _ = wellKnownProtocol(port.number(), port.name())
+		}
+	}
+
+	what.addLink(source.title, target.title, protocol)
+}
+
+// peers returns the workloads matching the peers of a network policy rule
+
+func (what *KubernetesModel) peers(policy string, namespace string, peers []kubernetesNetworkPolicyPeer) []*kubernetesWorkload {
+	workloads := make([]*kubernetesWorkload, 0)
+	if len(peers) == 0 {
+		what.Stub.Logf("skipped a rule of network policy %v allowing traffic from or to everywhere", policy)
+		return workloads
+	}
+
+	for _, peer := range peers {
+		if peer.IPBlock != nil {
+			what.Stub.Logf("skipped the address block %v of network policy %v, add the communication links to or from outside the cluster by hand", peer.IPBlock.CIDR, policy)
+			continue
+		}
+
+		for _, workload := range what.workloads {
+			namespaceMatches := workload.namespace == namespace
+			if peer.NamespaceSelector != nil {
+				namespaceMatches = peer.NamespaceSelector.matches(what.namespaces[workload.namespace])
+			}
+			if namespaceMatches && (peer.PodSelector == nil || peer.PodSelector.matches(workload.labels)) {
+				workloads = append(workloads, workload)
+			}
+		}
+	}
+
+	return workloads
+}
+
+// Write writes the cluster model, and the stub including it unless it exists already (as it holds what was added by
+// hand then), along with the conversion log; it returns whether the stub was written
+
+func (what *KubernetesModel) Write(clusterFilename string, filename string, logFilename string) (bool, error) {
+	var document yaml.Node
+	encodeError := document.Encode(what.Cluster)
+	if encodeError != nil {
+		return false, fmt.Errorf("error encoding %s: %v", clusterFilename, encodeError)
+	}
+	document.HeadComment = fmt.Sprintf("Generated by threagile %v from Kubernetes manifests, changes are lost on the next import:\n"+
+		"add what is left to model to a model including this one instead", docs.ThreagileVersion)
+
+	var content bytes.Buffer
+	encoder := yaml.NewEncoder(&content)
+	encoder.SetIndent(2)
+	encodeError = encoder.Encode(&document)
+	if encodeError != nil {
+		return false, fmt.Errorf("error encoding %s: %v", clusterFilename, encodeError)
+	}
+
+	writeError := os.WriteFile(filepath.Clean(clusterFilename), content.Bytes(), 0600)
+	if writeError != nil {
+		return false, fmt.Errorf("error writing %s: %v", clusterFilename, writeError)
+	}
+
+	include, relError := filepath.Rel(filepath.Dir(filename), clusterFilename)
+	if relError != nil {
+		include = clusterFilename
+	}
+
+	existing, readError := os.ReadFile(filepath.Clean(filename))
+	if readError != nil {
+		what.Stub.Model.Includes = []string{include}
+		return true, what.Stub.Write(filename, logFilename)
+	}
+
+	model := new(input.Model).Defaults()
+	unmarshalError := yaml.Unmarshal(existing, model)
+	if unmarshalError != nil {
+		return false, fmt.Errorf("error parsing %s: %v", filename, unmarshalError)
+	}
+
+	included := false
+	for _, existingInclude := range model.Includes {
+		included = included || filepath.Clean(existingInclude) == filepath.Clean(include)
+	}
+	if !included {
+		what.Stub.Logf("%s does not include %s", filename, include)
+	}
+
+	titles := make([]string, 0)
+	for title := range what.Cluster.TechnicalAssets {
+		titles = append(titles, title)
+	}
+	sort.Strings(titles)
+	for _, title := range titles {
+		if _, ok := model.TechnicalAssets[title]; !ok {
+			what.Stub.Logf("technical asset %q is new, add its owner, CIA rating and data assets to %s", title, filename)
+		}
+	}
+
+	return false, what.Stub.WriteLog(logFilename)
+}
@@ -0,0 +1,235 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const kubernetesTestShop = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: shop
+---
+# Source: shop/templates/frontend.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata: {name: frontend, namespace: shop}
+spec:
+  replicas: 3
+  selector: {matchLabels: {app: frontend}}
+  template:
+    metadata: {labels: {app: frontend}}
+    spec:
+      containers:
+        - name: frontend
+          image: registry.example.com/shop/frontend:1.2.3
+          env:
+            - {name: ORDERS_URL, value: "http://orders.shop.svc.cluster.local:8080/api"}
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata: {name: orders, namespace: shop}
+spec:
+  template:
+    metadata: {labels: {app: orders}}
+    spec:
+      containers:
+        - name: orders
+          image: example/orders
+          env:
+            - {name: DB_HOST, value: orders-db}
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata: {name: orders-db, namespace: shop}
+spec:
+  template:
+    metadata: {labels: {app: orders-db}}
+    spec:
+      containers:
+        - {name: postgres, image: "docker.io/bitnami/postgresql:15.4.0"}
+---
+apiVersion: v1
+kind: Service
+metadata: {name: frontend, namespace: shop}
+spec:
+  selector: {app: frontend}
+  ports: [{name: http, port: 80}]
+---
+apiVersion: v1
+kind: Service
+metadata: {name: orders, namespace: shop}
+spec:
+  selector: {app: orders}
+  ports: [{name: web, port: 8080}]
+---
+apiVersion: v1
+kind: Service
+metadata: {name: orders-db, namespace: shop}
+spec:
+  selector: {app: orders-db}
+  ports: [{name: tcp-postgresql, port: 5432}]
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata: {name: shop, namespace: shop}
+spec:
+  rules:
+    - http:
+        paths:
+          - path: /
+            pathType: Prefix
+            backend: {service: {name: frontend, port: {number: 80}}}
+---
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata: {name: monitoring, namespace: shop}
+spec:
+  podSelector: {}
+  ingress:
+    - from:
+        - namespaceSelector: {matchLabels: {team: ops}}
+          podSelector: {matchLabels: {app: prometheus}}
+      ports: [{port: 9090}]
+`
+
+const kubernetesTestOps = `apiVersion: v1
+kind: List
+items:
+  - apiVersion: v1
+    kind: Namespace
+    metadata: {name: ops, labels: {team: ops}}
+  - apiVersion: apps/v1
+    kind: DaemonSet
+    metadata: {name: node-exporter, namespace: ops}
+  - apiVersion: apps/v1
+    kind: Deployment
+    metadata: {name: prometheus, namespace: ops}
+    spec:
+      template:
+        metadata: {labels: {app: prometheus}}
+        spec:
+          containers: [{name: prometheus, image: "prom/prometheus:v2.45.0"}]
+`
+
+func writeKubernetesTestManifests(t *testing.T) string {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "shop.yaml"), []byte(kubernetesTestShop), 0600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "ops"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ops", "ops.yml"), []byte(kubernetesTestOps), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a manifest"), 0600))
+	return dir
+}
+
+func TestImportKubernetes(t *testing.T) {
+	imported, err := ImportKubernetes(writeKubernetesTestManifests(t), loadTestShapeTechnologies(t))
+	require.NoError(t, err)
+
+	assets := imported.Cluster.TechnicalAssets
+	require.Len(t, assets, 6)
+	for _, asset := range assets {
+		assert.Equal(t, []string{KubernetesTag}, asset.Tags)
+	}
+
+	assert.Equal(t, "shop-frontend", assets["shop/frontend"].ID)
+	assert.True(t, assets["shop/frontend"].Redundant)
+	assert.True(t, assets["shop/frontend"].CustomDevelopedParts)
+	assert.Empty(t, assets["shop/frontend"].Technology)
+	assert.Equal(t, "database", assets["shop/orders-db"].Technology)
+	assert.Equal(t, "datastore", assets["shop/orders-db"].Type)
+	assert.False(t, assets["shop/orders-db"].CustomDevelopedParts)
+	assert.Equal(t, "monitoring", assets["ops/prometheus"].Technology)
+	assert.Equal(t, "reverse-proxy", assets["Ingress Controller"].Technology)
+	assert.Equal(t, "container-platform", assets["Kubernetes Container Platform"].Technology)
+
+	assert.Equal(t, "http", assets["shop/frontend"].CommunicationLinks["Traffic to shop/orders"].Protocol)
+	assert.Equal(t, "sql-access-protocol", assets["shop/orders"].CommunicationLinks["Traffic to shop/orders-db"].Protocol)
+	assert.Equal(t, "http", assets["Ingress Controller"].CommunicationLinks["Traffic to shop/frontend"].Protocol)
+	assert.Equal(t, "http", assets["ops/prometheus"].CommunicationLinks["Traffic to shop/orders"].Protocol)
+	assert.Equal(t, "shop-orders-db", assets["shop/orders"].CommunicationLinks["Traffic to shop/orders-db"].Target)
+
+	require.Len(t, imported.Cluster.TrustBoundaries, 2)
+	shop := imported.Cluster.TrustBoundaries["Namespace shop"]
+	assert.Equal(t, "network-policy-namespace-isolation", shop.Type)
+	assert.Equal(t, []string{"shop-frontend", "shop-orders", "shop-orders-db"}, shop.TechnicalAssetsInside)
+
+	runtime := imported.Cluster.SharedRuntimes["Kubernetes Runtime"]
+	assert.Equal(t, "kubernetes-container-runtime", runtime.ID)
+	assert.ElementsMatch(t, []string{"shop-frontend", "shop-orders", "shop-orders-db", "ops-prometheus", "ingress-controller"}, runtime.TechnicalAssetsRunning)
+
+	assert.Equal(t, "TODO", imported.Stub.Model.TechnicalAssets["shop/frontend"].Owner)
+	assert.Equal(t, "unknown-technology", imported.Stub.Model.TechnicalAssets["shop/frontend"].Technology)
+	assert.Empty(t, imported.Stub.Model.TechnicalAssets["shop/orders-db"].Technology)
+	assert.NotEmpty(t, imported.Stub.Log)
+}
+
+func TestImportKubernetesWithoutWorkloads(t *testing.T) {
+	filename := writeTestFile(t, "namespace.yaml", "apiVersion: v1\nkind: Namespace\nmetadata: {name: empty}\n")
+
+	_, err := ImportKubernetes(filename, loadTestShapeTechnologies(t))
+	assert.Error(t, err)
+}
+
+func TestImportKubernetesWriteIncludes(t *testing.T) {
+	manifests := writeKubernetesTestManifests(t)
+	output := t.TempDir()
+	clusterFilename := filepath.Join(output, common.KubernetesModelFilename)
+	filename := filepath.Join(output, common.ImportedModelFilename)
+	logFilename := filepath.Join(output, common.ImportLogFilename)
+
+	imported, err := ImportKubernetes(manifests, loadTestShapeTechnologies(t))
+	require.NoError(t, err)
+	written, err := imported.Write(clusterFilename, filename, logFilename)
+	require.NoError(t, err)
+	assert.True(t, written)
+	assert.Equal(t, []string{common.KubernetesModelFilename}, imported.Stub.Model.Includes)
+
+	modelInput := new(input.Model).Defaults()
+	require.NoError(t, modelInput.Load(filename))
+	assert.Len(t, modelInput.TechnicalAssets, 6)
+	assert.Equal(t, "container", modelInput.TechnicalAssets["shop/orders"].Machine)
+	assert.Equal(t, "TODO", modelInput.TechnicalAssets["shop/orders"].Owner)
+
+	parsedModel, err := model.ParseModel(&common.Config{}, modelInput, make(types.RiskRules), make(types.RiskRules))
+	require.NoError(t, err)
+	assert.Len(t, parsedModel.TechnicalAssets, 6)
+	assert.Len(t, parsedModel.SharedRuntimes, 1)
+
+	edited := []byte("# rated by hand\n")
+	stub, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	edited = append(edited, stub...)
+	require.NoError(t, os.WriteFile(filename, edited, 0600))
+
+	require.NoError(t, os.WriteFile(filepath.Join(manifests, "ops", "grafana.yaml"), []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata: {name: grafana, namespace: ops}
+spec:
+  template:
+    spec:
+      containers: [{name: grafana, image: grafana/grafana}]
+`), 0600))
+
+	reimported, err := ImportKubernetes(manifests, loadTestShapeTechnologies(t))
+	require.NoError(t, err)
+	written, err = reimported.Write(clusterFilename, filename, logFilename)
+	require.NoError(t, err)
+	assert.False(t, written)
+
+	unchanged, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Equal(t, edited, unchanged)
+
+	assert.Contains(t, reimported.Stub.Log[len(reimported.Stub.Log)-1], `"ops/grafana" is new`)
+	log, err := os.ReadFile(logFilename)
+	require.NoError(t, err)
+	assert.Contains(t, string(log), "ops/grafana")
+}
@@ -0,0 +1,113 @@
+package importer
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// wellKnownProtocols maps protocol names, as used by URL schemes, port names and app protocols, to protocols
+
+var wellKnownProtocols = map[string]types.Protocol{
+	"http":        types.HTTP,
+	"http2":       types.HTTP,
+	"h2c":         types.HTTP,
+	"https":       types.HTTPS,
+	"h2":          types.HTTPS,
+	"tls":         types.HTTPS,
+	"ws":          types.WS,
+	"wss":         types.WSS,
+	"grpc":        types.BINARY,
+	"grpcs":       types.BinaryEncrypted,
+	"jdbc":        types.JDBC,
+	"postgres":    types.SqlAccessProtocol,
+	"postgresql":  types.SqlAccessProtocol,
+	"mysql":       types.SqlAccessProtocol,
+	"mariadb":     types.SqlAccessProtocol,
+	"mssql":       types.SqlAccessProtocol,
+	"sqlserver":   types.SqlAccessProtocol,
+	"oracle":      types.SqlAccessProtocol,
+	"mongo":       types.NosqlAccessProtocol,
+	"mongodb":     types.NosqlAccessProtocol,
+	"mongodb+srv": types.NosqlAccessProtocolEncrypted,
+	"redis":       types.NosqlAccessProtocol,
+	"rediss":      types.NosqlAccessProtocolEncrypted,
+	"cassandra":   types.NosqlAccessProtocol,
+	"cql":         types.NosqlAccessProtocol,
+	"amqp":        types.BINARY,
+	"amqps":       types.BinaryEncrypted,
+	"kafka":       types.BINARY,
+	"nats":        types.BINARY,
+	"mqtt":        types.MQTT,
+	"ldap":        types.LDAP,
+	"ldaps":       types.LDAPS,
+	"smtp":        types.SMTP,
+	"smtps":       types.SmtpEncrypted,
+	"ssh":         types.SSH,
+	"sftp":        types.SFTP,
+	"ftp":         types.FTP,
+	"nfs":         types.NFS,
+	"metrics":     types.HTTP,
+}
+
+// wellKnownPortProtocols maps well-known ports to protocols, for ports without a telling name
+
+var wellKnownPortProtocols = map[int]types.Protocol{
+	22:    types.SSH,
+	25:    types.SMTP,
+	80:    types.HTTP,
+	389:   types.LDAP,
+	443:   types.HTTPS,
+	587:   types.SmtpEncrypted,
+	636:   types.LDAPS,
+	1433:  types.SqlAccessProtocol,
+	1521:  types.SqlAccessProtocol,
+	1883:  types.MQTT,
+	3000:  types.HTTP,
+	3306:  types.SqlAccessProtocol,
+	4222:  types.BINARY,
+	5432:  types.SqlAccessProtocol,
+	5672:  types.BINARY,
+	6379:  types.NosqlAccessProtocol,
+	8000:  types.HTTP,
+	8080:  types.HTTP,
+	8443:  types.HTTPS,
+	9090:  types.HTTP,
+	9042:  types.NosqlAccessProtocol,
+	9092:  types.BINARY,
+	9200:  types.HTTP,
+	27017: types.NosqlAccessProtocol,
+}
+
+// wellKnownProtocol returns the protocol the first of the given names names (a URL scheme, port name or app
+// protocol, optionally suffixed like http-web), or the one of the given well-known port
+
+func wellKnownProtocol(port int, names ...string) (types.Protocol, bool) {
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if protocol, ok := wellKnownProtocols[name]; ok && protocol != types.UnknownProtocol {
+			return protocol, true
+		}
+		if prefix, _, found := strings.Cut(name, "-"); found {
+			if protocol, ok := wellKnownProtocols[prefix]; ok && protocol != types.UnknownProtocol {
+				return protocol, true
+			}
+		}
+		if protocol, parseError := types.ParseProtocol(name); parseError == nil && protocol != types.UnknownProtocol {
+			return protocol, true
+		}
+	}
+
+	protocol, ok := wellKnownPortProtocols[port]
+	return protocol, ok
+}
+
+// serviceAddress matches the service addresses in environment variables and arguments, like
+// postgres://orders-db.shop:5432/orders, with the scheme, host and port as groups
+
+var serviceAddress = regexp.MustCompile(`(?:([a-z][a-z0-9+.-]*)://(?:[^@/\s]*@)?)?([a-z0-9][a-z0-9.-]*)(?::([0-9]+))?`)
+
+// serviceAddressVariable matches the names of environment variables holding service addresses, like DB_HOST
+
+var serviceAddressVariable = regexp.MustCompile(`(?i)(host|url|uri|addr|address|endpoint|server|dsn)s?$`)
@@ -0,0 +1,192 @@
+package importer
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed shape-technologies.yaml
+var shapeTechnologiesLocation embed.FS
+
+// ShapeTechnologies maps the shapes of a diagram to the technologies of the technical assets drawn with them, backed
+// by the technologies known to the model
+
+type ShapeTechnologies struct {
+	shapes       map[string]string
+	technologies types.TechnologyMap
+}
+
+// LoadWithConfig loads the built-in shapes, extended or overridden by the config's shape technology file, and
+// checks them against the technologies of the config
+
+func (what *ShapeTechnologies) LoadWithConfig(config *common.Config) error {
+	*what = ShapeTechnologies{
+		shapes:       make(map[string]string),
+		technologies: make(types.TechnologyMap),
+	}
+
+	technologiesLoadError := what.technologies.LoadWithConfig(config, "technologies.yaml")
+	if technologiesLoadError != nil {
+		return technologiesLoadError
+	}
+	what.technologies.PropagateAttributes()
+
+	defaultShapes, readError := shapeTechnologiesLocation.ReadFile("shape-technologies.yaml")
+	if readError != nil {
+		return fmt.Errorf("error reading default shape technologies: %w", readError)
+	}
+
+	unmarshalError := yaml.Unmarshal(defaultShapes, &what.shapes)
+	if unmarshalError != nil {
+		return fmt.Errorf("error parsing default shape technologies: %w", unmarshalError)
+	}
+
+	if len(config.ShapeTechnologyFilename) > 0 {
+		data, fileReadError := os.ReadFile(filepath.Clean(config.ShapeTechnologyFilename))
+		if fileReadError != nil {
+			return fmt.Errorf("error reading shape technologies from %q: %w", config.ShapeTechnologyFilename, fileReadError)
+		}
+
+		unmarshalError = yaml.Unmarshal(data, &what.shapes)
+		if unmarshalError != nil {
+			return fmt.Errorf("error parsing shape technologies from %q: %w", config.ShapeTechnologyFilename, unmarshalError)
+		}
+	}
+
+	for shape, technology := range what.shapes {
+		if what.technologies.Get(technology) == nil {
+			return fmt.Errorf("unknown technology %q for shape %q", technology, shape)
+		}
+	}
+
+	return nil
+}
+
+// Find returns the technology of the first of the given shapes with one, or the one named or else mentioned by the
+// given labels, which is a guess then, or the unknown technology
+
+func (what *ShapeTechnologies) Find(shapes []string, labels []string) (*types.Technology, bool) {
+	if technology := what.Shape(shapes...); technology != nil {
+		return technology, false
+	}
+	if technology := what.Label(labels...); technology != nil {
+		return technology, false
+	}
+	if technology := what.Mentioned(labels...); technology != nil {
+		return technology, true
+	}
+	return what.Unknown(), false
+}
+
+// Shape returns the technology for the first of the given shapes with one, or nil
+
+func (what *ShapeTechnologies) Shape(shapes ...string) *types.Technology {
+	for _, shape := range shapes {
+		if technology, ok := what.shapes[shape]; ok {
+			return what.technologies.Get(technology)
+		}
+	}
+
+	return nil
+}
+
+// Label returns the technology the first of the given labels names, by its name or an alias, or nil
+
+func (what *ShapeTechnologies) Label(labels ...string) *types.Technology {
+	for _, label := range labels {
+		if technology := what.named(slug(label)); technology != nil {
+			return technology
+		}
+	}
+
+	return nil
+}
+
+// Image returns the technology of the first of the given container images with one, as mapped by its name (without
+// registry, path, tag and digest, like postgres) or named by it, or nil
+
+func (what *ShapeTechnologies) Image(images ...string) *types.Technology {
+	for _, image := range images {
+		name := image[strings.LastIndex(image, "/")+1:]
+		name, _, _ = strings.Cut(name, "@")
+		name, _, _ = strings.Cut(name, ":")
+		if technology := what.Shape(name); technology != nil {
+			return technology
+		}
+		if technology := what.named(strings.ToLower(name)); technology != nil {
+			return technology
+		}
+	}
+
+	return nil
+}
+
+// Mentioned returns the technology the first of the given labels mentions (like "Customer DB"), preferring longer
+// names, or nil
+
+func (what *ShapeTechnologies) Mentioned(labels ...string) *types.Technology {
+	for _, label := range labels {
+		words := strings.Fields(nonIdCharacters.ReplaceAllString(strings.ToLower(label), " "))
+		for length := len(words); length > 0; length-- {
+			for start := 0; start+length <= len(words); start++ {
+				if technology := what.named(strings.Join(words[start:start+length], "-")); technology != nil {
+					return technology
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Unknown returns the technology for technical assets of an unknown technology
+
+func (what *ShapeTechnologies) Unknown() *types.Technology {
+	if technology := what.technologies.Get(types.UnknownTechnology); technology != nil {
+		return technology
+	}
+
+	return &types.Technology{Name: types.UnknownTechnology}
+}
+
+// isStorageTechnology tells whether technical assets of the technology are datastores
+
+func isStorageTechnology(technology *types.Technology) bool {
+	return technology.GetAttribute(types.Database) || technology.GetAttribute("file_storage") || technology.GetAttribute("identity_store") ||
+		technology.GetAttribute(types.BlockStorage) || technology.GetAttribute(types.DataLake) || technology.GetAttribute(types.SearchIndex)
+}
+
+func (what *ShapeTechnologies) named(name string) *types.Technology {
+	if len(name) == 0 {
+		return nil
+	}
+
+	if technology := what.technologies.Get(name); technology != nil {
+		return technology
+	}
+
+	names := make([]string, 0)
+	for technologyName := range what.technologies {
+		names = append(names, technologyName)
+	}
+	sort.Strings(names)
+
+	for _, technologyName := range names {
+		technology := what.technologies[technologyName]
+		for _, alias := range technology.Aliases {
+			if strings.EqualFold(alias, name) {
+				return &technology
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,526 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/threagile/threagile/pkg/docs"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+	"gopkg.in/yaml.v3"
+)
+
+// StubModel is an imported model together with TODO markers for everything the import could not infer, and the
+// conversion log of everything it could not import at all
+
+type StubModel struct {
+	Model *input.Model
+	Log   []string
+	todos []stubTodo
+	ids   map[string]bool
+}
+
+type stubTodo struct {
+	path    []string
+	comment string
+	missing yaml.Kind
+}
+
+func (what *StubModel) Init(title string) *StubModel {
+	*what = StubModel{
+		Model: new(input.Model).Defaults(),
+		Log:   make([]string, 0),
+		todos: make([]stubTodo, 0),
+		ids:   make(map[string]bool),
+	}
+
+	what.Model.ThreagileVersion = docs.ThreagileVersion
+	what.Model.Title = title
+	what.Model.Date = time.Now().Format("2006-01-02")
+	what.Model.Author = input.Author{Name: "TODO"}
+	what.Model.BusinessCriticality = types.Important.String()
+	what.Todo("rate the business criticality", "business_criticality")
+	what.todos = append(what.todos, stubTodo{
+		path:    []string{"data_assets"},
+		comment: "TODO: add the data assets and reference them from the technical assets and communication links",
+		missing: yaml.MappingNode,
+	})
+
+	return what
+}
+
+// Todo marks the value at the given path with a TODO comment, a missing value is written as empty list
+
+func (what *StubModel) Todo(comment string, path ...string) {
+	what.todos = append(what.todos, stubTodo{path: path, comment: "TODO: " + comment, missing: yaml.SequenceNode})
+}
+
+// Logf adds an entry to the conversion log
+
+func (what *StubModel) Logf(format string, args ...any) {
+	what.Log = append(what.Log, fmt.Sprintf(format, args...))
+}
+
+// UniqueId derives an id from the given title, which is unique among all ids returned so far
+
+func (what *StubModel) UniqueId(title string) string {
+	base := slug(title)
+	if len(base) == 0 {
+		base = "imported"
+	}
+
+	id := base
+	for n := 2; what.ids[id]; n++ {
+		id = base + "-" + strconv.Itoa(n)
+	}
+	what.ids[id] = true
+
+	return id
+}
+
+var nonIdCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slug derives an id from the given text like the model parser derives the ids of communication links
+
+func slug(value string) string {
+	return strings.Trim(nonIdCharacters.ReplaceAllString(strings.ToLower(value), "-"), "-")
+}
+
+// AddTechnicalAsset adds a technical asset using the given technology, with a unique title and stub values marked
+// as TODO for its CIA rating and data assets, as well as a guessed or unknown technology; it returns the title the
+// asset was added with
+
+func (what *StubModel) AddTechnicalAsset(title string, technology *types.Technology, guessed bool, assetType types.TechnicalAssetType) string {
+	title = uniqueTitle(title, func(title string) bool {
+		_, exists := what.Model.TechnicalAssets[title]
+		return exists
+	})
+
+	what.Model.TechnicalAssets[title] = input.TechnicalAsset{
+		ID:                   what.UniqueId(title),
+		Description:          title,
+		Type:                 assetType.String(),
+		Usage:                types.Business.String(),
+		UsedAsClientByHuman:  assetType == types.ExternalEntity && technology.GetAttribute("client"),
+		Size:                 types.Component.String(),
+		Machine:              types.Virtual.String(),
+		CustomDevelopedParts: assetType == types.Process,
+		CommunicationLinks:   make(map[string]input.CommunicationLink),
+	}
+	what.AddTechnicalAssetRatings(title, technology, guessed)
+
+	return title
+}
+
+// AddTechnicalAssetRatings adds what only the owners of the technical asset with the given title know to it, with
+// stub values marked as TODO for its owner, CIA rating and data assets; its technology is set unless nil (when
+// another model including this one defines it), marked as TODO if guessed or unknown
+
+func (what *StubModel) AddTechnicalAssetRatings(title string, technology *types.Technology, guessed bool) {
+	asset, ok := what.Model.TechnicalAssets[title]
+	if !ok {
+		asset.CommunicationLinks = make(map[string]input.CommunicationLink)
+	}
+
+	asset.Encryption = types.NoneEncryption.String()
+	asset.Owner = "TODO"
+	asset.Confidentiality = types.Confidential.String()
+	asset.Integrity = types.Critical.String()
+	asset.Availability = types.Critical.String()
+	asset.JustificationCiaRating = "TODO"
+	if technology != nil {
+		asset.Technology = technology.Name
+	}
+	what.Model.TechnicalAssets[title] = asset
+
+	what.Todo("rate the confidentiality", "technical_assets", title, "confidentiality")
+	what.Todo("rate the integrity", "technical_assets", title, "integrity")
+	what.Todo("rate the availability", "technical_assets", title, "availability")
+	what.Todo("reference the data assets processed", "technical_assets", title, "data_assets_processed")
+	what.Todo("reference the data assets stored", "technical_assets", title, "data_assets_stored")
+	if technology == nil {
+		return
+	}
+	if technology.Name == types.UnknownTechnology {
+		what.Todo("set the technology", "technical_assets", title, "technology")
+	} else if guessed {
+		what.Todo("check the technology guessed from the label", "technical_assets", title, "technology")
+	}
+}
+
+// AddCommunicationLink adds a communication link between the technical assets with the given titles, with a unique
+// title and its authentication, authorization and data assets marked as TODO; an unknown protocol is marked as TODO,
+// too; it returns the title the link was added with
+
+func (what *StubModel) AddCommunicationLink(sourceTitle string, targetTitle string, title string, protocol types.Protocol) string {
+	source := what.Model.TechnicalAssets[sourceTitle]
+	title = uniqueTitle(title, func(title string) bool {
+		_, exists := source.CommunicationLinks[title]
+		return exists
+	})
+
+	source.CommunicationLinks[title] = input.CommunicationLink{
+		Target:      what.Model.TechnicalAssets[targetTitle].ID,
+		Description: title,
+		Protocol:    protocol.String(),
+		Usage:       types.Business.String(),
+	}
+	what.Model.TechnicalAssets[sourceTitle] = source
+
+	if protocol == types.UnknownProtocol {
+		what.Todo("set the protocol", "technical_assets", sourceTitle, "communication_links", title, "protocol")
+	}
+	what.AddCommunicationLinkRatings(sourceTitle, title)
+
+	return title
+}
+
+// AddCommunicationLinkRatings adds what only the owners of the communication link with the given title know to it,
+// with its authentication, authorization and data assets marked as TODO
+
+func (what *StubModel) AddCommunicationLinkRatings(sourceTitle string, title string) {
+	link := what.Model.TechnicalAssets[sourceTitle].CommunicationLinks[title]
+	link.Authentication = types.NoneAuthentication.String()
+	link.Authorization = types.NoneAuthorization.String()
+	what.Model.TechnicalAssets[sourceTitle].CommunicationLinks[title] = link
+
+	what.Todo("set the authentication", "technical_assets", sourceTitle, "communication_links", title, "authentication")
+	what.Todo("set the authorization", "technical_assets", sourceTitle, "communication_links", title, "authorization")
+	what.Todo("reference the data assets sent", "technical_assets", sourceTitle, "communication_links", title, "data_assets_sent")
+	what.Todo("reference the data assets received", "technical_assets", sourceTitle, "communication_links", title, "data_assets_received")
+}
+
+// AddTrustBoundary adds a trust boundary of the given type around the technical assets and trust boundaries with
+// the given ids, a missing type is marked as TODO; it returns the title the trust boundary was added with
+
+func (what *StubModel) AddTrustBoundary(title string, id string, boundaryType string, assetIds []string, nestedIds []string) string {
+	title = uniqueTitle(title, func(title string) bool {
+		_, exists := what.Model.TrustBoundaries[title]
+		return exists
+	})
+
+	if len(boundaryType) == 0 {
+		boundaryType = types.NetworkOnPrem.String()
+		what.Todo("set the trust boundary type", "trust_boundaries", title, "type")
+	}
+
+	what.Model.TrustBoundaries[title] = input.TrustBoundary{
+		ID:                    id,
+		Description:           title,
+		Type:                  boundaryType,
+		TechnicalAssetsInside: assetIds,
+		TrustBoundariesNested: nestedIds,
+	}
+
+	return title
+}
+
+// AddSharedRuntime adds a shared runtime running the technical assets with the given ids
+
+func (what *StubModel) AddSharedRuntime(title string, id string, assetIds []string) string {
+	title = uniqueTitle(title, func(title string) bool {
+		_, exists := what.Model.SharedRuntimes[title]
+		return exists
+	})
+
+	what.Model.SharedRuntimes[title] = input.SharedRuntime{
+		ID:                     id,
+		Description:            title,
+		TechnicalAssetsRunning: assetIds,
+	}
+
+	return title
+}
+
+// Threat is a threat of the imported model, which becomes a risk of the custom risk category with its category id
+// at the technical asset (and communication link of it, if any) with the given titles
+
+type Threat struct {
+	CategoryId        string
+	Category          string
+	STRIDE            types.STRIDE
+	Description       string
+	Mitigation        string
+	Title             string
+	Severity          types.RiskSeverity
+	Status            types.RiskStatus
+	Justification     string
+	TechnicalAsset    string
+	CommunicationLink string
+}
+
+// AddThreat adds the threat as risk of its custom risk category, created on first use, and tracks its status unless
+// it is unchecked without any justification; it returns false if there already is a risk of the category at the
+// same place, which the threat is merged into then
+
+func (what *StubModel) AddThreat(threat Threat) bool {
+	var category *input.RiskCategory
+	for _, existing := range what.Model.CustomRiskCategories {
+		if existing.ID == threat.CategoryId {
+			category = existing
+		}
+	}
+	if category == nil {
+		category = &input.RiskCategory{
+			ID:              threat.CategoryId,
+			Title:           threat.Category,
+			Description:     threat.Description,
+			Mitigation:      threat.Mitigation,
+			Function:        types.Architecture.String(),
+			STRIDE:          threat.STRIDE.String(),
+			RisksIdentified: make(map[string]input.RiskIdentified),
+		}
+		what.Model.CustomRiskCategories = append(what.Model.CustomRiskCategories, category)
+	}
+
+	impact := types.MediumImpact
+	switch threat.Severity {
+	case types.LowSeverity:
+		impact = types.LowImpact
+	case types.ElevatedSeverity, types.HighSeverity:
+		impact = types.HighImpact
+	case types.CriticalSeverity:
+		impact = types.VeryHighImpact
+	}
+
+	assetId := what.Model.TechnicalAssets[threat.TechnicalAsset].ID
+	risk := input.RiskIdentified{
+		Severity:                   threat.Severity.String(),
+		ExploitationLikelihood:     types.Likely.String(),
+		ExploitationImpact:         impact.String(),
+		DataBreachProbability:      types.Possible.String(),
+		MostRelevantTechnicalAsset: assetId,
+	}
+	syntheticId := category.ID + "@" + assetId
+	if len(threat.CommunicationLink) > 0 {
+		risk.MostRelevantCommunicationLink = assetId + ">" + slug(threat.CommunicationLink)
+		syntheticId += "@" + risk.MostRelevantCommunicationLink
+	}
+
+	for _, existing := range category.RisksIdentified {
+		if existing.MostRelevantTechnicalAsset == risk.MostRelevantTechnicalAsset && existing.MostRelevantCommunicationLink == risk.MostRelevantCommunicationLink {
+			return false
+		}
+	}
+
+	title := uniqueTitle(threat.Title, func(title string) bool {
+		_, exists := category.RisksIdentified[title]
+		return exists
+	})
+	category.RisksIdentified[title] = risk
+
+	if threat.Status != types.Unchecked || len(threat.Justification) > 0 {
+		what.Model.RiskTracking[syntheticId] = input.RiskTracking{
+			Status:        threat.Status.String(),
+			Justification: threat.Justification,
+		}
+	}
+
+	return true
+}
+
+// threatSTRIDE parses a STRIDE category given as text (like "Information Disclosure"), taking the CIA categories of
+// some tools as the STRIDE categories threatening them
+
+func threatSTRIDE(value string) (types.STRIDE, bool) {
+	name := slug(value)
+	switch name {
+	case "confidentiality":
+		return types.InformationDisclosure, true
+	case "integrity":
+		return types.Tampering, true
+	case "availability":
+		return types.DenialOfService, true
+	}
+
+	stride, parseError := types.ParseSTRIDE(name)
+	return stride, parseError == nil
+}
+
+// threatSeverity parses a severity given as text (like "High")
+
+func threatSeverity(value string) (types.RiskSeverity, bool) {
+	severity, parseError := types.ParseRiskSeverity(strings.ToLower(strings.TrimSpace(value)))
+	return severity, parseError == nil
+}
+
+// placedShape is a shape of an imported diagram at its position
+
+type placedShape struct {
+	title  string
+	x, y   float64
+	width  float64
+	height float64
+}
+
+func (what *placedShape) contains(other *placedShape) bool {
+	return what.width*what.height > other.width*other.height &&
+		what.x <= other.x && what.y <= other.y &&
+		what.x+what.width >= other.x+other.width && what.y+what.height >= other.y+other.height
+}
+
+// addEnclosingTrustBoundaries adds the given boundaries as trust boundaries around the technical assets and boundaries
+// they are drawn around, the smallest one being the one they are inside of
+
+func (what *StubModel) addEnclosingTrustBoundaries(assets []*placedShape, boundaries []*placedShape) {
+	containerOf := func(shape *placedShape) *placedShape {
+		var container *placedShape
+		for _, boundary := range boundaries {
+			if boundary != shape && boundary.contains(shape) && (container == nil || container.contains(boundary)) {
+				container = boundary
+			}
+		}
+		return container
+	}
+
+	ids := make(map[*placedShape]string)
+	for n, boundary := range boundaries {
+		if len(boundary.title) == 0 {
+			boundary.title = "Trust Boundary " + strconv.Itoa(n+1)
+		}
+		ids[boundary] = what.UniqueId(boundary.title)
+	}
+
+	for _, boundary := range boundaries {
+		assetIds := make([]string, 0)
+		for _, asset := range assets {
+			if containerOf(asset) == boundary {
+				assetIds = append(assetIds, what.Model.TechnicalAssets[asset.title].ID)
+			}
+		}
+		nestedIds := make([]string, 0)
+		for _, nested := range boundaries {
+			if containerOf(nested) == boundary {
+				nestedIds = append(nestedIds, ids[nested])
+			}
+		}
+		sort.Strings(assetIds)
+		sort.Strings(nestedIds)
+
+		what.AddTrustBoundary(boundary.title, ids[boundary], "", assetIds, nestedIds)
+	}
+}
+
+func uniqueTitle(title string, exists func(title string) bool) string {
+	unique := title
+	for n := 2; exists(unique); n++ {
+		unique = fmt.Sprintf("%v (%d)", title, n)
+	}
+
+	return unique
+}
+
+// Write writes the model as YAML with its TODO markers as line comments, and the conversion log (if any) next to it
+
+func (what *StubModel) Write(filename string, logFilename string) error {
+	content, err := what.Marshal()
+	if err != nil {
+		return fmt.Errorf("error encoding %s: %v", filename, err)
+	}
+
+	err = os.WriteFile(filepath.Clean(filename), content, 0600)
+	if err != nil {
+		return fmt.Errorf("error writing %s: %v", filename, err)
+	}
+
+	return what.WriteLog(logFilename)
+}
+
+// WriteLog writes the conversion log, if any
+
+func (what *StubModel) WriteLog(logFilename string) error {
+	if len(what.Log) == 0 {
+		return nil
+	}
+
+	err := os.WriteFile(filepath.Clean(logFilename), []byte(strings.Join(what.Log, "\n")+"\n"), 0600)
+	if err != nil {
+		return fmt.Errorf("error writing %s: %v", logFilename, err)
+	}
+	return nil
+}
+
+func (what *StubModel) Marshal() ([]byte, error) {
+	var root yaml.Node
+	err := root.Encode(what.Model)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, todo := range what.todos {
+		node := &root
+		for n, key := range todo.path {
+			value := yamlMappingValue(node, key)
+			if value == nil && n == len(todo.path)-1 && node.Kind == yaml.MappingNode {
+				value = &yaml.Node{Kind: todo.missing, Style: yaml.FlowStyle}
+				yamlInsertMappingEntry(node, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+			}
+			if value == nil {
+				return nil, fmt.Errorf("no value at %v", strings.Join(todo.path, "."))
+			}
+			if n == len(todo.path)-1 {
+				if value.Kind == yaml.ScalarNode || value.Style == yaml.FlowStyle {
+					value.LineComment = todo.comment
+				} else {
+					yamlMappingKey(node, key).HeadComment = todo.comment
+				}
+			}
+			node = value
+		}
+	}
+
+	var content bytes.Buffer
+	encoder := yaml.NewEncoder(&content)
+	encoder.SetIndent(2)
+	err = encoder.Encode(&root)
+	if err != nil {
+		return nil, err
+	}
+	_ = encoder.Close()
+
+	return content.Bytes(), nil
+}
+
+// yamlInsertMappingEntry inserts the entry in front of the nested technical assets or communication links of the
+// mapping, to keep it next to the values written by the model itself
+
+func yamlInsertMappingEntry(node *yaml.Node, key *yaml.Node, value *yaml.Node) {
+	n := 0
+	for ; n+1 < len(node.Content); n += 2 {
+		if node.Content[n].Value == "technical_assets" || node.Content[n].Value == "communication_links" {
+			break
+		}
+	}
+	node.Content = append(node.Content[:n], append([]*yaml.Node{key, value}, node.Content[n:]...)...)
+}
+
+func yamlMappingKey(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for n := 0; n+1 < len(node.Content); n += 2 {
+		if node.Content[n].Value == key {
+			return node.Content[n]
+		}
+	}
+	return nil
+}
+
+func yamlMappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for n := 0; n+1 < len(node.Content); n += 2 {
+		if node.Content[n].Value == key {
+			return node.Content[n+1]
+		}
+	}
+	return nil
+}
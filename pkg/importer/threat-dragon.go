@@ -0,0 +1,299 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type threatDragonFile struct {
+	Summary struct {
+		Title       string `json:"title"`
+		Owner       string `json:"owner"`
+		Description string `json:"description"`
+	} `json:"summary"`
+	Detail struct {
+		Diagrams []threatDragonDiagram `json:"diagrams"`
+	} `json:"detail"`
+}
+
+type threatDragonDiagram struct {
+	Title string              `json:"title"`
+	Cells []*threatDragonCell `json:"cells"`
+
+	// version 1 files keep the cells in the diagram's JointJS graph
+
+	DiagramJson struct {
+		Cells []*threatDragonCell `json:"cells"`
+	} `json:"diagramJson"`
+}
+
+// threatDragonCell is a shape of a diagram, version 2 files keep the element it shows in its data and version 1 files
+// in the cell itself
+
+type threatDragonCell struct {
+	threatDragonElement
+	Data     *threatDragonElement `json:"data"`
+	ID       string               `json:"id"`
+	Position struct {
+		X float64 `json:"x"`
+		Y float64 `json:"y"`
+	} `json:"position"`
+	Size struct {
+		Width  float64 `json:"width"`
+		Height float64 `json:"height"`
+	} `json:"size"`
+	Source threatDragonEnd  `json:"source"`
+	Target threatDragonEnd  `json:"target"`
+	Attrs  threatDragonText `json:"attrs"`
+	Labels []struct {
+		Attrs threatDragonText `json:"attrs"`
+	} `json:"labels"`
+}
+
+type threatDragonElement struct {
+	Type             string               `json:"type"`
+	Name             string               `json:"name"`
+	OutOfScope       bool                 `json:"outOfScope"`
+	ReasonOutOfScope string               `json:"reasonOutOfScope"`
+	IsWebApplication bool                 `json:"isWebApplication"`
+	Protocol         string               `json:"protocol"`
+	Threats          []threatDragonThreat `json:"threats"`
+}
+
+type threatDragonEnd struct {
+	Cell string `json:"cell"`
+	ID   string `json:"id"`
+}
+
+type threatDragonText struct {
+	Text struct {
+		Text string `json:"text"`
+	} `json:"text"`
+}
+
+type threatDragonThreat struct {
+	Title       string `json:"title"`
+	Status      string `json:"status"`
+	Severity    string `json:"severity"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Mitigation  string `json:"mitigation"`
+}
+
+// element returns the element the cell shows, named by the cell's text or label if it has no name
+
+func (what *threatDragonCell) element() *threatDragonElement {
+	element := what.Data
+	if element == nil {
+		element = &what.threatDragonElement
+	}
+
+	if len(element.Name) == 0 {
+		element.Name = what.Attrs.Text.Text
+	}
+	for _, label := range what.Labels {
+		if len(element.Name) == 0 {
+			element.Name = label.Attrs.Text.Text
+		}
+	}
+	element.Name = strings.Join(strings.Fields(element.Name), " ")
+
+	return element
+}
+
+func (what threatDragonEnd) cell() string {
+	if len(what.Cell) > 0 {
+		return what.Cell
+	}
+	return what.ID
+}
+
+var threatDragonTechnicalAssetTypes = map[string]types.TechnicalAssetType{
+	"tm.Process": types.Process,
+	"tm.Actor":   types.ExternalEntity,
+	"tm.Store":   types.Datastore,
+}
+
+var threatDragonThreatStates = map[string]types.RiskStatus{
+	"mitigated":     types.Mitigated,
+	"notapplicable": types.FalsePositive,
+}
+
+// ImportThreatDragon reads a Threat Dragon (version 1 or 2) model into a stub model: processes, actors and stores of
+// all diagrams become technical assets of the technology their name names, boundary boxes become trust boundaries
+// around what they are drawn around, data flows become communication links and threats become risks of custom risk
+// categories, one per threat title, with their status tracked
+
+func ImportThreatDragon(filename string, shapeTechnologies *ShapeTechnologies) (*StubModel, error) {
+	data, readError := os.ReadFile(filepath.Clean(filename))
+	if readError != nil {
+		return nil, fmt.Errorf("error reading %s: %v", filename, readError)
+	}
+
+	file := new(threatDragonFile)
+	unmarshalError := json.Unmarshal(data, file)
+	if unmarshalError != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", filename, unmarshalError)
+	}
+
+	title := file.Summary.Title
+	if len(title) == 0 {
+		title = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	}
+	stub := new(StubModel).Init(title)
+	if len(file.Summary.Owner) > 0 {
+		stub.Model.Author.Name = file.Summary.Owner
+	}
+	stub.Model.AppDescription.Description = file.Summary.Description
+
+	assetTitles := make(map[string]string)
+	threats := make([]Threat, 0)
+	for _, diagram := range file.Detail.Diagrams {
+		cells := diagram.Cells
+		if len(cells) == 0 {
+			cells = diagram.DiagramJson.Cells
+		}
+
+		assets := make([]*placedShape, 0)
+		boundaries := make([]*placedShape, 0)
+		flows := make([]*threatDragonCell, 0)
+		for _, cell := range cells {
+			element := cell.element()
+			shape := &placedShape{
+				title:  element.Name,
+				x:      cell.Position.X,
+				y:      cell.Position.Y,
+				width:  cell.Size.Width,
+				height: cell.Size.Height,
+			}
+
+			assetType, ok := threatDragonTechnicalAssetTypes[element.Type]
+			switch {
+			case ok:
+			case element.Type == "tm.BoundaryBox":
+				boundaries = append(boundaries, shape)
+				continue
+			case element.Type == "tm.Flow":
+				flows = append(flows, cell)
+				continue
+			case element.Type == "tm.Boundary":
+				stub.Logf("%v: skipped trust boundary line %q, as only the trust boundaries drawn around elements can be imported", diagram.Title, element.Name)
+				continue
+			case element.Type == "tm.Text":
+				continue
+			default:
+				stub.Logf("%v: skipped %v %q, which is no process, actor, store, data flow or trust boundary", diagram.Title, element.Type, element.Name)
+				continue
+			}
+
+			if len(shape.title) == 0 {
+				shape.title = strings.TrimPrefix(element.Type, "tm.")
+			}
+
+			technology, guessed := shapeTechnologies.Find(nil, []string{element.Name})
+			if technology.Name == types.UnknownTechnology {
+				if element.IsWebApplication {
+					technology = shapeTechnologies.Label("web-application")
+				} else if shapeTechnology := shapeTechnologies.Shape(element.Type); shapeTechnology != nil {
+					technology = shapeTechnology
+				}
+			}
+
+			shape.title = stub.AddTechnicalAsset(shape.title, technology, guessed, assetType)
+			if element.OutOfScope {
+				asset := stub.Model.TechnicalAssets[shape.title]
+				asset.OutOfScope = true
+				asset.JustificationOutOfScope = element.ReasonOutOfScope
+				stub.Model.TechnicalAssets[shape.title] = asset
+			}
+			assetTitles[cell.ID] = shape.title
+			assets = append(assets, shape)
+
+			for _, threat := range element.Threats {
+				threats = append(threats, threatDragonThreatAt(stub, threat, shape.title, ""))
+			}
+		}
+
+		stub.addEnclosingTrustBoundaries(assets, boundaries)
+
+		for _, flow := range flows {
+			element := flow.element()
+			sourceTitle, sourceOk := assetTitles[flow.Source.cell()]
+			targetTitle, targetOk := assetTitles[flow.Target.cell()]
+			if !sourceOk || !targetOk {
+				stub.Logf("%v: skipped data flow %q, which is not connected to two imported elements", diagram.Title, element.Name)
+				continue
+			}
+
+			protocol, parseError := types.ParseProtocol(strings.ToLower(element.Protocol))
+			if parseError != nil {
+				protocol, parseError = types.ParseProtocol(strings.ToLower(element.Name))
+			}
+			if parseError != nil {
+				protocol = types.UnknownProtocol
+				if len(element.Protocol) > 0 {
+					stub.Logf("%v: data flow %q has the unknown protocol %q", diagram.Title, element.Name, element.Protocol)
+				}
+			}
+
+			linkTitle := element.Name
+			if len(linkTitle) == 0 || strings.EqualFold(linkTitle, protocol.String()) || strings.EqualFold(linkTitle, "Data Flow") {
+				linkTitle = "Traffic to " + targetTitle
+			}
+			linkTitle = stub.AddCommunicationLink(sourceTitle, targetTitle, linkTitle, protocol)
+
+			for _, threat := range element.Threats {
+				threats = append(threats, threatDragonThreatAt(stub, threat, sourceTitle, linkTitle))
+			}
+		}
+	}
+
+	if len(stub.Model.TechnicalAssets) == 0 {
+		return nil, fmt.Errorf("error importing %s: no elements found to import as technical assets", filename)
+	}
+
+	for _, threat := range threats {
+		if !stub.AddThreat(threat) {
+			stub.Logf("merged threat %q into the one of the same title at the same element or data flow", threat.Title)
+		}
+	}
+
+	return stub, nil
+}
+
+// threatDragonThreatAt converts the threat at the technical asset (and communication link of it, if any) with the given
+// titles
+
+func threatDragonThreatAt(stub *StubModel, threat threatDragonThreat, assetTitle string, linkTitle string) Threat {
+	title := strings.TrimSpace(threat.Title)
+	if len(title) == 0 {
+		title = "Unnamed Threat"
+	}
+
+	stride, strideOk := threatSTRIDE(threat.Type)
+	if !strideOk {
+		stub.Logf("threat %q has no STRIDE category but %q, which was imported as %v", title, threat.Type, stride)
+	}
+	severity, severityOk := threatSeverity(threat.Severity)
+	if !severityOk {
+		severity = types.MediumSeverity
+	}
+
+	return Threat{
+		CategoryId:        "threat-dragon-" + slug(title),
+		Category:          title,
+		STRIDE:            stride,
+		Description:       threat.Description,
+		Mitigation:        threat.Mitigation,
+		Title:             title,
+		Severity:          severity,
+		Status:            threatDragonThreatStates[strings.ToLower(threat.Status)],
+		TechnicalAsset:    assetTitle,
+		CommunicationLink: linkTitle,
+	}
+}
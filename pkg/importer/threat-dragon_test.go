@@ -0,0 +1,156 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
+	"gopkg.in/yaml.v3"
+)
+
+const threatDragonTestFileV2 = `{
+  "version": "2.2.0",
+  "summary": {"title": "Ticket Shop", "owner": "Jane Doe", "description": "Sells tickets."},
+  "detail": {
+    "contributors": [],
+    "diagrams": [{
+      "title": "Main",
+      "diagramType": "STRIDE",
+      "cells": [
+        {"id": "a1", "shape": "actor", "position": {"x": 0, "y": 0}, "size": {"width": 100, "height": 60},
+         "data": {"type": "tm.Actor", "name": "Customer", "threats": []}},
+        {"id": "p1", "shape": "process", "position": {"x": 300, "y": 300}, "size": {"width": 60, "height": 60},
+         "data": {"type": "tm.Process", "name": "Shop Frontend", "isWebApplication": true, "threats": [
+           {"id": "t1", "title": "Generic spoofing threat", "status": "Mitigated", "severity": "High", "type": "Spoofing",
+            "description": "Someone pretends to be the shop.", "mitigation": "TLS", "modelType": "STRIDE", "number": 1, "score": ""},
+           {"id": "t2", "title": "Secrets in the page", "status": "Open", "severity": "TBD", "type": "Linkability", "modelType": "LINDDUN", "number": 2}
+         ]}},
+        {"id": "s1", "shape": "store", "position": {"x": 500, "y": 300}, "size": {"width": 80, "height": 60},
+         "data": {"type": "tm.Store", "name": "Orders", "outOfScope": true, "reasonOutOfScope": "Run by the ERP team", "threats": []}},
+        {"id": "b1", "shape": "trust-boundary-box", "position": {"x": 250, "y": 250}, "size": {"width": 400, "height": 200},
+         "data": {"type": "tm.BoundaryBox", "name": "Cloud"}},
+        {"id": "b2", "shape": "trust-boundary-curve", "source": {"x": 200, "y": 0}, "target": {"x": 200, "y": 500},
+         "data": {"type": "tm.Boundary", "name": "Internet"}},
+        {"id": "f1", "shape": "flow", "source": {"cell": "a1"}, "target": {"cell": "p1"},
+         "data": {"type": "tm.Flow", "name": "Browse", "protocol": "HTTPS", "threats": [
+           {"title": "Eavesdropping", "status": "NotApplicable", "severity": "Low", "type": "Information disclosure", "description": "", "mitigation": ""}
+         ]}},
+        {"id": "f2", "shape": "flow", "source": {"cell": "p1"}, "target": {"cell": "s1"},
+         "data": {"type": "tm.Flow", "name": "Data Flow", "protocol": "", "threats": []}},
+        {"id": "f3", "shape": "flow", "source": {"cell": "p1"}, "target": {"x": 900, "y": 900},
+         "data": {"type": "tm.Flow", "name": "Dangling", "threats": []}}
+      ]
+    }]
+  }
+}`
+
+const threatDragonTestFileV1 = `{
+  "summary": {"title": "Legacy Model", "owner": ""},
+  "detail": {
+    "contributors": [],
+    "diagrams": [{
+      "title": "Context",
+      "id": 0,
+      "diagramJson": {"cells": [
+        {"type": "tm.Actor", "id": "a1", "size": {"width": 160, "height": 80}, "position": {"x": 0, "y": 0},
+         "attrs": {"text": {"text": "Admin"}}, "threats": []},
+        {"type": "tm.Process", "id": "p1", "size": {"width": 100, "height": 100}, "position": {"x": 300, "y": 0},
+         "attrs": {"text": {"text": "LDAP"}},
+         "threats": [{"title": "Brute force", "status": "Open", "severity": "Medium", "type": "Elevation of privilege", "description": "Guessing passwords", "mitigation": "Lockout"}]},
+        {"type": "tm.Flow", "id": "f1", "source": {"id": "a1"}, "target": {"id": "p1"},
+         "labels": [{"position": 0.5, "attrs": {"text": {"text": "ldaps", "font-weight": "400"}}}], "threats": []}
+      ]}
+    }]
+  }
+}`
+
+func TestImportThreatDragon(t *testing.T) {
+	filename := writeTestFile(t, "ticket-shop.json", threatDragonTestFileV2)
+
+	stub, err := ImportThreatDragon(filename, loadTestShapeTechnologies(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Ticket Shop", stub.Model.Title)
+	assert.Equal(t, "Jane Doe", stub.Model.Author.Name)
+	assert.Equal(t, "Sells tickets.", stub.Model.AppDescription.Description)
+
+	assets := stub.Model.TechnicalAssets
+	require.Len(t, assets, 3)
+	assert.Equal(t, "browser", assets["Customer"].Technology)
+	assert.Equal(t, "external-entity", assets["Customer"].Type)
+	assert.Equal(t, "web-application", assets["Shop Frontend"].Technology)
+	assert.Equal(t, "process", assets["Shop Frontend"].Type)
+	assert.Equal(t, "database", assets["Orders"].Technology)
+	assert.True(t, assets["Orders"].OutOfScope)
+	assert.Equal(t, "Run by the ERP team", assets["Orders"].JustificationOutOfScope)
+
+	assert.Equal(t, "https", assets["Customer"].CommunicationLinks["Browse"].Protocol)
+	assert.Equal(t, "unknown-protocol", assets["Shop Frontend"].CommunicationLinks["Traffic to Orders"].Protocol)
+
+	require.Len(t, stub.Model.TrustBoundaries, 1)
+	assert.Equal(t, []string{"orders", "shop-frontend"}, stub.Model.TrustBoundaries["Cloud"].TechnicalAssetsInside)
+
+	categories := stub.Model.CustomRiskCategories
+	require.Len(t, categories, 3)
+	assert.Equal(t, "threat-dragon-generic-spoofing-threat", categories[0].ID)
+	assert.Equal(t, "spoofing", categories[0].STRIDE)
+	assert.Equal(t, "TLS", categories[0].Mitigation)
+	assert.Equal(t, "high", categories[0].RisksIdentified["Generic spoofing threat"].Severity)
+	assert.Equal(t, "medium", categories[1].RisksIdentified["Secrets in the page"].Severity)
+	assert.Equal(t, "customer>browse", categories[2].RisksIdentified["Eavesdropping"].MostRelevantCommunicationLink)
+	assert.Equal(t, "information-disclosure", categories[2].STRIDE)
+
+	assert.Equal(t, map[string]input.RiskTracking{
+		"threat-dragon-generic-spoofing-threat@shop-frontend":  {Status: "mitigated"},
+		"threat-dragon-eavesdropping@customer@customer>browse": {Status: "false-positive"},
+	}, stub.Model.RiskTracking)
+
+	require.Len(t, stub.Log, 3)
+	assert.Contains(t, stub.Log[0], "Linkability")
+	assert.Contains(t, stub.Log[1], "Internet")
+	assert.Contains(t, stub.Log[2], "Dangling")
+}
+
+func TestImportThreatDragonVersion1(t *testing.T) {
+	filename := writeTestFile(t, "legacy.json", threatDragonTestFileV1)
+
+	stub, err := ImportThreatDragon(filename, loadTestShapeTechnologies(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Legacy Model", stub.Model.Title)
+	assert.Equal(t, "TODO", stub.Model.Author.Name)
+
+	assets := stub.Model.TechnicalAssets
+	require.Len(t, assets, 2)
+	assert.Equal(t, "browser", assets["Admin"].Technology)
+	assert.Equal(t, "ldap-server", assets["LDAP"].Technology)
+	assert.Equal(t, "ldaps", assets["Admin"].CommunicationLinks["Traffic to LDAP"].Protocol)
+
+	require.Len(t, stub.Model.CustomRiskCategories, 1)
+	assert.Equal(t, "elevation-of-privilege", stub.Model.CustomRiskCategories[0].STRIDE)
+	assert.Empty(t, stub.Model.RiskTracking)
+	assert.Empty(t, stub.Log)
+}
+
+func TestImportThreatDragonModelParses(t *testing.T) {
+	filename := writeTestFile(t, "ticket-shop.json", threatDragonTestFileV2)
+
+	stub, err := ImportThreatDragon(filename, loadTestShapeTechnologies(t))
+	require.NoError(t, err)
+
+	content, err := stub.Marshal()
+	require.NoError(t, err)
+
+	modelInput := new(input.Model).Defaults()
+	require.NoError(t, yaml.Unmarshal(content, modelInput))
+
+	parsedModel, err := model.ParseModel(&common.Config{}, modelInput, make(types.RiskRules), make(types.RiskRules))
+	require.NoError(t, err)
+	assert.Len(t, parsedModel.TechnicalAssets, 3)
+	assert.Len(t, parsedModel.CustomRiskCategories, 3)
+	assert.Equal(t, types.FalsePositive, parsedModel.RiskTracking["threat-dragon-eavesdropping@customer@customer>browse"].Status)
+}
@@ -0,0 +1,307 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// tm7Node is an element of a Microsoft Threat Modeling Tool file, read generically since the data contract
+// serialization spreads the elements over many namespaces
+
+type tm7Node struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Children []*tm7Node `xml:",any"`
+	Text     string     `xml:",chardata"`
+}
+
+func (what *tm7Node) child(name string) *tm7Node {
+	if what == nil {
+		return nil
+	}
+	for _, child := range what.Children {
+		if child.XMLName.Local == name {
+			return child
+		}
+	}
+	return nil
+}
+
+func (what *tm7Node) all(name string) []*tm7Node {
+	result := make([]*tm7Node, 0)
+	if what == nil {
+		return result
+	}
+	for _, child := range what.Children {
+		if child.XMLName.Local == name {
+			result = append(result, child)
+		}
+	}
+	return result
+}
+
+func (what *tm7Node) value(path ...string) string {
+	node := what
+	for _, name := range path {
+		node = node.child(name)
+	}
+	if node == nil {
+		return ""
+	}
+	return strings.TrimSpace(node.Text)
+}
+
+func (what *tm7Node) number(name string) float64 {
+	value, _ := strconv.ParseFloat(what.value(name), 64)
+	return value
+}
+
+// entries returns the values of a serialized dictionary, like the shapes of a drawing surface
+
+func (what *tm7Node) entries() []*tm7Node {
+	result := make([]*tm7Node, 0)
+	if what == nil {
+		return result
+	}
+	for _, entry := range what.Children {
+		if value := entry.child("Value"); value != nil {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// property returns the value of the element property with the given display name
+
+func (what *tm7Node) property(displayName string) string {
+	for _, property := range what.child("Properties").Children {
+		if property.value("DisplayName") == displayName {
+			return property.value("Value")
+		}
+	}
+	return ""
+}
+
+// header returns the display name of the element's stencil, like "Web Application"
+
+func (what *tm7Node) header() string {
+	for _, property := range what.child("Properties").Children {
+		for _, attribute := range property.Attrs {
+			if attribute.Name.Local == "type" && strings.HasSuffix(attribute.Value, "HeaderDisplayAttribute") {
+				return property.value("DisplayName")
+			}
+		}
+	}
+	return ""
+}
+
+// threatProperty returns the value of the threat property with the given key
+
+func (what *tm7Node) threatProperty(key string) string {
+	for _, property := range what.child("Properties").Children {
+		if property.value("Key") == key {
+			return property.value("Value")
+		}
+	}
+	return ""
+}
+
+var tm7TechnicalAssetTypes = map[string]types.TechnicalAssetType{
+	"GE.P":  types.Process,
+	"GE.EI": types.ExternalEntity,
+	"GE.DS": types.Datastore,
+}
+
+var tm7TemplatePlaceholder = regexp.MustCompile(`\{(\w+)\.Name}`)
+
+// tm7ThreatStates maps the threat states to risk tracking states, the missing AutoGenerated and NotStarted states are
+// unchecked
+
+var tm7ThreatStates = map[string]types.RiskStatus{
+	"NeedsInvestigation": types.InDiscussion,
+	"NotApplicable":      types.FalsePositive,
+	"Mitigated":          types.Mitigated,
+}
+
+// ImportTM7 reads a Microsoft Threat Modeling Tool (.tm7) file into a stub model: processes, external interactors and
+// data stores of all diagrams become technical assets of the technology their stencil or name names, border
+// boundaries become trust boundaries around what they are drawn around, data flows become communication links and
+// threats become risks of custom risk categories with their state tracked
+
+func ImportTM7(filename string, shapeTechnologies *ShapeTechnologies) (*StubModel, error) {
+	data, readError := os.ReadFile(filepath.Clean(filename))
+	if readError != nil {
+		return nil, fmt.Errorf("error reading %s: %v", filename, readError)
+	}
+
+	root := new(tm7Node)
+	unmarshalError := xml.Unmarshal(data, root)
+	if unmarshalError != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", filename, unmarshalError)
+	}
+	if root.XMLName.Local != "ThreatModel" {
+		return nil, fmt.Errorf("error parsing %s: not a threat model but %v", filename, root.XMLName.Local)
+	}
+
+	title := root.value("MetaInformation", "ThreatModelName")
+	if len(title) == 0 {
+		title = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	}
+	stub := new(StubModel).Init(title)
+	if owner := root.value("MetaInformation", "Owner"); len(owner) > 0 {
+		stub.Model.Author.Name = owner
+	}
+
+	assetTitles := make(map[string]string)
+	linkTitles := make(map[string]string)
+	for _, surface := range root.child("DrawingSurfaceList").all("DrawingSurfaceModel") {
+		surfaceTitle := surface.property("Name")
+
+		assets := make([]*placedShape, 0)
+		boundaries := make([]*placedShape, 0)
+		for _, element := range surface.child("Borders").entries() {
+			shape := &placedShape{
+				title:  element.property("Name"),
+				x:      element.number("Left"),
+				y:      element.number("Top"),
+				width:  element.number("Width"),
+				height: element.number("Height"),
+			}
+			genericType := element.value("GenericTypeId")
+
+			if genericType == "GE.TB.B" {
+				boundaries = append(boundaries, shape)
+				continue
+			}
+
+			assetType, ok := tm7TechnicalAssetTypes[genericType]
+			if !ok {
+				stub.Logf("%v: skipped %v %q, which is no process, external interactor, data store or border boundary", surfaceTitle, genericType, shape.title)
+				continue
+			}
+			if len(shape.title) == 0 {
+				shape.title = element.header()
+			}
+
+			technology, guessed := shapeTechnologies.Find([]string{element.value("TypeId")}, []string{shape.title, element.header()})
+			shape.title = stub.AddTechnicalAsset(shape.title, technology, guessed, assetType)
+			if strings.EqualFold(element.property("Out Of Scope"), "true") {
+				asset := stub.Model.TechnicalAssets[shape.title]
+				asset.OutOfScope = true
+				asset.JustificationOutOfScope = element.property("Reason For Out Of Scope")
+				stub.Model.TechnicalAssets[shape.title] = asset
+			}
+			assetTitles[element.value("Guid")] = shape.title
+			assets = append(assets, shape)
+		}
+
+		stub.addEnclosingTrustBoundaries(assets, boundaries)
+
+		for _, line := range surface.child("Lines").entries() {
+			name := line.property("Name")
+			switch line.value("GenericTypeId") {
+			case "GE.DF":
+			case "GE.TB.L":
+				stub.Logf("%v: skipped trust boundary line %q, as only the trust boundaries drawn around elements can be imported", surfaceTitle, name)
+				continue
+			default:
+				stub.Logf("%v: skipped %v %q, which is no data flow", surfaceTitle, line.value("GenericTypeId"), name)
+				continue
+			}
+
+			sourceTitle, sourceOk := assetTitles[line.value("SourceGuid")]
+			targetTitle, targetOk := assetTitles[line.value("TargetGuid")]
+			if !sourceOk || !targetOk {
+				stub.Logf("%v: skipped data flow %q, which is not connected to two imported elements", surfaceTitle, name)
+				continue
+			}
+
+			typeId := line.value("TypeId")
+			protocol, parseError := types.ParseProtocol(strings.ToLower(typeId[strings.LastIndex(typeId, ".")+1:]))
+			if parseError != nil {
+				protocol, parseError = types.ParseProtocol(strings.ToLower(name))
+			}
+			if parseError != nil {
+				protocol = types.UnknownProtocol
+			}
+
+			linkTitle := name
+			if len(linkTitle) == 0 || strings.EqualFold(linkTitle, protocol.String()) || strings.EqualFold(linkTitle, "Generic Data Flow") {
+				linkTitle = "Traffic to " + targetTitle
+			}
+			linkTitles[line.value("Guid")] = stub.AddCommunicationLink(sourceTitle, targetTitle, linkTitle, protocol)
+			assetTitles[line.value("Guid")] = sourceTitle
+		}
+	}
+
+	if len(stub.Model.TechnicalAssets) == 0 {
+		return nil, fmt.Errorf("error importing %s: no elements found to import as technical assets", filename)
+	}
+
+	importTM7Threats(stub, root, assetTitles, linkTitles)
+
+	return stub, nil
+}
+
+// importTM7Threats adds the threats as risks of custom risk categories, one per threat type of the knowledge base
+
+func importTM7Threats(stub *StubModel, root *tm7Node, assetTitles map[string]string, linkTitles map[string]string) {
+	threatTypes := make(map[string]*tm7Node)
+	for _, threatType := range root.child("KnowledgeBase").child("ThreatTypes").all("ThreatType") {
+		threatTypes[threatType.value("Id")] = threatType
+	}
+
+	for _, threat := range root.child("ThreatInstances").entries() {
+		title := threat.threatProperty("Title")
+		typeId := threat.value("TypeId")
+
+		flowGuid := threat.value("FlowGuid")
+		assetTitle, ok := assetTitles[flowGuid]
+		if !ok {
+			assetTitle, ok = assetTitles[threat.value("TargetGuid")]
+		}
+		if !ok {
+			stub.Logf("skipped threat %v %q, which is not at an imported element or data flow", threat.value("Id"), title)
+			continue
+		}
+
+		category := tm7TemplatePlaceholder.ReplaceAllString(threatTypes[typeId].value("ShortTitle"), "$1")
+		if len(category) == 0 {
+			category = title
+		}
+
+		stride, strideOk := threatSTRIDE(threat.threatProperty("UserThreatCategory"))
+		if !strideOk {
+			stub.Logf("threat %v %q has no STRIDE category but %q, which was imported as %v", threat.value("Id"), title, threat.threatProperty("UserThreatCategory"), stride)
+		}
+
+		severity, severityOk := threatSeverity(threat.threatProperty("Priority"))
+		if !severityOk {
+			severity = types.MediumSeverity
+		}
+
+		added := stub.AddThreat(Threat{
+			CategoryId:        "tmt-" + slug(typeId),
+			Category:          category,
+			STRIDE:            stride,
+			Description:       threat.threatProperty("UserThreatShortDescription"),
+			Mitigation:        threat.threatProperty("PossibleMitigations"),
+			Title:             title,
+			Severity:          severity,
+			Status:            tm7ThreatStates[threat.value("State")],
+			Justification:     threat.threatProperty("StateInformation"),
+			TechnicalAsset:    assetTitle,
+			CommunicationLink: linkTitles[flowGuid],
+		})
+		if !added {
+			stub.Logf("merged threat %v %q into the one of the same type at the same element or data flow", threat.value("Id"), title)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
+	"gopkg.in/yaml.v3"
+)
+
+const tm7TestFile = `<ThreatModel xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.Model" xmlns:i="http://www.w3.org/2001/XMLSchema-instance">
+<DrawingSurfaceList>
+<DrawingSurfaceModel z:Id="i1" xmlns:z="http://schemas.microsoft.com/2003/10/Serialization/">
+  <GenericTypeId xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">DRAWINGSURFACE</GenericTypeId>
+  <Properties xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase" xmlns:a="http://schemas.microsoft.com/2003/10/Serialization/Arrays">
+    <a:anyType i:type="StringDisplayAttribute"><DisplayName>Name</DisplayName><Name/><Value i:type="b:string" xmlns:b="http://www.w3.org/2001/XMLSchema">Diagram 1</Value></a:anyType>
+  </Properties>
+  <Borders xmlns:a="http://schemas.microsoft.com/2003/10/Serialization/Arrays">
+    <a:KeyValueOfguidanyType>
+      <a:Key>11111111-0000-0000-0000-000000000001</a:Key>
+      <a:Value i:type="StencilRectangle">
+        <GenericTypeId xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">GE.EI</GenericTypeId>
+        <Guid xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">11111111-0000-0000-0000-000000000001</Guid>
+        <Properties xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">
+          <a:anyType i:type="HeaderDisplayAttribute"><DisplayName>Browser</DisplayName><Name/><Value i:nil="true"/></a:anyType>
+          <a:anyType i:type="StringDisplayAttribute"><DisplayName>Name</DisplayName><Name/><Value i:type="b:string" xmlns:b="http://www.w3.org/2001/XMLSchema">Customer Browser</Value></a:anyType>
+        </Properties>
+        <TypeId xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">SE.EI.TMCore.Browser</TypeId>
+        <Height>100</Height><Left>10</Left><Top>10</Top><Width>100</Width>
+      </a:Value>
+    </a:KeyValueOfguidanyType>
+    <a:KeyValueOfguidanyType>
+      <a:Key>11111111-0000-0000-0000-000000000002</a:Key>
+      <a:Value i:type="StencilEllipse">
+        <GenericTypeId xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">GE.P</GenericTypeId>
+        <Guid xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">11111111-0000-0000-0000-000000000002</Guid>
+        <Properties xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">
+          <a:anyType i:type="HeaderDisplayAttribute"><DisplayName>Web Application</DisplayName><Name/><Value i:nil="true"/></a:anyType>
+          <a:anyType i:type="StringDisplayAttribute"><DisplayName>Name</DisplayName><Name/><Value i:type="b:string" xmlns:b="http://www.w3.org/2001/XMLSchema">Shop</Value></a:anyType>
+        </Properties>
+        <TypeId xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">SE.P.TMCore.WebApp</TypeId>
+        <Height>100</Height><Left>300</Left><Top>300</Top><Width>100</Width>
+      </a:Value>
+    </a:KeyValueOfguidanyType>
+    <a:KeyValueOfguidanyType>
+      <a:Key>11111111-0000-0000-0000-000000000003</a:Key>
+      <a:Value i:type="StencilParallelLines">
+        <GenericTypeId xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">GE.DS</GenericTypeId>
+        <Guid xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">11111111-0000-0000-0000-000000000003</Guid>
+        <Properties xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">
+          <a:anyType i:type="StringDisplayAttribute"><DisplayName>Name</DisplayName><Name/><Value i:type="b:string" xmlns:b="http://www.w3.org/2001/XMLSchema">Legacy Archive</Value></a:anyType>
+          <a:anyType i:type="BooleanDisplayAttribute"><DisplayName>Out Of Scope</DisplayName><Name>71f3d9aa-b8ef-4e54-8126-607a1d903103</Name><Value i:type="b:boolean" xmlns:b="http://www.w3.org/2001/XMLSchema">true</Value></a:anyType>
+          <a:anyType i:type="StringDisplayAttribute"><DisplayName>Reason For Out Of Scope</DisplayName><Name>752473b6-52d4-4776-9a24-202153f7d579</Name><Value i:type="b:string" xmlns:b="http://www.w3.org/2001/XMLSchema">Being replaced</Value></a:anyType>
+        </Properties>
+        <TypeId xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">GE.DS</TypeId>
+        <Height>100</Height><Left>600</Left><Top>300</Top><Width>100</Width>
+      </a:Value>
+    </a:KeyValueOfguidanyType>
+    <a:KeyValueOfguidanyType>
+      <a:Key>11111111-0000-0000-0000-000000000004</a:Key>
+      <a:Value i:type="BorderBoundary">
+        <GenericTypeId xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">GE.TB.B</GenericTypeId>
+        <Guid xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">11111111-0000-0000-0000-000000000004</Guid>
+        <Properties xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">
+          <a:anyType i:type="StringDisplayAttribute"><DisplayName>Name</DisplayName><Name/><Value i:type="b:string" xmlns:b="http://www.w3.org/2001/XMLSchema">Datacenter</Value></a:anyType>
+        </Properties>
+        <TypeId xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">SE.TB.B.TMCore.CorpNet</TypeId>
+        <Height>300</Height><Left>250</Left><Top>250</Top><Width>500</Width>
+      </a:Value>
+    </a:KeyValueOfguidanyType>
+    <a:KeyValueOfguidanyType>
+      <a:Key>11111111-0000-0000-0000-000000000005</a:Key>
+      <a:Value i:type="Annotation">
+        <GenericTypeId xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">GE.A</GenericTypeId>
+        <Guid xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">11111111-0000-0000-0000-000000000005</Guid>
+        <Properties xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase"/>
+        <TypeId xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">GE.A</TypeId>
+      </a:Value>
+    </a:KeyValueOfguidanyType>
+  </Borders>
+  <Lines xmlns:a="http://schemas.microsoft.com/2003/10/Serialization/Arrays">
+    <a:KeyValueOfguidanyType>
+      <a:Key>22222222-0000-0000-0000-000000000001</a:Key>
+      <a:Value i:type="Connector">
+        <GenericTypeId xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">GE.DF</GenericTypeId>
+        <Guid xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">22222222-0000-0000-0000-000000000001</Guid>
+        <Properties xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">
+          <a:anyType i:type="StringDisplayAttribute"><DisplayName>Name</DisplayName><Name/><Value i:type="b:string" xmlns:b="http://www.w3.org/2001/XMLSchema">HTTPS</Value></a:anyType>
+        </Properties>
+        <TypeId xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">SE.DF.TMCore.HTTPS</TypeId>
+        <SourceGuid>11111111-0000-0000-0000-000000000001</SourceGuid>
+        <TargetGuid>11111111-0000-0000-0000-000000000002</TargetGuid>
+      </a:Value>
+    </a:KeyValueOfguidanyType>
+    <a:KeyValueOfguidanyType>
+      <a:Key>22222222-0000-0000-0000-000000000002</a:Key>
+      <a:Value i:type="Connector">
+        <GenericTypeId xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">GE.DF</GenericTypeId>
+        <Guid xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">22222222-0000-0000-0000-000000000002</Guid>
+        <Properties xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">
+          <a:anyType i:type="StringDisplayAttribute"><DisplayName>Name</DisplayName><Name/><Value i:type="b:string" xmlns:b="http://www.w3.org/2001/XMLSchema">Generic Data Flow</Value></a:anyType>
+        </Properties>
+        <TypeId xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">GE.DF</TypeId>
+        <SourceGuid>11111111-0000-0000-0000-000000000002</SourceGuid>
+        <TargetGuid>11111111-0000-0000-0000-000000000003</TargetGuid>
+      </a:Value>
+    </a:KeyValueOfguidanyType>
+    <a:KeyValueOfguidanyType>
+      <a:Key>22222222-0000-0000-0000-000000000003</a:Key>
+      <a:Value i:type="LineBoundary">
+        <GenericTypeId xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">GE.TB.L</GenericTypeId>
+        <Guid xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">22222222-0000-0000-0000-000000000003</Guid>
+        <Properties xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">
+          <a:anyType i:type="StringDisplayAttribute"><DisplayName>Name</DisplayName><Name/><Value i:type="b:string" xmlns:b="http://www.w3.org/2001/XMLSchema">Internet Boundary</Value></a:anyType>
+        </Properties>
+        <TypeId xmlns="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">SE.TB.L.TMCore.Internet</TypeId>
+      </a:Value>
+    </a:KeyValueOfguidanyType>
+  </Lines>
+</DrawingSurfaceModel>
+</DrawingSurfaceList>
+<MetaInformation>
+  <Assumptions/><Contributors/><ExternalDependencies/>
+  <HighLevelSystemDescription/>
+  <Owner>Jane Doe</Owner>
+  <Reviewer/>
+  <ThreatModelName>Web Shop</ThreatModelName>
+</MetaInformation>
+<ThreatInstances xmlns:a="http://schemas.microsoft.com/2003/10/Serialization/Arrays">
+  <a:KeyValueOfstringThreatpc_P0_PhOB>
+    <a:Key>TH1</a:Key>
+    <a:Value xmlns:b="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">
+      <b:ChangedBy/>
+      <b:DrawingSurfaceGuid>00000000-0000-0000-0000-000000000000</b:DrawingSurfaceGuid>
+      <b:FlowGuid>22222222-0000-0000-0000-000000000001</b:FlowGuid>
+      <b:Id>1</b:Id>
+      <b:Properties>
+        <a:KeyValueOfstringstring><a:Key>Title</a:Key><a:Value>Spoofing of the Customer Browser</a:Value></a:KeyValueOfstringstring>
+        <a:KeyValueOfstringstring><a:Key>UserThreatCategory</a:Key><a:Value>Spoofing</a:Value></a:KeyValueOfstringstring>
+        <a:KeyValueOfstringstring><a:Key>UserThreatShortDescription</a:Key><a:Value>Spoofing is when a process or entity is something other than its claimed identity.</a:Value></a:KeyValueOfstringstring>
+        <a:KeyValueOfstringstring><a:Key>PossibleMitigations</a:Key><a:Value>Use strong authentication.</a:Value></a:KeyValueOfstringstring>
+        <a:KeyValueOfstringstring><a:Key>StateInformation</a:Key><a:Value>Customers log in with a second factor.</a:Value></a:KeyValueOfstringstring>
+        <a:KeyValueOfstringstring><a:Key>Priority</a:Key><a:Value>High</a:Value></a:KeyValueOfstringstring>
+      </b:Properties>
+      <b:SourceGuid>11111111-0000-0000-0000-000000000001</b:SourceGuid>
+      <b:State>Mitigated</b:State>
+      <b:TargetGuid>11111111-0000-0000-0000-000000000002</b:TargetGuid>
+      <b:TypeId>S1</b:TypeId>
+    </a:Value>
+  </a:KeyValueOfstringThreatpc_P0_PhOB>
+  <a:KeyValueOfstringThreatpc_P0_PhOB>
+    <a:Key>TH2</a:Key>
+    <a:Value xmlns:b="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">
+      <b:FlowGuid>22222222-0000-0000-0000-000000000002</b:FlowGuid>
+      <b:Id>2</b:Id>
+      <b:Properties>
+        <a:KeyValueOfstringstring><a:Key>Title</a:Key><a:Value>Data Store Inaccessible</a:Value></a:KeyValueOfstringstring>
+        <a:KeyValueOfstringstring><a:Key>UserThreatCategory</a:Key><a:Value>Denial Of Service</a:Value></a:KeyValueOfstringstring>
+        <a:KeyValueOfstringstring><a:Key>Priority</a:Key><a:Value>Medium</a:Value></a:KeyValueOfstringstring>
+      </b:Properties>
+      <b:SourceGuid>11111111-0000-0000-0000-000000000002</b:SourceGuid>
+      <b:State>AutoGenerated</b:State>
+      <b:TargetGuid>11111111-0000-0000-0000-000000000003</b:TargetGuid>
+      <b:TypeId>D1</b:TypeId>
+    </a:Value>
+  </a:KeyValueOfstringThreatpc_P0_PhOB>
+  <a:KeyValueOfstringThreatpc_P0_PhOB>
+    <a:Key>TH3</a:Key>
+    <a:Value xmlns:b="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">
+      <b:FlowGuid>99999999-0000-0000-0000-000000000000</b:FlowGuid>
+      <b:Id>3</b:Id>
+      <b:Properties>
+        <a:KeyValueOfstringstring><a:Key>Title</a:Key><a:Value>Lost Threat</a:Value></a:KeyValueOfstringstring>
+      </b:Properties>
+      <b:State>NeedsInvestigation</b:State>
+      <b:TypeId>T1</b:TypeId>
+    </a:Value>
+  </a:KeyValueOfstringThreatpc_P0_PhOB>
+</ThreatInstances>
+<KnowledgeBase xmlns:a="http://schemas.datacontract.org/2004/07/ThreatModeling.KnowledgeBase">
+  <a:ThreatTypes>
+    <a:ThreatType>
+      <a:Category>S</a:Category>
+      <a:Description>Spoofing is when a process or entity is something other than its claimed identity.</a:Description>
+      <a:Id>S1</a:Id>
+      <a:ShortTitle>Spoofing of the {source.Name} External Destination Entity</a:ShortTitle>
+    </a:ThreatType>
+  </a:ThreatTypes>
+</KnowledgeBase>
+</ThreatModel>`
+
+func TestImportTM7(t *testing.T) {
+	filename := writeTestFile(t, "shop.tm7", tm7TestFile)
+
+	stub, err := ImportTM7(filename, loadTestShapeTechnologies(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Web Shop", stub.Model.Title)
+	assert.Equal(t, "Jane Doe", stub.Model.Author.Name)
+
+	assets := stub.Model.TechnicalAssets
+	require.Len(t, assets, 3)
+	assert.Equal(t, "browser", assets["Customer Browser"].Technology)
+	assert.Equal(t, "external-entity", assets["Customer Browser"].Type)
+	assert.Equal(t, "web-application", assets["Shop"].Technology)
+	assert.Equal(t, "process", assets["Shop"].Type)
+	assert.Equal(t, "database", assets["Legacy Archive"].Technology)
+	assert.Equal(t, "datastore", assets["Legacy Archive"].Type)
+	assert.True(t, assets["Legacy Archive"].OutOfScope)
+	assert.Equal(t, "Being replaced", assets["Legacy Archive"].JustificationOutOfScope)
+
+	assert.Equal(t, "https", assets["Customer Browser"].CommunicationLinks["Traffic to Shop"].Protocol)
+	assert.Equal(t, "unknown-protocol", assets["Shop"].CommunicationLinks["Traffic to Legacy Archive"].Protocol)
+
+	require.Len(t, stub.Model.TrustBoundaries, 1)
+	assert.Equal(t, []string{"legacy-archive", "shop"}, stub.Model.TrustBoundaries["Datacenter"].TechnicalAssetsInside)
+
+	categories := stub.Model.CustomRiskCategories
+	require.Len(t, categories, 2)
+	assert.Equal(t, "tmt-s1", categories[0].ID)
+	assert.Equal(t, "Spoofing of the source External Destination Entity", categories[0].Title)
+	assert.Equal(t, "spoofing", categories[0].STRIDE)
+	assert.Equal(t, "Use strong authentication.", categories[0].Mitigation)
+	assert.Equal(t, input.RiskIdentified{
+		Severity:                      "high",
+		ExploitationLikelihood:        "likely",
+		ExploitationImpact:            "high",
+		DataBreachProbability:         "possible",
+		MostRelevantTechnicalAsset:    "customer-browser",
+		MostRelevantCommunicationLink: "customer-browser>traffic-to-shop",
+	}, categories[0].RisksIdentified["Spoofing of the Customer Browser"])
+	assert.Equal(t, "Data Store Inaccessible", categories[1].Title)
+	assert.Equal(t, "denial-of-service", categories[1].STRIDE)
+
+	assert.Equal(t, map[string]input.RiskTracking{
+		"tmt-s1@customer-browser@customer-browser>traffic-to-shop": {Status: "mitigated", Justification: "Customers log in with a second factor."},
+	}, stub.Model.RiskTracking)
+
+	require.Len(t, stub.Log, 3)
+	assert.Contains(t, stub.Log[0], "GE.A")
+	assert.Contains(t, stub.Log[1], "Internet Boundary")
+	assert.Contains(t, stub.Log[2], "Lost Threat")
+}
+
+func TestImportTM7ModelParses(t *testing.T) {
+	filename := writeTestFile(t, "shop.tm7", tm7TestFile)
+
+	stub, err := ImportTM7(filename, loadTestShapeTechnologies(t))
+	require.NoError(t, err)
+
+	content, err := stub.Marshal()
+	require.NoError(t, err)
+
+	modelInput := new(input.Model).Defaults()
+	require.NoError(t, yaml.Unmarshal(content, modelInput))
+
+	parsedModel, err := model.ParseModel(&common.Config{}, modelInput, make(types.RiskRules), make(types.RiskRules))
+	require.NoError(t, err)
+	assert.Len(t, parsedModel.TechnicalAssets, 3)
+	assert.Len(t, parsedModel.GeneratedRisksByCategory["tmt-s1"], 1)
+	assert.Equal(t, types.Mitigated, parsedModel.RiskTracking["tmt-s1@customer-browser@customer-browser>traffic-to-shop"].Status)
+}
+
+func TestImportTM7NoThreatModel(t *testing.T) {
+	filename := writeTestFile(t, "other.tm7", `<mxfile/>`)
+
+	_, err := ImportTM7(filename, loadTestShapeTechnologies(t))
+	assert.ErrorContains(t, err, "not a threat model")
+}
@@ -17,6 +17,7 @@ type CommunicationLink struct {
 	DataAssetsReceived     []string `yaml:"data_assets_received,omitempty" json:"data_assets_received,omitempty"`
 	DiagramTweakWeight     int      `yaml:"diagram_tweak_weight,omitempty" json:"diagram_tweak_weight,omitempty"`
 	DiagramTweakConstraint bool     `yaml:"diagram_tweak_constraint,omitempty" json:"diagram_tweak_constraint,omitempty"`
+	Position               Position `yaml:"-" json:"-"`
 }
 
 func (what *CommunicationLink) Merge(other CommunicationLink) error {
@@ -14,6 +14,7 @@ type DataAsset struct {
 	Integrity              string   `yaml:"integrity,omitempty" json:"integrity,omitempty"`
 	Availability           string   `yaml:"availability,omitempty" json:"availability,omitempty"`
 	JustificationCiaRating string   `yaml:"justification_cia_rating,omitempty" json:"justification_cia_rating,omitempty"`
+	Position               Position `yaml:"-" json:"-"`
 }
 
 func (what *DataAsset) Merge(other DataAsset) error {
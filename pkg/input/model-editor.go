@@ -0,0 +1,477 @@
+package input
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ModelEditor edits a model file and the files it includes at the level of their YAML nodes: only the lines of the
+// values edited or added change, while the comments, key order, anchors and formatting of everything else stay as they
+// are. Edits go to the file holding what they change; new entries go to the first file with their section, or to the
+// model file itself.
+
+type ModelEditor struct {
+	Filename string
+	files    []*modelFileEditor
+}
+
+// idReferences are the keys whose values (or items) reference ids
+
+var idReferences = map[string]bool{
+	"id":                               true,
+	"target":                           true,
+	"data_assets_processed":            true,
+	"data_assets_stored":               true,
+	"data_assets_sent":                 true,
+	"data_assets_received":             true,
+	"technical_assets_inside":          true,
+	"trust_boundaries_nested":          true,
+	"technical_assets_running":         true,
+	"data_breach_technical_assets":     true,
+	"most_relevant_data_asset":         true,
+	"most_relevant_technical_asset":    true,
+	"most_relevant_communication_link": true,
+	"most_relevant_trust_boundary":     true,
+	"most_relevant_shared_runtime":     true,
+	"diagram_tweak_same_rank_assets":   true,
+}
+
+func (what *ModelEditor) Load(filename string) error {
+	what.Filename = filename
+	what.files = make([]*modelFileEditor, 0)
+	return what.load(filename, make(map[string]bool))
+}
+
+func (what *ModelEditor) load(filename string, loaded map[string]bool) error {
+	if loaded[filepath.Clean(filename)] {
+		return nil
+	}
+	loaded[filepath.Clean(filename)] = true
+
+	file := new(modelFileEditor)
+	loadError := file.load(filename)
+	if loadError != nil {
+		return loadError
+	}
+	what.files = append(what.files, file)
+
+	_, includes := file.node("includes")
+	if includes == nil || includes.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	for _, include := range includes.Content {
+		includeError := what.load(filepath.Join(filepath.Dir(filename), include.Value), loaded)
+		if includeError != nil {
+			return fmt.Errorf("unable to load model include %q: %v", include.Value, includeError)
+		}
+	}
+
+	return nil
+}
+
+// ModifiedFiles returns the names of the files edited since loading or saving them
+
+func (what *ModelEditor) ModifiedFiles() []string {
+	filenames := make([]string, 0)
+	for _, file := range what.files {
+		if file.modified {
+			filenames = append(filenames, file.filename)
+		}
+	}
+
+	return filenames
+}
+
+// Save writes the files edited
+
+func (what *ModelEditor) Save() error {
+	for _, file := range what.files {
+		if file.modified {
+			saveError := file.save()
+			if saveError != nil {
+				return saveError
+			}
+		}
+	}
+
+	return nil
+}
+
+// CheckWritable tells whether the files can be written, without changing them
+
+func (what *ModelEditor) CheckWritable() error {
+	for _, file := range what.files {
+		handle, openError := os.OpenFile(filepath.Clean(file.filename), os.O_WRONLY, 0600)
+		if openError != nil {
+			return fmt.Errorf("unable to write model file: %v", openError)
+		}
+		_ = handle.Close()
+	}
+
+	return nil
+}
+
+// HasRiskTracking tells whether there is a risk tracking of the given (possibly wildcard) synthetic risk id
+
+func (what *ModelEditor) HasRiskTracking(syntheticRiskId string) bool {
+	return what.fileWith("risk_tracking", syntheticRiskId) != nil
+}
+
+// SetRiskTracking sets the non-empty fields of the risk tracking of the given (possibly wildcard) synthetic risk id,
+// adding the risk tracking, and the risk_tracking section, if missing
+
+func (what *ModelEditor) SetRiskTracking(syntheticRiskId string, tracking RiskTracking) error {
+	path := []string{"risk_tracking", syntheticRiskId}
+	file := what.fileFor(path...)
+	fields := tracking.fields()
+
+	_, entry := file.node(path...)
+	if entry == nil || isEmptyNode(entry) {
+		mapping := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, field := range fields {
+			mapping.Content = append(mapping.Content, stringNode(field[0]), stringNode(field[1]))
+		}
+		return file.setValue(path, mapping)
+	}
+
+	for _, field := range fields {
+		setError := file.setScalar(append(path, field[0]), stringNode(field[1]))
+		if setError != nil {
+			return fmt.Errorf("unable to set %v of risk tracking %q: %v", field[0], syntheticRiskId, setError)
+		}
+	}
+
+	return nil
+}
+
+func (what *ModelEditor) AddDataAsset(title string, asset DataAsset) error {
+	return what.addAsset("data_assets", title, asset.ID, asset)
+}
+
+func (what *ModelEditor) AddTechnicalAsset(title string, asset TechnicalAsset) error {
+	return what.addAsset("technical_assets", title, asset.ID, asset)
+}
+
+func (what *ModelEditor) AddTrustBoundary(title string, boundary TrustBoundary) error {
+	return what.addAsset("trust_boundaries", title, boundary.ID, boundary)
+}
+
+func (what *ModelEditor) AddSharedRuntime(title string, runtime SharedRuntime) error {
+	return what.addAsset("shared_runtimes", title, runtime.ID, runtime)
+}
+
+func (what *ModelEditor) addAsset(section string, title string, id string, asset any) error {
+	if what.fileWith(section, title) != nil {
+		return fmt.Errorf("%v already has %q", section, title)
+	}
+	if len(id) > 0 && what.findId(id) != nil {
+		return fmt.Errorf("id %q is already in use", id)
+	}
+
+	var node yaml.Node
+	encodeError := node.Encode(asset)
+	if encodeError != nil {
+		return fmt.Errorf("unable to encode %q: %v", title, encodeError)
+	}
+
+	return what.fileFor(section, title).setValue([]string{section, title}, &node)
+}
+
+// RenameId renames an id of an asset, a trust boundary, a shared runtime or a risk category, along with the references
+// to it, including those in synthetic risk ids of risk tracking
+
+func (what *ModelEditor) RenameId(oldId string, newId string) error {
+	if oldId == newId {
+		return nil
+	}
+	if what.findId(oldId) == nil {
+		return fmt.Errorf("unknown id %q", oldId)
+	}
+	if what.findId(newId) != nil {
+		return fmt.Errorf("id %q is already in use", newId)
+	}
+
+	for _, file := range what.files {
+		for {
+			node, value := findIdReference(file.root, "", false, oldId, newId)
+			if node == nil {
+				break
+			}
+
+			replaceError := file.replaceScalar(node, value)
+			if replaceError != nil {
+				return fmt.Errorf("unable to rename %q: %v", oldId, replaceError)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ApplyChanges edits the files by the differences between the model before and after changing it (as loaded from
+// them, with its includes merged), like a macro does: changed scalars are set, added entries added and removed ones
+// removed, and scalars added to or removed from sequences added to the first file with the sequence, or removed from
+// the files holding them
+
+func (what *ModelEditor) ApplyChanges(before *Model, after *Model) error {
+	var beforeNode, afterNode yaml.Node
+	encodeError := beforeNode.Encode(before)
+	if encodeError != nil {
+		return fmt.Errorf("unable to encode model: %v", encodeError)
+	}
+	encodeError = afterNode.Encode(after)
+	if encodeError != nil {
+		return fmt.Errorf("unable to encode model: %v", encodeError)
+	}
+
+	return what.applyMapping(nil, &beforeNode, &afterNode)
+}
+
+func (what *ModelEditor) applyMapping(path []string, before *yaml.Node, after *yaml.Node) error {
+	for i := 0; i+1 < len(after.Content); i += 2 {
+		name, value := after.Content[i].Value, after.Content[i+1]
+		entryPath := append(append(make([]string, 0, len(path)+1), path...), name)
+
+		var applyError error
+		_, previous := mappingEntry(before, name)
+		switch {
+		case previous == nil:
+			applyError = what.fileFor(entryPath...).setValue(entryPath, value)
+
+		case previous.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+			applyError = what.applyMapping(entryPath, previous, value)
+
+		case previous.Kind == yaml.SequenceNode && value.Kind == yaml.SequenceNode && isScalarSequence(previous) && isScalarSequence(value):
+			applyError = what.applySequence(entryPath, previous, value)
+
+		case !equalNodes(previous, value):
+			applyError = what.fileFor(entryPath...).setValue(entryPath, value)
+		}
+
+		if applyError != nil {
+			return fmt.Errorf("unable to change %v: %v", strings.Join(entryPath, "."), applyError)
+		}
+	}
+
+	for i := 0; i+1 < len(before.Content); i += 2 {
+		name := before.Content[i].Value
+		if key, _ := mappingEntry(after, name); key != nil {
+			continue
+		}
+
+		entryPath := append(append(make([]string, 0, len(path)+1), path...), name)
+		if file := what.fileWith(entryPath...); file != nil {
+			removeError := file.removeEntry(entryPath)
+			if removeError != nil {
+				return fmt.Errorf("unable to remove %v: %v", strings.Join(entryPath, "."), removeError)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (what *ModelEditor) applySequence(path []string, before *yaml.Node, after *yaml.Node) error {
+	for _, item := range before.Content {
+		if containsScalar(after, item.Value) {
+			continue
+		}
+
+		for _, file := range what.files {
+			removeError := file.removeFromSequence(path, item.Value)
+			if removeError != nil {
+				return removeError
+			}
+		}
+	}
+
+	added := make([]*yaml.Node, 0)
+	for _, item := range after.Content {
+		if !containsScalar(before, item.Value) {
+			added = append(added, item)
+		}
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	return what.fileFor(path...).appendToSequence(path, added)
+}
+
+// fileWith returns the first file with a value at the path, or nil if there is none
+
+func (what *ModelEditor) fileWith(path ...string) *modelFileEditor {
+	for _, file := range what.files {
+		if _, node := file.node(path...); node != nil {
+			return file
+		}
+	}
+
+	return nil
+}
+
+// fileFor returns the file to set the value at the path in: the first file with the value or its closest parent, or
+// the model file itself
+
+func (what *ModelEditor) fileFor(path ...string) *modelFileEditor {
+	for n := len(path); n > 0; n-- {
+		if file := what.fileWith(path[:n]...); file != nil {
+			return file
+		}
+	}
+
+	return what.files[0]
+}
+
+// findId returns the value of the id key defining the id, or nil if there is none
+
+func (what *ModelEditor) findId(id string) *yaml.Node {
+	for _, file := range what.files {
+		if definition := findIdDefinition(file.root, "", id); definition != nil {
+			return definition
+		}
+	}
+
+	return nil
+}
+
+func findIdDefinition(node *yaml.Node, key string, id string) *yaml.Node {
+	switch node.Kind {
+	case yaml.MappingNode, yaml.SequenceNode:
+		for i, child := range node.Content {
+			childKey := key
+			if node.Kind == yaml.MappingNode {
+				if i%2 == 0 {
+					continue
+				}
+				childKey = node.Content[i-1].Value
+			}
+			if found := findIdDefinition(child, childKey, id); found != nil {
+				return found
+			}
+		}
+
+	case yaml.ScalarNode:
+		if key == "id" && node.Value == id {
+			return node
+		}
+	}
+
+	return nil
+}
+
+// findIdReference returns the first scalar referencing the old id, and its value with the new id instead
+
+func findIdReference(node *yaml.Node, key string, riskTracking bool, oldId string, newId string) (*yaml.Node, string) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			name := node.Content[i]
+			if riskTracking {
+				if renamed := renameIdParts(name.Value, oldId, newId, "@", ">"); renamed != name.Value {
+					return name, renamed
+				}
+			}
+
+			found, value := findIdReference(node.Content[i+1], name.Value, key == "" && name.Value == "risk_tracking", oldId, newId)
+			if found != nil {
+				return found, value
+			}
+		}
+
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			if found, value := findIdReference(item, key, false, oldId, newId); found != nil {
+				return found, value
+			}
+		}
+
+	case yaml.ScalarNode:
+		switch {
+		case idReferences[key] && node.Value == oldId:
+			return node, newId
+
+		case key == "diagram_tweak_invisible_connections_between_assets":
+			if renamed := renameIdParts(node.Value, oldId, newId, ":"); renamed != node.Value {
+				return node, renamed
+			}
+		}
+	}
+
+	return nil, ""
+}
+
+// renameIdParts replaces the parts of the value, split by the separators in turn, that are the old id
+
+func renameIdParts(value string, oldId string, newId string, separators ...string) string {
+	if value == oldId {
+		return newId
+	}
+	if len(separators) == 0 {
+		return value
+	}
+
+	parts := strings.Split(value, separators[0])
+	for n, part := range parts {
+		parts[n] = renameIdParts(part, oldId, newId, separators[1:]...)
+	}
+
+	return strings.Join(parts, separators[0])
+}
+
+// fields returns the non-empty fields of the risk tracking, by their name in the model file
+
+func (what *RiskTracking) fields() [][2]string {
+	fields := make([][2]string, 0)
+	for _, field := range [][2]string{
+		{"status", what.Status},
+		{"justification", what.Justification},
+		{"ticket", what.Ticket},
+		{"date", what.Date},
+		{"checked_by", what.CheckedBy},
+		{"owner", what.Owner},
+		{"expires", what.Expires},
+		{"review_by", what.ReviewBy},
+	} {
+		if len(field[1]) > 0 {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+func stringNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+func isScalarSequence(node *yaml.Node) bool {
+	for _, item := range node.Content {
+		if item.Kind != yaml.ScalarNode {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsScalar(sequence *yaml.Node, value string) bool {
+	for _, item := range sequence.Content {
+		if item.Value == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func equalNodes(left *yaml.Node, right *yaml.Node) bool {
+	leftData, leftError := yaml.Marshal(left)
+	rightData, rightError := yaml.Marshal(right)
+	return leftError == nil && rightError == nil && string(leftData) == string(rightData)
+}
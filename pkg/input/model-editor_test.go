@@ -0,0 +1,333 @@
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelEditor(t *testing.T) {
+	crlf := func(text string) string { return strings.ReplaceAll(text, "\n", "\r\n") }
+
+	for _, test := range []struct {
+		name     string
+		files    map[string]string // by name, the model file being model.yaml
+		edit     func(editor *ModelEditor) error
+		expected map[string]string // the files changed
+	}{
+		{
+			name: "comments",
+			files: map[string]string{"model.yaml": `# the shop
+title: Shop # of the team
+
+risk_tracking:
+  # accepted for now
+  missing-waf@web:
+    status: accepted # see below
+    justification: behind a CDN
+`},
+			edit: func(editor *ModelEditor) error {
+				return editor.SetRiskTracking("missing-waf@web", RiskTracking{Status: "mitigated", Ticket: "SEC-1"})
+			},
+			expected: map[string]string{"model.yaml": `# the shop
+title: Shop # of the team
+
+risk_tracking:
+  # accepted for now
+  missing-waf@web:
+    status: mitigated # see below
+    justification: behind a CDN
+    ticket: SEC-1
+`},
+		},
+		{
+			name: "anchors and aliases",
+			files: map[string]string{"model.yaml": `technical_assets:
+  Web Server:
+    id: web
+    data_assets_processed: &processed
+      - customers
+      - orders
+  App Server:
+    id: app
+    data_assets_processed: *processed
+
+data_assets:
+  Customers:
+    id: customers
+  Orders:
+    id: orders
+`},
+			edit: func(editor *ModelEditor) error { return editor.RenameId("customers", "clients") },
+			expected: map[string]string{"model.yaml": `technical_assets:
+  Web Server:
+    id: web
+    data_assets_processed: &processed
+      - clients
+      - orders
+  App Server:
+    id: app
+    data_assets_processed: *processed
+
+data_assets:
+  Customers:
+    id: clients
+  Orders:
+    id: orders
+`},
+		},
+		{
+			name: "flow sequences",
+			files: map[string]string{"model.yaml": `technical_assets:
+  Web Server:
+    id: web
+    data_assets_processed: [customers, "orders", 'invoices']
+    data_assets_stored: ["customers"]
+
+data_assets:
+  Customers:
+    id: customers
+  Orders:
+    id: orders
+  Invoices:
+    id: invoices
+`},
+			edit: func(editor *ModelEditor) error {
+				renameError := editor.RenameId("orders", "purchases")
+				if renameError != nil {
+					return renameError
+				}
+				return editor.RenameId("customers", "clients")
+			},
+			expected: map[string]string{"model.yaml": `technical_assets:
+  Web Server:
+    id: web
+    data_assets_processed: [clients, "purchases", 'invoices']
+    data_assets_stored: ["clients"]
+
+data_assets:
+  Customers:
+    id: clients
+  Orders:
+    id: purchases
+  Invoices:
+    id: invoices
+`},
+		},
+		{
+			name: "block scalars",
+			files: map[string]string{"model.yaml": `risk_tracking:
+  missing-waf@web:
+    status: accepted
+    justification: |
+      behind a CDN
+
+      - which filters requests
+    checked_by: Alice
+`},
+			edit: func(editor *ModelEditor) error {
+				return editor.SetRiskTracking("missing-waf@web", RiskTracking{Justification: "no longer behind a CDN", CheckedBy: "Bob"})
+			},
+			expected: map[string]string{"model.yaml": `risk_tracking:
+  missing-waf@web:
+    status: accepted
+    justification: no longer behind a CDN
+    checked_by: Bob
+`},
+		},
+		{
+			name: "crlf",
+			files: map[string]string{"model.yaml": crlf(`title: Shop
+
+risk_tracking:
+  missing-waf@web:
+    status: accepted
+`)},
+			edit: func(editor *ModelEditor) error {
+				return editor.SetRiskTracking("missing-vault@web", RiskTracking{Status: "unchecked"})
+			},
+			expected: map[string]string{"model.yaml": crlf(`title: Shop
+
+risk_tracking:
+  missing-waf@web:
+    status: accepted
+  missing-vault@web:
+    status: unchecked
+`)},
+		},
+		{
+			name: "rename in risk tracking and diagram tweaks",
+			files: map[string]string{"model.yaml": `includes:
+  - risk-tracking.yaml
+
+technical_assets:
+  Web Server:
+    id: web
+    communication_links:
+      Database Traffic:
+        target: db
+  Database:
+    id: db
+
+diagram_tweak_same_rank_assets: [web, db]
+diagram_tweak_invisible_connections_between_assets:
+  - web:db # keep them apart
+`,
+				"risk-tracking.yaml": `risk_tracking:
+  missing-waf@web:
+    status: accepted
+  unencrypted-communication@web>database-traffic@web@db:
+    status: mitigated
+  missing-webserver@*:
+    status: false-positive
+`},
+			edit: func(editor *ModelEditor) error { return editor.RenameId("web", "shop") },
+			expected: map[string]string{"model.yaml": `includes:
+  - risk-tracking.yaml
+
+technical_assets:
+  Web Server:
+    id: shop
+    communication_links:
+      Database Traffic:
+        target: db
+  Database:
+    id: db
+
+diagram_tweak_same_rank_assets: [shop, db]
+diagram_tweak_invisible_connections_between_assets:
+  - shop:db # keep them apart
+`,
+				"risk-tracking.yaml": `risk_tracking:
+  missing-waf@shop:
+    status: accepted
+  unencrypted-communication@shop>database-traffic@shop@db:
+    status: mitigated
+  missing-webserver@*:
+    status: false-positive
+`},
+		},
+		{
+			name: "changes across an include",
+			files: map[string]string{"model.yaml": `title: Shop
+includes: [data-assets.yaml]
+
+tags_available: [pii]
+`,
+				"data-assets.yaml": `# the data of the shop
+data_assets:
+  Customers:
+    id: customers
+    description: Customers of the shop # the registered ones
+    tags: [pii]
+`},
+			edit: func(editor *ModelEditor) error {
+				before := new(Model).Defaults()
+				loadError := before.Load(filepath.Join(filepath.Dir(editor.Filename), "model.yaml"))
+				if loadError != nil {
+					return loadError
+				}
+
+				after, cloneError := before.Clone()
+				if cloneError != nil {
+					return cloneError
+				}
+
+				customers := after.DataAssets["Customers"]
+				customers.Description = "Customers and prospects of the shop"
+				customers.Tags = append(customers.Tags, "gdpr")
+				after.DataAssets["Customers"] = customers
+				after.TagsAvailable = append(after.TagsAvailable, "gdpr")
+
+				return editor.ApplyChanges(before, after)
+			},
+			expected: map[string]string{"model.yaml": `title: Shop
+includes: [data-assets.yaml]
+
+tags_available: [pii, gdpr]
+`,
+				"data-assets.yaml": `# the data of the shop
+data_assets:
+  Customers:
+    id: customers
+    description: Customers and prospects of the shop # the registered ones
+    tags: [pii, gdpr]
+`},
+		},
+		{
+			name: "comment-only include",
+			files: map[string]string{"model.yaml": `title: Shop
+includes:
+  - diagram-tweaks.yaml
+`,
+				"diagram-tweaks.yaml": `# diagram tweaks go here
+#diagram_tweak_nodesep: 2
+`},
+			edit: func(editor *ModelEditor) error {
+				return editor.SetRiskTracking("missing-waf@web", RiskTracking{Status: "unchecked"})
+			},
+			expected: map[string]string{"model.yaml": `title: Shop
+includes:
+  - diagram-tweaks.yaml
+risk_tracking:
+  missing-waf@web:
+    status: unchecked
+`},
+		},
+		{
+			name: "comment-only model file",
+			files: map[string]string{"model.yaml": `# to be done
+`},
+			edit: func(editor *ModelEditor) error {
+				return editor.AddDataAsset("Customers", DataAsset{ID: "customers", Usage: "business"})
+			},
+			expected: map[string]string{"model.yaml": `# to be done
+
+data_assets:
+  Customers:
+    id: customers
+    usage: business
+`},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			folder := t.TempDir()
+			for name, content := range test.files {
+				require.NoError(t, os.WriteFile(filepath.Join(folder, name), []byte(content), 0600))
+			}
+
+			editor := new(ModelEditor)
+			require.NoError(t, editor.Load(filepath.Join(folder, "model.yaml")))
+			require.NoError(t, test.edit(editor))
+			require.NoError(t, editor.Save())
+
+			for name, content := range test.files {
+				expected, changed := test.expected[name]
+				if !changed {
+					expected = content
+				}
+
+				data, readError := os.ReadFile(filepath.Join(folder, name))
+				require.NoError(t, readError)
+				assert.Equal(t, expected, string(data), name)
+			}
+		})
+	}
+}
+
+func TestModelEditorRenameIdErrors(t *testing.T) {
+	folder := t.TempDir()
+	filename := filepath.Join(folder, "model.yaml")
+	require.NoError(t, os.WriteFile(filename, []byte("data_assets:\n  Customers:\n    id: customers\n  Orders:\n    id: orders\n"), 0600))
+
+	editor := new(ModelEditor)
+	require.NoError(t, editor.Load(filename))
+
+	assert.ErrorContains(t, editor.RenameId("invoices", "bills"), "unknown id")
+	assert.ErrorContains(t, editor.RenameId("customers", "orders"), "already in use")
+	assert.Empty(t, editor.ModifiedFiles())
+}
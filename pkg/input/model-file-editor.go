@@ -0,0 +1,613 @@
+package input
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// modelFileEditor edits the lines of a single model file: each edit replaces the lines of one value and parses the
+// file again, so the nodes always match the lines
+
+type modelFileEditor struct {
+	filename string
+	lines    []string
+	newline  string
+	root     *yaml.Node
+	modified bool
+}
+
+func (what *modelFileEditor) load(filename string) error {
+	data, readError := os.ReadFile(filepath.Clean(filename))
+	if readError != nil {
+		return fmt.Errorf("unable to read model file: %v", readError)
+	}
+
+	what.filename = filename
+	what.newline = "\n"
+	if strings.Contains(string(data), "\r\n") {
+		what.newline = "\r\n"
+	}
+	what.lines = strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+
+	return what.parse()
+}
+
+func (what *modelFileEditor) bytes() []byte {
+	return []byte(strings.Join(what.lines, what.newline))
+}
+
+func (what *modelFileEditor) save() error {
+	writeError := os.WriteFile(filepath.Clean(what.filename), what.bytes(), 0600)
+	if writeError != nil {
+		return fmt.Errorf("error writing %s: %v", what.filename, writeError)
+	}
+
+	what.modified = false
+	return nil
+}
+
+func (what *modelFileEditor) parse() error {
+	var document yaml.Node
+	unmarshalError := yaml.Unmarshal(what.bytes(), &document)
+	if unmarshalError != nil {
+		return fmt.Errorf("unable to parse model file %q: %v", what.filename, unmarshalError)
+	}
+
+	what.root = nil
+	if document.Kind == yaml.DocumentNode && len(document.Content) > 0 {
+		what.root = document.Content[0]
+	}
+	if what.root == nil {
+		// an empty file, or one of comments only (like an include waiting for content), is an empty mapping
+		what.root = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	}
+	if what.root.Kind != yaml.MappingNode {
+		return fmt.Errorf("model file %q is no YAML mapping", what.filename)
+	}
+
+	return nil
+}
+
+// edit replaces the lines from first to last (0-based, last exclusive) and parses the result, undoing the edit if the
+// file does not parse any more
+
+func (what *modelFileEditor) edit(first int, last int, lines []string) error {
+	previous := what.lines
+	edited := make([]string, 0, len(what.lines)+len(lines))
+	edited = append(edited, what.lines[:first]...)
+	edited = append(edited, lines...)
+	edited = append(edited, what.lines[last:]...)
+	what.lines = edited
+
+	parseError := what.parse()
+	if parseError != nil {
+		what.lines = previous
+		_ = what.parse()
+		return fmt.Errorf("edit would break the model file: %v", parseError)
+	}
+
+	what.modified = true
+	return nil
+}
+
+// node returns the key and value node at the path of mapping keys, or nil if there is none
+
+func (what *modelFileEditor) node(path ...string) (*yaml.Node, *yaml.Node) {
+	var key *yaml.Node
+	value := what.root
+	for _, name := range path {
+		if value == nil || value.Kind != yaml.MappingNode {
+			return nil, nil
+		}
+
+		key, value = mappingEntry(value, name)
+	}
+
+	return key, value
+}
+
+func mappingEntry(mapping *yaml.Node, name string) (*yaml.Node, *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == name {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+
+	return nil, nil
+}
+
+// ensureMapping adds the keys of the path missing, with an empty value
+
+func (what *modelFileEditor) ensureMapping(path ...string) error {
+	for n := 1; n <= len(path); n++ {
+		_, value := what.node(path[:n]...)
+		switch {
+		case value == nil:
+			insertError := what.insertIntoMapping(path[:n-1], []string{formatKey(path[n-1]) + ":"})
+			if insertError != nil {
+				return insertError
+			}
+
+		case value.Kind != yaml.MappingNode && !isEmptyNode(value):
+			return fmt.Errorf("%v is no mapping", strings.Join(path[:n], "."))
+		}
+	}
+
+	return nil
+}
+
+// setValue sets the value at the path, adding the key (and the mappings of the path) if missing; scalars are replaced
+// in place, keeping their comment, other values are replaced along with their key
+
+func (what *modelFileEditor) setValue(path []string, value *yaml.Node) error {
+	key, node := what.node(path...)
+	if node == nil {
+		ensureError := what.ensureMapping(path[:len(path)-1]...)
+		if ensureError != nil {
+			return ensureError
+		}
+		return what.insertIntoMapping(path[:len(path)-1], what.renderEntry(path[len(path)-1], value))
+	}
+
+	if value.Kind == yaml.ScalarNode && node.Kind == yaml.ScalarNode {
+		return what.setScalar(path, value)
+	}
+
+	first, last := key.Line-1, what.endLine(node, key.Column-1)
+	lines := what.renderEntry(path[len(path)-1], value)
+	if comment := lineComment(key, node); len(comment) > 0 && first+1 == last {
+		lines[0] += " " + comment
+	}
+
+	replaced := prefixLines(what.lines[first][:key.Column-1], lines[:1])
+	return what.edit(first, last, append(replaced, prefixLines(strings.Repeat(" ", key.Column-1), lines[1:])...))
+}
+
+// setScalar sets the scalar value at the path, adding the key to its (existing) mapping if missing
+
+func (what *modelFileEditor) setScalar(path []string, value *yaml.Node) error {
+	key, node := what.node(path...)
+	if node == nil {
+		return what.insertIntoMapping(path[:len(path)-1], []string{formatKey(path[len(path)-1]) + ": " + what.renderScalar(value, what.indentStep())})
+	}
+
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("%v is no scalar", strings.Join(path, "."))
+	}
+
+	if node.Value == value.Value && !isEmptyNode(node) {
+		return nil
+	}
+
+	indent := strings.Repeat(" ", key.Column-1)
+	if isEmptyNode(node) && len(node.Value) == 0 {
+		content, comment := splitLineComment(what.lines[key.Line-1], key.Column-1, lineComment(key, node))
+		return what.edit(key.Line-1, key.Line, strings.Split(strings.TrimRight(content, " \t")+" "+what.renderScalar(value, indent+what.indentStep())+comment, "\n"))
+	}
+
+	first, last := node.Line-1, what.endLine(node, key.Column-1)
+	line, comment := what.lines[first], ""
+	if first+1 == last {
+		line, comment = splitLineComment(line, node.Column-1, lineComment(key, node))
+	}
+
+	return what.edit(first, last, strings.Split(line[:node.Column-1]+what.renderScalar(value, indent+what.indentStep())+comment, "\n"))
+}
+
+// replaceScalar replaces the text of a single-line scalar (like a key or an item of a flow sequence), keeping its
+// quoting style
+
+func (what *modelFileEditor) replaceScalar(node *yaml.Node, value string) error {
+	line := what.lines[node.Line-1]
+	start, end := node.Column-1, -1
+	if start > len(line) {
+		return fmt.Errorf("unable to replace %q in line %d of %v", node.Value, node.Line, what.filename)
+	}
+
+	var text string
+	switch node.Style &^ yaml.TaggedStyle {
+	case yaml.DoubleQuotedStyle:
+		for index := start + 1; index < len(line); index++ {
+			if line[index] == '\\' {
+				index++
+			} else if line[index] == '"' {
+				end = index + 1
+				break
+			}
+		}
+		quoted, _ := json.Marshal(value)
+		text = string(quoted)
+
+	case yaml.SingleQuotedStyle:
+		for index := start + 1; index < len(line); index++ {
+			if line[index] == '\'' {
+				if index+1 < len(line) && line[index+1] == '\'' {
+					index++
+					continue
+				}
+				end = index + 1
+				break
+			}
+		}
+		text = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+
+	case 0:
+		if strings.HasPrefix(line[start:], node.Value) {
+			end = start + len(node.Value)
+		}
+		text = formatScalar(value, "")
+	}
+
+	if end < 0 {
+		return fmt.Errorf("unable to replace %q in line %d of %v", node.Value, node.Line, what.filename)
+	}
+
+	return what.edit(node.Line-1, node.Line, []string{line[:start] + text + line[end:]})
+}
+
+// removeEntry removes the key at the path along with its value
+
+func (what *modelFileEditor) removeEntry(path []string) error {
+	key, node := what.node(path...)
+	if node == nil {
+		return nil
+	}
+
+	first, last := key.Line-1, what.endLine(node, key.Column-1)
+	if first > 0 && len(strings.TrimSpace(what.lines[first-1])) == 0 && (last >= len(what.lines) || len(strings.TrimSpace(what.lines[last])) == 0) {
+		first--
+	}
+
+	return what.edit(first, last, nil)
+}
+
+// appendToSequence adds the scalars to the end of the sequence at the path, adding the key if missing
+
+func (what *modelFileEditor) appendToSequence(path []string, items []*yaml.Node) error {
+	_, sequence := what.node(path...)
+	if sequence == nil || isEmptyNode(sequence) || sequence.Kind != yaml.SequenceNode || len(sequence.Content) == 0 || sequence.Style&yaml.FlowStyle != 0 {
+		merged := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if sequence != nil && sequence.Kind == yaml.SequenceNode {
+			merged.Style = sequence.Style
+			merged.Content = append(merged.Content, sequence.Content...)
+		}
+		merged.Content = append(merged.Content, items...)
+		return what.setValue(path, plainNode(merged))
+	}
+
+	last := sequence.Content[len(sequence.Content)-1]
+	line := what.lines[last.Line-1]
+	dashIndent := len(line) - len(strings.TrimLeft(line, " \t"))
+	if !strings.HasPrefix(line[dashIndent:], "-") {
+		return fmt.Errorf("unable to add to %v in line %d of %v", strings.Join(path, "."), last.Line, what.filename)
+	}
+
+	lines := make([]string, 0)
+	for _, item := range items {
+		lines = append(lines, line[:dashIndent]+"- "+what.renderScalar(item, line[:dashIndent]+"  "))
+	}
+
+	at := what.endLine(last, dashIndent)
+	return what.edit(at, at, prefixLines("", lines))
+}
+
+// removeFromSequence removes the scalars with the value from the sequence at the path
+
+func (what *modelFileEditor) removeFromSequence(path []string, value string) error {
+	for {
+		_, sequence := what.node(path...)
+		if sequence == nil || sequence.Kind != yaml.SequenceNode {
+			return nil
+		}
+
+		index := -1
+		for n, item := range sequence.Content {
+			if item.Kind == yaml.ScalarNode && item.Value == value {
+				index = n
+				break
+			}
+		}
+		if index < 0 {
+			return nil
+		}
+
+		item := sequence.Content[index]
+		line := what.lines[item.Line-1]
+		dashIndent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if sequence.Style&yaml.FlowStyle != 0 || len(sequence.Content) == 1 || !strings.HasPrefix(line[dashIndent:], "-") {
+			remaining := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: sequence.Style}
+			remaining.Content = append(remaining.Content, sequence.Content[:index]...)
+			remaining.Content = append(remaining.Content, sequence.Content[index+1:]...)
+			if len(remaining.Content) == 0 {
+				remaining.Style = yaml.FlowStyle
+			}
+			setError := what.setValue(path, plainNode(remaining))
+			if setError != nil {
+				return setError
+			}
+			continue
+		}
+
+		editError := what.edit(item.Line-1, what.endLine(item, dashIndent), nil)
+		if editError != nil {
+			return editError
+		}
+	}
+}
+
+// insertIntoMapping adds the lines (indented relative to the keys of the mapping) as the last entries of the mapping
+// at the path
+
+func (what *modelFileEditor) insertIntoMapping(path []string, lines []string) error {
+	key, mapping := what.node(path...)
+	if mapping == nil {
+		return fmt.Errorf("%v not found", strings.Join(path, "."))
+	}
+
+	var indent string
+	var at int
+	switch {
+	case mapping.Kind == yaml.MappingNode && len(mapping.Content) > 0:
+		if mapping.Style&yaml.FlowStyle != 0 {
+			return fmt.Errorf("%v is a flow style mapping", strings.Join(path, "."))
+		}
+
+		indent = strings.Repeat(" ", mapping.Content[0].Column-1)
+		at = what.endLine(mapping, mapping.Content[0].Column-1)
+
+		// keep entries separated by blank lines if they are
+		lastKey := mapping.Content[len(mapping.Content)-2]
+		if lastKey.Line > 1 && len(strings.TrimSpace(what.lines[lastKey.Line-2])) == 0 {
+			lines = append([]string{""}, lines...)
+		}
+
+	case key != nil && isEmptyNode(mapping):
+		indent = strings.Repeat(" ", key.Column-1) + what.indentStep()
+		at = key.Line
+		if len(mapping.Value) > 0 || mapping.Kind == yaml.MappingNode {
+			line := what.lines[key.Line-1]
+			_, comment := splitLineComment(line, mapping.Column-1, lineComment(key, mapping))
+			return what.edit(key.Line-1, key.Line, append([]string{strings.TrimRight(line[:mapping.Column-1], " \t") + comment}, prefixLines(indent, lines)...))
+		}
+
+	case key == nil && mapping == what.root && mapping.Line == 0:
+		// the empty mapping of an empty file: add the lines after its comments, separated by a blank line
+		at = len(what.lines)
+		if at > 0 && len(what.lines[at-1]) == 0 {
+			at--
+		}
+		if at > 0 && len(strings.TrimSpace(what.lines[at-1])) > 0 {
+			lines = append([]string{""}, lines...)
+		}
+
+	default:
+		return fmt.Errorf("%v is no mapping", strings.Join(path, "."))
+	}
+
+	return what.edit(at, at, prefixLines(indent, lines))
+}
+
+// endLine returns the line (1-based, i.e. the 0-based index after it) the node ends on, given the indentation of its
+// key: the lines following its start that are indented deeper, without trailing blank lines and, unless it is a block
+// scalar, comments
+
+func (what *modelFileEditor) endLine(node *yaml.Node, keyIndent int) int {
+	switch node.Kind {
+	case yaml.MappingNode, yaml.SequenceNode:
+		if len(node.Content) > 0 && node.Style&yaml.FlowStyle == 0 {
+			last := node.Content[len(node.Content)-1]
+			lastIndent := last.Column - 2
+			if node.Kind == yaml.MappingNode {
+				lastIndent = node.Content[len(node.Content)-2].Column - 1
+			}
+			return what.endLine(last, lastIndent)
+		}
+
+	case yaml.AliasNode:
+		return node.Line
+	}
+
+	block := node.Kind == yaml.ScalarNode && node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0
+	end := node.Line
+	for index := node.Line; index < len(what.lines); index++ {
+		line := what.lines[index]
+		trimmed := strings.TrimSpace(line)
+		if len(trimmed) > 0 && len(line)-len(strings.TrimLeft(line, " \t")) <= keyIndent {
+			break
+		}
+		if len(trimmed) > 0 && (block || !strings.HasPrefix(trimmed, "#")) {
+			end = index + 1
+		}
+	}
+
+	return end
+}
+
+// indentStep returns the indentation of nested mappings in the file, two spaces by default
+
+func (what *modelFileEditor) indentStep() string {
+	var find func(node *yaml.Node) int
+	find = func(node *yaml.Node) int {
+		if node.Kind != yaml.MappingNode || node.Style&yaml.FlowStyle != 0 {
+			return 0
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			value := node.Content[i+1]
+			if value.Kind == yaml.MappingNode && value.Style&yaml.FlowStyle == 0 && len(value.Content) > 0 && value.Column > node.Content[i].Column {
+				return value.Column - node.Content[i].Column
+			}
+		}
+		for i := 1; i < len(node.Content); i += 2 {
+			if step := find(node.Content[i]); step > 0 {
+				return step
+			}
+		}
+		return 0
+	}
+
+	if step := find(what.root); step > 0 {
+		return strings.Repeat(" ", step)
+	}
+	return "  "
+}
+
+// renderEntry returns the lines of a mapping entry, indented relative to its key
+
+func (what *modelFileEditor) renderEntry(name string, value *yaml.Node) []string {
+	switch {
+	case value.Kind == yaml.ScalarNode:
+		return []string{formatKey(name) + ": " + what.renderScalar(value, what.indentStep())}
+
+	case value.Kind == yaml.MappingNode && len(value.Content) == 0:
+		return []string{formatKey(name) + ": {}"}
+
+	case value.Kind == yaml.SequenceNode && len(value.Content) == 0:
+		return []string{formatKey(name) + ": []"}
+
+	case value.Style&yaml.FlowStyle != 0:
+		return []string{formatKey(name) + ": " + strings.Join(what.encode(value), " ")}
+	}
+
+	return append([]string{formatKey(name) + ":"}, prefixLines(what.indentStep(), what.encode(value))...)
+}
+
+// renderScalar formats a scalar value, with the lines of multi-line values indented by the given indentation
+
+func (what *modelFileEditor) renderScalar(value *yaml.Node, indent string) string {
+	if value.Tag == "" || value.Tag == "!!str" {
+		return formatScalar(value.Value, indent)
+	}
+	if value.Tag == "!!null" {
+		return ""
+	}
+
+	return value.Value
+}
+
+// encode returns the lines of the node as YAML, with the indentation step of the file
+
+func (what *modelFileEditor) encode(node *yaml.Node) []string {
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(len(what.indentStep()))
+	_ = encoder.Encode(plainNode(node))
+	_ = encoder.Close()
+
+	return strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+}
+
+// plainNode marks strings holding dates as dates, so they are written unquoted, like in hand-written models
+
+func plainNode(node *yaml.Node) *yaml.Node {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" && node.Style == 0 {
+		var parsed any
+		if yaml.Unmarshal([]byte(node.Value), &parsed) == nil {
+			if _, isDate := parsed.(time.Time); isDate {
+				node.Tag = "!!timestamp"
+			}
+		}
+	}
+
+	for _, child := range node.Content {
+		plainNode(child)
+	}
+
+	return node
+}
+
+func isEmptyNode(node *yaml.Node) bool {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Tag == "!!null"
+	case yaml.MappingNode:
+		return len(node.Content) == 0
+	}
+
+	return false
+}
+
+func lineComment(key *yaml.Node, value *yaml.Node) string {
+	if len(value.LineComment) > 0 {
+		return value.LineComment
+	}
+
+	return key.LineComment
+}
+
+// splitLineComment splits the line into its content and its comment (with the white space before it), looking for
+// the comment after the given column
+
+func splitLineComment(line string, column int, comment string) (string, string) {
+	if len(comment) == 0 || column > len(line) {
+		return line, ""
+	}
+
+	index := strings.LastIndex(line[column:], comment)
+	if index < 0 {
+		return line, ""
+	}
+
+	index += column
+	start := len(strings.TrimRight(line[:index], " \t"))
+	return line[:start], line[start:]
+}
+
+// formatKey formats a mapping key, quoting it only if needed
+
+func formatKey(key string) string {
+	data, marshalError := yaml.Marshal(key)
+	if marshalError != nil {
+		return key
+	}
+
+	return strings.TrimSuffix(string(data), "\n")
+}
+
+// formatScalar formats a value, quoting it only if needed and writing multi-line values as block scalars with their
+// lines indented by the given indentation; dates are not quoted, like in hand-written models
+
+func formatScalar(value string, indent string) string {
+	var parsed any
+	if yaml.Unmarshal([]byte(value), &parsed) == nil {
+		if _, isDate := parsed.(time.Time); isDate && !strings.ContainsAny(value, "\n#:") {
+			return value
+		}
+	}
+
+	data, marshalError := yaml.Marshal(value)
+	if marshalError != nil {
+		return value
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	for n := 1; n < len(lines); n++ {
+		if len(lines[n]) > 0 {
+			lines[n] = indent + strings.TrimPrefix(lines[n], "    ")
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// prefixLines splits the lines at line breaks and prefixes each non-empty one with the indentation
+
+func prefixLines(indent string, lines []string) []string {
+	prefixed := make([]string, 0, len(lines))
+	for _, line := range lines {
+		for _, part := range strings.Split(line, "\n") {
+			if len(part) > 0 {
+				part = indent + part
+			}
+			prefixed = append(prefixed, part)
+		}
+	}
+
+	return prefixed
+}
@@ -67,6 +67,23 @@ func (model *Model) Defaults() *Model {
 	return model
 }
 
+// Clone returns a deep copy of the model, without positions
+
+func (model *Model) Clone() (*Model, error) {
+	data, marshalError := yaml.Marshal(model)
+	if marshalError != nil {
+		return nil, fmt.Errorf("unable to copy model: %v", marshalError)
+	}
+
+	clone := new(Model)
+	unmarshalError := yaml.Unmarshal(data, clone)
+	if unmarshalError != nil {
+		return nil, fmt.Errorf("unable to copy model: %v", unmarshalError)
+	}
+
+	return clone, nil
+}
+
 func (model *Model) Load(inputFilename string) error {
 	modelYaml, readError := os.ReadFile(filepath.Clean(inputFilename))
 	if readError != nil {
@@ -78,6 +95,11 @@ func (model *Model) Load(inputFilename string) error {
 		log.Fatal("Unable to parse model yaml: ", unmarshalError)
 	}
 
+	positionError := model.setPositions(inputFilename, modelYaml)
+	if positionError != nil {
+		log.Fatal("Unable to parse model yaml: ", positionError)
+	}
+
 	for _, includeFile := range model.Includes {
 		mergeError := model.Merge(filepath.Dir(inputFilename), includeFile)
 		if mergeError != nil {
@@ -106,6 +128,11 @@ func (model *Model) Merge(dir string, includeFilename string) error {
 		return fmt.Errorf("unable to parse model yaml: %v", unmarshalError)
 	}
 
+	positionError := includedModel.setPositions(filepath.Join(dir, includeFilename), modelYaml)
+	if positionError != nil {
+		return fmt.Errorf("unable to parse model yaml: %v", positionError)
+	}
+
 	var mergeError error
 	for item := range fileStructure {
 		switch strings.ToLower(item) {
@@ -261,6 +288,61 @@ func (model *Model) Merge(dir string, includeFilename string) error {
 	return nil
 }
 
+// setPositions records where in the file the data assets, technical assets, communication links, trust boundaries,
+// shared runtimes and risk tracking entries of the model are defined, so that they survive merging includes
+
+func (model *Model) setPositions(filename string, modelYaml []byte) error {
+	var document yaml.Node
+	unmarshalError := yaml.Unmarshal(modelYaml, &document)
+	if unmarshalError != nil {
+		return unmarshalError
+	}
+
+	if len(document.Content) == 0 {
+		return nil
+	}
+
+	yamlMappingEntries(document.Content[0], func(section *yaml.Node, items *yaml.Node) {
+		yamlMappingEntries(items, func(key *yaml.Node, value *yaml.Node) {
+			position := newPosition(filename, key)
+			switch section.Value {
+			case "data_assets":
+				if item, ok := model.DataAssets[key.Value]; ok {
+					item.Position = position
+					model.DataAssets[key.Value] = item
+				}
+
+			case "technical_assets":
+				if item, ok := model.TechnicalAssets[key.Value]; ok {
+					item.Position = position
+					item.setCommunicationLinkPositions(filename, value)
+					model.TechnicalAssets[key.Value] = item
+				}
+
+			case "trust_boundaries":
+				if item, ok := model.TrustBoundaries[key.Value]; ok {
+					item.Position = position
+					model.TrustBoundaries[key.Value] = item
+				}
+
+			case "shared_runtimes":
+				if item, ok := model.SharedRuntimes[key.Value]; ok {
+					item.Position = position
+					model.SharedRuntimes[key.Value] = item
+				}
+
+			case "risk_tracking":
+				if item, ok := model.RiskTracking[key.Value]; ok {
+					item.Position = position
+					model.RiskTracking[key.Value] = item
+				}
+			}
+		})
+	})
+
+	return nil
+}
+
 func (model *Model) AddTagToModelInput(tag string, dryRun bool, changes *[]string) {
 	tag = NormalizeTag(tag)
 
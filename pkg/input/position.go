@@ -0,0 +1,68 @@
+package input
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Position is where an element of the model is defined, i.e. the file name along with line and column of its key; the
+// file name is that of the model file or of the include file the element comes from
+
+type Position struct {
+	Filename string `yaml:"filename,omitempty" json:"filename,omitempty"`
+	Line     int    `yaml:"line,omitempty" json:"line,omitempty"`
+	Column   int    `yaml:"column,omitempty" json:"column,omitempty"`
+}
+
+func (what Position) IsKnown() bool {
+	return len(what.Filename) > 0 && what.Line > 0
+}
+
+func (what Position) String() string {
+	if !what.IsKnown() {
+		return ""
+	}
+
+	return fmt.Sprintf("%v:%d:%d", what.Filename, what.Line, what.Column)
+}
+
+// Errorf formats an error prefixed with the position, if known, in the style of compiler messages
+
+func (what Position) Errorf(format string, args ...any) error {
+	if !what.IsKnown() {
+		return fmt.Errorf(format, args...)
+	}
+
+	return fmt.Errorf("%v: %w", what, fmt.Errorf(format, args...))
+}
+
+// Wrap prefixes the error with the position, if known
+
+func (what Position) Wrap(err error) error {
+	if err == nil || !what.IsKnown() {
+		return err
+	}
+
+	return fmt.Errorf("%v: %w", what, err)
+}
+
+func newPosition(filename string, node *yaml.Node) Position {
+	return Position{
+		Filename: filename,
+		Line:     node.Line,
+		Column:   node.Column,
+	}
+}
+
+// yamlMappingEntries calls handle for each key-value pair of a mapping node
+
+func yamlMappingEntries(node *yaml.Node, handle func(key *yaml.Node, value *yaml.Node)) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return
+	}
+
+	for n := 0; n+1 < len(node.Content); n += 2 {
+		handle(node.Content[n], node.Content[n+1])
+	}
+}
@@ -3,11 +3,15 @@ package input
 import "fmt"
 
 type RiskTracking struct {
-	Status        string `yaml:"status,omitempty" json:"status,omitempty"`
-	Justification string `yaml:"justification,omitempty" json:"justification,omitempty"`
-	Ticket        string `yaml:"ticket,omitempty" json:"ticket,omitempty"`
-	Date          string `yaml:"date,omitempty" json:"date,omitempty"`
-	CheckedBy     string `yaml:"checked_by,omitempty" json:"checked_by,omitempty"`
+	Status        string   `yaml:"status,omitempty" json:"status,omitempty"`
+	Justification string   `yaml:"justification,omitempty" json:"justification,omitempty"`
+	Ticket        string   `yaml:"ticket,omitempty" json:"ticket,omitempty"`
+	Date          string   `yaml:"date,omitempty" json:"date,omitempty"`
+	CheckedBy     string   `yaml:"checked_by,omitempty" json:"checked_by,omitempty"`
+	Expires       string   `yaml:"expires,omitempty" json:"expires,omitempty"`
+	ReviewBy      string   `yaml:"review_by,omitempty" json:"review_by,omitempty"`
+	Owner         string   `yaml:"owner,omitempty" json:"owner,omitempty"`
+	Position      Position `yaml:"-" json:"-"`
 }
 
 func (what *RiskTracking) Merge(other RiskTracking) error {
@@ -37,6 +41,21 @@ func (what *RiskTracking) Merge(other RiskTracking) error {
 		return fmt.Errorf("failed to merge checked_by: %v", mergeError)
 	}
 
+	what.Expires, mergeError = new(Strings).MergeSingleton(what.Expires, other.Expires)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge expires: %v", mergeError)
+	}
+
+	what.ReviewBy, mergeError = new(Strings).MergeSingleton(what.ReviewBy, other.ReviewBy)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge review_by: %v", mergeError)
+	}
+
+	what.Owner, mergeError = new(Strings).MergeSingleton(what.Owner, other.Owner)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge owner: %v", mergeError)
+	}
+
 	return nil
 }
 
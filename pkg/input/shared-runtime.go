@@ -7,6 +7,7 @@ type SharedRuntime struct {
 	Description            string   `yaml:"description,omitempty" json:"description,omitempty"`
 	Tags                   []string `yaml:"tags,omitempty" json:"tag,omitempty"`
 	TechnicalAssetsRunning []string `yaml:"technical_assets_running,omitempty" json:"technical_assets_running,omitempty"`
+	Position               Position `yaml:"-" json:"-"`
 }
 
 func (what *SharedRuntime) Merge(other SharedRuntime) error {
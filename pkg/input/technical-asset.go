@@ -1,6 +1,10 @@
 package input
 
-import "fmt"
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
 
 type TechnicalAsset struct {
 	ID                      string                       `yaml:"id,omitempty" json:"id,omitempty"`
@@ -30,6 +34,7 @@ type TechnicalAsset struct {
 	DataFormatsAccepted     []string                     `yaml:"data_formats_accepted,omitempty" json:"data_formats_accepted,omitempty"`
 	DiagramTweakOrder       int                          `yaml:"diagram_tweak_order,omitempty" json:"diagram_tweak_order,omitempty"`
 	CommunicationLinks      map[string]CommunicationLink `yaml:"communication_links,omitempty" json:"communication_links,omitempty"`
+	Position                Position                     `yaml:"-" json:"-"`
 }
 
 func (what *TechnicalAsset) Merge(other TechnicalAsset) error {
@@ -142,6 +147,21 @@ func (what *TechnicalAsset) Merge(other TechnicalAsset) error {
 	return nil
 }
 
+func (what *TechnicalAsset) setCommunicationLinkPositions(filename string, node *yaml.Node) {
+	yamlMappingEntries(node, func(key *yaml.Node, value *yaml.Node) {
+		if key.Value != "communication_links" {
+			return
+		}
+
+		yamlMappingEntries(value, func(key *yaml.Node, _ *yaml.Node) {
+			if item, ok := what.CommunicationLinks[key.Value]; ok {
+				item.Position = newPosition(filename, key)
+				what.CommunicationLinks[key.Value] = item
+			}
+		})
+	})
+}
+
 func (what *TechnicalAsset) MergeMap(first map[string]TechnicalAsset, second map[string]TechnicalAsset) (map[string]TechnicalAsset, error) {
 	for mapKey, mapValue := range second {
 		mapItem, ok := first[mapKey]
@@ -9,6 +9,7 @@ type TrustBoundary struct {
 	Tags                  []string `yaml:"tags,omitempty" json:"tags,omitempty"`
 	TechnicalAssetsInside []string `yaml:"technical_assets_inside,omitempty" json:"technical_assets_inside,omitempty"`
 	TrustBoundariesNested []string `yaml:"trust_boundaries_nested,omitempty" json:"trust_boundaries_nested,omitempty"`
+	Position              Position `yaml:"-" json:"-"`
 }
 
 func (what *TrustBoundary) Merge(other TrustBoundary) error {
@@ -27,6 +27,12 @@ type Macros interface {
 	Execute(modelInput *input.Model, model *types.Model) (message string, validResult bool, err error)
 }
 
+// ModelRewriter is implemented by macros writing the whole model file anew, rather than editing what they change in it
+
+type ModelRewriter interface {
+	RewritesModelFile() bool
+}
+
 func ListBuiltInMacros() []Macros {
 	return []Macros{
 		NewBuildPipeline(),
@@ -229,6 +235,10 @@ func ExecuteModelMacro(modelInput *input.Model, inputFile string, parsedModel *t
 		answer = strings.ToLower(answer)
 		fmt.Println()
 		if answer == "yes" || answer == "y" {
+			before, err := modelInput.Clone()
+			if err != nil {
+				return err
+			}
 			message, validResult, err = macros.Execute(modelInput, parsedModel)
 			if err != nil {
 				return err
@@ -239,27 +249,10 @@ func ExecuteModelMacro(modelInput *input.Model, inputFile string, parsedModel *t
 			}
 			fmt.Println(message)
 			fmt.Println()
-			backupFilename := inputFile + ".backup"
-			fmt.Println("Creating backup model file:", backupFilename) // TODO add random files in /dev/shm space?
-			_, err = copyFile(inputFile, backupFilename)
-			if err != nil {
-				return err
-			}
-			fmt.Println("Updating model")
-			yamlBytes, err := yaml.Marshal(modelInput)
-			if err != nil {
-				return err
+			if rewriter, ok := macros.(ModelRewriter); ok && rewriter.RewritesModelFile() {
+				return rewriteModelFile(modelInput, inputFile)
 			}
-			/*
-				yamlBytes = model.ReformatYAML(yamlBytes)
-			*/
-			fmt.Println("Writing model file:", inputFile)
-			err = os.WriteFile(inputFile, yamlBytes, 0400)
-			if err != nil {
-				return err
-			}
-			fmt.Println("Model file successfully updated")
-			return nil
+			return editModelFiles(before, modelInput, inputFile)
 		} else if answer == "no" || answer == "n" {
 			fmt.Println("Quitting without executing the model macro")
 			return nil
@@ -267,6 +260,66 @@ func ExecuteModelMacro(modelInput *input.Model, inputFile string, parsedModel *t
 	}
 }
 
+// editModelFiles edits what the macro changed in the model file and the files it includes, keeping everything else
+// in them as it is
+
+func editModelFiles(before *input.Model, after *input.Model, inputFile string) error {
+	editor := new(input.ModelEditor)
+	err := editor.Load(inputFile)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Updating model")
+	err = editor.ApplyChanges(before, after)
+	if err != nil {
+		return err
+	}
+	filenames := editor.ModifiedFiles()
+	if len(filenames) == 0 {
+		fmt.Println("Model file unchanged")
+		return nil
+	}
+	for _, filename := range filenames {
+		backupFilename := filename + ".backup"
+		fmt.Println("Creating backup model file:", backupFilename) // TODO add random files in /dev/shm space?
+		_, err = copyFile(filename, backupFilename)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Writing model file:", filename)
+	}
+	err = editor.Save()
+	if err != nil {
+		return err
+	}
+	fmt.Println("Model file successfully updated")
+	return nil
+}
+
+func rewriteModelFile(modelInput *input.Model, inputFile string) error {
+	backupFilename := inputFile + ".backup"
+	fmt.Println("Creating backup model file:", backupFilename) // TODO add random files in /dev/shm space?
+	_, err := copyFile(inputFile, backupFilename)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Updating model")
+	yamlBytes, err := yaml.Marshal(modelInput)
+	if err != nil {
+		return err
+	}
+	/*
+		yamlBytes = model.ReformatYAML(yamlBytes)
+	*/
+	fmt.Println("Writing model file:", inputFile)
+	err = os.WriteFile(inputFile, yamlBytes, 0400)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Model file successfully updated")
+	return nil
+}
+
 func printBorder(length int, bold bool) {
 	char := "-"
 	if bold {
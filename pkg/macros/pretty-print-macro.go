@@ -39,3 +39,9 @@ func (*PrettyPrintMacro) GetFinalChangeImpact(_ *input.Model, _ *types.Model) (c
 func (*PrettyPrintMacro) Execute(_ *input.Model, _ *types.Model) (message string, validResult bool, err error) {
 	return "Model pretty printing successful", true, nil
 }
+
+// RewritesModelFile makes the macro write the model file anew, pretty-printed
+
+func (*PrettyPrintMacro) RewritesModelFile() bool {
+	return true
+}
@@ -58,7 +58,7 @@ func (*removeUnusedTagsMacro) Execute(modelInput *input.Model, parsedModel *type
 		modelInput.TagsAvailable = append(modelInput.TagsAvailable, runtime.Tags...)
 	}
 	count := len(modelInput.TagsAvailable)
-	unique.Strings(&modelInput.TagsAvailable)
 	sort.Strings(modelInput.TagsAvailable)
+	unique.Strings(&modelInput.TagsAvailable)
 	return "Model file removal of " + strconv.Itoa(count-len(modelInput.TagsAvailable)) + " unused tags successful", true, nil
 }
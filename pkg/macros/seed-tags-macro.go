@@ -45,7 +45,7 @@ func (*SeedTagsMacro) Execute(modelInput *input.Model, parsedModel *types.Model)
 	for tag := range parsedModel.AllSupportedTags {
 		modelInput.TagsAvailable = append(modelInput.TagsAvailable, tag)
 	}
-	unique.Strings(&modelInput.TagsAvailable)
 	sort.Strings(modelInput.TagsAvailable)
+	unique.Strings(&modelInput.TagsAvailable)
 	return "Model file seeding with " + strconv.Itoa(len(parsedModel.AllSupportedTags)) + " tags successful", true, nil
 }
@@ -0,0 +1,274 @@
+package model
+
+import (
+	"container/heap"
+	"sort"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// AttackPath is a way for an attacker from an entry point, i.e. a technical asset on the internet or an external
+// entity, along communication links to a crown jewel, i.e. a technical asset storing strictly-confidential data. The
+// score of a path is the sum of the scores of its hops.
+
+type AttackPath struct {
+	EntryPointId string           `json:"entry_point" yaml:"entry_point"`
+	TargetId     string           `json:"target" yaml:"target"`
+	DataAssetIds []string         `json:"data_assets" yaml:"data_assets"`
+	Hops         []*AttackPathHop `json:"hops" yaml:"hops"`
+	Score        int              `json:"score" yaml:"score"`
+}
+
+// AttackPathHop is a communication link of an attack path along with the risks still open on it and on the technical
+// asset it leads to; each open risk scores one plus its severity, i.e. from 1 (low) to 5 (critical)
+
+type AttackPathHop struct {
+	CommunicationLinkId string   `json:"communication_link" yaml:"communication_link"`
+	SourceId            string   `json:"source" yaml:"source"`
+	TargetId            string   `json:"target" yaml:"target"`
+	OpenRiskIds         []string `json:"open_risks" yaml:"open_risks"`
+	Score               int      `json:"score" yaml:"score"`
+}
+
+// TechnicalAssetIds returns the ids of the technical assets along the path, starting with the entry point
+
+func (what *AttackPath) TechnicalAssetIds() []string {
+	ids := []string{what.EntryPointId}
+	for _, hop := range what.Hops {
+		ids = append(ids, hop.TargetId)
+	}
+
+	return ids
+}
+
+// FindAttackPaths searches the paths without cycles of at most config.MaxHops communication links from each entry point
+// to each crown jewel for the config.MaxPaths highest scoring ones. Paths follow communication links from their source
+// to their target only, as that is the direction requests are sent in. The search keeps the best paths found so far
+// and skips the ways that can't score better than the worst of them; it stops after config.MaxSteps steps, returning
+// the best paths found until then and false.
+
+func FindAttackPaths(parsedModel *types.Model, config common.AttackPathConfig) ([]*AttackPath, bool) {
+	finder := &attackPathFinder{
+		parsedModel:        parsedModel,
+		maxHops:            config.HopLimit(),
+		maxPaths:           config.PathLimit(),
+		stepsLeft:          config.StepLimit(),
+		hopsByLinkId:       make(map[string]*AttackPathHop),
+		crownJewelsByAsset: make(map[string][]string),
+		visited:            make(map[string]bool),
+		best:               make(attackPathHeap, 0),
+	}
+	finder.indexOpenRisks()
+
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		if isAttackPathEntryPoint(parsedModel.TechnicalAssets[id]) {
+			finder.walk(id, id, make([]*AttackPathHop, 0), 0)
+		}
+	}
+
+	best := finder.best
+	sort.Slice(best, func(i, j int) bool {
+		return best.less(best[j], best[i])
+	})
+
+	paths := make([]*AttackPath, 0, len(best))
+	for _, found := range best {
+		paths = append(paths, found.path)
+	}
+
+	return paths, finder.stepsLeft >= 0
+}
+
+func isAttackPathEntryPoint(techAsset *types.TechnicalAsset) bool {
+	return techAsset.Internet || techAsset.Type == types.ExternalEntity
+}
+
+// crownJewelDataAssetIds returns the sorted ids of the strictly-confidential data assets stored by the technical asset
+
+func crownJewelDataAssetIds(parsedModel *types.Model, techAsset *types.TechnicalAsset) []string {
+	ids := make([]string, 0)
+	for _, dataAsset := range techAsset.DataAssetsStoredSorted(parsedModel) {
+		if dataAsset.Confidentiality >= types.StrictlyConfidential {
+			ids = append(ids, dataAsset.Id)
+		}
+	}
+
+	return ids
+}
+
+type attackPathFinder struct {
+	parsedModel        *types.Model
+	maxHops            int
+	maxPaths           int
+	stepsLeft          int
+	maxHopScore        int
+	risksByLinkId      map[string][]*types.Risk
+	risksByTargetId    map[string][]*types.Risk
+	hopsByLinkId       map[string]*AttackPathHop
+	crownJewelsByAsset map[string][]string
+	visited            map[string]bool
+	found              int
+	best               attackPathHeap
+}
+
+// indexOpenRisks sorts the risks still at risk by the communication link or the technical asset they are about, and
+// finds the highest score of a hop, which bounds the score a path can still gain
+
+func (what *attackPathFinder) indexOpenRisks() {
+	what.risksByLinkId = make(map[string][]*types.Risk)
+	what.risksByTargetId = make(map[string][]*types.Risk)
+	for _, risk := range types.ReduceToOnlyStillAtRisk(what.parsedModel, types.AllRisks(what.parsedModel)) {
+		if len(risk.MostRelevantCommunicationLinkId) > 0 {
+			what.risksByLinkId[risk.MostRelevantCommunicationLinkId] = append(what.risksByLinkId[risk.MostRelevantCommunicationLinkId], risk)
+		} else {
+			what.risksByTargetId[risk.MostRelevantTechnicalAssetId] = append(what.risksByTargetId[risk.MostRelevantTechnicalAssetId], risk)
+		}
+	}
+
+	for _, link := range what.parsedModel.CommunicationLinks {
+		if hop := what.hop(link); hop.Score > what.maxHopScore {
+			what.maxHopScore = hop.Score
+		}
+	}
+}
+
+func (what *attackPathFinder) walk(entryPointId string, techAssetId string, hops []*AttackPathHop, score int) {
+	what.stepsLeft--
+	if what.stepsLeft < 0 {
+		return
+	}
+
+	what.visited[techAssetId] = true
+	defer delete(what.visited, techAssetId)
+
+	if len(hops) > 0 {
+		dataAssetIds := what.crownJewelDataAssetIds(techAssetId)
+		if len(dataAssetIds) > 0 {
+			what.add(&AttackPath{
+				EntryPointId: entryPointId,
+				TargetId:     techAssetId,
+				DataAssetIds: dataAssetIds,
+				Hops:         append(make([]*AttackPathHop, 0, len(hops)), hops...),
+				Score:        score,
+			})
+		}
+	}
+
+	if len(hops) >= what.maxHops {
+		return
+	}
+
+	if len(what.best) >= what.maxPaths && score+(what.maxHops-len(hops))*what.maxHopScore < what.best[0].path.Score {
+		return
+	}
+
+	for _, link := range what.parsedModel.TechnicalAssets[techAssetId].CommunicationLinksSorted() {
+		if _, ok := what.parsedModel.TechnicalAssets[link.TargetId]; !ok || what.visited[link.TargetId] {
+			continue
+		}
+
+		hop := what.hop(link)
+		what.walk(entryPointId, link.TargetId, append(hops, hop), score+hop.Score)
+	}
+}
+
+// add keeps the path if it is among the best ones found so far
+
+func (what *attackPathFinder) add(path *AttackPath) {
+	found := &foundAttackPath{path: path, order: what.found}
+	what.found++
+
+	if len(what.best) < what.maxPaths {
+		heap.Push(&what.best, found)
+		return
+	}
+
+	if what.best.less(what.best[0], found) {
+		what.best[0] = found
+		heap.Fix(&what.best, 0)
+	}
+}
+
+func (what *attackPathFinder) crownJewelDataAssetIds(techAssetId string) []string {
+	ids, ok := what.crownJewelsByAsset[techAssetId]
+	if !ok {
+		ids = crownJewelDataAssetIds(what.parsedModel, what.parsedModel.TechnicalAssets[techAssetId])
+		what.crownJewelsByAsset[techAssetId] = ids
+	}
+
+	return ids
+}
+
+// hop collects the open risks of the communication link and those of its target that aren't about another link
+
+func (what *attackPathFinder) hop(link *types.CommunicationLink) *AttackPathHop {
+	if hop, ok := what.hopsByLinkId[link.Id]; ok {
+		return hop
+	}
+
+	hop := &AttackPathHop{
+		CommunicationLinkId: link.Id,
+		SourceId:            link.SourceId,
+		TargetId:            link.TargetId,
+		OpenRiskIds:         make([]string, 0),
+	}
+
+	risks := append(append(make([]*types.Risk, 0), what.risksByLinkId[link.Id]...), what.risksByTargetId[link.TargetId]...)
+	sort.Slice(risks, func(i, j int) bool {
+		if risks[i].Severity != risks[j].Severity {
+			return risks[i].Severity > risks[j].Severity
+		}
+
+		return risks[i].SyntheticId < risks[j].SyntheticId
+	})
+
+	for _, risk := range risks {
+		hop.OpenRiskIds = append(hop.OpenRiskIds, risk.SyntheticId)
+		hop.Score += int(risk.Severity) + 1
+	}
+
+	what.hopsByLinkId[link.Id] = hop
+	return hop
+}
+
+// foundAttackPath is a path along with the order it has been found in, which breaks ties between paths of equal score
+// and length
+
+type foundAttackPath struct {
+	path  *AttackPath
+	order int
+}
+
+// attackPathHeap holds the best paths found so far with the worst of them on top
+
+type attackPathHeap []*foundAttackPath
+
+// less tells whether the first path is worse than the second one: higher scores are better, then shorter paths, then
+// the path found first
+
+func (what attackPathHeap) less(a *foundAttackPath, b *foundAttackPath) bool {
+	if a.path.Score != b.path.Score {
+		return a.path.Score < b.path.Score
+	}
+	if len(a.path.Hops) != len(b.path.Hops) {
+		return len(a.path.Hops) > len(b.path.Hops)
+	}
+
+	return a.order > b.order
+}
+
+func (what attackPathHeap) Len() int           { return len(what) }
+func (what attackPathHeap) Less(i, j int) bool { return what.less(what[i], what[j]) }
+func (what attackPathHeap) Swap(i, j int)      { what[i], what[j] = what[j], what[i] }
+
+func (what *attackPathHeap) Push(value any) {
+	*what = append(*what, value.(*foundAttackPath))
+}
+
+func (what *attackPathHeap) Pop() any {
+	old := *what
+	value := old[len(old)-1]
+	*what = old[:len(old)-1]
+	return value
+}
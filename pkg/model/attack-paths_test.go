@@ -0,0 +1,145 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// createAttackPathModel has a web server on the internet that reaches a database storing the customers through either
+// an app server or a cache; a monitoring server is connected to the database but can't be reached from the internet
+
+func createAttackPathModel() *types.Model {
+	parsedModel := &types.Model{
+		TechnicalAssets:    make(map[string]*types.TechnicalAsset),
+		CommunicationLinks: make(map[string]*types.CommunicationLink),
+		DataAssets: map[string]*types.DataAsset{
+			"customers": {Id: "customers", Confidentiality: types.StrictlyConfidential},
+			"logs":      {Id: "logs", Confidentiality: types.Internal},
+		},
+		GeneratedRisksByCategory: map[string][]*types.Risk{
+			"some-category": {
+				{SyntheticId: "app-risk", MostRelevantTechnicalAssetId: "app", Severity: types.HighSeverity},
+				{SyntheticId: "app>db-risk", MostRelevantTechnicalAssetId: "app", MostRelevantCommunicationLinkId: "app>db", Severity: types.MediumSeverity},
+				{SyntheticId: "cache-risk", MostRelevantTechnicalAssetId: "cache", Severity: types.LowSeverity, RiskStatus: types.Mitigated},
+			},
+		},
+	}
+
+	addAsset := func(id string, internet bool, stored []string, targetIds ...string) {
+		techAsset := &types.TechnicalAsset{Id: id, Type: types.Process, Internet: internet, DataAssetsStored: stored}
+		for _, targetId := range targetIds {
+			link := &types.CommunicationLink{Id: id + ">" + targetId, SourceId: id, TargetId: targetId}
+			techAsset.CommunicationLinks = append(techAsset.CommunicationLinks, link)
+			parsedModel.CommunicationLinks[link.Id] = link
+		}
+
+		parsedModel.TechnicalAssets[id] = techAsset
+	}
+
+	addAsset("web", true, nil, "app", "cache")
+	addAsset("app", false, nil, "db")
+	addAsset("cache", false, []string{"logs"}, "db")
+	addAsset("db", false, []string{"customers"})
+	addAsset("monitoring", false, nil, "db")
+
+	return parsedModel
+}
+
+func TestFindAttackPathsScoresOpenRisks(t *testing.T) {
+	paths, complete := FindAttackPaths(createAttackPathModel(), common.AttackPathConfig{})
+
+	assert.True(t, complete)
+
+	assert.Len(t, paths, 2)
+	assert.Equal(t, []string{"web", "app", "db"}, paths[0].TechnicalAssetIds())
+	assert.Equal(t, []string{"customers"}, paths[0].DataAssetIds)
+	assert.Equal(t, []string{"app-risk"}, paths[0].Hops[0].OpenRiskIds)
+	assert.Equal(t, []string{"app>db-risk"}, paths[0].Hops[1].OpenRiskIds)
+	assert.Equal(t, 4+2, paths[0].Score)
+
+	// the mitigated risk of the cache doesn't count
+	assert.Equal(t, []string{"web", "cache", "db"}, paths[1].TechnicalAssetIds())
+	assert.Equal(t, 0, paths[1].Score)
+}
+
+func TestFindAttackPathsHonoursLimits(t *testing.T) {
+	parsedModel := createAttackPathModel()
+
+	paths, _ := FindAttackPaths(parsedModel, common.AttackPathConfig{MaxPaths: 1})
+	assert.Len(t, paths, 1)
+	assert.Equal(t, "app", paths[0].Hops[0].TargetId)
+
+	paths, _ = FindAttackPaths(parsedModel, common.AttackPathConfig{MaxHops: 1})
+	assert.Empty(t, paths)
+
+	// the first two steps only reach the entry point and the app server
+	paths, complete := FindAttackPaths(parsedModel, common.AttackPathConfig{MaxSteps: 2})
+	assert.False(t, complete)
+	assert.Empty(t, paths)
+}
+
+func TestFindAttackPathsKeepsTheBestOfManyPaths(t *testing.T) {
+	// a chain of layers, each with an asset with an open risk and one without, connected to both assets of the next
+	// layer; the best path goes through all the risky ones, and searching all the others would take 511 steps
+	parsedModel := &types.Model{
+		TechnicalAssets:    make(map[string]*types.TechnicalAsset),
+		CommunicationLinks: make(map[string]*types.CommunicationLink),
+		DataAssets:         map[string]*types.DataAsset{"customers": {Id: "customers", Confidentiality: types.StrictlyConfidential}},
+		GeneratedRisksByCategory: map[string][]*types.Risk{
+			"some-category": make([]*types.Risk, 0),
+		},
+	}
+
+	layers := 8
+	previous := []*types.TechnicalAsset{{Id: "entry", Internet: true}}
+	parsedModel.TechnicalAssets["entry"] = previous[0]
+	for layer := 0; layer < layers; layer++ {
+		current := make([]*types.TechnicalAsset, 0)
+		for _, kind := range []string{"risky", "plain"} {
+			techAsset := &types.TechnicalAsset{Id: fmt.Sprintf("%v-%d", kind, layer), Type: types.Process}
+			if layer == layers-1 {
+				techAsset.DataAssetsStored = []string{"customers"}
+			}
+			if kind == "risky" {
+				parsedModel.GeneratedRisksByCategory["some-category"] = append(parsedModel.GeneratedRisksByCategory["some-category"],
+					&types.Risk{SyntheticId: techAsset.Id + "-risk", MostRelevantTechnicalAssetId: techAsset.Id, Severity: types.LowSeverity})
+			}
+			parsedModel.TechnicalAssets[techAsset.Id] = techAsset
+			current = append(current, techAsset)
+		}
+
+		for _, source := range previous {
+			for _, target := range current {
+				id := source.Id + ">" + target.Id
+				link := &types.CommunicationLink{Id: id, Title: id, SourceId: source.Id, TargetId: target.Id}
+				source.CommunicationLinks = append(source.CommunicationLinks, link)
+				parsedModel.CommunicationLinks[link.Id] = link
+			}
+		}
+		previous = current
+	}
+
+	paths, complete := FindAttackPaths(parsedModel, common.AttackPathConfig{MaxPaths: 2, MaxHops: layers, MaxSteps: 100})
+
+	assert.True(t, complete, "paths that can't beat the best ones are skipped")
+	require.Len(t, paths, 2)
+	assert.Equal(t, layers, paths[0].Score)
+	assert.Equal(t, "risky-7", paths[0].TargetId)
+	assert.Equal(t, layers-1, paths[1].Score)
+	assert.Equal(t, "plain-7", paths[1].TargetId)
+}
+
+func TestFindAttackPathsStartsAtExternalEntities(t *testing.T) {
+	parsedModel := createAttackPathModel()
+	parsedModel.TechnicalAssets["monitoring"].Type = types.ExternalEntity
+
+	paths, _ := FindAttackPaths(parsedModel, common.AttackPathConfig{})
+
+	assert.Len(t, paths, 3)
+	assert.Equal(t, []string{"monitoring", "db"}, paths[1].TechnicalAssetIds(), "shorter paths go first among those of equal score")
+}
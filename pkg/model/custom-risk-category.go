@@ -1,9 +1,11 @@
 package model
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
+	"github.com/threagile/threagile/pkg/common"
 	"github.com/threagile/threagile/pkg/security/types"
 )
 
@@ -11,7 +13,9 @@ type CustomRiskCategory struct {
 	types.RiskCategory `json:"risk_category" yaml:"risk_category,omitempty"`
 
 	Tags   []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	client *pluginClient
 	runner *runner
+	ctx    context.Context
 }
 
 func (what *CustomRiskCategory) Init(category *types.RiskCategory, tags []string) *CustomRiskCategory {
@@ -31,21 +35,59 @@ func (what *CustomRiskCategory) SupportedTags() []string {
 	return what.Tags
 }
 
+// Filename returns the plugin file the custom risk rule was loaded from
+
+func (what *CustomRiskCategory) Filename() string {
+	switch {
+	case what.client != nil:
+		return what.client.Filename
+
+	case what.runner != nil:
+		return what.runner.Filename
+
+	default:
+		return ""
+	}
+}
+
+// Close stops the plugin if it is kept running
+
+func (what *CustomRiskCategory) Close() error {
+	if what.client == nil {
+		return nil
+	}
+
+	return what.client.Close()
+}
+
 func (what *CustomRiskCategory) GenerateRisks(parsedModel *types.Model) ([]*types.Risk, error) {
+	if what.client != nil {
+		generatedRisks, clientError := what.client.GenerateRisks(parsedModel)
+		if clientError != nil {
+			return nil, fmt.Errorf("Failed to generate risks for custom risk rule %q: %w\n", what.client.Filename, clientError)
+		}
+
+		return generatedRisks, nil
+	}
+
 	if what.runner == nil {
 		return nil, nil
 	}
 
 	generatedRisks := make([]*types.Risk, 0)
-	runError := what.runner.Run(parsedModel, &generatedRisks, "-generate-risks")
+	runError := what.runner.Run(what.ctx, parsedModel, &generatedRisks, "-generate-risks")
 	if runError != nil {
-		return nil, fmt.Errorf("Failed to generate risks for custom risk rule %q: %v\n", what.runner.Filename, runError)
+		return nil, fmt.Errorf("Failed to generate risks for custom risk rule %q: %w\n", what.runner.Filename, runError)
 	}
 
 	return generatedRisks, nil
 }
 
-func LoadCustomRiskRules(pluginFiles []string, reporter types.ProgressReporter) types.RiskRules {
+// LoadCustomRiskRules loads the configured risk rule plugins, which run within their configured limits until the
+// context ends
+
+func LoadCustomRiskRules(ctx context.Context, config *common.Config, reporter types.ProgressReporter) types.RiskRules {
+	pluginFiles := config.RiskRulesPlugins
 	customRiskRuleList := make([]string, 0)
 	customRiskRules := make(types.RiskRules)
 	if len(pluginFiles) > 0 {
@@ -53,18 +95,12 @@ func LoadCustomRiskRules(pluginFiles []string, reporter types.ProgressReporter)
 
 		for _, pluginFile := range pluginFiles {
 			if len(pluginFile) > 0 {
-				newRunner, loadError := new(runner).Load(pluginFile)
+				risk, loadError := loadCustomRiskRule(ctx, config, pluginFile, reporter)
 				if loadError != nil {
 					reporter.Error(fmt.Sprintf("WARNING: Custom risk rule %q not loaded: %v\n", pluginFile, loadError))
+					continue
 				}
 
-				risk := new(CustomRiskCategory)
-				runError := newRunner.Run(nil, &risk, "-get-info")
-				if runError != nil {
-					reporter.Error(fmt.Sprintf("WARNING: Failed to get info for custom risk rule %q: %v\n", pluginFile, runError))
-				}
-
-				risk.runner = newRunner
 				customRiskRules[risk.ID] = risk
 				customRiskRuleList = append(customRiskRuleList, risk.ID)
 				reporter.Info("Custom risk rule loaded:", risk.ID)
@@ -76,3 +112,41 @@ func LoadCustomRiskRules(pluginFiles []string, reporter types.ProgressReporter)
 
 	return customRiskRules
 }
+
+// loadCustomRiskRule keeps the plugin running if it supports serve mode, and otherwise runs it once per call
+
+func loadCustomRiskRule(ctx context.Context, config *common.Config, pluginFile string, reporter types.ProgressReporter) (*CustomRiskCategory, error) {
+	newRunner, loadError := new(runner).Load(pluginFile, config)
+	if loadError != nil {
+		return nil, loadError
+	}
+
+	client := new(pluginClient)
+	risk, startError := client.Start(ctx, pluginFile, config)
+	if startError == nil {
+		risk.client = client
+		return risk, nil
+	}
+
+	reporter.Infof("Custom risk rule %q does not support serve mode, running it once per call: %v", pluginFile, startError)
+
+	risk = new(CustomRiskCategory)
+	runError := newRunner.Run(ctx, nil, &risk, "-get-info")
+	if runError != nil {
+		return nil, fmt.Errorf("failed to get info: %w", runError)
+	}
+
+	risk.runner = newRunner
+	risk.ctx = ctx
+	return risk, nil
+}
+
+// CloseCustomRiskRules stops the plugins of the custom risk rules that are kept running
+
+func CloseCustomRiskRules(rules types.RiskRules) {
+	for _, rule := range rules {
+		if customRule, ok := rule.(*CustomRiskCategory); ok {
+			_ = customRule.Close()
+		}
+	}
+}
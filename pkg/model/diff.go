@@ -0,0 +1,269 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const (
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffChanged = "changed"
+
+	RiskDiffNew             = "new"
+	RiskDiffResolved        = "resolved"
+	RiskDiffSeverityChanged = "severity-changed"
+)
+
+// ModelDiff is what changed between two analyzed versions of a model: the technical assets, communication links,
+// data assets and trust boundaries added, removed or changed, and the risks new, resolved or of changed severity
+
+type ModelDiff struct {
+	OldTitle           string      `json:"old_title" yaml:"old_title"`
+	NewTitle           string      `json:"new_title" yaml:"new_title"`
+	TechnicalAssets    []*ItemDiff `json:"technical_assets" yaml:"technical_assets"`
+	CommunicationLinks []*ItemDiff `json:"communication_links" yaml:"communication_links"`
+	DataAssets         []*ItemDiff `json:"data_assets" yaml:"data_assets"`
+	TrustBoundaries    []*ItemDiff `json:"trust_boundaries" yaml:"trust_boundaries"`
+	Risks              []*RiskDiff `json:"risks" yaml:"risks"`
+}
+
+// ItemDiff is a model element added, removed or changed, with the fields changed (named like in the model file)
+
+type ItemDiff struct {
+	Change string       `json:"change" yaml:"change"`
+	Id     string       `json:"id" yaml:"id"`
+	Title  string       `json:"title" yaml:"title"`
+	Fields []*FieldDiff `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
+type FieldDiff struct {
+	Field string `json:"field" yaml:"field"`
+	Old   string `json:"old" yaml:"old"`
+	New   string `json:"new" yaml:"new"`
+}
+
+// RiskDiff is a risk new to, resolved in or of changed severity in the newer model, keyed by its synthetic id; the
+// status is the one in the newer model, or the older one for resolved risks
+
+type RiskDiff struct {
+	Change      string `json:"change" yaml:"change"`
+	SyntheticId string `json:"synthetic_id" yaml:"synthetic_id"`
+	Category    string `json:"category" yaml:"category"`
+	Title       string `json:"title" yaml:"title"`
+	Status      string `json:"status" yaml:"status"`
+	OldSeverity string `json:"old_severity,omitempty" yaml:"old_severity,omitempty"`
+	NewSeverity string `json:"new_severity,omitempty" yaml:"new_severity,omitempty"`
+}
+
+// DiffModels compares two analyzed models, the older one first
+
+func DiffModels(oldModel *types.Model, newModel *types.Model) *ModelDiff {
+	diff := &ModelDiff{
+		OldTitle: oldModel.Title,
+		NewTitle: newModel.Title,
+	}
+
+	diff.TechnicalAssets = diffItems(oldModel.TechnicalAssets, newModel.TechnicalAssets,
+		func(asset *types.TechnicalAsset) string { return asset.Title }, technicalAssetFields)
+	diff.CommunicationLinks = diffItems(oldModel.CommunicationLinks, newModel.CommunicationLinks,
+		func(link *types.CommunicationLink) string { return link.Title }, communicationLinkFields)
+	diff.DataAssets = diffItems(oldModel.DataAssets, newModel.DataAssets,
+		func(asset *types.DataAsset) string { return asset.Title }, dataAssetFields)
+	diff.TrustBoundaries = diffItems(oldModel.TrustBoundaries, newModel.TrustBoundaries,
+		func(boundary *types.TrustBoundary) string { return boundary.Title }, trustBoundaryFields)
+	diff.Risks = diffRisks(oldModel.GeneratedRisksBySyntheticId, newModel.GeneratedRisksBySyntheticId)
+
+	return diff
+}
+
+// IsEmpty tells whether nothing changed
+
+func (what *ModelDiff) IsEmpty() bool {
+	return len(what.TechnicalAssets) == 0 && len(what.CommunicationLinks) == 0 && len(what.DataAssets) == 0 &&
+		len(what.TrustBoundaries) == 0 && len(what.Risks) == 0
+}
+
+// RisksByChange returns the risk differences of the given change, like RiskDiffNew
+
+func (what *ModelDiff) RisksByChange(change string) []*RiskDiff {
+	risks := make([]*RiskDiff, 0)
+	for _, risk := range what.Risks {
+		if risk.Change == change {
+			risks = append(risks, risk)
+		}
+	}
+	return risks
+}
+
+// diffItems compares the elements of two models by their id (as the model parser derives it from the title), the
+// key of the maps the parsed models keep them in
+
+func diffItems[T any](oldItems map[string]T, newItems map[string]T, title func(T) string, fields func(T) map[string]string) []*ItemDiff {
+	ids := make([]string, 0)
+	for id := range oldItems {
+		ids = append(ids, id)
+	}
+	for id := range newItems {
+		if _, ok := oldItems[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	diffs := make([]*ItemDiff, 0)
+	for _, id := range ids {
+		oldItem, inOld := oldItems[id]
+		newItem, inNew := newItems[id]
+		switch {
+		case !inOld:
+			diffs = append(diffs, &ItemDiff{Change: DiffAdded, Id: id, Title: title(newItem)})
+
+		case !inNew:
+			diffs = append(diffs, &ItemDiff{Change: DiffRemoved, Id: id, Title: title(oldItem)})
+
+		default:
+			oldFields, newFields := fields(oldItem), fields(newItem)
+			names := make([]string, 0)
+			for name := range oldFields {
+				if oldFields[name] != newFields[name] {
+					names = append(names, name)
+				}
+			}
+			sort.Strings(names)
+
+			if len(names) > 0 {
+				item := &ItemDiff{Change: DiffChanged, Id: id, Title: title(newItem)}
+				for _, name := range names {
+					item.Fields = append(item.Fields, &FieldDiff{Field: name, Old: oldFields[name], New: newFields[name]})
+				}
+				diffs = append(diffs, item)
+			}
+		}
+	}
+
+	return diffs
+}
+
+func diffRisks(oldRisks map[string]*types.Risk, newRisks map[string]*types.Risk) []*RiskDiff {
+	diffs := make([]*RiskDiff, 0)
+	for id, risk := range newRisks {
+		oldRisk, ok := oldRisks[id]
+		switch {
+		case !ok:
+			diffs = append(diffs, &RiskDiff{Change: RiskDiffNew, SyntheticId: risk.SyntheticId, Category: risk.CategoryId,
+				Title: risk.Title, Status: risk.RiskStatus.String(), NewSeverity: risk.Severity.String()})
+
+		case oldRisk.Severity != risk.Severity:
+			diffs = append(diffs, &RiskDiff{Change: RiskDiffSeverityChanged, SyntheticId: risk.SyntheticId, Category: risk.CategoryId,
+				Title: risk.Title, Status: risk.RiskStatus.String(), OldSeverity: oldRisk.Severity.String(), NewSeverity: risk.Severity.String()})
+		}
+	}
+	for id, risk := range oldRisks {
+		if _, ok := newRisks[id]; !ok {
+			diffs = append(diffs, &RiskDiff{Change: RiskDiffResolved, SyntheticId: risk.SyntheticId, Category: risk.CategoryId,
+				Title: risk.Title, Status: risk.RiskStatus.String(), OldSeverity: risk.Severity.String()})
+		}
+	}
+
+	severity := func(risk *RiskDiff) types.RiskSeverity {
+		value := risk.NewSeverity
+		if len(value) == 0 {
+			value = risk.OldSeverity
+		}
+		parsed, _ := types.ParseRiskSeverity(value)
+		return parsed
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		if severity(diffs[i]) != severity(diffs[j]) {
+			return severity(diffs[i]) > severity(diffs[j])
+		}
+		return diffs[i].SyntheticId < diffs[j].SyntheticId
+	})
+
+	return diffs
+}
+
+func diffList(values []string) string {
+	sorted := append(make([]string, 0), values...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ", ")
+}
+
+func technicalAssetFields(asset *types.TechnicalAsset) map[string]string {
+	formats := make([]string, 0)
+	for _, format := range asset.DataFormatsAccepted {
+		formats = append(formats, format.String())
+	}
+
+	return map[string]string{
+		"description":                asset.Description,
+		"usage":                      asset.Usage.String(),
+		"type":                       asset.Type.String(),
+		"size":                       asset.Size.String(),
+		"technology":                 asset.Technologies.String(),
+		"machine":                    asset.Machine.String(),
+		"internet":                   fmt.Sprint(asset.Internet),
+		"multi_tenant":               fmt.Sprint(asset.MultiTenant),
+		"redundant":                  fmt.Sprint(asset.Redundant),
+		"custom_developed_parts":     fmt.Sprint(asset.CustomDevelopedParts),
+		"out_of_scope":               fmt.Sprint(asset.OutOfScope),
+		"used_as_client_by_human":    fmt.Sprint(asset.UsedAsClientByHuman),
+		"encryption":                 asset.Encryption.String(),
+		"justification_out_of_scope": asset.JustificationOutOfScope,
+		"owner":                      asset.Owner,
+		"confidentiality":            asset.Confidentiality.String(),
+		"integrity":                  asset.Integrity.String(),
+		"availability":               asset.Availability.String(),
+		"justification_cia_rating":   asset.JustificationCiaRating,
+		"tags":                       diffList(asset.Tags),
+		"data_assets_processed":      diffList(asset.DataAssetsProcessed),
+		"data_assets_stored":         diffList(asset.DataAssetsStored),
+		"data_formats_accepted":      diffList(formats),
+	}
+}
+
+func communicationLinkFields(link *types.CommunicationLink) map[string]string {
+	return map[string]string{
+		"target":               link.TargetId,
+		"description":          link.Description,
+		"protocol":             link.Protocol.String(),
+		"authentication":       link.Authentication.String(),
+		"authorization":        link.Authorization.String(),
+		"tags":                 diffList(link.Tags),
+		"vpn":                  fmt.Sprint(link.VPN),
+		"ip_filtered":          fmt.Sprint(link.IpFiltered),
+		"readonly":             fmt.Sprint(link.Readonly),
+		"usage":                link.Usage.String(),
+		"data_assets_sent":     diffList(link.DataAssetsSent),
+		"data_assets_received": diffList(link.DataAssetsReceived),
+	}
+}
+
+func dataAssetFields(asset *types.DataAsset) map[string]string {
+	return map[string]string{
+		"description":              asset.Description,
+		"usage":                    asset.Usage.String(),
+		"tags":                     diffList(asset.Tags),
+		"origin":                   asset.Origin,
+		"owner":                    asset.Owner,
+		"quantity":                 asset.Quantity.String(),
+		"confidentiality":          asset.Confidentiality.String(),
+		"integrity":                asset.Integrity.String(),
+		"availability":             asset.Availability.String(),
+		"justification_cia_rating": asset.JustificationCiaRating,
+	}
+}
+
+func trustBoundaryFields(boundary *types.TrustBoundary) map[string]string {
+	return map[string]string{
+		"description":             boundary.Description,
+		"type":                    boundary.Type.String(),
+		"tags":                    diffList(boundary.Tags),
+		"technical_assets_inside": diffList(boundary.TechnicalAssetsInside),
+		"trust_boundaries_nested": diffList(boundary.TrustBoundariesNested),
+	}
+}
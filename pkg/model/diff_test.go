@@ -0,0 +1,96 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestDiffModelsUnchanged(t *testing.T) {
+	parsedModel := &types.Model{
+		Title: "Shop",
+		TechnicalAssets: map[string]*types.TechnicalAsset{
+			"web": {Id: "web", Title: "Web", Owner: "Shop Team", Tags: []string{"b", "a"}},
+		},
+		GeneratedRisksBySyntheticId: map[string]*types.Risk{
+			"missing-waf@web": {CategoryId: "missing-waf", SyntheticId: "missing-waf@web", Severity: types.LowSeverity},
+		},
+	}
+
+	diff := DiffModels(parsedModel, parsedModel)
+	assert.True(t, diff.IsEmpty())
+}
+
+func TestDiffModels(t *testing.T) {
+	oldModel := &types.Model{
+		Title: "Shop",
+		TechnicalAssets: map[string]*types.TechnicalAsset{
+			"web": {Id: "web", Title: "Web", Owner: "Shop Team", Confidentiality: types.Internal, Tags: []string{"b", "a"}},
+			"db":  {Id: "db", Title: "Database", Owner: "Shop Team"},
+		},
+		CommunicationLinks: map[string]*types.CommunicationLink{
+			"web>db": {Id: "web>db", Title: "DB Traffic", SourceId: "web", TargetId: "db", Protocol: types.JdbcEncrypted},
+		},
+		DataAssets: map[string]*types.DataAsset{
+			"orders": {Id: "orders", Title: "Orders"},
+		},
+		TrustBoundaries: map[string]*types.TrustBoundary{
+			"dmz": {Id: "dmz", Title: "DMZ", TechnicalAssetsInside: []string{"web"}},
+		},
+		GeneratedRisksBySyntheticId: map[string]*types.Risk{
+			"missing-waf@web":        {CategoryId: "missing-waf", SyntheticId: "missing-waf@web", Severity: types.LowSeverity},
+			"sql-nosql-injection@db": {CategoryId: "sql-nosql-injection", SyntheticId: "sql-nosql-injection@db", Severity: types.MediumSeverity},
+			"missing-hardening@web":  {CategoryId: "missing-hardening", SyntheticId: "missing-hardening@web", Severity: types.MediumSeverity},
+		},
+	}
+	newModel := &types.Model{
+		Title: "Shop",
+		TechnicalAssets: map[string]*types.TechnicalAsset{
+			"web":   {Id: "web", Title: "Web", Owner: "Shop Team", Confidentiality: types.Confidential, Tags: []string{"a", "b"}},
+			"db":    {Id: "db", Title: "Database", Owner: "Shop Team"},
+			"cache": {Id: "cache", Title: "Cache"},
+		},
+		CommunicationLinks: map[string]*types.CommunicationLink{
+			"web>db": {Id: "web>db", Title: "DB Traffic", SourceId: "web", TargetId: "db", Protocol: types.JDBC},
+		},
+		DataAssets: map[string]*types.DataAsset{},
+		TrustBoundaries: map[string]*types.TrustBoundary{
+			"dmz": {Id: "dmz", Title: "DMZ", TechnicalAssetsInside: []string{"web", "cache"}},
+		},
+		GeneratedRisksBySyntheticId: map[string]*types.Risk{
+			"sql-nosql-injection@db": {CategoryId: "sql-nosql-injection", SyntheticId: "sql-nosql-injection@db", Severity: types.HighSeverity},
+			"missing-hardening@web":  {CategoryId: "missing-hardening", SyntheticId: "missing-hardening@web", Severity: types.MediumSeverity},
+			"unencrypted-communication@web>db": {CategoryId: "unencrypted-communication", SyntheticId: "unencrypted-communication@web>db",
+				Severity: types.ElevatedSeverity},
+		},
+	}
+
+	diff := DiffModels(oldModel, newModel)
+	assert.False(t, diff.IsEmpty())
+
+	require.Len(t, diff.TechnicalAssets, 2)
+	assert.Equal(t, &ItemDiff{Change: DiffAdded, Id: "cache", Title: "Cache"}, diff.TechnicalAssets[0])
+	assert.Equal(t, &ItemDiff{Change: DiffChanged, Id: "web", Title: "Web", Fields: []*FieldDiff{
+		{Field: "confidentiality", Old: "internal", New: "confidential"},
+	}}, diff.TechnicalAssets[1])
+
+	assert.Equal(t, []*ItemDiff{{Change: DiffChanged, Id: "web>db", Title: "DB Traffic", Fields: []*FieldDiff{
+		{Field: "protocol", Old: "jdbc-encrypted", New: "jdbc"},
+	}}}, diff.CommunicationLinks)
+	assert.Equal(t, []*ItemDiff{{Change: DiffRemoved, Id: "orders", Title: "Orders"}}, diff.DataAssets)
+	require.Len(t, diff.TrustBoundaries, 1)
+	assert.Equal(t, "technical_assets_inside", diff.TrustBoundaries[0].Fields[0].Field)
+
+	require.Len(t, diff.Risks, 3)
+	assert.Equal(t, &RiskDiff{Change: RiskDiffSeverityChanged, SyntheticId: "sql-nosql-injection@db", Category: "sql-nosql-injection",
+		Status: "unchecked", OldSeverity: "medium", NewSeverity: "high"}, diff.Risks[0])
+	assert.Equal(t, RiskDiffNew, diff.Risks[1].Change)
+	assert.Equal(t, "elevated", diff.Risks[1].NewSeverity)
+	assert.Equal(t, RiskDiffResolved, diff.Risks[2].Change)
+	assert.Equal(t, "missing-waf@web", diff.Risks[2].SyntheticId)
+
+	assert.Len(t, diff.RisksByChange(RiskDiffNew), 1)
+	assert.Len(t, diff.RisksByChange(RiskDiffResolved), 1)
+}
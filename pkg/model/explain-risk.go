@@ -0,0 +1,392 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/script"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func (what ReadResult) ExplainRisk(riskId string) ([]string, error) {
+	if what.ParsedModel == nil {
+		return nil, fmt.Errorf("no model loaded")
+	}
+
+	risk, ok := what.ParsedModel.GeneratedRisksBySyntheticId[strings.ToLower(strings.TrimSpace(riskId))]
+	if !ok {
+		return nil, fmt.Errorf("risk %q not found (use the synthetic risk id as shown in the report, the excel sheet or the risks json)", riskId)
+	}
+
+	explanation := make([]string, 0)
+	explanation = append(explanation, fmt.Sprintf("risk %q", risk.SyntheticId))
+	explanation = append(explanation, fmt.Sprintf("  - title: %v", common.StripHTMLTags(risk.Title)))
+	if risk.Position.IsKnown() {
+		explanation = append(explanation, fmt.Sprintf("  - position: %v", risk.Position))
+	}
+	explanation = append(explanation, what.explainRiskCategory(risk)...)
+	explanation = append(explanation, what.explainRiskRule(risk)...)
+	explanation = append(explanation, what.explainRiskElements(risk)...)
+	explanation = append(explanation, explainRiskSeverity(risk)...)
+	explanation = append(explanation, what.explainRiskTracking(risk)...)
+
+	return explanation, nil
+}
+
+func (what ReadResult) explainRiskCategory(risk *types.Risk) []string {
+	category := types.GetRiskCategory(what.ParsedModel, risk.CategoryId)
+	if category == nil {
+		return []string{fmt.Sprintf("  - category: %v (unknown)", risk.CategoryId)}
+	}
+
+	explanation := []string{
+		"",
+		fmt.Sprintf("category %q", category.ID),
+		fmt.Sprintf("  - title: %v", category.Title),
+		fmt.Sprintf("  - stride: %v", category.STRIDE.Title()),
+		fmt.Sprintf("  - function: %v", category.Function.Title()),
+	}
+
+	if category.CWE > 0 {
+		explanation = append(explanation, fmt.Sprintf("  - cwe: CWE-%d", category.CWE))
+	}
+
+	if len(category.DetectionLogic) > 0 {
+		explanation = append(explanation, fmt.Sprintf("  - detection logic: %v", common.StripHTMLTags(category.DetectionLogic)))
+	}
+
+	if len(category.RiskAssessment) > 0 {
+		explanation = append(explanation, fmt.Sprintf("  - risk assessment: %v", common.StripHTMLTags(category.RiskAssessment)))
+	}
+
+	return explanation
+}
+
+func (what ReadResult) explainRiskRule(risk *types.Risk) []string {
+	explanation := []string{"", "rule"}
+
+	rule, isCustomRule := what.CustomRiskRules[risk.CategoryId]
+	if !isCustomRule {
+		rule = what.BuiltinRiskRules[risk.CategoryId]
+	}
+
+	switch castRule := rule.(type) {
+	case nil:
+		if what.isIndividualRiskCategory(risk.CategoryId) {
+			explanation = append(explanation, "  - individual risk identified manually in the model (custom_risk_categories)")
+		} else {
+			explanation = append(explanation, "  - no rule found for this risk category")
+		}
+
+		return explanation
+
+	case *CustomRiskCategory:
+		explanation = append(explanation, fmt.Sprintf("  - custom risk rule plugin %q", castRule.Filename()))
+
+	case *script.RiskRule:
+		explanation = append(explanation, fmt.Sprintf("  - script risk rule %q from %q", castRule.Category().ID, castRule.Source()))
+
+	default:
+		explanation = append(explanation, fmt.Sprintf("  - built-in risk rule %q (%T)", castRule.Category().ID, castRule))
+	}
+
+	if len(rule.SupportedTags()) > 0 {
+		explanation = append(explanation, fmt.Sprintf("  - supported tags: %v", strings.Join(rule.SupportedTags(), ", ")))
+	}
+
+	return explanation
+}
+
+func (what ReadResult) isIndividualRiskCategory(categoryId string) bool {
+	if what.ModelInput == nil {
+		return false
+	}
+
+	for _, category := range what.ModelInput.CustomRiskCategories {
+		if strings.EqualFold(category.ID, categoryId) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (what ReadResult) explainRiskElements(risk *types.Risk) []string {
+	parsedModel := what.ParsedModel
+	explanation := make([]string, 0)
+
+	if len(risk.MostRelevantTechnicalAssetId) > 0 {
+		techAsset, ok := parsedModel.TechnicalAssets[risk.MostRelevantTechnicalAssetId]
+		if ok {
+			explanation = append(explanation, "")
+			explanation = append(explanation, explainTechnicalAsset(parsedModel, techAsset)...)
+		}
+	}
+
+	if len(risk.MostRelevantCommunicationLinkId) > 0 {
+		commLink, ok := parsedModel.CommunicationLinks[risk.MostRelevantCommunicationLinkId]
+		if ok {
+			explanation = append(explanation, "")
+			explanation = append(explanation, explainCommunicationLink(parsedModel, commLink)...)
+		}
+	}
+
+	if len(risk.MostRelevantDataAssetId) > 0 {
+		dataAsset, ok := parsedModel.DataAssets[risk.MostRelevantDataAssetId]
+		if ok {
+			explanation = append(explanation, "")
+			explanation = append(explanation, explainDataAsset(dataAsset)...)
+		}
+	}
+
+	if len(risk.MostRelevantTrustBoundaryId) > 0 {
+		trustBoundary, ok := parsedModel.TrustBoundaries[risk.MostRelevantTrustBoundaryId]
+		if ok {
+			explanation = append(explanation, "")
+			explanation = append(explanation, explainTrustBoundary(parsedModel, trustBoundary)...)
+		}
+	}
+
+	if len(risk.MostRelevantSharedRuntimeId) > 0 {
+		sharedRuntime, ok := parsedModel.SharedRuntimes[risk.MostRelevantSharedRuntimeId]
+		if ok {
+			explanation = append(explanation, "")
+			explanation = append(explanation, explainSharedRuntime(sharedRuntime)...)
+		}
+	}
+
+	explanation = append(explanation, "")
+	explanation = append(explanation, "data breach")
+	explanation = append(explanation, fmt.Sprintf("  - probability: %v", risk.DataBreachProbability))
+	if len(risk.DataBreachTechnicalAssetIDs) > 0 {
+		explanation = append(explanation, fmt.Sprintf("  - technical assets: %v", strings.Join(risk.DataBreachTechnicalAssetIDs, ", ")))
+	}
+
+	return explanation
+}
+
+func explainTechnicalAsset(parsedModel *types.Model, techAsset *types.TechnicalAsset) []string {
+	explanation := []string{
+		fmt.Sprintf("technical asset %q (%v)", techAsset.Id, techAsset.Title),
+		fmt.Sprintf("  - out of scope: %v", techAsset.OutOfScope),
+		fmt.Sprintf("  - type: %v", techAsset.Type),
+		fmt.Sprintf("  - machine: %v", techAsset.Machine),
+		fmt.Sprintf("  - internet: %v", techAsset.Internet),
+		fmt.Sprintf("  - encryption: %v", techAsset.Encryption),
+		fmt.Sprintf("  - custom developed parts: %v", techAsset.CustomDevelopedParts),
+		fmt.Sprintf("  - used as client by human: %v", techAsset.UsedAsClientByHuman),
+		fmt.Sprintf("  - multi tenant: %v", techAsset.MultiTenant),
+		fmt.Sprintf("  - redundant: %v", techAsset.Redundant),
+	}
+
+	explanation = append(explanation, "  - technologies:")
+	for _, technology := range techAsset.Technologies {
+		attributes := make([]string, 0)
+		for name, value := range technology.Attributes {
+			if value {
+				attributes = append(attributes, name)
+			}
+		}
+		sort.Strings(attributes)
+
+		if len(attributes) > 0 {
+			explanation = append(explanation, fmt.Sprintf("      %v (%v)", technology.Name, strings.Join(attributes, ", ")))
+		} else {
+			explanation = append(explanation, fmt.Sprintf("      %v", technology.Name))
+		}
+	}
+
+	explanation = append(explanation,
+		fmt.Sprintf("  - confidentiality: %v (highest processed: %v, highest stored: %v)", techAsset.Confidentiality,
+			techAsset.HighestProcessedConfidentiality(parsedModel), techAsset.HighestStoredConfidentiality(parsedModel)),
+		fmt.Sprintf("  - integrity: %v (highest processed: %v, highest stored: %v)", techAsset.Integrity,
+			techAsset.HighestProcessedIntegrity(parsedModel), techAsset.HighestStoredIntegrity(parsedModel)),
+		fmt.Sprintf("  - availability: %v (highest processed: %v, highest stored: %v)", techAsset.Availability,
+			techAsset.HighestProcessedAvailability(parsedModel), techAsset.HighestStoredAvailability(parsedModel)),
+		fmt.Sprintf("  - trust boundary: %v", explainTrustBoundaryOf(parsedModel, techAsset.Id)),
+	)
+
+	if len(techAsset.Tags) > 0 {
+		explanation = append(explanation, fmt.Sprintf("  - tags: %v", strings.Join(techAsset.Tags, ", ")))
+	}
+
+	return explanation
+}
+
+func explainCommunicationLink(parsedModel *types.Model, commLink *types.CommunicationLink) []string {
+	explanation := []string{
+		fmt.Sprintf("communication link %q (%v)", commLink.Id, commLink.Title),
+		fmt.Sprintf("  - source: %v (trust boundary: %v)", commLink.SourceId, explainTrustBoundaryOf(parsedModel, commLink.SourceId)),
+		fmt.Sprintf("  - target: %v (trust boundary: %v)", commLink.TargetId, explainTrustBoundaryOf(parsedModel, commLink.TargetId)),
+		fmt.Sprintf("  - protocol: %v (encrypted: %v, process local: %v)", commLink.Protocol, commLink.Protocol.IsEncrypted(), commLink.Protocol.IsProcessLocal()),
+		fmt.Sprintf("  - authentication: %v", commLink.Authentication),
+		fmt.Sprintf("  - authorization: %v", commLink.Authorization),
+		fmt.Sprintf("  - usage: %v", commLink.Usage),
+		fmt.Sprintf("  - vpn: %v", commLink.VPN),
+		fmt.Sprintf("  - ip filtered: %v", commLink.IpFiltered),
+		fmt.Sprintf("  - readonly: %v", commLink.Readonly),
+		fmt.Sprintf("  - across trust boundary: %v (network only: %v)", commLink.IsAcrossTrustBoundary(parsedModel), commLink.IsAcrossTrustBoundaryNetworkOnly(parsedModel)),
+		fmt.Sprintf("  - highest confidentiality: %v", commLink.HighestConfidentiality(parsedModel)),
+		fmt.Sprintf("  - highest integrity: %v", commLink.HighestIntegrity(parsedModel)),
+		fmt.Sprintf("  - highest availability: %v", commLink.HighestAvailability(parsedModel)),
+	}
+
+	if len(commLink.DataAssetsSent) > 0 {
+		explanation = append(explanation, fmt.Sprintf("  - data assets sent: %v", strings.Join(commLink.DataAssetsSent, ", ")))
+	}
+
+	if len(commLink.DataAssetsReceived) > 0 {
+		explanation = append(explanation, fmt.Sprintf("  - data assets received: %v", strings.Join(commLink.DataAssetsReceived, ", ")))
+	}
+
+	if len(commLink.Tags) > 0 {
+		explanation = append(explanation, fmt.Sprintf("  - tags: %v", strings.Join(commLink.Tags, ", ")))
+	}
+
+	return explanation
+}
+
+func explainDataAsset(dataAsset *types.DataAsset) []string {
+	explanation := []string{
+		fmt.Sprintf("data asset %q (%v)", dataAsset.Id, dataAsset.Title),
+		fmt.Sprintf("  - confidentiality: %v", dataAsset.Confidentiality),
+		fmt.Sprintf("  - integrity: %v", dataAsset.Integrity),
+		fmt.Sprintf("  - availability: %v", dataAsset.Availability),
+		fmt.Sprintf("  - quantity: %v", dataAsset.Quantity),
+	}
+
+	if len(dataAsset.Tags) > 0 {
+		explanation = append(explanation, fmt.Sprintf("  - tags: %v", strings.Join(dataAsset.Tags, ", ")))
+	}
+
+	return explanation
+}
+
+func explainTrustBoundary(parsedModel *types.Model, trustBoundary *types.TrustBoundary) []string {
+	explanation := []string{
+		fmt.Sprintf("trust boundary %q (%v)", trustBoundary.Id, trustBoundary.Title),
+		fmt.Sprintf("  - type: %v (network boundary: %v)", trustBoundary.Type, trustBoundary.Type.IsNetworkBoundary()),
+		fmt.Sprintf("  - technical assets inside: %v", strings.Join(trustBoundary.RecursivelyAllTechnicalAssetIDsInside(parsedModel), ", ")),
+	}
+
+	parentId := trustBoundary.ParentTrustBoundaryID(parsedModel)
+	if len(parentId) > 0 {
+		explanation = append(explanation, fmt.Sprintf("  - nested in: %v", parentId))
+	}
+
+	if len(trustBoundary.Tags) > 0 {
+		explanation = append(explanation, fmt.Sprintf("  - tags: %v", strings.Join(trustBoundary.Tags, ", ")))
+	}
+
+	return explanation
+}
+
+func explainSharedRuntime(sharedRuntime *types.SharedRuntime) []string {
+	explanation := []string{
+		fmt.Sprintf("shared runtime %q (%v)", sharedRuntime.Id, sharedRuntime.Title),
+		fmt.Sprintf("  - technical assets running: %v", strings.Join(sharedRuntime.TechnicalAssetsRunning, ", ")),
+	}
+
+	if len(sharedRuntime.Tags) > 0 {
+		explanation = append(explanation, fmt.Sprintf("  - tags: %v", strings.Join(sharedRuntime.Tags, ", ")))
+	}
+
+	return explanation
+}
+
+func explainTrustBoundaryOf(parsedModel *types.Model, techAssetId string) string {
+	trustBoundary, ok := parsedModel.DirectContainingTrustBoundaryMappedByTechnicalAssetId[techAssetId]
+	if !ok || trustBoundary == nil {
+		return "none"
+	}
+
+	return fmt.Sprintf("%v (%v)", trustBoundary.Id, trustBoundary.Type)
+}
+
+func explainRiskSeverity(risk *types.Risk) []string {
+	product := risk.ExploitationLikelihood.Weight() * risk.ExploitationImpact.Weight()
+	calculated := types.CalculateSeverity(risk.ExploitationLikelihood, risk.ExploitationImpact)
+
+	explanation := []string{
+		"",
+		"severity",
+		fmt.Sprintf("  - exploitation likelihood: %v (weight %d)", risk.ExploitationLikelihood, risk.ExploitationLikelihood.Weight()),
+		fmt.Sprintf("  - exploitation impact: %v (weight %d)", risk.ExploitationImpact, risk.ExploitationImpact.Weight()),
+		fmt.Sprintf("  - likelihood x impact = %d x %d = %d => %v (low: <=1, medium: <=3, elevated: <=8, high: <=12, critical: >12)",
+			risk.ExploitationLikelihood.Weight(), risk.ExploitationImpact.Weight(), product, calculated),
+	}
+
+	if calculated != risk.Severity {
+		explanation = append(explanation, fmt.Sprintf("  - severity: %v (set explicitly, differs from calculated severity %v)", risk.Severity, calculated))
+	} else {
+		explanation = append(explanation, fmt.Sprintf("  - severity: %v", risk.Severity))
+	}
+
+	return explanation
+}
+
+func (what ReadResult) explainRiskTracking(risk *types.Risk) []string {
+	explanation := []string{"", "risk tracking"}
+
+	directIds := make([]string, 0)
+	wildcardIds := make([]string, 0)
+	if what.ModelInput != nil {
+		for trackingId := range what.ModelInput.RiskTracking {
+			if strings.Contains(trackingId, "*") {
+				matchingRiskIdExpression := regexp.MustCompile(strings.ReplaceAll(regexp.QuoteMeta(trackingId), `\*`, `[^@]+`))
+				if matchingRiskIdExpression.MatchString(strings.ToLower(risk.SyntheticId)) {
+					wildcardIds = append(wildcardIds, trackingId)
+				}
+			} else if strings.EqualFold(strings.TrimSpace(trackingId), risk.SyntheticId) {
+				directIds = append(directIds, trackingId)
+			}
+		}
+	}
+
+	sort.Strings(directIds)
+	sort.Strings(wildcardIds)
+
+	for _, trackingId := range directIds {
+		explanation = append(explanation, fmt.Sprintf("  - direct entry %q", trackingId))
+	}
+
+	for _, trackingId := range wildcardIds {
+		if len(directIds) > 0 {
+			explanation = append(explanation, fmt.Sprintf("  - wildcard entry %q (not applied, overridden by direct entry)", trackingId))
+		} else {
+			explanation = append(explanation, fmt.Sprintf("  - wildcard entry %q", trackingId))
+		}
+	}
+
+	tracking := risk.GetRiskTracking(what.ParsedModel)
+	if tracking == nil {
+		tracking = what.ParsedModel.RiskTracking[strings.ToLower(risk.SyntheticId)]
+	}
+
+	if tracking == nil {
+		explanation = append(explanation, fmt.Sprintf("  - no risk tracking entry applies, status is %v", types.Unchecked))
+		return explanation
+	}
+
+	explanation = append(explanation, fmt.Sprintf("  - status: %v", tracking.Status))
+	if len(tracking.Justification) > 0 {
+		explanation = append(explanation, fmt.Sprintf("  - justification: %v", tracking.Justification))
+	}
+
+	if len(tracking.Ticket) > 0 {
+		explanation = append(explanation, fmt.Sprintf("  - ticket: %v", tracking.Ticket))
+	}
+
+	if len(tracking.CheckedBy) > 0 {
+		explanation = append(explanation, fmt.Sprintf("  - checked by: %v", tracking.CheckedBy))
+	}
+
+	if !tracking.Date.IsZero() {
+		explanation = append(explanation, fmt.Sprintf("  - date: %v", tracking.Date.Format("2006-01-02")))
+	}
+
+	return explanation
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestExplainRiskUnknownRiskFails(t *testing.T) {
+	result := ReadResult{ParsedModel: createExplainModel()}
+
+	_, err := result.ExplainRisk("unknown-risk@ta1")
+
+	assert.Error(t, err)
+}
+
+func TestExplainRiskShowsSeverityDerivation(t *testing.T) {
+	result := ReadResult{ParsedModel: createExplainModel(), ModelInput: new(input.Model).Defaults()}
+
+	explanation, err := result.ExplainRisk("some-risk@ta1")
+
+	assert.NoError(t, err)
+	text := strings.Join(explanation, "\n")
+	assert.Contains(t, text, `technical asset "ta1"`)
+	assert.Contains(t, text, "likelihood x impact = 2 x 3 = 6 => elevated")
+	assert.Contains(t, text, "no risk tracking entry applies")
+}
+
+func TestExplainRiskShowsWildcardRiskTracking(t *testing.T) {
+	parsedModel := createExplainModel()
+	parsedModel.RiskTracking["some-risk@*"] = &types.RiskTracking{SyntheticRiskId: "some-risk@*", Status: types.Accepted, Ticket: "XYZ-1"}
+	parsedModel.RiskTracking["some-risk@ta1"] = &types.RiskTracking{SyntheticRiskId: "some-risk@ta1", Status: types.Accepted, Ticket: "XYZ-1"}
+
+	modelInput := new(input.Model).Defaults()
+	modelInput.RiskTracking["some-risk@*"] = input.RiskTracking{Status: types.Accepted.String(), Ticket: "XYZ-1"}
+
+	result := ReadResult{ParsedModel: parsedModel, ModelInput: modelInput}
+
+	explanation, err := result.ExplainRisk("some-risk@ta1")
+
+	assert.NoError(t, err)
+	text := strings.Join(explanation, "\n")
+	assert.Contains(t, text, `wildcard entry "some-risk@*"`)
+	assert.Contains(t, text, "status: accepted")
+	assert.Contains(t, text, "ticket: XYZ-1")
+}
+
+func createExplainModel() *types.Model {
+	risk := &types.Risk{
+		CategoryId:                   "some-risk",
+		SyntheticId:                  "some-risk@ta1",
+		Title:                        "<b>Some Risk</b> at <b>TA1</b>",
+		ExploitationLikelihood:       types.Likely,
+		ExploitationImpact:           types.HighImpact,
+		Severity:                     types.CalculateSeverity(types.Likely, types.HighImpact),
+		MostRelevantTechnicalAssetId: "ta1",
+	}
+
+	return &types.Model{
+		TechnicalAssets: map[string]*types.TechnicalAsset{
+			"ta1": {Id: "ta1", Title: "TA1"},
+		},
+		DirectContainingTrustBoundaryMappedByTechnicalAssetId: make(map[string]*types.TrustBoundary),
+		RiskTracking: make(map[string]*types.RiskTracking),
+		GeneratedRisksByCategory: map[string][]*types.Risk{
+			"some-risk": {risk},
+		},
+		GeneratedRisksBySyntheticId: map[string]*types.Risk{
+			"some-risk@ta1": risk,
+		},
+	}
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const positionsModelYaml = `business_criticality: archive
+includes:
+  - included.yaml
+technical_assets:
+  Web Server:
+    id: web
+    usage: business
+    type: process
+    size: system
+    technology: web-server
+    encryption: none
+    machine: virtual
+    confidentiality: internal
+    integrity: operational
+    availability: operational
+    communication_links:
+      Database Access:
+        target: db
+        protocol: jdbc
+        authentication: none
+        authorization: none
+        usage: business
+risk_tracking:
+  some-risk@web:
+    status: mitigated
+`
+
+const positionsIncludedYaml = `data_assets:
+  Customers:
+    id: customers
+    usage: business
+    quantity: many
+    confidentiality: confidential
+    integrity: critical
+    availability: critical
+technical_assets:
+  Database:
+    id: db
+    usage: business
+    type: datastore
+    size: component
+    technology: database
+    encryption: none
+    machine: virtual
+    confidentiality: internal
+    integrity: operational
+    availability: operational
+trust_boundaries:
+  Backend:
+    id: backend
+    type: network-cloud-provider
+    technical_assets_inside:
+      - db
+`
+
+func loadPositionsModel(t *testing.T, modelYaml string, includedYaml string) *input.Model {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "threagile.yaml"), []byte(modelYaml), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "included.yaml"), []byte(includedYaml), 0600))
+
+	modelInput := new(input.Model).Defaults()
+	assert.NoError(t, modelInput.Load(filepath.Join(dir, "threagile.yaml")))
+
+	return modelInput
+}
+
+func TestParseModelKeepsPositionsAcrossIncludes(t *testing.T) {
+	modelInput := loadPositionsModel(t, positionsModelYaml, positionsIncludedYaml)
+
+	parsedModel, err := ParseModel(&common.Config{}, modelInput, make(types.RiskRules), make(types.RiskRules))
+	assert.NoError(t, err)
+
+	modelFilename := filepath.Base(parsedModel.TechnicalAssets["web"].Position.Filename)
+	assert.Equal(t, "threagile.yaml", modelFilename)
+	assert.Equal(t, 5, parsedModel.TechnicalAssets["web"].Position.Line)
+	assert.Equal(t, 3, parsedModel.TechnicalAssets["web"].Position.Column)
+	assert.Equal(t, 17, parsedModel.CommunicationLinks["web>database-access"].Position.Line)
+	assert.Equal(t, 7, parsedModel.CommunicationLinks["web>database-access"].Position.Column)
+	assert.Equal(t, 24, parsedModel.RiskTracking["some-risk@web"].Position.Line)
+
+	includedFilename := filepath.Base(parsedModel.TechnicalAssets["db"].Position.Filename)
+	assert.Equal(t, "included.yaml", includedFilename)
+	assert.Equal(t, 2, parsedModel.DataAssets["customers"].Position.Line)
+	assert.Equal(t, 10, parsedModel.TechnicalAssets["db"].Position.Line)
+	assert.Equal(t, 22, parsedModel.TrustBoundaries["backend"].Position.Line)
+	assert.Equal(t, "included.yaml", filepath.Base(parsedModel.TrustBoundaries["backend"].Position.Filename))
+}
+
+func TestParseModelReportsPositionOfErrors(t *testing.T) {
+	modelInput := loadPositionsModel(t, positionsModelYaml, positionsIncludedYaml+`shared_runtimes:
+  Cluster:
+    id: cluster
+    technical_assets_running:
+      - no-such-asset
+`)
+
+	_, err := ParseModel(&common.Config{}, modelInput, make(types.RiskRules), make(types.RiskRules))
+	assert.ErrorContains(t, err, "included.yaml:28:3: ")
+}
+
+func TestRiskPositionPrefersCommunicationLink(t *testing.T) {
+	modelInput := loadPositionsModel(t, positionsModelYaml, positionsIncludedYaml)
+	parsedModel, err := ParseModel(&common.Config{}, modelInput, make(types.RiskRules), make(types.RiskRules))
+	assert.NoError(t, err)
+
+	linkRisk := &types.Risk{MostRelevantTechnicalAssetId: "web", MostRelevantCommunicationLinkId: "web>database-access"}
+	assert.Equal(t, 17, parsedModel.RiskPosition(linkRisk).Line)
+
+	assetRisk := &types.Risk{MostRelevantTechnicalAssetId: "db", MostRelevantTrustBoundaryId: "backend"}
+	assert.Equal(t, 10, parsedModel.RiskPosition(assetRisk).Line)
+
+	dataRisk := &types.Risk{MostRelevantDataAssetId: "customers"}
+	assert.Equal(t, 2, parsedModel.RiskPosition(dataRisk).Line)
+
+	assert.False(t, parsedModel.RiskPosition(&types.Risk{MostRelevantTechnicalAssetId: "unknown"}).IsKnown())
+}
@@ -80,35 +80,35 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 
 		usage, err := types.ParseUsage(asset.Usage)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'usage' value of data asset %q: %v", title, asset.Usage)
+			return nil, asset.Position.Errorf("unknown 'usage' value of data asset %q: %v", title, asset.Usage)
 		}
 		quantity, err := types.ParseQuantity(asset.Quantity)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'quantity' value of data asset %q: %v", title, asset.Quantity)
+			return nil, asset.Position.Errorf("unknown 'quantity' value of data asset %q: %v", title, asset.Quantity)
 		}
 		confidentiality, err := types.ParseConfidentiality(asset.Confidentiality)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'confidentiality' value of data asset %q: %v", title, asset.Confidentiality)
+			return nil, asset.Position.Errorf("unknown 'confidentiality' value of data asset %q: %v", title, asset.Confidentiality)
 		}
 		integrity, err := types.ParseCriticality(asset.Integrity)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'integrity' value of data asset %q: %v", title, asset.Integrity)
+			return nil, asset.Position.Errorf("unknown 'integrity' value of data asset %q: %v", title, asset.Integrity)
 		}
 		availability, err := types.ParseCriticality(asset.Availability)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'availability' value of data asset %q: %v", title, asset.Availability)
+			return nil, asset.Position.Errorf("unknown 'availability' value of data asset %q: %v", title, asset.Availability)
 		}
 
 		err = checkIdSyntax(id)
 		if err != nil {
-			return nil, err
+			return nil, asset.Position.Wrap(err)
 		}
 		if _, exists := parsedModel.DataAssets[id]; exists {
-			return nil, fmt.Errorf("duplicate id used: %v", id)
+			return nil, asset.Position.Errorf("duplicate id used: %v", id)
 		}
 		tags, err := parsedModel.CheckTags(lowerCaseAndTrim(asset.Tags), "data asset '"+title+"'")
 		if err != nil {
-			return nil, err
+			return nil, asset.Position.Wrap(err)
 		}
 		parsedModel.DataAssets[id] = &types.DataAsset{
 			Id:                     id,
@@ -123,6 +123,7 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 			Integrity:              integrity,
 			Availability:           availability,
 			JustificationCiaRating: fmt.Sprintf("%v", asset.JustificationCiaRating),
+			Position:               asset.Position,
 		}
 	}
 
@@ -133,7 +134,7 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 
 		usage, err := types.ParseUsage(asset.Usage)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'usage' value of technical asset %q: %v", title, asset.Usage)
+			return nil, asset.Position.Errorf("unknown 'usage' value of technical asset %q: %v", title, asset.Usage)
 		}
 
 		var dataAssetsStored = make([]string, 0)
@@ -146,7 +147,7 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 
 				err := parsedModel.CheckDataAssetTargetExists(referencedAsset, fmt.Sprintf("technical asset %q", title))
 				if err != nil {
-					return nil, err
+					return nil, asset.Position.Wrap(err)
 				}
 				dataAssetsStored = append(dataAssetsStored, referencedAsset)
 			}
@@ -162,7 +163,7 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 
 				err := parsedModel.CheckDataAssetTargetExists(referencedAsset, "technical asset '"+title+"'")
 				if err != nil {
-					return nil, err
+					return nil, asset.Position.Wrap(err)
 				}
 				dataAssetsProcessed = append(dataAssetsProcessed, referencedAsset)
 			}
@@ -170,11 +171,11 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 
 		technicalAssetType, err := types.ParseTechnicalAssetType(asset.Type)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'type' value of technical asset %q: %v", title, asset.Type)
+			return nil, asset.Position.Errorf("unknown 'type' value of technical asset %q: %v", title, asset.Type)
 		}
 		technicalAssetSize, err := types.ParseTechnicalAssetSize(asset.Size)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'size' value of technical asset %q: %v", title, asset.Size)
+			return nil, asset.Position.Errorf("unknown 'size' value of technical asset %q: %v", title, asset.Size)
 		}
 
 		technicalAssetTechnologies := make([]*types.Technology, 0)
@@ -186,7 +187,7 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 		for _, technologyName := range allTechnologies {
 			technicalAssetTechnology := technologies.Get(technologyName)
 			if technicalAssetTechnology == nil {
-				return nil, fmt.Errorf("unknown 'technology' value of technical asset %q: %v", title, asset.Technology)
+				return nil, asset.Position.Errorf("unknown 'technology' value of technical asset %q: %v", title, asset.Technology)
 			}
 
 			technicalAssetTechnologies = append(technicalAssetTechnologies, technicalAssetTechnology)
@@ -194,23 +195,23 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 
 		encryption, err := types.ParseEncryptionStyle(asset.Encryption)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'encryption' value of technical asset %q: %v", title, asset.Encryption)
+			return nil, asset.Position.Errorf("unknown 'encryption' value of technical asset %q: %v", title, asset.Encryption)
 		}
 		technicalAssetMachine, err := types.ParseTechnicalAssetMachine(asset.Machine)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'machine' value of technical asset %q: %v", title, asset.Machine)
+			return nil, asset.Position.Errorf("unknown 'machine' value of technical asset %q: %v", title, asset.Machine)
 		}
 		confidentiality, err := types.ParseConfidentiality(asset.Confidentiality)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'confidentiality' value of technical asset %q: %v", title, asset.Confidentiality)
+			return nil, asset.Position.Errorf("unknown 'confidentiality' value of technical asset %q: %v", title, asset.Confidentiality)
 		}
 		integrity, err := types.ParseCriticality(asset.Integrity)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'integrity' value of technical asset %q: %v", title, asset.Integrity)
+			return nil, asset.Position.Errorf("unknown 'integrity' value of technical asset %q: %v", title, asset.Integrity)
 		}
 		availability, err := types.ParseCriticality(asset.Availability)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'availability' value of technical asset %q: %v", title, asset.Availability)
+			return nil, asset.Position.Errorf("unknown 'availability' value of technical asset %q: %v", title, asset.Availability)
 		}
 
 		dataFormatsAccepted := make([]types.DataFormat, 0)
@@ -218,7 +219,7 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 			for _, dataFormatName := range asset.DataFormatsAccepted {
 				dataFormat, err := types.ParseDataFormat(dataFormatName)
 				if err != nil {
-					return nil, fmt.Errorf("unknown 'data_formats_accepted' value of technical asset %q: %v", title, dataFormatName)
+					return nil, asset.Position.Errorf("unknown 'data_formats_accepted' value of technical asset %q: %v", title, dataFormatName)
 				}
 				dataFormatsAccepted = append(dataFormatsAccepted, dataFormat)
 			}
@@ -233,19 +234,19 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 
 				authentication, err := types.ParseAuthentication(commLink.Authentication)
 				if err != nil {
-					return nil, fmt.Errorf("unknown 'authentication' value of technical asset %q communication link %q: %v", title, commLinkTitle, commLink.Authentication)
+					return nil, commLink.Position.Errorf("unknown 'authentication' value of technical asset %q communication link %q: %v", title, commLinkTitle, commLink.Authentication)
 				}
 				authorization, err := types.ParseAuthorization(commLink.Authorization)
 				if err != nil {
-					return nil, fmt.Errorf("unknown 'authorization' value of technical asset %q communication link %q: %v", title, commLinkTitle, commLink.Authorization)
+					return nil, commLink.Position.Errorf("unknown 'authorization' value of technical asset %q communication link %q: %v", title, commLinkTitle, commLink.Authorization)
 				}
 				usage, err := types.ParseUsage(commLink.Usage)
 				if err != nil {
-					return nil, fmt.Errorf("unknown 'usage' value of technical asset %q communication link %q: %v", title, commLinkTitle, commLink.Usage)
+					return nil, commLink.Position.Errorf("unknown 'usage' value of technical asset %q communication link %q: %v", title, commLinkTitle, commLink.Usage)
 				}
 				protocol, err := types.ParseProtocol(commLink.Protocol)
 				if err != nil {
-					return nil, fmt.Errorf("unknown 'protocol' value of technical asset %q communication link %q: %v", title, commLinkTitle, commLink.Protocol)
+					return nil, commLink.Position.Errorf("unknown 'protocol' value of technical asset %q communication link %q: %v", title, commLinkTitle, commLink.Protocol)
 				}
 
 				if commLink.DataAssetsSent != nil {
@@ -254,7 +255,7 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 						if !contains(dataAssetsSent, referencedAsset) {
 							err := parsedModel.CheckDataAssetTargetExists(referencedAsset, fmt.Sprintf("communication link %q of technical asset %q", commLinkTitle, title))
 							if err != nil {
-								return nil, err
+								return nil, commLink.Position.Wrap(err)
 							}
 
 							dataAssetsSent = append(dataAssetsSent, referencedAsset)
@@ -274,7 +275,7 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 
 						err := parsedModel.CheckDataAssetTargetExists(referencedAsset, "communication link '"+commLinkTitle+"' of technical asset '"+title+"'")
 						if err != nil {
-							return nil, err
+							return nil, commLink.Position.Wrap(err)
 						}
 						dataAssetsReceived = append(dataAssetsReceived, referencedAsset)
 
@@ -291,11 +292,11 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 				dataFlowTitle := fmt.Sprintf("%v", commLinkTitle)
 				commLinkId, err := createDataFlowId(id, dataFlowTitle)
 				if err != nil {
-					return nil, err
+					return nil, commLink.Position.Wrap(err)
 				}
 				tags, err := parsedModel.CheckTags(lowerCaseAndTrim(commLink.Tags), "communication link '"+commLinkTitle+"' of technical asset '"+title+"'")
 				if err != nil {
-					return nil, err
+					return nil, commLink.Position.Wrap(err)
 				}
 				commLink := &types.CommunicationLink{
 					Id:                     commLinkId,
@@ -315,6 +316,7 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 					DataAssetsReceived:     dataAssetsReceived,
 					DiagramTweakWeight:     weight,
 					DiagramTweakConstraint: !commLink.DiagramTweakConstraint,
+					Position:               commLink.Position,
 				}
 				communicationLinks = append(communicationLinks, commLink)
 				// track all comm links
@@ -327,14 +329,14 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 
 		err = checkIdSyntax(id)
 		if err != nil {
-			return nil, err
+			return nil, asset.Position.Wrap(err)
 		}
 		if _, exists := parsedModel.TechnicalAssets[id]; exists {
-			return nil, fmt.Errorf("duplicate id used: %v", id)
+			return nil, asset.Position.Errorf("duplicate id used: %v", id)
 		}
 		tags, err := parsedModel.CheckTags(lowerCaseAndTrim(asset.Tags), fmt.Sprintf("technical asset %q", title))
 		if err != nil {
-			return nil, err
+			return nil, asset.Position.Wrap(err)
 		}
 		parsedModel.TechnicalAssets[id] = &types.TechnicalAsset{
 			Id:                      id,
@@ -364,6 +366,7 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 			DataFormatsAccepted:     dataFormatsAccepted,
 			CommunicationLinks:      communicationLinks,
 			DiagramTweakOrder:       asset.DiagramTweakOrder,
+			Position:                asset.Position,
 		}
 	}
 
@@ -395,7 +398,7 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 			}
 			targetTechAsset := parsedModel.TechnicalAssets[commLink.TargetId]
 			if targetTechAsset == nil {
-				return nil, fmt.Errorf("missing target technical asset %q for communication link: %q", commLink.TargetId, commLink.Title)
+				return nil, commLink.Position.Errorf("missing target technical asset %q for communication link: %q", commLink.TargetId, commLink.Title)
 			}
 			dataAssetsProcessedByTarget := targetTechAsset.DataAssetsProcessed
 			for _, dataAssetSent := range commLink.DataAssetsSent {
@@ -427,10 +430,10 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 				technicalAssetsInside[i] = strings.ToLower(parsedInsideAsset)
 				_, found := parsedModel.TechnicalAssets[technicalAssetsInside[i]]
 				if !found {
-					return nil, fmt.Errorf("missing referenced technical asset %q at trust boundary %q", technicalAssetsInside[i], title)
+					return nil, boundary.Position.Errorf("missing referenced technical asset %q at trust boundary %q", technicalAssetsInside[i], title)
 				}
 				if checklistToAvoidAssetBeingModeledInMultipleTrustBoundaries[technicalAssetsInside[i]] {
-					return nil, fmt.Errorf("referenced technical asset %q at trust boundary %q is modeled in multiple trust boundaries", technicalAssetsInside[i], title)
+					return nil, boundary.Position.Errorf("referenced technical asset %q at trust boundary %q is modeled in multiple trust boundaries", technicalAssetsInside[i], title)
 				}
 				checklistToAvoidAssetBeingModeledInMultipleTrustBoundaries[technicalAssetsInside[i]] = true
 				//fmt.Println("asset "+technicalAssetsInside[i]+" at i="+strconv.Itoa(i))
@@ -448,11 +451,11 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 
 		trustBoundaryType, err := types.ParseTrustBoundary(boundary.Type)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'type' of trust boundary %q: %v", title, boundary.Type)
+			return nil, boundary.Position.Errorf("unknown 'type' of trust boundary %q: %v", title, boundary.Type)
 		}
 		tags, err := parsedModel.CheckTags(lowerCaseAndTrim(boundary.Tags), fmt.Sprintf("trust boundary %q", title))
 		if err != nil {
-			return nil, err
+			return nil, boundary.Position.Wrap(err)
 		}
 		trustBoundary := &types.TrustBoundary{
 			Id:                    id,
@@ -462,13 +465,14 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 			Tags:                  tags,
 			TechnicalAssetsInside: technicalAssetsInside,
 			TrustBoundariesNested: trustBoundariesNested,
+			Position:              boundary.Position,
 		}
 		err = checkIdSyntax(id)
 		if err != nil {
-			return nil, err
+			return nil, boundary.Position.Wrap(err)
 		}
 		if _, exists := parsedModel.TrustBoundaries[id]; exists {
-			return nil, fmt.Errorf("duplicate id used: %v", id)
+			return nil, boundary.Position.Errorf("duplicate id used: %v", id)
 		}
 		parsedModel.TrustBoundaries[id] = trustBoundary
 		for _, technicalAsset := range trustBoundary.TechnicalAssetsInside {
@@ -494,14 +498,14 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 				assetId := fmt.Sprintf("%v", parsedRunningAsset)
 				err := parsedModel.CheckTechnicalAssetExists(assetId, "shared runtime '"+title+"'", false)
 				if err != nil {
-					return nil, err
+					return nil, inputRuntime.Position.Wrap(err)
 				}
 				technicalAssetsRunning[i] = assetId
 			}
 		}
 		tags, err := parsedModel.CheckTags(lowerCaseAndTrim(inputRuntime.Tags), "shared runtime '"+title+"'")
 		if err != nil {
-			return nil, err
+			return nil, inputRuntime.Position.Wrap(err)
 		}
 		sharedRuntime := &types.SharedRuntime{
 			Id:                     id,
@@ -509,13 +513,14 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 			Description:            withDefault(fmt.Sprintf("%v", inputRuntime.Description), title),
 			Tags:                   tags,
 			TechnicalAssetsRunning: technicalAssetsRunning,
+			Position:               inputRuntime.Position,
 		}
 		err = checkIdSyntax(id)
 		if err != nil {
-			return nil, err
+			return nil, inputRuntime.Position.Wrap(err)
 		}
 		if _, exists := parsedModel.SharedRuntimes[id]; exists {
-			return nil, fmt.Errorf("duplicate id used: %v", id)
+			return nil, inputRuntime.Position.Errorf("duplicate id used: %v", id)
 		}
 		parsedModel.SharedRuntimes[id] = sharedRuntime
 	}
@@ -679,13 +684,31 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 			var parseError error
 			date, parseError = time.Parse("2006-01-02", riskTracking.Date)
 			if parseError != nil {
-				return nil, fmt.Errorf("unable to parse 'date' of risk tracking %q: %v", syntheticRiskId, riskTracking.Date)
+				return nil, riskTracking.Position.Errorf("unable to parse 'date' of risk tracking %q: %v", syntheticRiskId, riskTracking.Date)
+			}
+		}
+
+		var expires time.Time
+		if len(riskTracking.Expires) > 0 {
+			var parseError error
+			expires, parseError = time.Parse("2006-01-02", riskTracking.Expires)
+			if parseError != nil {
+				return nil, riskTracking.Position.Errorf("unable to parse 'expires' of risk tracking %q: %v", syntheticRiskId, riskTracking.Expires)
+			}
+		}
+
+		var reviewBy time.Time
+		if len(riskTracking.ReviewBy) > 0 {
+			var parseError error
+			reviewBy, parseError = time.Parse("2006-01-02", riskTracking.ReviewBy)
+			if parseError != nil {
+				return nil, riskTracking.Position.Errorf("unable to parse 'review_by' of risk tracking %q: %v", syntheticRiskId, riskTracking.ReviewBy)
 			}
 		}
 
 		status, err := types.ParseRiskStatus(riskTracking.Status)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'status' value of risk tracking %q: %v", syntheticRiskId, riskTracking.Status)
+			return nil, riskTracking.Position.Errorf("unknown 'status' value of risk tracking %q: %v", syntheticRiskId, riskTracking.Status)
 		}
 
 		tracking := &types.RiskTracking{
@@ -694,7 +717,11 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 			CheckedBy:       checkedBy,
 			Ticket:          ticket,
 			Date:            types.Date{Time: date},
+			Expires:         types.Date{Time: expires},
+			ReviewBy:        types.Date{Time: reviewBy},
+			Owner:           strings.TrimSpace(riskTracking.Owner),
 			Status:          status,
+			Position:        riskTracking.Position,
 		}
 
 		parsedModel.RiskTracking[syntheticRiskId] = tracking
@@ -705,7 +732,7 @@ func ParseModel(config *common.Config, modelInput *input.Model, builtinRiskRules
 		for _, commLink := range technicalAsset.CommunicationLinks {
 			err := parsedModel.CheckTechnicalAssetExists(commLink.TargetId, "communication link '"+commLink.Title+"' of technical asset '"+technicalAsset.Title+"'", false)
 			if err != nil {
-				return nil, err
+				return nil, commLink.Position.Wrap(err)
 			}
 		}
 	}
@@ -0,0 +1,213 @@
+package model
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const pluginStartTimeout = 10 * time.Second
+
+// pluginClient keeps a custom risk rule plugin running and calls it over its stdin and stdout; the model is sent once
+// and then reused for all calls until a different model is analyzed
+
+type pluginClient struct {
+	Filename string
+	Limits   common.PluginLimits
+	lock     sync.Mutex
+	command  *exec.Cmd
+	cleanup  func()
+	stdin    io.WriteCloser
+	stdout   *bufio.Reader
+	stderr   *limitedBuffer
+	lastId   int
+	model    *types.Model
+}
+
+// Start runs the plugin in serve mode until the context ends and gets its risk category; plugins that don't answer
+// within pluginStartTimeout are stopped, so the caller can fall back to running them once per call
+
+func (what *pluginClient) Start(ctx context.Context, filename string, config *common.Config) (*CustomRiskCategory, error) {
+	what.Filename = filename
+	what.Limits = config.Plugins.Limits(filename)
+
+	command, cleanup, commandError := newPluginCommand(ctx, filename, what.Limits, config.TempFolder, PluginServeFlag)
+	if commandError != nil {
+		return nil, commandError
+	}
+
+	what.command = command
+	what.cleanup = cleanup
+	what.stderr = newLimitedBuffer(what.Limits.MaxOutputBytes, nil)
+	what.command.Stderr = what.stderr
+
+	stdin, stdinError := what.command.StdinPipe()
+	if stdinError != nil {
+		cleanup()
+		return nil, stdinError
+	}
+
+	stdout, stdoutError := what.command.StdoutPipe()
+	if stdoutError != nil {
+		cleanup()
+		return nil, stdoutError
+	}
+
+	what.stdin = stdin
+	what.stdout = bufio.NewReader(stdout)
+
+	startError := what.command.Start()
+	if startError != nil {
+		cleanup()
+		return nil, startError
+	}
+
+	category := new(CustomRiskCategory)
+	callError := what.callWithin(pluginStartTimeout, PluginGetInfoMethod, nil, category)
+	if callError != nil {
+		_ = what.Close()
+		return nil, callError
+	}
+
+	return category, nil
+}
+
+func (what *pluginClient) GenerateRisks(parsedModel *types.Model) ([]*types.Risk, error) {
+	what.lock.Lock()
+	defer what.lock.Unlock()
+
+	if what.model != parsedModel {
+		setError := what.callWithin(what.Limits.Timeout(), PluginSetModelMethod, parsedModel, nil)
+		if setError != nil {
+			return nil, setError
+		}
+
+		what.model = parsedModel
+	}
+
+	generatedRisks := make([]*types.Risk, 0)
+	callError := what.callWithin(what.Limits.Timeout(), PluginGenerateRisksMethod, nil, &generatedRisks)
+	if callError != nil {
+		return nil, callError
+	}
+
+	return generatedRisks, nil
+}
+
+func (what *pluginClient) Call(method string, params any, result any) error {
+	what.lock.Lock()
+	defer what.lock.Unlock()
+
+	return what.callWithin(what.Limits.Timeout(), method, params, result)
+}
+
+// Close ends the plugin by closing its input, and kills it if it doesn't exit within its timeout
+
+func (what *pluginClient) Close() error {
+	what.lock.Lock()
+	defer what.lock.Unlock()
+
+	what.model = nil
+	if what.stdin != nil {
+		_ = what.stdin.Close()
+	}
+
+	if what.command == nil || what.command.Process == nil {
+		return nil
+	}
+
+	timeout := what.Limits.Timeout()
+	process := what.command.Process
+	timedOut := new(atomic.Bool)
+	timer := time.AfterFunc(timeout, func() {
+		timedOut.Store(true)
+		_ = process.Kill()
+	})
+
+	waitError := what.command.Wait()
+	timer.Stop()
+	what.command = nil
+	what.cleanup()
+	if timedOut.Load() {
+		return &PluginRunError{Filename: what.Filename, Reason: PluginTimedOut, Output: what.stderr.String(), Err: fmt.Errorf("exit after %v", timeout)}
+	}
+
+	return waitError
+}
+
+// callWithin kills the plugin if it doesn't answer in time, since a plugin stuck in a call can't take any more calls
+
+func (what *pluginClient) callWithin(timeout time.Duration, method string, params any, result any) error {
+	if what.command == nil || what.command.Process == nil {
+		return what.call(method, params, result)
+	}
+
+	process := what.command.Process
+	timedOut := new(atomic.Bool)
+	timer := time.AfterFunc(timeout, func() {
+		timedOut.Store(true)
+		_ = process.Kill()
+	})
+
+	callError := what.call(method, params, result)
+	if !timer.Stop() && timedOut.Load() {
+		return &PluginRunError{Filename: what.Filename, Reason: PluginTimedOut, Output: what.stderr.String(), Err: fmt.Errorf("call %q after %v", method, timeout)}
+	}
+
+	return callError
+}
+
+func (what *pluginClient) call(method string, params any, result any) error {
+	if what.command == nil {
+		return fmt.Errorf("plugin %q is not running", what.Filename)
+	}
+
+	what.lastId++
+	request := PluginRequest{Version: pluginRpcVersion, Id: what.lastId, Method: method}
+	if params != nil {
+		data, marshalError := json.Marshal(params)
+		if marshalError != nil {
+			return fmt.Errorf("error encoding parameters of %q: %w", method, marshalError)
+		}
+
+		request.Params = data
+	}
+
+	writeError := writePluginMessage(what.stdin, request)
+	if writeError != nil {
+		return what.failed(method, writeError)
+	}
+
+	var response PluginResponse
+	readError := readPluginMessage(what.stdout, &response, what.Limits.MaxOutputBytes)
+	if readError != nil {
+		return what.failed(method, readError)
+	}
+
+	if response.Id != request.Id {
+		return fmt.Errorf("plugin %q answered call #%d instead of #%d", what.Filename, response.Id, request.Id)
+	}
+
+	if response.Error != nil {
+		return response.Error
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return json.Unmarshal(response.Result, result)
+}
+
+func (what *pluginClient) failed(method string, callError error) error {
+	return &PluginRunError{Filename: what.Filename, Reason: PluginFailed, Output: what.stderr.String(), Err: fmt.Errorf("call %q: %v", method, callError)}
+}
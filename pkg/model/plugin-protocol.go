@@ -0,0 +1,159 @@
+package model
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// Custom risk rule plugins started with PluginServeFlag stay running for the whole analysis and answer JSON-RPC 2.0
+// calls on stdin and stdout. Each message is framed by a Content-Length header, as in the language server protocol.
+
+const (
+	PluginServeFlag = "-serve"
+
+	PluginSetModelMethod      = "set-model"
+	PluginGetInfoMethod       = "get-info"
+	PluginGenerateRisksMethod = "generate-risks"
+	PluginSupportedTagsMethod = "supported-tags"
+
+	pluginRpcVersion          = "2.0"
+	pluginMethodNotFound      = -32601
+	pluginInvalidParams       = -32602
+	pluginInternalError       = -32603
+	pluginContentLengthHeader = "Content-Length"
+	pluginMaxContentLength    = 1 << 30
+)
+
+type PluginRequest struct {
+	Version string          `json:"jsonrpc"`
+	Id      int             `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type PluginResponse struct {
+	Version string          `json:"jsonrpc"`
+	Id      int             `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *PluginError    `json:"error,omitempty"`
+}
+
+type PluginError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (what *PluginError) Error() string {
+	return fmt.Sprintf("plugin error %d: %v", what.Code, what.Message)
+}
+
+// ServePlugin answers calls for the given risk rule until its input is closed; a plugin calls it when started with
+// PluginServeFlag
+
+func ServePlugin(rule types.RiskRule, in io.Reader, out io.Writer) error {
+	reader := bufio.NewReader(in)
+	var model *types.Model
+	for {
+		var request PluginRequest
+		readError := readPluginMessage(reader, &request, pluginMaxContentLength)
+		if readError == io.EOF {
+			return nil
+		}
+
+		if readError != nil {
+			return readError
+		}
+
+		var result any
+		var callError *PluginError
+		switch request.Method {
+		case PluginSetModelMethod:
+			newModel := new(types.Model)
+			if unmarshalError := json.Unmarshal(request.Params, newModel); unmarshalError != nil {
+				callError = &PluginError{Code: pluginInvalidParams, Message: unmarshalError.Error()}
+				break
+			}
+
+			model = newModel
+
+		case PluginGetInfoMethod:
+			result = new(CustomRiskCategory).Init(rule.Category(), rule.SupportedTags())
+
+		case PluginSupportedTagsMethod:
+			result = rule.SupportedTags()
+
+		case PluginGenerateRisksMethod:
+			if model == nil {
+				callError = &PluginError{Code: pluginInvalidParams, Message: "no model set"}
+				break
+			}
+
+			risks, riskError := rule.GenerateRisks(model)
+			if riskError != nil {
+				callError = &PluginError{Code: pluginInternalError, Message: riskError.Error()}
+				break
+			}
+
+			result = risks
+
+		default:
+			callError = &PluginError{Code: pluginMethodNotFound, Message: fmt.Sprintf("unknown method %q", request.Method)}
+		}
+
+		response := PluginResponse{Version: pluginRpcVersion, Id: request.Id, Error: callError}
+		if callError == nil {
+			data, marshalError := json.Marshal(result)
+			if marshalError != nil {
+				return fmt.Errorf("failed to encode result of %q: %v", request.Method, marshalError)
+			}
+
+			response.Result = data
+		}
+
+		writeError := writePluginMessage(out, response)
+		if writeError != nil {
+			return writeError
+		}
+	}
+}
+
+func writePluginMessage(writer io.Writer, message any) error {
+	data, marshalError := json.Marshal(message)
+	if marshalError != nil {
+		return marshalError
+	}
+
+	_, writeError := fmt.Fprintf(writer, "%v: %d\r\n\r\n%s", pluginContentLengthHeader, len(data), data)
+	return writeError
+}
+
+func readPluginMessage(reader *bufio.Reader, message any, maxLength int) error {
+	header, headerError := textproto.NewReader(reader).ReadMIMEHeader()
+	if headerError != nil {
+		if headerError == io.EOF && len(header) == 0 {
+			return io.EOF
+		}
+
+		return fmt.Errorf("failed to read message header: %v", headerError)
+	}
+
+	length, lengthError := strconv.Atoi(strings.TrimSpace(header.Get(pluginContentLengthHeader)))
+	if lengthError != nil || length < 0 || length > maxLength {
+		return fmt.Errorf("invalid %v header %q", pluginContentLengthHeader, header.Get(pluginContentLengthHeader))
+	}
+
+	data := make([]byte, length)
+	_, readError := io.ReadFull(reader, data)
+	if readError != nil {
+		return fmt.Errorf("failed to read message: %v", readError)
+	}
+
+	return json.Unmarshal(data, message)
+}
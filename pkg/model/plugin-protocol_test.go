@@ -0,0 +1,175 @@
+package model
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const (
+	servePluginTestEnv       = "THREAGILE_TEST_SERVE_PLUGIN"
+	servePluginTestLingering = "lingering" // keeps running after its input is closed
+)
+
+// pluginTestRule creates one risk per technical asset
+
+type pluginTestRule struct{}
+
+func (what pluginTestRule) Category() *types.RiskCategory {
+	return &types.RiskCategory{ID: "plugin-test", Title: "Plugin Test"}
+}
+
+func (what pluginTestRule) SupportedTags() []string {
+	return []string{"plugin-tag"}
+}
+
+func (what pluginTestRule) GenerateRisks(parsedModel *types.Model) ([]*types.Risk, error) {
+	risks := make([]*types.Risk, 0)
+	for id := range parsedModel.TechnicalAssets {
+		risks = append(risks, &types.Risk{CategoryId: "plugin-test", SyntheticId: "plugin-test@" + id, MostRelevantTechnicalAssetId: id})
+	}
+
+	return risks, nil
+}
+
+// TestMain lets the test binary act as a plugin when started by the plugin client and runner tests
+
+func TestMain(m *testing.M) {
+	if len(os.Args) > 2 && os.Args[1] == runnerTestPluginFlag {
+		os.Exit(runRunnerTestPlugin(os.Args[2]))
+	}
+
+	if mode := os.Getenv(servePluginTestEnv); mode == "1" || mode == servePluginTestLingering {
+		if len(os.Args) > 1 && os.Args[len(os.Args)-1] == PluginServeFlag {
+			if serveError := ServePlugin(pluginTestRule{}, os.Stdin, os.Stdout); serveError != nil {
+				os.Exit(2)
+			}
+
+			if mode == servePluginTestLingering {
+				time.Sleep(time.Minute)
+			}
+
+			os.Exit(0)
+		}
+
+		os.Exit(2)
+	}
+
+	os.Exit(m.Run())
+}
+
+type pluginTestConnection struct {
+	in     *io.PipeWriter
+	out    *bufio.Reader
+	lastId int
+}
+
+func newPluginTestConnection(t *testing.T) *pluginTestConnection {
+	requestReader, requestWriter := io.Pipe()
+	responseReader, responseWriter := io.Pipe()
+
+	go func() {
+		_ = ServePlugin(pluginTestRule{}, requestReader, responseWriter)
+		_ = responseWriter.Close()
+	}()
+
+	t.Cleanup(func() { _ = requestWriter.Close() })
+
+	return &pluginTestConnection{in: requestWriter, out: bufio.NewReader(responseReader)}
+}
+
+func (what *pluginTestConnection) call(t *testing.T, method string, params []byte) PluginResponse {
+	what.lastId++
+	assert.NoError(t, writePluginMessage(what.in, PluginRequest{Version: pluginRpcVersion, Id: what.lastId, Method: method, Params: params}))
+
+	var response PluginResponse
+	assert.NoError(t, readPluginMessage(what.out, &response, pluginMaxContentLength))
+	assert.Equal(t, what.lastId, response.Id)
+
+	return response
+}
+
+func TestServePluginGetInfo(t *testing.T) {
+	response := newPluginTestConnection(t).call(t, PluginGetInfoMethod, nil)
+
+	assert.Nil(t, response.Error)
+
+	category := new(CustomRiskCategory)
+	assert.NoError(t, json.Unmarshal(response.Result, category))
+	assert.Equal(t, "plugin-test", category.ID)
+	assert.Equal(t, []string{"plugin-tag"}, category.Tags)
+}
+
+func TestServePluginSupportedTags(t *testing.T) {
+	response := newPluginTestConnection(t).call(t, PluginSupportedTagsMethod, nil)
+
+	assert.Nil(t, response.Error)
+	assert.JSONEq(t, `["plugin-tag"]`, string(response.Result))
+}
+
+func TestServePluginGenerateRisksNeedsModel(t *testing.T) {
+	response := newPluginTestConnection(t).call(t, PluginGenerateRisksMethod, nil)
+
+	if assert.NotNil(t, response.Error) {
+		assert.Equal(t, pluginInvalidParams, response.Error.Code)
+	}
+}
+
+func TestServePluginGenerateRisksReusesModel(t *testing.T) {
+	connection := newPluginTestConnection(t)
+
+	response := connection.call(t, PluginSetModelMethod, []byte(`{"technical_assets":{"web":{"id":"web"}}}`))
+	assert.Nil(t, response.Error)
+
+	for n := 0; n < 2; n++ {
+		response = connection.call(t, PluginGenerateRisksMethod, nil)
+		assert.Nil(t, response.Error)
+		assert.Contains(t, string(response.Result), `"plugin-test@web"`)
+	}
+}
+
+func TestServePluginUnknownMethodFails(t *testing.T) {
+	response := newPluginTestConnection(t).call(t, "no-such-method", nil)
+
+	if assert.NotNil(t, response.Error) {
+		assert.Equal(t, pluginMethodNotFound, response.Error.Code)
+	}
+}
+
+func TestPluginClientRunsPluginInServeMode(t *testing.T) {
+	t.Setenv(servePluginTestEnv, "1")
+
+	client := new(pluginClient)
+	category, startError := client.Start(context.Background(), os.Args[0], new(common.Config).Defaults(""))
+	if !assert.NoError(t, startError) {
+		return
+	}
+
+	defer func() { assert.NoError(t, client.Close()) }()
+
+	assert.Equal(t, "plugin-test", category.ID)
+	assert.Equal(t, []string{"plugin-tag"}, category.Tags)
+
+	parsedModel := &types.Model{TechnicalAssets: map[string]*types.TechnicalAsset{"db": {Id: "db"}}}
+	generatedRisks, riskError := client.GenerateRisks(parsedModel)
+	assert.NoError(t, riskError)
+	if assert.Len(t, generatedRisks, 1) {
+		assert.Equal(t, "plugin-test@db", generatedRisks[0].SyntheticId)
+	}
+}
+
+func TestPluginClientFailsForMissingPlugin(t *testing.T) {
+	t.Setenv(servePluginTestEnv, "1")
+
+	_, startError := new(pluginClient).Start(context.Background(), os.Args[0]+"-missing", new(common.Config).Defaults(""))
+
+	assert.Error(t, startError)
+}
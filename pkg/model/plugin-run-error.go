@@ -0,0 +1,114 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"path/filepath"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type PluginFailureReason string
+
+const (
+	PluginStartFailed    PluginFailureReason = "failed to start"
+	PluginFailed         PluginFailureReason = "failed"
+	PluginTimedOut       PluginFailureReason = "timed out"
+	PluginCanceled       PluginFailureReason = "was canceled"
+	PluginOutputTooLarge PluginFailureReason = "exceeded the output limit"
+	PluginInvalidOutput  PluginFailureReason = "returned invalid output"
+)
+
+const pluginFailureCategoryId = "plugin-failure"
+
+// PluginRunError tells why running a RAA or custom risk rule plugin failed, along with what it wrote to stderr
+
+type PluginRunError struct {
+	Filename string
+	Reason   PluginFailureReason
+	Output   string
+	Err      error
+}
+
+func (what *PluginRunError) Error() string {
+	text := fmt.Sprintf("plugin %q %v", what.Filename, what.Reason)
+	if what.Err != nil {
+		text += fmt.Sprintf(": %v", what.Err)
+	}
+
+	if output := strings.TrimSpace(what.Output); len(output) > 0 {
+		text += fmt.Sprintf(": %v", output)
+	}
+
+	return text
+}
+
+func (what *PluginRunError) Unwrap() error {
+	return what.Err
+}
+
+func newPluginRunError(ctx context.Context, filename string, limits common.PluginLimits, runError error, outputExceeded bool, output string) error {
+	switch {
+	case outputExceeded:
+		return &PluginRunError{Filename: filename, Reason: PluginOutputTooLarge, Output: output, Err: fmt.Errorf("more than %d bytes", limits.MaxOutputBytes)}
+
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return &PluginRunError{Filename: filename, Reason: PluginTimedOut, Output: output, Err: fmt.Errorf("after %v", limits.Timeout())}
+
+	case errors.Is(ctx.Err(), context.Canceled):
+		return &PluginRunError{Filename: filename, Reason: PluginCanceled, Output: output}
+
+	default:
+		return &PluginRunError{Filename: filename, Reason: PluginFailed, Output: output, Err: runError}
+	}
+}
+
+func pluginFailureCategory() *types.RiskCategory {
+	return &types.RiskCategory{
+		ID:    pluginFailureCategoryId,
+		Title: "Plugin Failure",
+		Description: "A RAA or custom risk rule plugin failed, timed out or wrote more output than allowed, so its " +
+			"results are missing from the analysis.",
+		Impact:                     "If this risk is unmitigated, other risks might not be noticed as the analysis is incomplete.",
+		ASVS:                       "V1 - Architecture, Design and Threat Modeling Requirements",
+		CheatSheet:                 "https://cheatsheetseries.owasp.org/cheatsheets/Threat_Modeling_Cheat_Sheet.html",
+		Action:                     "Threat Modeling Completeness",
+		Mitigation:                 "Fix the plugin or raise its limits in the plugin settings of the config file.",
+		Check:                      "Did all plugins run?",
+		Function:                   types.Architecture,
+		STRIDE:                     types.InformationDisclosure,
+		DetectionLogic:             "All plugins that failed during the analysis.",
+		RiskAssessment:             types.LowSeverity.String(),
+		FalsePositives:             "None, as the plugin did not run.",
+		ModelFailurePossibleReason: true,
+		CWE:                        1008,
+	}
+}
+
+// addPluginFailure records a failed plugin as a risk, so it shows up in the model failures of the report
+
+func addPluginFailure(parsedModel *types.Model, filename string, pluginError error) {
+	if types.GetRiskCategory(parsedModel, pluginFailureCategoryId) == nil {
+		parsedModel.BuiltInRiskCategories = append(parsedModel.BuiltInRiskCategories, pluginFailureCategory())
+	}
+
+	risk := &types.Risk{
+		CategoryId:             pluginFailureCategoryId,
+		Severity:               types.CalculateSeverity(types.Unlikely, types.LowImpact),
+		ExploitationLikelihood: types.Unlikely,
+		ExploitationImpact:     types.LowImpact,
+		Title:                  fmt.Sprintf("<b>Plugin Failure</b> of <b>%v</b>: %v", filepath.Base(filename), html.EscapeString(pluginError.Error())),
+		DataBreachProbability:  types.Improbable,
+	}
+	risk.SyntheticId = risk.CategoryId + "@" + filepath.Base(filename)
+
+	if parsedModel.GeneratedRisksByCategory == nil {
+		parsedModel.GeneratedRisksByCategory = make(map[string][]*types.Risk)
+	}
+
+	parsedModel.GeneratedRisksByCategory[pluginFailureCategoryId] = append(parsedModel.GeneratedRisksByCategory[pluginFailureCategoryId], risk)
+}
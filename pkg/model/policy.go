@@ -0,0 +1,224 @@
+package model
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	PolicyMaxRisks              = "max-risks"
+	PolicyForbiddenRiskCategory = "forbidden-risk-category"
+	PolicyRequiredTrackingField = "required-tracking-field"
+	PolicyMaxTrackingAge        = "max-tracking-age"
+)
+
+// policyTrackingFields are the risk tracking fields a policy can require, by their name in the model file
+
+var policyTrackingFields = map[string]func(tracking *types.RiskTracking) bool{
+	"justification": func(tracking *types.RiskTracking) bool { return len(strings.TrimSpace(tracking.Justification)) > 0 },
+	"ticket":        func(tracking *types.RiskTracking) bool { return len(strings.TrimSpace(tracking.Ticket)) > 0 },
+	"checked_by":    func(tracking *types.RiskTracking) bool { return len(strings.TrimSpace(tracking.CheckedBy)) > 0 },
+	"date":          func(tracking *types.RiskTracking) bool { return !tracking.Date.IsZero() },
+	"expires":       func(tracking *types.RiskTracking) bool { return !tracking.Expires.IsZero() },
+	"review_by":     func(tracking *types.RiskTracking) bool { return !tracking.ReviewBy.IsZero() },
+	"owner":         func(tracking *types.RiskTracking) bool { return len(strings.TrimSpace(tracking.Owner)) > 0 },
+}
+
+// Policy is what an analyzed model must comply with to pass a check, like in a CI pipeline: the maximum number of
+// risks per severity and status, the risk categories no risk still at risk may be of, the risk tracking fields
+// required per status, and the maximum age (in days, by their date) of risk tracking entries per status
+
+type Policy struct {
+	MaxRisks                map[string]map[string]int `yaml:"max_risks,omitempty" json:"max_risks,omitempty"`
+	ForbiddenRiskCategories []string                  `yaml:"forbidden_risk_categories,omitempty" json:"forbidden_risk_categories,omitempty"`
+	RequiredTrackingFields  map[string][]string       `yaml:"required_tracking_fields,omitempty" json:"required_tracking_fields,omitempty"`
+	MaxTrackingAgeDays      map[string]int            `yaml:"max_tracking_age_days,omitempty" json:"max_tracking_age_days,omitempty"`
+}
+
+// PolicyViolation is a policy rule (like PolicyMaxRisks) a model violates, at the position of the risk or risk
+// tracking entry violating it, if any
+
+type PolicyViolation struct {
+	Rule     string         `yaml:"rule" json:"rule"`
+	Message  string         `yaml:"message" json:"message"`
+	Position input.Position `yaml:"position,omitempty" json:"position,omitempty"`
+}
+
+func (what PolicyViolation) String() string {
+	return what.Position.Errorf("%v (%v)", what.Message, what.Rule).Error()
+}
+
+// Load loads the policy from a YAML file, rejecting unknown keys, severities, statuses and tracking fields
+
+func (what *Policy) Load(filename string) error {
+	data, readError := os.ReadFile(filepath.Clean(filename))
+	if readError != nil {
+		return fmt.Errorf("unable to read policy file: %v", readError)
+	}
+
+	*what = Policy{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	decodeError := decoder.Decode(what)
+	if decodeError != nil && !errors.Is(decodeError, io.EOF) {
+		return fmt.Errorf("unable to parse policy file %q: %v", filename, decodeError)
+	}
+
+	return what.validate()
+}
+
+func (what *Policy) validate() error {
+	for severity, statuses := range what.MaxRisks {
+		if _, parseError := types.ParseRiskSeverity(severity); parseError != nil {
+			return fmt.Errorf("unknown risk severity %q in max_risks", severity)
+		}
+		for status := range statuses {
+			if _, parseError := types.ParseRiskStatus(status); parseError != nil {
+				return fmt.Errorf("unknown risk status %q in max_risks of %v", status, severity)
+			}
+		}
+	}
+
+	for status, fields := range what.RequiredTrackingFields {
+		if _, parseError := types.ParseRiskStatus(status); parseError != nil {
+			return fmt.Errorf("unknown risk status %q in required_tracking_fields", status)
+		}
+		for _, field := range fields {
+			if _, ok := policyTrackingFields[field]; !ok {
+				return fmt.Errorf("unknown risk tracking field %q in required_tracking_fields of %v", field, status)
+			}
+		}
+	}
+
+	for status, days := range what.MaxTrackingAgeDays {
+		if _, parseError := types.ParseRiskStatus(status); parseError != nil {
+			return fmt.Errorf("unknown risk status %q in max_tracking_age_days", status)
+		}
+		if days < 0 {
+			return fmt.Errorf("negative maximum age %d in max_tracking_age_days of %v", days, status)
+		}
+	}
+
+	return nil
+}
+
+// CheckRiskCategories rejects forbidden risk categories that are neither built-in nor custom risk categories of the
+// analyzed model, as no risk would ever be of such a category, like one misspelled
+
+func (what *Policy) CheckRiskCategories(parsedModel *types.Model) error {
+	known := make(map[string]bool)
+	for _, category := range parsedModel.BuiltInRiskCategories {
+		known[category.ID] = true
+	}
+	for _, category := range parsedModel.CustomRiskCategories {
+		known[category.ID] = true
+	}
+
+	for _, category := range what.ForbiddenRiskCategories {
+		if !known[category] {
+			return fmt.Errorf("unknown risk category %q in forbidden_risk_categories", category)
+		}
+	}
+
+	return nil
+}
+
+// Check returns the policy violations of the analyzed model as of the given day; risk tracking entries applied
+// by a wildcard entry are checked once, as the wildcard entry
+
+func (what *Policy) Check(parsedModel *types.Model, today time.Time) []*PolicyViolation {
+	violations := make([]*PolicyViolation, 0)
+
+	statistics := types.OverallRiskStatistics(parsedModel)
+	for _, severity := range types.RiskSeverityValues() {
+		for _, status := range types.RiskStatusValues() {
+			maxCount, ok := what.MaxRisks[severity.String()][status.String()]
+			count := statistics.Risks[severity.String()][status.String()]
+			if ok && count > maxCount {
+				violations = append(violations, &PolicyViolation{
+					Rule:    PolicyMaxRisks,
+					Message: fmt.Sprintf("%d %v risks of %v severity, at most %d allowed", count, status, severity, maxCount),
+				})
+			}
+		}
+	}
+
+	forbidden := make(map[string]bool)
+	for _, category := range what.ForbiddenRiskCategories {
+		forbidden[category] = true
+	}
+	for _, risk := range sortedRisks(parsedModel) {
+		if forbidden[risk.CategoryId] && risk.RiskStatus.IsStillAtRisk() {
+			violations = append(violations, &PolicyViolation{
+				Rule:     PolicyForbiddenRiskCategory,
+				Message:  fmt.Sprintf("risk %v is of the forbidden risk category %v and %v", risk.SyntheticId, risk.CategoryId, risk.RiskStatus),
+				Position: risk.Position,
+			})
+		}
+	}
+
+	for _, tracking := range parsedModel.RiskTrackingEntries() {
+		status := tracking.Status.String()
+
+		missing := make([]string, 0)
+		for _, field := range what.RequiredTrackingFields[status] {
+			if !policyTrackingFields[field](tracking) {
+				missing = append(missing, field)
+			}
+		}
+		if len(missing) > 0 {
+			violations = append(violations, &PolicyViolation{
+				Rule:     PolicyRequiredTrackingField,
+				Message:  fmt.Sprintf("risk tracking of %v is %v without %v", tracking.SyntheticRiskId, status, strings.Join(missing, ", ")),
+				Position: tracking.Position,
+			})
+		}
+
+		maxDays, ok := what.MaxTrackingAgeDays[status]
+		switch {
+		case !ok:
+
+		case tracking.Date.IsZero():
+			violations = append(violations, &PolicyViolation{
+				Rule:     PolicyMaxTrackingAge,
+				Message:  fmt.Sprintf("risk tracking of %v is %v without a date, at most %d days allowed", tracking.SyntheticRiskId, status, maxDays),
+				Position: tracking.Position,
+			})
+
+		case today.Sub(tracking.Date.Time) > time.Duration(maxDays)*24*time.Hour:
+			violations = append(violations, &PolicyViolation{
+				Rule: PolicyMaxTrackingAge,
+				Message: fmt.Sprintf("risk tracking of %v is %v since %v (%d days), at most %d days allowed", tracking.SyntheticRiskId, status,
+					tracking.Date.Format("2006-01-02"), int(today.Sub(tracking.Date.Time).Hours()/24), maxDays),
+				Position: tracking.Position,
+			})
+		}
+	}
+
+	return violations
+}
+
+func sortedRisks(parsedModel *types.Model) []*types.Risk {
+	ids := make([]string, 0)
+	for id := range parsedModel.GeneratedRisksBySyntheticId {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	risks := make([]*types.Risk, 0)
+	for _, id := range ids {
+		risks = append(risks, parsedModel.GeneratedRisksBySyntheticId[id])
+	}
+	return risks
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestPolicyCheck(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "threagile-policy.yaml")
+	require.NoError(t, os.WriteFile(filename, []byte(`
+max_risks:
+  high:
+    unchecked: 0
+forbidden_risk_categories:
+  - unencrypted-communication
+required_tracking_fields:
+  accepted: [ticket, checked_by]
+max_tracking_age_days:
+  in-discussion: 30
+`), 0600))
+
+	policy := new(Policy)
+	require.NoError(t, policy.Load(filename))
+
+	trackedAt := func(line int) input.Position {
+		return input.Position{Filename: "threagile.yaml", Line: line, Column: 3}
+	}
+	risks := []*types.Risk{
+		{CategoryId: "sql-nosql-injection", SyntheticId: "sql-nosql-injection@db", Severity: types.HighSeverity, RiskStatus: types.Unchecked},
+		{CategoryId: "unencrypted-communication", SyntheticId: "unencrypted-communication@web>db", Severity: types.ElevatedSeverity, RiskStatus: types.InDiscussion, Position: trackedAt(10)},
+		{CategoryId: "unencrypted-communication", SyntheticId: "unencrypted-communication@web>cache", Severity: types.ElevatedSeverity, RiskStatus: types.Mitigated},
+		{CategoryId: "missing-hardening", SyntheticId: "missing-hardening@web", Severity: types.MediumSeverity, RiskStatus: types.Accepted, Position: trackedAt(20)},
+		{CategoryId: "missing-hardening", SyntheticId: "missing-hardening@db", Severity: types.MediumSeverity, RiskStatus: types.Accepted, Position: trackedAt(20)},
+	}
+	parsedModel := &types.Model{
+		GeneratedRisksByCategory:    make(map[string][]*types.Risk),
+		GeneratedRisksBySyntheticId: make(map[string]*types.Risk),
+		RiskTracking: map[string]*types.RiskTracking{
+			"unencrypted-communication@web>db": {SyntheticRiskId: "unencrypted-communication@web>db", Status: types.InDiscussion,
+				Date: types.Date{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}, Position: trackedAt(10)},
+			"unencrypted-communication@web>cache": {SyntheticRiskId: "unencrypted-communication@web>cache", Status: types.Mitigated},
+			"missing-hardening@*":                 {SyntheticRiskId: "missing-hardening@*", Status: types.Accepted, Ticket: "SEC-1", Position: trackedAt(20)},
+			"missing-hardening@web":               {SyntheticRiskId: "missing-hardening@web", Status: types.Accepted, Ticket: "SEC-1", Position: trackedAt(20)},
+			"missing-hardening@db":                {SyntheticRiskId: "missing-hardening@db", Status: types.Accepted, Ticket: "SEC-1", Position: trackedAt(20)},
+		},
+	}
+	for _, risk := range risks {
+		parsedModel.GeneratedRisksByCategory[risk.CategoryId] = append(parsedModel.GeneratedRisksByCategory[risk.CategoryId], risk)
+		parsedModel.GeneratedRisksBySyntheticId[risk.SyntheticId] = risk
+	}
+
+	violations := policy.Check(parsedModel, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	require.Len(t, violations, 4)
+
+	assert.Equal(t, PolicyMaxRisks, violations[0].Rule)
+	assert.Equal(t, "1 unchecked risks of high severity, at most 0 allowed (max-risks)", violations[0].String())
+
+	assert.Equal(t, PolicyForbiddenRiskCategory, violations[1].Rule)
+	assert.Contains(t, violations[1].Message, "unencrypted-communication@web>db")
+	assert.Equal(t, 10, violations[1].Position.Line)
+
+	assert.Equal(t, PolicyRequiredTrackingField, violations[2].Rule)
+	assert.Equal(t, "threagile.yaml:20:3: risk tracking of missing-hardening@* is accepted without checked_by (required-tracking-field)", violations[2].String())
+
+	assert.Equal(t, PolicyMaxTrackingAge, violations[3].Rule)
+	assert.Contains(t, violations[3].Message, "since 2024-01-01 (60 days)")
+}
+
+func TestPolicyCheckCompliant(t *testing.T) {
+	risk := &types.Risk{CategoryId: "sql-nosql-injection", SyntheticId: "sql-nosql-injection@db", Severity: types.HighSeverity, RiskStatus: types.Unchecked}
+	parsedModel := &types.Model{
+		GeneratedRisksByCategory:    map[string][]*types.Risk{risk.CategoryId: {risk}},
+		GeneratedRisksBySyntheticId: map[string]*types.Risk{risk.SyntheticId: risk},
+		RiskTracking:                map[string]*types.RiskTracking{},
+	}
+
+	policy := &Policy{MaxRisks: map[string]map[string]int{"high": {"unchecked": 1}}}
+	assert.Empty(t, policy.Check(parsedModel, time.Now()))
+}
+
+func TestPolicyCheckMissingDate(t *testing.T) {
+	parsedModel := &types.Model{
+		GeneratedRisksByCategory:    map[string][]*types.Risk{},
+		GeneratedRisksBySyntheticId: map[string]*types.Risk{},
+		RiskTracking: map[string]*types.RiskTracking{
+			"missing-hardening@*": {SyntheticRiskId: "missing-hardening@*", Status: types.Accepted, Ticket: "SEC-1"},
+		},
+	}
+
+	policy := &Policy{MaxTrackingAgeDays: map[string]int{"accepted": 365}}
+	violations := policy.Check(parsedModel, time.Now())
+	require.Len(t, violations, 1)
+	assert.Contains(t, violations[0].Message, "missing-hardening@* is accepted without a date")
+}
+
+func TestPolicyLoadInvalid(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "threagile-policy.yaml")
+	for _, content := range []string{
+		"max_risks:\n  severe:\n    unchecked: 0\n",
+		"max_risks:\n  high:\n    open: 0\n",
+		"required_tracking_fields:\n  accepted: [assignee]\n",
+		"max_tracking_age_days:\n  in-discussion: -1\n",
+		"max_unchecked: 0\n",
+	} {
+		require.NoError(t, os.WriteFile(filename, []byte(content), 0600))
+		assert.Error(t, new(Policy).Load(filename), content)
+	}
+}
+
+func TestPolicyCheckRiskCategories(t *testing.T) {
+	parsedModel := &types.Model{
+		BuiltInRiskCategories: types.RiskCategories{{ID: "unencrypted-communication"}},
+		CustomRiskCategories:  types.RiskCategories{{ID: "missing-vault-rotation"}},
+	}
+
+	policy := &Policy{ForbiddenRiskCategories: []string{"unencrypted-communication", "missing-vault-rotation"}}
+	assert.NoError(t, policy.CheckRiskCategories(parsedModel))
+
+	policy.ForbiddenRiskCategories = append(policy.ForbiddenRiskCategories, "unencrypted-comunication")
+	assert.EqualError(t, policy.CheckRiskCategories(parsedModel), `unknown risk category "unencrypted-comunication" in forbidden_risk_categories`)
+}
@@ -0,0 +1,251 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const (
+	DefaultRAAAlgorithm  = "default"
+	ExposureRAAAlgorithm = "exposure"
+
+	// legacyRAAAlgorithm is the name of the plugin that used to run the default algorithm
+	legacyRAAAlgorithm = "raa_calc"
+)
+
+// RAACalculator sets the relative attacker attractiveness (RAA) of all technical assets of a model and returns the
+// intro text of the RAA chapter of the report
+
+type RAACalculator interface {
+	Name() string
+	Parameters() []string
+	Calculate(ctx context.Context, parsedModel *types.Model) (string, error)
+}
+
+// NewRAACalculator returns the built-in algorithm named by config.RAAPlugin, or else runs the plugin of that name from
+// the plugin folder
+
+func NewRAACalculator(config *common.Config) (RAACalculator, error) {
+	switch strings.ToLower(config.RAAPlugin) {
+	case DefaultRAAAlgorithm, legacyRAAAlgorithm:
+		return new(DefaultRAACalculator).Init(config.Attractiveness), nil
+
+	case ExposureRAAAlgorithm:
+		return new(ExposureRAACalculator).Init(config.Attractiveness), nil
+	}
+
+	pluginRunner, loadError := new(runner).Load(filepath.Join(config.PluginFolder, config.RAAPlugin), config)
+	if loadError != nil {
+		return nil, loadError
+	}
+
+	return &pluginRAACalculator{runner: pluginRunner}, nil
+}
+
+// pluginRAACalculator runs a RAA plugin that reads the model from stdin, writes it back with the RAA values set to
+// stdout and the intro text to stderr
+
+type pluginRAACalculator struct {
+	runner *runner
+}
+
+func (what *pluginRAACalculator) Name() string {
+	return fmt.Sprintf("plugin %v", filepath.Base(what.runner.Filename))
+}
+
+func (what *pluginRAACalculator) Parameters() []string {
+	return []string{}
+}
+
+func (what *pluginRAACalculator) Calculate(ctx context.Context, parsedModel *types.Model) (string, error) {
+	runError := what.runner.Run(ctx, parsedModel, parsedModel)
+	if runError != nil {
+		return "", runError
+	}
+
+	return what.runner.ErrorOutput, nil
+}
+
+// raaFibonacci holds the numbers the attacker attractiveness weights of ratings and quantities are taken from
+
+var raaFibonacci = [...]float64{1, 2, 3, 5, 8, 13, 21, 34, 55, 89, 144, 233, 377, 610, 987}
+
+// raaWeights holds the base index of each weight scale: the n-th rating of a scale with base index b weighs the
+// (b+n)-th number of raaFibonacci, counting from 1. Zero base indexes of the config take the weights of the original
+// algorithm.
+
+type raaWeights common.Attractiveness
+
+func newRAAWeights(attractiveness common.Attractiveness) raaWeights {
+	defaultIndex := func(value int, defaultValue int) int {
+		if value <= 0 {
+			return defaultValue
+		}
+
+		return value
+	}
+
+	return raaWeights{
+		Quantity: defaultIndex(attractiveness.Quantity, 1),
+		Confidentiality: common.AttackerFocus{
+			Asset:                 defaultIndex(attractiveness.Confidentiality.Asset, 5),
+			ProcessedOrStoredData: defaultIndex(attractiveness.Confidentiality.ProcessedOrStoredData, 4),
+			TransferredData:       defaultIndex(attractiveness.Confidentiality.TransferredData, 2),
+		},
+		Integrity: common.AttackerFocus{
+			Asset:                 defaultIndex(attractiveness.Integrity.Asset, 4),
+			ProcessedOrStoredData: defaultIndex(attractiveness.Integrity.ProcessedOrStoredData, 3),
+			TransferredData:       defaultIndex(attractiveness.Integrity.TransferredData, 2),
+		},
+		Availability: common.AttackerFocus{
+			Asset:                 defaultIndex(attractiveness.Availability.Asset, 4),
+			ProcessedOrStoredData: defaultIndex(attractiveness.Availability.ProcessedOrStoredData, 3),
+			TransferredData:       defaultIndex(attractiveness.Availability.TransferredData, 2),
+		},
+	}
+}
+
+func raaWeight(baseIndex int, rating int) float64 {
+	index := baseIndex - 1 + rating
+	if index < 0 {
+		index = 0
+	}
+
+	if index >= len(raaFibonacci) {
+		index = len(raaFibonacci) - 1
+	}
+
+	return raaFibonacci[index]
+}
+
+func (what raaWeights) Parameters() []string {
+	focus := func(name string, value common.AttackerFocus) string {
+		return fmt.Sprintf("%v weights start at %v (asset), %v (processed or stored data) and %v (transferred data)",
+			name, raaWeight(value.Asset, 0), raaWeight(value.ProcessedOrStoredData, 0), raaWeight(value.TransferredData, 0))
+	}
+
+	return []string{
+		focus("confidentiality", what.Confidentiality),
+		focus("integrity", what.Integrity),
+		focus("availability", what.Availability),
+		fmt.Sprintf("quantity factors start at %v", raaWeight(what.Quantity, 0)),
+	}
+}
+
+// score sums the weights of the asset's own ratings, those of the data it processes and stores and those of the data
+// it transfers, and then scales the sum by the kind of asset
+
+func (what raaWeights) score(parsedModel *types.Model, techAsset *types.TechnicalAsset) float64 {
+	if techAsset.OutOfScope {
+		return 0
+	}
+
+	score := raaWeight(what.Confidentiality.Asset, int(techAsset.Confidentiality)) +
+		raaWeight(what.Integrity.Asset, int(techAsset.Integrity)) +
+		raaWeight(what.Availability.Asset, int(techAsset.Availability))
+
+	// NOTE: Assuming all stored data is also processed, this effectively scores stored data twice
+	for _, dataAssetIds := range [][]string{techAsset.DataAssetsProcessed, techAsset.DataAssetsStored} {
+		for _, dataAssetId := range dataAssetIds {
+			score += what.dataScore(parsedModel.DataAssets[dataAssetId], what.Confidentiality.ProcessedOrStoredData,
+				what.Integrity.ProcessedOrStoredData, what.Availability.ProcessedOrStoredData)
+		}
+	}
+
+	// NOTE: To send or receive data effectively is processing that data and it's questionable if the attractiveness increases further
+	for _, dataFlow := range techAsset.CommunicationLinks {
+		for _, dataAssetIds := range [][]string{dataFlow.DataAssetsSent, dataFlow.DataAssetsReceived} {
+			for _, dataAssetId := range dataAssetIds {
+				score += what.dataScore(parsedModel.DataAssets[dataAssetId], what.Confidentiality.TransferredData,
+					what.Integrity.TransferredData, what.Availability.TransferredData)
+			}
+		}
+	}
+
+	if techAsset.Technologies.GetAttribute(types.LoadBalancer, types.ReverseProxy) {
+		score = score / 5.5
+	} else if techAsset.Technologies.GetAttribute(types.Monitoring) {
+		score = score / 5
+	} else if techAsset.Technologies.GetAttribute(types.ContainerPlatform) {
+		score = score * 5
+	} else if techAsset.Technologies.GetAttribute(types.Vault) {
+		score = score * 2
+	} else if techAsset.Technologies.GetAttribute(types.BuildPipeline, types.SourcecodeRepository, types.ArtifactRegistry) {
+		score = score * 2
+	} else if techAsset.Technologies.GetAttribute(types.IdentityProvider, types.IdentityStoreDatabase, types.IdentityStoreLDAP) {
+		score = score * 2.5
+	} else if techAsset.Type == types.Datastore {
+		score = score * 2
+	}
+
+	if techAsset.MultiTenant {
+		score = score * 1.5
+	}
+
+	return score
+}
+
+// dataScore weighs confidentiality and integrity of a data asset by its quantity, but not its availability
+
+func (what raaWeights) dataScore(dataAsset *types.DataAsset, confidentialityIndex int, integrityIndex int, availabilityIndex int) float64 {
+	if dataAsset == nil {
+		return 0
+	}
+
+	quantityFactor := raaWeight(what.Quantity, int(dataAsset.Quantity))
+	return raaWeight(confidentialityIndex, int(dataAsset.Confidentiality))*quantityFactor +
+		raaWeight(integrityIndex, int(dataAsset.Integrity))*quantityFactor +
+		raaWeight(availabilityIndex, int(dataAsset.Availability))
+}
+
+func (what raaWeights) scores(parsedModel *types.Model) map[string]float64 {
+	scores := make(map[string]float64)
+	for id, techAsset := range parsedModel.TechnicalAssets {
+		scores[id] = what.score(parsedModel, techAsset)
+	}
+
+	return scores
+}
+
+// raaScale maps attacker attractiveness scores to percent of the range of scores of all technical assets
+
+type raaScale struct {
+	minimum float64
+	spread  float64
+}
+
+func newRAAScale(scores map[string]float64) raaScale {
+	first := true
+	minimum, maximum := 0.0, 0.0
+	for _, score := range scores {
+		if first || score < minimum {
+			minimum = score
+		}
+
+		if first || score > maximum {
+			maximum = score
+		}
+
+		first = false
+	}
+
+	if !(minimum < maximum) {
+		maximum = minimum + 1
+	}
+
+	return raaScale{minimum: minimum, spread: maximum - minimum}
+}
+
+func (what raaScale) relative(score float64) float64 {
+	percent := (score - what.minimum) / what.spread * 100
+	if percent <= 0 {
+		return 1 // since 0 suggests no attacks at all
+	}
+
+	return percent
+}
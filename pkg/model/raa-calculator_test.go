@@ -0,0 +1,107 @@
+package model
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// createRAAModel has a web server on the internet that talks to a database in a trust boundary of its own
+
+func createRAAModel() *types.Model {
+	link := &types.CommunicationLink{Id: "web>db", SourceId: "web", TargetId: "db", DataAssetsSent: []string{"customers"}}
+	web := &types.TechnicalAsset{Id: "web", Internet: true, CommunicationLinks: []*types.CommunicationLink{link}}
+	db := &types.TechnicalAsset{Id: "db", Confidentiality: types.Confidential, DataAssetsStored: []string{"customers"}}
+	boundary := &types.TrustBoundary{Id: "backend", TechnicalAssetsInside: []string{"db"}}
+
+	return &types.Model{
+		TechnicalAssets:    map[string]*types.TechnicalAsset{"web": web, "db": db},
+		CommunicationLinks: map[string]*types.CommunicationLink{link.Id: link},
+		TrustBoundaries:    map[string]*types.TrustBoundary{"backend": boundary},
+		DataAssets: map[string]*types.DataAsset{
+			"customers": {Id: "customers", Confidentiality: types.StrictlyConfidential, Quantity: types.Many},
+		},
+		DirectContainingTrustBoundaryMappedByTechnicalAssetId: map[string]*types.TrustBoundary{"db": boundary},
+	}
+}
+
+func TestNewRAACalculatorSelectsAlgorithm(t *testing.T) {
+	config := new(common.Config).Defaults("")
+
+	calculator, err := NewRAACalculator(config)
+	assert.NoError(t, err)
+	assert.IsType(t, new(DefaultRAACalculator), calculator)
+
+	config.RAAPlugin = "raa_calc"
+	calculator, err = NewRAACalculator(config)
+	assert.NoError(t, err)
+	assert.IsType(t, new(DefaultRAACalculator), calculator)
+
+	config.RAAPlugin = "exposure"
+	calculator, err = NewRAACalculator(config)
+	assert.NoError(t, err)
+	assert.IsType(t, new(ExposureRAACalculator), calculator)
+
+	config.RAAPlugin = "no-such-plugin"
+	config.PluginFolder = t.TempDir()
+	_, err = NewRAACalculator(config)
+	assert.Error(t, err)
+}
+
+func TestDefaultRAACalculatorAddsPivotingBonus(t *testing.T) {
+	parsedModel := createRAAModel()
+
+	_, err := new(DefaultRAACalculator).Init(common.Attractiveness{}).Calculate(context.Background(), parsedModel)
+	assert.NoError(t, err)
+
+	// web scores 8+5+5 for itself and 13*3+2*3+2 for the customers it sends, db scores 34+5+5 for itself and
+	// 34*3+3*3+3 for the customers it stores; web gets a third of the difference of 99 percent to db as bonus
+	web, db := 18.0+47.0, 44.0+114.0
+	assert.InDelta(t, 100, parsedModel.TechnicalAssets["db"].RAA, 1e-9)
+	assert.InDelta(t, 33/(db-web)*100, parsedModel.TechnicalAssets["web"].RAA, 1e-9)
+}
+
+func TestDefaultRAACalculatorTakesWeightsFromConfig(t *testing.T) {
+	defaultModel, weightedModel := createRAAModel(), createRAAModel()
+
+	_, err := new(DefaultRAACalculator).Init(common.Attractiveness{}).Calculate(context.Background(), defaultModel)
+	assert.NoError(t, err)
+
+	attractiveness := common.Attractiveness{Confidentiality: common.AttackerFocus{TransferredData: 9}}
+	_, err = new(DefaultRAACalculator).Init(attractiveness).Calculate(context.Background(), weightedModel)
+	assert.NoError(t, err)
+
+	assert.Greater(t, weightedModel.TechnicalAssets["web"].RAA, defaultModel.TechnicalAssets["web"].RAA)
+}
+
+func TestDefaultRAACalculatorNamesParameters(t *testing.T) {
+	calculator := new(DefaultRAACalculator).Init(common.Attractiveness{Quantity: 2})
+
+	assert.Equal(t, "default", calculator.Name())
+	assert.Contains(t, calculator.Parameters(), "confidentiality weights start at 8 (asset), 5 (processed or stored data) and 2 (transferred data)")
+	assert.Contains(t, calculator.Parameters(), "quantity factors start at 2")
+}
+
+func TestExposureRAACalculatorWeighsInternetExposure(t *testing.T) {
+	parsedModel := createRAAModel()
+
+	_, err := new(ExposureRAACalculator).Init(common.Attractiveness{}).Calculate(context.Background(), parsedModel)
+	assert.NoError(t, err)
+
+	// web doubles to 130, db halves to 79
+	assert.InDelta(t, 100, parsedModel.TechnicalAssets["web"].RAA, 1e-9)
+	assert.InDelta(t, 1, parsedModel.TechnicalAssets["db"].RAA, 1e-9)
+}
+
+func TestTrustBoundaryDistances(t *testing.T) {
+	parsedModel := createRAAModel()
+	parsedModel.TechnicalAssets["island"] = &types.TechnicalAsset{Id: "island"}
+
+	distances, maxDistance := trustBoundaryDistances(parsedModel)
+
+	assert.Equal(t, map[string]int{"web": 0, "db": 1}, distances)
+	assert.Equal(t, 1, maxDistance)
+}
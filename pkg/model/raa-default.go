@@ -0,0 +1,70 @@
+package model
+
+import (
+	"context"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// DefaultRAACalculator rates technical assets by the sensitivity and quantity of the data they hold and transfer;
+// neighbours of attractive assets get part of the difference as a pivoting bonus
+
+type DefaultRAACalculator struct {
+	weights raaWeights
+}
+
+func (what *DefaultRAACalculator) Init(attractiveness common.Attractiveness) *DefaultRAACalculator {
+	*what = DefaultRAACalculator{
+		weights: newRAAWeights(attractiveness),
+	}
+
+	return what
+}
+
+func (what *DefaultRAACalculator) Name() string {
+	return DefaultRAAAlgorithm
+}
+
+func (what *DefaultRAACalculator) Parameters() []string {
+	return append(what.weights.Parameters(), "pivoting bonus is a third of the difference to the most attractive target")
+}
+
+func (what *DefaultRAACalculator) Calculate(_ context.Context, parsedModel *types.Model) (string, error) {
+	scores := what.weights.scores(parsedModel)
+	scale := newRAAScale(scores)
+	for id, techAsset := range parsedModel.TechnicalAssets {
+		techAsset.RAA = scale.relative(scores[id] + what.pivotingAdjustment(parsedModel, techAsset, scores, scale))
+	}
+
+	// return intro text (for reporting etc., can be short summary-like)
+	return "For each technical asset the <b>\"Relative Attacker Attractiveness\"</b> (RAA) value was calculated " +
+		"in percent. The higher the RAA, the more interesting it is for an attacker to compromise the asset. The calculation algorithm takes " +
+		"the sensitivity ratings and quantities of stored and processed data into account as well as the communication links of the " +
+		"technical asset. Neighbouring assets to high-value RAA targets might receive an increase in their RAA value when they have " +
+		"a communication link towards that target (\"Pivoting-Factor\").<br><br>The following lists all technical assets sorted by their " +
+		"RAA value from highest (most attacker attractive) to lowest. This list can be used to prioritize on efforts relevant for the most " +
+		"attacker-attractive technical assets:", nil
+}
+
+// increase the RAA (relative attacker attractiveness) by one third (1/3) of the delta to the highest outgoing neighbour (if positive delta)
+
+func (what *DefaultRAACalculator) pivotingAdjustment(parsedModel *types.Model, techAsset *types.TechnicalAsset, scores map[string]float64, scale raaScale) float64 {
+	if techAsset.OutOfScope {
+		return 0
+	}
+
+	adjustment := 0.0
+	for _, commLink := range techAsset.CommunicationLinks {
+		if _, ok := parsedModel.TechnicalAssets[commLink.TargetId]; !ok {
+			continue
+		}
+
+		delta := scale.relative(scores[commLink.TargetId]) - scale.relative(scores[techAsset.Id])
+		if delta/3 > adjustment {
+			adjustment = delta / 3
+		}
+	}
+
+	return adjustment
+}
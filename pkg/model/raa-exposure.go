@@ -0,0 +1,145 @@
+package model
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const (
+	raaInternetExposureFactor = 2.0
+	raaTrustBoundaryFactor    = 0.5
+)
+
+// ExposureRAACalculator weighs the attacker attractiveness of the default algorithm by how exposed a technical asset
+// is: assets on the internet count double, and each trust boundary an attacker has to cross along the communication
+// links from the internet halves the attractiveness
+
+type ExposureRAACalculator struct {
+	weights raaWeights
+}
+
+func (what *ExposureRAACalculator) Init(attractiveness common.Attractiveness) *ExposureRAACalculator {
+	*what = ExposureRAACalculator{
+		weights: newRAAWeights(attractiveness),
+	}
+
+	return what
+}
+
+func (what *ExposureRAACalculator) Name() string {
+	return ExposureRAAAlgorithm
+}
+
+func (what *ExposureRAACalculator) Parameters() []string {
+	return append(what.weights.Parameters(),
+		fmt.Sprintf("internet exposure factor is %v", raaInternetExposureFactor),
+		fmt.Sprintf("factor per trust boundary crossed from the internet is %v", raaTrustBoundaryFactor),
+	)
+}
+
+func (what *ExposureRAACalculator) Calculate(_ context.Context, parsedModel *types.Model) (string, error) {
+	distances, maxDistance := trustBoundaryDistances(parsedModel)
+
+	scores := what.weights.scores(parsedModel)
+	for id, techAsset := range parsedModel.TechnicalAssets {
+		distance, reachable := distances[id]
+		switch {
+		case techAsset.Internet:
+			scores[id] *= raaInternetExposureFactor
+
+		case reachable:
+			scores[id] *= raaExposureFactor(distance)
+
+		default:
+			scores[id] *= raaExposureFactor(maxDistance + 1)
+		}
+	}
+
+	scale := newRAAScale(scores)
+	for id, techAsset := range parsedModel.TechnicalAssets {
+		techAsset.RAA = scale.relative(scores[id])
+	}
+
+	return "For each technical asset the <b>\"Relative Attacker Attractiveness\"</b> (RAA) value was calculated " +
+		"in percent. The higher the RAA, the more interesting it is for an attacker to compromise the asset. The calculation algorithm takes " +
+		"the sensitivity ratings and quantities of stored and processed data into account as well as the communication links of the " +
+		"technical asset, and weighs the result by the exposure of the asset: assets on the internet are more attractive, while each " +
+		"trust boundary an attacker has to cross from the internet makes an asset less attractive.<br><br>The following lists all " +
+		"technical assets sorted by their RAA value from highest (most attacker attractive) to lowest. This list can be used to prioritize " +
+		"on efforts relevant for the most attacker-attractive technical assets:", nil
+}
+
+func raaExposureFactor(distance int) float64 {
+	factor := 1.0
+	for n := 0; n < distance; n++ {
+		factor *= raaTrustBoundaryFactor
+	}
+
+	return factor
+}
+
+// trustBoundaryDistances returns the least number of trust boundaries crossed along communication links from any
+// asset on the internet to each reachable technical asset, along with the largest of these numbers
+
+func trustBoundaryDistances(parsedModel *types.Model) (map[string]int, int) {
+	distances := make(map[string]int)
+	queue := make([]string, 0)
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		if parsedModel.TechnicalAssets[id].Internet {
+			distances[id] = 0
+			queue = append(queue, id)
+		}
+	}
+
+	// links within a trust boundary cost nothing, so assets are put in front of the queue when reached through them
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		for _, link := range parsedModel.TechnicalAssets[id].CommunicationLinks {
+			if _, ok := parsedModel.TechnicalAssets[link.TargetId]; !ok {
+				continue
+			}
+
+			cost := 0
+			if trustBoundaryIdOf(parsedModel, link.SourceId) != trustBoundaryIdOf(parsedModel, link.TargetId) {
+				cost = 1
+			}
+
+			distance, known := distances[link.TargetId]
+			if known && distance <= distances[id]+cost {
+				continue
+			}
+
+			distances[link.TargetId] = distances[id] + cost
+			if cost == 0 {
+				queue = append([]string{link.TargetId}, queue...)
+			} else {
+				queue = append(queue, link.TargetId)
+			}
+		}
+	}
+
+	maxDistance := 0
+	for _, distance := range distances {
+		if distance > maxDistance {
+			maxDistance = distance
+		}
+	}
+
+	return distances, maxDistance
+}
+
+// trustBoundaryIdOf returns the id of the trust boundary directly containing the technical asset, or an empty string
+// for assets outside of all trust boundaries
+
+func trustBoundaryIdOf(parsedModel *types.Model, techAssetId string) string {
+	if trustBoundary, ok := parsedModel.DirectContainingTrustBoundaryMappedByTechnicalAssetId[techAssetId]; ok {
+		return trustBoundary.Id
+	}
+
+	return ""
+}
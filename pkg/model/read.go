@@ -1,9 +1,11 @@
 package model
 
 import (
+	"context"
 	"fmt"
-	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/threagile/threagile/pkg/common"
 	"github.com/threagile/threagile/pkg/input"
@@ -15,22 +17,34 @@ type ReadResult struct {
 	ModelInput       *input.Model
 	ParsedModel      *types.Model
 	IntroTextRAA     string
+	RAACalculator    RAACalculator
+	AttackPaths      []*AttackPath // found by FindAttackPaths for the outputs showing them only
 	BuiltinRiskRules types.RiskRules
 	CustomRiskRules  types.RiskRules
 }
 
-func (what ReadResult) ExplainRisk(cfg *common.Config, risk string, reporter common.DefaultProgressReporter) error {
-	return fmt.Errorf("not implemented")
+// Close stops the plugins of the custom risk rules kept running for analyzing the model
+
+func (what *ReadResult) Close() {
+	CloseCustomRiskRules(what.CustomRiskRules)
 }
 
+// ReadAndAnalyzeModel reads and analyzes the model of the config; the result keeps the plugins of the custom risk
+// rules running until it is closed
 // TODO: consider about splitting this function into smaller ones for better reusability
 
-func ReadAndAnalyzeModel(config *common.Config, progressReporter types.ProgressReporter) (*ReadResult, error) {
+func ReadAndAnalyzeModel(ctx context.Context, config *common.Config, progressReporter types.ProgressReporter) (*ReadResult, error) {
 	progressReporter.Infof("Writing into output directory: %v", config.OutputFolder)
 	progressReporter.Infof("Parsing model: %v", config.InputFile)
 
-	builtinRiskRules := risks.GetBuiltInRiskRules()
-	customRiskRules := LoadCustomRiskRules(config.RiskRulesPlugins, progressReporter)
+	builtinRiskRules := risks.GetBuiltInRiskRules(config, progressReporter)
+	customRiskRules := LoadCustomRiskRules(ctx, config, progressReporter)
+	success := false
+	defer func() {
+		if !success {
+			CloseCustomRiskRules(customRiskRules)
+		}
+	}()
 
 	modelInput := new(input.Model).Defaults()
 	loadError := modelInput.Load(config.InputFile)
@@ -51,31 +65,37 @@ func ReadAndAnalyzeModel(config *common.Config, progressReporter types.ProgressR
 	_ = os.WriteFile("parsed-model.yaml", yamlData, 0600)
 	/**/
 
-	introTextRAA := applyRAA(parsedModel, config.PluginFolder, config.RAAPlugin, progressReporter)
+	// errors of the RAA and of risk rules are reported as warnings only, along with the plugin failures in the report
+	raaCalculator, introTextRAA, _ := applyRAA(ctx, parsedModel, config, progressReporter)
 
-	applyRiskGeneration(parsedModel, builtinRiskRules.Merge(customRiskRules), config.SkipRiskRules, progressReporter)
+	_ = applyRiskGeneration(parsedModel, builtinRiskRules.Merge(customRiskRules), config.SkipRiskRules, progressReporter)
 	err := parsedModel.ApplyWildcardRiskTrackingEvaluation(config.IgnoreOrphanedRiskTracking, progressReporter)
 	if err != nil {
 		return nil, fmt.Errorf("unable to apply wildcard risk tracking evaluation: %v", err)
 	}
 
-	err = parsedModel.CheckRiskTracking(config.IgnoreOrphanedRiskTracking, progressReporter)
+	err = parsedModel.CheckRiskTracking(config.IgnoreOrphanedRiskTracking, progressReporter, time.Now())
 	if err != nil {
 		return nil, fmt.Errorf("unable to check risk tracking: %v", err)
 	}
 
+	success = true
 	return &ReadResult{
 		ModelInput:       modelInput,
 		ParsedModel:      parsedModel,
 		IntroTextRAA:     introTextRAA,
+		RAACalculator:    raaCalculator,
 		BuiltinRiskRules: builtinRiskRules,
 		CustomRiskRules:  customRiskRules,
 	}, nil
 }
 
+// applyRiskGeneration generates the risks of the rules not skipped, and returns the errors of the rules failing, sorted
+// by rule
+
 func applyRiskGeneration(parsedModel *types.Model, rules types.RiskRules,
 	skipRiskRules []string,
-	progressReporter types.ProgressReporter) {
+	progressReporter types.ProgressReporter) []error {
 	progressReporter.Info("Applying risk generation")
 
 	skippedRules := make(map[string]bool)
@@ -85,6 +105,7 @@ func applyRiskGeneration(parsedModel *types.Model, rules types.RiskRules,
 		}
 	}
 
+	ruleErrors := make([]error, 0)
 	for id, rule := range rules {
 		_, ok := skippedRules[id]
 		if ok {
@@ -97,6 +118,11 @@ func applyRiskGeneration(parsedModel *types.Model, rules types.RiskRules,
 		newRisks, riskError := rule.GenerateRisks(parsedModel)
 		if riskError != nil {
 			progressReporter.Warnf("Error generating risks for %q: %v", id, riskError)
+			ruleErrors = append(ruleErrors, fmt.Errorf("error generating risks for %q: %v", id, riskError))
+			if customRule, ok := rule.(*CustomRiskCategory); ok {
+				addPluginFailure(parsedModel, customRule.Filename(), riskError)
+			}
+
 			continue
 		}
 
@@ -119,25 +145,37 @@ func applyRiskGeneration(parsedModel *types.Model, rules types.RiskRules,
 	for _, category := range types.SortedRiskCategories(parsedModel) {
 		someRisks := types.SortedRisksOfCategory(parsedModel, category)
 		for _, risk := range someRisks {
+			risk.Position = parsedModel.RiskPosition(risk)
 			parsedModel.GeneratedRisksBySyntheticId[strings.ToLower(risk.SyntheticId)] = risk
 		}
 	}
+
+	sort.Slice(ruleErrors, func(i, j int) bool {
+		return ruleErrors[i].Error() < ruleErrors[j].Error()
+	})
+
+	return ruleErrors
 }
 
-func applyRAA(parsedModel *types.Model, binFolder, raaPlugin string, progressReporter types.ProgressReporter) string {
+func applyRAA(ctx context.Context, parsedModel *types.Model, config *common.Config, progressReporter types.ProgressReporter) (RAACalculator, string, error) {
+	raaPlugin := config.RAAPlugin
 	progressReporter.Infof("Applying RAA calculation: %v", raaPlugin)
 
-	runner, loadError := new(runner).Load(filepath.Join(binFolder, raaPlugin))
+	calculator, loadError := NewRAACalculator(config)
 	if loadError != nil {
 		progressReporter.Warnf("raa %q not loaded: %v\n", raaPlugin, loadError)
-		return ""
+		return nil, "", fmt.Errorf("raa %q not loaded: %v", raaPlugin, loadError)
 	}
 
-	runError := runner.Run(parsedModel, parsedModel)
+	introText, runError := calculator.Calculate(ctx, parsedModel)
 	if runError != nil {
 		progressReporter.Warnf("raa %q not applied: %v\n", raaPlugin, runError)
-		return ""
+		if plugin, ok := calculator.(*pluginRAACalculator); ok {
+			addPluginFailure(parsedModel, plugin.runner.Filename, runError)
+		}
+
+		return calculator, "", fmt.Errorf("raa %q not applied: %v", raaPlugin, runError)
 	}
 
-	return runner.ErrorOutput
+	return calculator, introText, nil
 }
@@ -0,0 +1,243 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/script"
+	"github.com/threagile/threagile/pkg/security/types"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ruleFixtureExpectedSuffix = ".expected"
+)
+
+// RuleFixture is a model file together with the risks the risk rules are expected to generate for it; the expected
+// risks are read from a sidecar file named like the model with an additional ".expected" before the extension
+
+type RuleFixture struct {
+	Name         string         `yaml:"-"`
+	ModelFile    string         `yaml:"model,omitempty"`
+	ExpectedFile string         `yaml:"-"`
+	Rules        []string       `yaml:"rules,omitempty"`
+	Risks        []ExpectedRisk `yaml:"risks"`
+}
+
+type ExpectedRisk struct {
+	SyntheticId string `yaml:"synthetic_id"`
+	Severity    string `yaml:"severity,omitempty"`
+	Title       string `yaml:"title,omitempty"`
+}
+
+type RuleFixtureResult struct {
+	Fixture     *RuleFixture
+	Differences []string
+}
+
+func (what *RuleFixtureResult) Passed() bool {
+	return len(what.Differences) == 0
+}
+
+// LoadRuleFixtures collects the rule fixtures from the given paths; a path may be a folder (searched recursively for
+// expected-risk sidecar files), a model file or an expected-risk sidecar file
+
+func LoadRuleFixtures(paths []string) ([]*RuleFixture, error) {
+	fixtures := make([]*RuleFixture, 0)
+	for _, path := range paths {
+		info, statError := os.Stat(path)
+		if statError != nil {
+			return nil, statError
+		}
+
+		if !info.IsDir() {
+			fixture, loadError := new(RuleFixture).Load(path)
+			if loadError != nil {
+				return nil, loadError
+			}
+
+			fixtures = append(fixtures, fixture)
+			continue
+		}
+
+		walkError := filepath.WalkDir(path, func(filename string, entry os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if entry.IsDir() || !isRuleFixtureExpectedFile(filename) {
+				return nil
+			}
+
+			fixture, loadError := new(RuleFixture).Load(filename)
+			if loadError != nil {
+				return loadError
+			}
+
+			fixtures = append(fixtures, fixture)
+			return nil
+		})
+
+		if walkError != nil {
+			return nil, walkError
+		}
+	}
+
+	return fixtures, nil
+}
+
+func (what *RuleFixture) Load(filename string) (*RuleFixture, error) {
+	expectedFile := filename
+	modelFile := ""
+	if !isRuleFixtureExpectedFile(filename) {
+		extension := filepath.Ext(filename)
+		expectedFile = strings.TrimSuffix(filename, extension) + ruleFixtureExpectedSuffix + extension
+		modelFile = filename
+	}
+
+	data, readError := os.ReadFile(filepath.Clean(expectedFile))
+	if readError != nil {
+		return nil, fmt.Errorf("unable to read expected risks for %q: %w", filename, readError)
+	}
+
+	parseError := yaml.Unmarshal(data, what)
+	if parseError != nil {
+		return nil, fmt.Errorf("unable to parse expected risks from %q: %w", expectedFile, parseError)
+	}
+
+	switch {
+	case len(modelFile) > 0:
+		what.ModelFile = modelFile
+
+	case len(what.ModelFile) > 0:
+		if !filepath.IsAbs(what.ModelFile) {
+			what.ModelFile = filepath.Join(filepath.Dir(expectedFile), what.ModelFile)
+		}
+
+	default:
+		extension := filepath.Ext(expectedFile)
+		what.ModelFile = strings.TrimSuffix(strings.TrimSuffix(expectedFile, extension), ruleFixtureExpectedSuffix) + extension
+	}
+
+	what.ExpectedFile = expectedFile
+	what.Name = strings.TrimSuffix(strings.TrimSuffix(expectedFile, filepath.Ext(expectedFile)), ruleFixtureExpectedSuffix)
+
+	return what, nil
+}
+
+// Run analyzes the fixture model with the rules under test and compares the generated risks with the expected ones;
+// if the fixture names its rules, those are taken from all available rules instead
+
+func (what *RuleFixture) Run(ctx context.Context, config *common.Config, rulesUnderTest types.RiskRules, availableRules types.RiskRules, progressReporter types.ProgressReporter) (*RuleFixtureResult, error) {
+	rules := rulesUnderTest
+	if len(what.Rules) > 0 {
+		rules = make(types.RiskRules)
+		for _, id := range what.Rules {
+			rule, ok := availableRules[id]
+			if !ok {
+				return nil, fmt.Errorf("unknown risk rule %q in %q", id, what.ExpectedFile)
+			}
+
+			rules[id] = rule
+		}
+	}
+
+	modelInput := new(input.Model).Defaults()
+	loadError := modelInput.Load(what.ModelFile)
+	if loadError != nil {
+		return nil, fmt.Errorf("unable to load model yaml %q: %v", what.ModelFile, loadError)
+	}
+
+	parsedModel, parseError := ParseModel(config, modelInput, rules, make(types.RiskRules))
+	if parseError != nil {
+		return nil, fmt.Errorf("unable to parse model yaml %q: %v", what.ModelFile, parseError)
+	}
+
+	failures := make([]string, 0)
+	_, _, raaError := applyRAA(ctx, parsedModel, config, progressReporter)
+	if raaError != nil {
+		failures = append(failures, raaError.Error())
+	}
+	for _, ruleError := range applyRiskGeneration(parsedModel, rules, nil, progressReporter) {
+		failures = append(failures, ruleError.Error())
+	}
+
+	generatedRisks := make(map[string]*types.Risk)
+	for id, risks := range parsedModel.GeneratedRisksByCategory {
+		if _, ok := rules[id]; !ok {
+			continue
+		}
+
+		for _, risk := range risks {
+			generatedRisks[strings.ToLower(risk.SyntheticId)] = risk
+		}
+	}
+
+	result := what.compare(generatedRisks)
+	result.Differences = append(failures, result.Differences...)
+	return result, nil
+}
+
+func (what *RuleFixture) compare(generatedRisks map[string]*types.Risk) *RuleFixtureResult {
+	result := &RuleFixtureResult{Fixture: what, Differences: make([]string, 0)}
+
+	expectedRisks := make(map[string]bool)
+	for _, expected := range what.Risks {
+		id := strings.ToLower(expected.SyntheticId)
+		expectedRisks[id] = true
+
+		risk, ok := generatedRisks[id]
+		if !ok {
+			result.Differences = append(result.Differences, fmt.Sprintf("missing risk %q", expected.SyntheticId))
+			continue
+		}
+
+		if len(expected.Severity) > 0 && !strings.EqualFold(expected.Severity, risk.Severity.String()) {
+			result.Differences = append(result.Differences, fmt.Sprintf("risk %q: severity is %q, expected %q", expected.SyntheticId, risk.Severity.String(), expected.Severity))
+		}
+
+		if len(expected.Title) > 0 && expected.Title != risk.Title {
+			result.Differences = append(result.Differences, fmt.Sprintf("risk %q: title is %q, expected %q", expected.SyntheticId, risk.Title, expected.Title))
+		}
+	}
+
+	unexpectedRisks := make([]string, 0)
+	for id, risk := range generatedRisks {
+		if !expectedRisks[id] {
+			unexpectedRisks = append(unexpectedRisks, fmt.Sprintf("unexpected risk %q (severity %q, title %q)", risk.SyntheticId, risk.Severity.String(), risk.Title))
+		}
+	}
+
+	sort.Strings(unexpectedRisks)
+	result.Differences = append(result.Differences, unexpectedRisks...)
+
+	return result
+}
+
+// ScriptRiskRules returns the script risk rules out of a set of risk rules
+
+func ScriptRiskRules(rules types.RiskRules) types.RiskRules {
+	scriptRules := make(types.RiskRules)
+	for id, rule := range rules {
+		if _, ok := rule.(*script.RiskRule); ok {
+			scriptRules[id] = rule
+		}
+	}
+
+	return scriptRules
+}
+
+func isRuleFixtureExpectedFile(filename string) bool {
+	extension := strings.ToLower(filepath.Ext(filename))
+	if extension != ".yaml" && extension != ".yml" {
+		return false
+	}
+
+	return strings.HasSuffix(strings.TrimSuffix(filename, filepath.Ext(filename)), ruleFixtureExpectedSuffix)
+}
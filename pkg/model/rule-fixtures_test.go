@@ -0,0 +1,98 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestRuleFixtureLoadResolvesModelFile(t *testing.T) {
+	folder := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, "model.expected.yaml"), []byte("risks:\n  - synthetic_id: some-risk@ta1\n"), 0600))
+
+	fixture, err := new(RuleFixture).Load(filepath.Join(folder, "model.expected.yaml"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(folder, "model.yaml"), fixture.ModelFile)
+	assert.Equal(t, filepath.Join(folder, "model"), fixture.Name)
+	assert.Len(t, fixture.Risks, 1)
+}
+
+func TestLoadRuleFixturesFindsSidecarFiles(t *testing.T) {
+	folder := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, "model.yaml"), []byte("title: model\n"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, "model.expected.yaml"), []byte("risks: []\n"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, "other.yaml"), []byte("title: other\n"), 0600))
+
+	fixtures, err := LoadRuleFixtures([]string{folder})
+
+	assert.NoError(t, err)
+	assert.Len(t, fixtures, 1)
+}
+
+func TestRuleFixtureCompareReportsDifferences(t *testing.T) {
+	fixture := &RuleFixture{
+		Risks: []ExpectedRisk{
+			{SyntheticId: "some-risk@ta1", Severity: "high"},
+			{SyntheticId: "some-risk@ta2"},
+			{SyntheticId: "some-risk@ta3", Title: "Some Risk at TA3"},
+		},
+	}
+
+	result := fixture.compare(map[string]*types.Risk{
+		"some-risk@ta1": {SyntheticId: "some-risk@ta1", Severity: types.MediumSeverity},
+		"some-risk@ta3": {SyntheticId: "some-risk@ta3", Title: "Some Risk at TA3"},
+		"some-risk@ta4": {SyntheticId: "some-risk@ta4"},
+	})
+
+	assert.False(t, result.Passed())
+	assert.Equal(t, []string{
+		`risk "some-risk@ta1": severity is "medium", expected "high"`,
+		`missing risk "some-risk@ta2"`,
+		`unexpected risk "some-risk@ta4" (severity "low", title "")`,
+	}, result.Differences)
+}
+
+// failingRiskRule fails to generate its risks
+
+type failingRiskRule struct{}
+
+func (what failingRiskRule) Category() *types.RiskCategory {
+	return &types.RiskCategory{ID: "failing-rule", Title: "Failing Rule"}
+}
+
+func (what failingRiskRule) SupportedTags() []string {
+	return []string{}
+}
+
+func (what failingRiskRule) GenerateRisks(*types.Model) ([]*types.Risk, error) {
+	return nil, fmt.Errorf("division by zero")
+}
+
+func TestRuleFixtureRunReportsRuleAndRAAErrors(t *testing.T) {
+	folder := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, "model.yaml"), []byte("threagile_version: 1.0.0\ntitle: model\ndate: 2024-01-01\nbusiness_criticality: important\n"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, "model.expected.yaml"), []byte("risks: []\n"), 0600))
+	fixture, err := new(RuleFixture).Load(filepath.Join(folder, "model.expected.yaml"))
+	require.NoError(t, err)
+
+	config := new(common.Config).Defaults("")
+	config.PluginFolder = folder
+	config.RAAPlugin = "missing-raa-plugin"
+	rules := types.RiskRules{"failing-rule": failingRiskRule{}}
+
+	result, err := fixture.Run(context.Background(), config, rules, rules, &common.DefaultProgressReporter{SuppressError: true})
+
+	require.NoError(t, err)
+	assert.False(t, result.Passed())
+	require.Len(t, result.Differences, 2)
+	assert.Contains(t, result.Differences[0], `raa "missing-raa-plugin" not loaded`)
+	assert.Equal(t, `error generating risks for "failing-rule": division by zero`, result.Differences[1])
+}
@@ -4,23 +4,38 @@ package model
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/threagile/threagile/pkg/common"
+	"gopkg.in/yaml.v3"
 )
 
+// pluginWaitDelay bounds how long a killed plugin may keep its output open, e.g. through processes it started itself
+
+const pluginWaitDelay = time.Second
+
 type runner struct {
 	Filename    string
 	Parameters  []string
 	In          any
 	Out         any
 	ErrorOutput string
+	Limits      common.PluginLimits
+	TempFolder  string
 }
 
-func (p *runner) Load(filename string) (*runner, error) {
+func (p *runner) Load(filename string, config *common.Config) (*runner, error) {
 	*p = runner{
-		Filename: filename,
+		Filename:   filename,
+		Limits:     config.Plugins.Limits(filename),
+		TempFolder: config.TempFolder,
 	}
 
 	fileInfo, statError := os.Stat(filename)
@@ -35,60 +50,120 @@ func (p *runner) Load(filename string) (*runner, error) {
 	return p, nil
 }
 
-func (p *runner) Run(in any, out any, parameters ...string) error {
+func (p *runner) Run(ctx context.Context, in any, out any, parameters ...string) error {
 	*p = runner{
 		Filename:   p.Filename,
 		Parameters: parameters,
 		In:         in,
 		Out:        out,
+		Limits:     p.Limits,
+		TempFolder: p.TempFolder,
 	}
 
-	plugin := exec.Command(p.Filename, p.Parameters...) // #nosec G204
-	stdin, stdinError := plugin.StdinPipe()
-	if stdinError != nil {
-		return stdinError
+	inData, inError := yaml.Marshal(p.In)
+	if inError != nil {
+		return fmt.Errorf("error encoding input data: %w", inError)
 	}
-	defer func() { _ = stdin.Close() }()
 
-	var stdoutBuf bytes.Buffer
-	plugin.Stdout = &stdoutBuf
+	ctx, cancel := context.WithTimeout(ctx, p.Limits.Timeout())
+	defer cancel()
 
-	var stderrBuf bytes.Buffer
-	plugin.Stderr = &stderrBuf
-
-	startError := plugin.Start()
-	if startError != nil {
-		return startError
+	plugin, cleanup, commandError := newPluginCommand(ctx, p.Filename, p.Limits, p.TempFolder, p.Parameters...)
+	if commandError != nil {
+		return &PluginRunError{Filename: p.Filename, Reason: PluginStartFailed, Err: commandError}
+	}
+	defer cleanup()
+
+	stdout := newLimitedBuffer(p.Limits.MaxOutputBytes, cancel)
+	stderr := newLimitedBuffer(p.Limits.MaxOutputBytes, cancel)
+	plugin.Stdin = bytes.NewReader(inData)
+	plugin.Stdout = stdout
+	plugin.Stderr = stderr
+
+	runError := plugin.Run()
+	p.ErrorOutput = stderr.String()
+	if runError != nil || stdout.exceeded || stderr.exceeded {
+		return newPluginRunError(ctx, p.Filename, p.Limits, runError, stdout.exceeded || stderr.exceeded, p.ErrorOutput)
 	}
 
-	inData, inError := yaml.Marshal(p.In)
-	if inError != nil {
-		return fmt.Errorf("error encoding input data: %w", inError)
+	unmarshalError := yaml.Unmarshal(stdout.Bytes(), p.Out)
+	if unmarshalError != nil {
+		return &PluginRunError{Filename: p.Filename, Reason: PluginInvalidOutput, Output: p.ErrorOutput, Err: unmarshalError}
 	}
 
-	_, writeError := stdin.Write(inData)
-	if writeError != nil {
-		return writeError
+	// _ = os.WriteFile(fmt.Sprintf("%v.yaml", rand.Int31()), stdout, 0644)
+
+	return nil
+}
+
+// newPluginCommand prepares running a plugin that is killed when the context ends; only stdin, stdout and stderr are
+// passed on to it, as all files opened by threagile are closed on exec. The returned cleanup removes the sandbox
+// folder.
+
+func newPluginCommand(ctx context.Context, filename string, limits common.PluginLimits, tempFolder string, parameters ...string) (*exec.Cmd, func(), error) {
+	path, pathError := filepath.Abs(filename)
+	if pathError != nil {
+		return nil, nil, pathError
 	}
 
-	inCloseError := stdin.Close()
-	if inCloseError != nil {
-		return inCloseError
+	plugin := exec.CommandContext(ctx, path, parameters...) // #nosec G204
+	plugin.WaitDelay = pluginWaitDelay
+	if !limits.Sandbox {
+		return plugin, func() {}, nil
 	}
 
-	waitError := plugin.Wait()
-	p.ErrorOutput = stderrBuf.String()
-	if waitError != nil {
-		return fmt.Errorf("%v: %v", waitError, p.ErrorOutput)
+	folder, folderError := os.MkdirTemp(tempFolder, "plugin-")
+	if folderError != nil {
+		return nil, nil, fmt.Errorf("failed to create sandbox folder: %w", folderError)
 	}
 
-	stdout := stdoutBuf.Bytes()
-	unmarshalError := yaml.Unmarshal(stdout, p.Out)
-	if unmarshalError != nil {
-		return unmarshalError
+	plugin.Dir = folder
+	plugin.Env = make([]string, 0)
+
+	return plugin, func() { _ = os.RemoveAll(folder) }, nil
+}
+
+// limitedBuffer keeps at most limit bytes and calls onExceeded once more data is written; it doesn't embed
+// bytes.Buffer, as io.Copy would bypass the limit through its ReadFrom method
+
+type limitedBuffer struct {
+	lock       sync.Mutex
+	buffer     bytes.Buffer
+	limit      int
+	onExceeded func()
+	exceeded   bool
+}
+
+func newLimitedBuffer(limit int, onExceeded func()) *limitedBuffer {
+	return &limitedBuffer{limit: limit, onExceeded: onExceeded}
+}
+
+func (what *limitedBuffer) Write(data []byte) (int, error) {
+	what.lock.Lock()
+	defer what.lock.Unlock()
+
+	if what.buffer.Len()+len(data) > what.limit {
+		if !what.exceeded && what.onExceeded != nil {
+			what.onExceeded()
+		}
+
+		what.exceeded = true
+		return 0, errors.New("output limit exceeded")
 	}
 
-	// _ = os.WriteFile(fmt.Sprintf("%v.yaml", rand.Int31()), stdout, 0644)
+	return what.buffer.Write(data)
+}
 
-	return nil
+func (what *limitedBuffer) Bytes() []byte {
+	what.lock.Lock()
+	defer what.lock.Unlock()
+
+	return what.buffer.Bytes()
+}
+
+func (what *limitedBuffer) String() string {
+	what.lock.Lock()
+	defer what.lock.Unlock()
+
+	return what.buffer.String()
 }
@@ -0,0 +1,166 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const runnerTestPluginFlag = "-runner-test-plugin"
+
+// runRunnerTestPlugin behaves like a misbehaving plugin, or reports what it got from the runner
+
+func runRunnerTestPlugin(mode string) int {
+	switch mode {
+	case "hang":
+		time.Sleep(time.Minute)
+
+	case "flood":
+		for n := 0; n < 1024; n++ {
+			_, _ = os.Stdout.WriteString(strings.Repeat("x", 1024))
+		}
+
+	case "fail":
+		_, _ = os.Stderr.WriteString("broken plugin")
+		return 3
+
+	case "environment":
+		folder, _ := os.Getwd()
+		fmt.Printf("variables: %d\nfolder: %q\n", len(os.Environ()), folder)
+	}
+
+	return 0
+}
+
+func newRunnerTest(t *testing.T, limits common.PerPluginLimits) *runner {
+	config := new(common.Config).Defaults("")
+	config.TempFolder = t.TempDir()
+	config.Plugins.PerPlugin[filepath.Base(os.Args[0])] = limits
+
+	testRunner, loadError := new(runner).Load(os.Args[0], config)
+	assert.NoError(t, loadError)
+
+	return testRunner
+}
+
+func TestRunnerStopsPluginAfterTimeout(t *testing.T) {
+	started := time.Now()
+
+	runError := newRunnerTest(t, common.PerPluginLimits{TimeoutSeconds: 1}).Run(context.Background(), nil, new(any), runnerTestPluginFlag, "hang")
+
+	var pluginError *PluginRunError
+	if assert.True(t, errors.As(runError, &pluginError)) {
+		assert.Equal(t, PluginTimedOut, pluginError.Reason)
+	}
+	assert.Less(t, time.Since(started), 30*time.Second)
+}
+
+func TestPluginClientStopsPluginNotExitingOnClose(t *testing.T) {
+	t.Setenv(servePluginTestEnv, servePluginTestLingering)
+
+	config := new(common.Config).Defaults("")
+	config.Plugins.PerPlugin[filepath.Base(os.Args[0])] = common.PerPluginLimits{TimeoutSeconds: 1}
+
+	client := new(pluginClient)
+	_, startError := client.Start(context.Background(), os.Args[0], config)
+	if !assert.NoError(t, startError) {
+		return
+	}
+
+	started := time.Now()
+	closeError := client.Close()
+
+	var pluginError *PluginRunError
+	if assert.True(t, errors.As(closeError, &pluginError)) {
+		assert.Equal(t, PluginTimedOut, pluginError.Reason)
+	}
+	assert.Less(t, time.Since(started), 30*time.Second)
+}
+
+func TestRunnerStopsPluginOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	runError := newRunnerTest(t, common.PerPluginLimits{}).Run(ctx, nil, new(any), runnerTestPluginFlag, "hang")
+
+	var pluginError *PluginRunError
+	if assert.True(t, errors.As(runError, &pluginError)) {
+		assert.Equal(t, PluginCanceled, pluginError.Reason)
+	}
+}
+
+func TestRunnerLimitsOutput(t *testing.T) {
+	runError := newRunnerTest(t, common.PerPluginLimits{MaxOutputBytes: 4096}).Run(context.Background(), nil, new(any), runnerTestPluginFlag, "flood")
+
+	var pluginError *PluginRunError
+	if assert.True(t, errors.As(runError, &pluginError)) {
+		assert.Equal(t, PluginOutputTooLarge, pluginError.Reason)
+	}
+}
+
+func TestRunnerReportsPluginOutputOnFailure(t *testing.T) {
+	runError := newRunnerTest(t, common.PerPluginLimits{}).Run(context.Background(), nil, new(any), runnerTestPluginFlag, "fail")
+
+	var pluginError *PluginRunError
+	if assert.True(t, errors.As(runError, &pluginError)) {
+		assert.Equal(t, PluginFailed, pluginError.Reason)
+		assert.Equal(t, "broken plugin", pluginError.Output)
+	}
+}
+
+func TestRunnerSandboxClearsEnvironmentAndFolder(t *testing.T) {
+	sandbox := true
+	testRunner := newRunnerTest(t, common.PerPluginLimits{Sandbox: &sandbox})
+
+	result := struct {
+		Variables int
+		Folder    string
+	}{}
+	assert.NoError(t, testRunner.Run(context.Background(), nil, &result, runnerTestPluginFlag, "environment"))
+
+	assert.Equal(t, 0, result.Variables)
+	assert.True(t, strings.HasPrefix(result.Folder, testRunner.TempFolder), result.Folder)
+	_, statError := os.Stat(result.Folder)
+	assert.True(t, os.IsNotExist(statError))
+}
+
+func TestPluginConfigLimitsOfPlugin(t *testing.T) {
+	sandbox, noSandbox := true, false
+	config := common.PluginConfig{
+		PluginLimits: common.PluginLimits{TimeoutSeconds: 10},
+		PerPlugin:    map[string]common.PerPluginLimits{"raa_calc": {MaxOutputBytes: 100, Sandbox: &sandbox}},
+	}
+
+	assert.Equal(t, common.PluginLimits{TimeoutSeconds: 10, MaxOutputBytes: 100, Sandbox: true}, config.Limits("/app/raa_calc"))
+	assert.Equal(t, common.PluginLimits{TimeoutSeconds: 10, MaxOutputBytes: common.DefaultPluginMaxOutputBytes}, config.Limits("/app/other"))
+
+	config = common.PluginConfig{
+		PluginLimits: common.PluginLimits{TimeoutSeconds: 10, Sandbox: true},
+		PerPlugin:    map[string]common.PerPluginLimits{"raa_calc": {Sandbox: &noSandbox}, "custom_rules": {TimeoutSeconds: 20}},
+	}
+
+	assert.False(t, config.Limits("/app/raa_calc").Sandbox)
+	assert.True(t, config.Limits("/app/custom_rules").Sandbox)
+	assert.True(t, config.Limits("/app/other").Sandbox)
+}
+
+func TestPluginFailureShowsUpAsModelFailure(t *testing.T) {
+	parsedModel := &types.Model{}
+
+	addPluginFailure(parsedModel, "/app/raa_calc", &PluginRunError{Filename: "/app/raa_calc", Reason: PluginTimedOut})
+
+	failures := types.FlattenRiskSlice(types.FilterByModelFailures(parsedModel, parsedModel.GeneratedRisksByCategory))
+	if assert.Len(t, failures, 1) {
+		assert.Equal(t, "plugin-failure@raa_calc", failures[0].SyntheticId)
+		assert.Contains(t, failures[0].Title, "timed out")
+	}
+}
@@ -0,0 +1,361 @@
+package model
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/risks"
+	"github.com/threagile/threagile/pkg/security/types"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	parityModelFile     = "../../test/all.yaml"
+	parityModelVariants = 30
+)
+
+var (
+	parityTags = []string{"aws", "aws:ec2", "aws:s3", "aws:lambda", "azure", "gcp", "ocp", "git", "docker", "nexus"}
+)
+
+// TestScriptRiskRulesMatchGoRiskRules checks that each embedded script risk rule generates the same risks as the Go
+// risk rule it shadows, on test/all.yaml and on randomly altered variants of it
+
+func TestScriptRiskRulesMatchGoRiskRules(t *testing.T) {
+	goRules := risks.GetGoRiskRules()
+	scriptRules, scriptError := risks.GetScriptRiskRules()
+	if !assert.NoError(t, scriptError) {
+		return
+	}
+
+	models := createParityModels(t, goRules)
+
+	ids := make([]string, 0)
+	for id := range scriptRules {
+		if _, ok := goRules[id]; ok {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Strings(ids)
+	for _, id := range ids {
+		t.Run(id, func(t *testing.T) {
+			for n, parsedModel := range models {
+				goRisks, goError := goRules[id].GenerateRisks(parsedModel)
+				if !assert.NoError(t, goError) {
+					return
+				}
+
+				scriptRisks, scriptError := scriptRules[id].GenerateRisks(parsedModel)
+				if !assert.NoError(t, scriptError) {
+					return
+				}
+
+				differences := compareParityRisks(goRisks, scriptRisks)
+				if !assert.Empty(t, differences, "model variant #%d", n) {
+					return
+				}
+			}
+		})
+	}
+}
+
+func createParityModels(t *testing.T, rules types.RiskRules) []*types.Model {
+	config := new(common.Config).Defaults("")
+	technologies := make([]string, 0)
+	for _, technology := range types.TechnicalAssetTechnologyValues(config) {
+		technologies = append(technologies, technology.String())
+	}
+
+	models := make([]*types.Model, 0)
+	for n := 0; n <= parityModelVariants; n++ {
+		modelInput := new(input.Model).Defaults()
+		loadError := modelInput.Load(parityModelFile)
+		if !assert.NoError(t, loadError) {
+			t.FailNow()
+		}
+
+		random := rand.New(rand.NewSource(int64(n)))
+		if n > 0 {
+			alterParityModel(modelInput, random, n, technologies)
+		}
+
+		parsedModel, parseError := ParseModel(config, modelInput, rules, make(types.RiskRules))
+		if parseError != nil {
+			t.Logf("skipping model variant #%d: %v", n, parseError)
+			continue
+		}
+
+		for i, id := range parsedModel.SortedTechnicalAssetIDs() {
+			parsedModel.TechnicalAssets[id].RAA = float64((i*37 + n*11) % 100)
+		}
+
+		if n > 0 {
+			alterParsedParityModel(parsedModel, random)
+		}
+
+		models = append(models, parsedModel)
+	}
+
+	return models
+}
+
+// alterParityModel randomly changes properties of the model that the risk rules look at
+
+func alterParityModel(modelInput *input.Model, random *rand.Rand, variant int, technologies []string) {
+	modelInput.TagsAvailable = append(modelInput.TagsAvailable, parityTags...)
+	tags := modelInput.TagsAvailable
+
+	dataAssets := make([]string, 0)
+	for _, title := range sortedKeys(modelInput.DataAssets) {
+		dataAssets = append(dataAssets, modelInput.DataAssets[title].ID)
+	}
+
+	if random.Intn(2) == 0 {
+		modelInput.DataAssets["Parity Unused Data"] = input.DataAsset{
+			ID:              "parity-unused-data",
+			Usage:           pickParityValue(random, types.UsageValues()),
+			Quantity:        pickParityValue(random, types.QuantityValues()),
+			Confidentiality: pickParityValue(random, types.ConfidentialityValues()),
+			Integrity:       pickParityValue(random, types.CriticalityValues()),
+			Availability:    pickParityValue(random, types.CriticalityValues()),
+		}
+	}
+
+	for _, title := range sortedKeys(modelInput.DataAssets) {
+		dataAsset := modelInput.DataAssets[title]
+		if random.Intn(3) == 0 {
+			dataAsset.Confidentiality = pickParityValue(random, types.ConfidentialityValues())
+			dataAsset.Integrity = pickParityValue(random, types.CriticalityValues())
+			dataAsset.Availability = pickParityValue(random, types.CriticalityValues())
+		}
+
+		modelInput.DataAssets[title] = dataAsset
+	}
+
+	for index, title := range sortedKeys(modelInput.TechnicalAssets) {
+		asset := modelInput.TechnicalAssets[title]
+		if random.Intn(3) == 0 {
+			// cycle through the technologies over the variants, so that rarely used ones are covered too
+			asset.Technology = ""
+			asset.Technologies = []string{technologies[(variant*len(modelInput.TechnicalAssets)+index)%len(technologies)]}
+		}
+
+		for i := random.Intn(4); i > 0; i-- {
+			switch random.Intn(14) {
+			case 0:
+				asset.Technology = ""
+				asset.Technologies = []string{technologies[random.Intn(len(technologies))]}
+
+			case 1:
+				asset.Type = pickParityValue(random, types.TechnicalAssetTypeValues())
+
+			case 2:
+				asset.Machine = pickParityValue(random, types.TechnicalAssetMachineValues())
+
+			case 3:
+				asset.Encryption = pickParityValue(random, types.EncryptionStyleValues())
+
+			case 4:
+				asset.Confidentiality = pickParityValue(random, types.ConfidentialityValues())
+				asset.Integrity = pickParityValue(random, types.CriticalityValues())
+				asset.Availability = pickParityValue(random, types.CriticalityValues())
+
+			case 5:
+				asset.Internet = !asset.Internet
+				asset.MultiTenant = random.Intn(2) == 0
+
+			case 6:
+				asset.OutOfScope = random.Intn(4) == 0
+				asset.CustomDevelopedParts = random.Intn(2) == 0
+
+			case 7:
+				asset.Tags = pickParityItems(random, tags)
+
+			case 8:
+				asset.DataFormatsAccepted = []string{pickParityValue(random, types.DataFormatValues())}
+
+			case 9:
+				asset.DataAssetsProcessed = pickParityItems(random, dataAssets)
+				asset.DataAssetsStored = pickParityItems(random, asset.DataAssetsProcessed)
+
+			case 10:
+				asset.Usage = pickParityValue(random, types.UsageValues())
+				asset.UsedAsClientByHuman = random.Intn(2) == 0
+
+			case 11:
+				asset.Redundant = !asset.Redundant
+				asset.Size = pickParityValue(random, types.TechnicalAssetSizeValues())
+
+			case 12:
+				asset.Technology = ""
+				asset.Technologies = append(asset.Technologies, technologies[random.Intn(len(technologies))])
+
+			default:
+				asset.DataFormatsAccepted = append(asset.DataFormatsAccepted, pickParityValue(random, types.DataFormatValues()))
+			}
+		}
+
+		for _, linkTitle := range sortedKeys(asset.CommunicationLinks) {
+			link := asset.CommunicationLinks[linkTitle]
+			for i := random.Intn(3); i > 0; i-- {
+				switch random.Intn(9) {
+				case 0:
+					link.Protocol = pickParityValue(random, types.ProtocolValues())
+
+				case 1:
+					link.Authentication = pickParityValue(random, types.AuthenticationValues())
+					link.Authorization = pickParityValue(random, types.AuthorizationValues())
+
+				case 2:
+					link.Usage = pickParityValue(random, types.UsageValues())
+
+				case 3:
+					link.VPN = !link.VPN
+					link.IpFiltered = random.Intn(2) == 0
+
+				case 4:
+					link.Readonly = !link.Readonly
+
+				case 5:
+					link.DataAssetsSent = pickParityItems(random, dataAssets)
+					link.DataAssetsReceived = pickParityItems(random, dataAssets)
+
+				case 6:
+					link.Tags = pickParityItems(random, tags)
+
+				case 7:
+					link.DataAssetsSent = nil
+					link.DataAssetsReceived = nil
+
+				default:
+					link.Protocol = pickParityValue(random, types.ProtocolValues())
+					link.Authentication = pickParityValue(random, types.AuthenticationValues())
+				}
+			}
+
+			asset.CommunicationLinks[linkTitle] = link
+		}
+
+		modelInput.TechnicalAssets[title] = asset
+	}
+
+	for _, title := range sortedKeys(modelInput.TrustBoundaries) {
+		trustBoundary := modelInput.TrustBoundaries[title]
+		if random.Intn(3) == 0 {
+			trustBoundary.Type = pickParityValue(random, types.TrustBoundaryTypeValues())
+		}
+
+		if random.Intn(4) == 0 {
+			trustBoundary.Tags = pickParityItems(random, tags)
+		}
+
+		modelInput.TrustBoundaries[title] = trustBoundary
+	}
+
+	for _, title := range sortedKeys(modelInput.SharedRuntimes) {
+		sharedRuntime := modelInput.SharedRuntimes[title]
+		if random.Intn(3) == 0 {
+			sharedRuntime.Tags = pickParityItems(random, tags)
+		}
+
+		modelInput.SharedRuntimes[title] = sharedRuntime
+	}
+}
+
+// alterParsedParityModel drops data assets from technical assets after parsing, which otherwise adds all data
+// transferred by an asset's communication links to the data it processes
+
+func alterParsedParityModel(parsedModel *types.Model, random *rand.Rand) {
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		asset := parsedModel.TechnicalAssets[id]
+		if random.Intn(4) == 0 {
+			asset.DataAssetsProcessed = pickParityItems(random, asset.DataAssetsProcessed)
+			asset.DataAssetsStored = pickParityItems(random, asset.DataAssetsStored)
+		}
+	}
+}
+
+func compareParityRisks(goRisks []*types.Risk, scriptRisks []*types.Risk) []string {
+	differences := make([]string, 0)
+	goRisksById := parityRisksById(goRisks)
+	scriptRisksById := parityRisksById(scriptRisks)
+
+	for _, id := range sortedKeys(goRisksById) {
+		goRisk := goRisksById[id]
+		scriptRisk, ok := scriptRisksById[id]
+		if !ok {
+			differences = append(differences, fmt.Sprintf("missing risk %q", goRisk.SyntheticId))
+			continue
+		}
+
+		goText := printParityRisk(goRisk)
+		scriptText := printParityRisk(scriptRisk)
+		if goText != scriptText {
+			differences = append(differences, fmt.Sprintf("risk %q differs:\ngo:\n%v\nscript:\n%v", goRisk.SyntheticId, goText, scriptText))
+		}
+	}
+
+	for _, id := range sortedKeys(scriptRisksById) {
+		if _, ok := goRisksById[id]; !ok {
+			differences = append(differences, fmt.Sprintf("unexpected risk %q", scriptRisksById[id].SyntheticId))
+		}
+	}
+
+	return differences
+}
+
+func parityRisksById(riskList []*types.Risk) map[string]*types.Risk {
+	risksById := make(map[string]*types.Risk)
+	for _, risk := range riskList {
+		risksById[strings.ToLower(risk.SyntheticId)] = risk
+	}
+
+	return risksById
+}
+
+// printParityRisk prints the fields of a risk that a rule is responsible for; data breach assets are compared as a set
+
+func printParityRisk(risk *types.Risk) string {
+	breachAssets := make(map[string]bool)
+	for _, id := range risk.DataBreachTechnicalAssetIDs {
+		breachAssets[id] = true
+	}
+
+	normalized := *risk
+	normalized.DataBreachTechnicalAssetIDs = sortedKeys(breachAssets)
+
+	text, _ := yaml.Marshal(normalized)
+	return string(text)
+}
+
+func pickParityValue(random *rand.Rand, values []types.TypeEnum) string {
+	return values[random.Intn(len(values))].String()
+}
+
+func pickParityItems(random *rand.Rand, items []string) []string {
+	picked := make([]string, 0)
+	for _, item := range items {
+		if random.Intn(3) == 0 {
+			picked = append(picked, item)
+		}
+	}
+
+	return picked
+}
+
+func sortedKeys[V any](items map[string]V) []string {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
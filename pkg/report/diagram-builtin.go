@@ -0,0 +1,474 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// UseBuiltinDiagramRenderer tells whether the diagrams are rendered by the built-in layout and rendering, which is
+// the case when asked for or, by default, when the dot command of graphviz is not installed
+
+func UseBuiltinDiagramRenderer(renderer string) (bool, error) {
+	switch renderer {
+	case common.DiagramRendererGraphviz:
+		return false, nil
+	case common.DiagramRendererBuiltin:
+		return true, nil
+	case common.DiagramRendererAuto, "":
+		_, err := exec.LookPath("dot")
+		return err != nil, nil
+	default:
+		return false, fmt.Errorf("unknown diagram renderer %q (%v, %v, %v)", renderer, common.DiagramRendererAuto, common.DiagramRendererGraphviz, common.DiagramRendererBuiltin)
+	}
+}
+
+// RenderDataFlowDiagram renders the data flow diagram without graphviz as PNG and, if svgFilename is given, as SVG
+
+func RenderDataFlowDiagram(parsedModel *types.Model, pngFilename string, svgFilename string, dpi int, addModelTitle bool,
+	reportFilename string, progressReporter progressReporter) error {
+	progressReporter.Info("Rendering data flow diagram")
+	links := &diagramLinks{reportFilename: reportFilename, idPrefix: "data-flow-"}
+	graph, err := newDataFlowDiagram(parsedModel, addModelTitle, links, nil)
+	if err != nil {
+		return err
+	}
+
+	return graph.render(pngFilename, svgFilename, dpi)
+}
+
+// RenderAttackPathDiagram renders the attack path diagram without graphviz as PNG and, if svgFilename is given, as SVG
+
+func RenderAttackPathDiagram(parsedModel *types.Model, attackPaths []*model.AttackPath, pngFilename string, svgFilename string,
+	dpi int, addModelTitle bool, reportFilename string, progressReporter progressReporter) error {
+	progressReporter.Info("Rendering attack path diagram")
+	links := &diagramLinks{reportFilename: reportFilename, idPrefix: "attack-path-"}
+	graph, err := newDataFlowDiagram(parsedModel, addModelTitle, links, newDiagramHighlight(attackPaths))
+	if err != nil {
+		return err
+	}
+
+	return graph.render(pngFilename, svgFilename, dpi)
+}
+
+// RenderDataAssetDiagram renders the data asset diagram without graphviz as PNG and, if svgFilename is given, as SVG
+
+func RenderDataAssetDiagram(parsedModel *types.Model, pngFilename string, svgFilename string, dpi int,
+	reportFilename string, progressReporter progressReporter) error {
+	progressReporter.Info("Rendering data asset diagram")
+	links := &diagramLinks{reportFilename: reportFilename, idPrefix: "data-asset-diagram-"}
+	return newDataAssetDiagram(parsedModel, links).render(pngFilename, svgFilename, dpi)
+}
+
+func (what *diagramGraph) render(pngFilename string, svgFilename string, dpi int) error {
+	what.layout()
+
+	if len(pngFilename) > 0 {
+		err := what.writePNG(pngFilename, float64(dpi)/72)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(svgFilename) > 0 {
+		err := what.writeSVG(svgFilename)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newDataFlowDiagram builds the data flow diagram the way writeDataFlowDiagramGraphvizDOT does for graphviz, with
+// shared runtimes drawn as clusters as long as their technical assets share the same trust boundary
+
+func newDataFlowDiagram(parsedModel *types.Model, addModelTitle bool, links *diagramLinks, highlight *diagramHighlight) (*diagramGraph, error) {
+	graph := newDiagramGraph()
+	graph.orthogonal = true
+	graph.leftToRight = parsedModel.DiagramTweakLayoutLeftToRight
+	switch parsedModel.DiagramTweakEdgeLayout {
+	case "", "ortho":
+	case "spline", "polyline", "curved", "false":
+		graph.orthogonal = false
+	default:
+		return nil, fmt.Errorf("unknown value for diagram_tweak_edge_layout (spline, polyline, ortho, curved, false): %s", parsedModel.DiagramTweakEdgeLayout)
+	}
+	if parsedModel.DiagramTweakNodesep > 0 { // in inches, as for graphviz
+		graph.nodeSep = float64(parsedModel.DiagramTweakNodesep) * 72
+	}
+	if parsedModel.DiagramTweakRanksep > 0 {
+		graph.rankSep = float64(parsedModel.DiagramTweakRanksep) * 72
+	}
+	if addModelTitle {
+		graph.title = &diagramText{text: parsedModel.Title, size: 40, color: Black}
+	}
+
+	// Trust Boundaries ===============================================================================
+	clusters := make(map[string]*diagramCluster)
+	var addTrustBoundary func(id string) *diagramCluster
+	addTrustBoundary = func(id string) *diagramCluster {
+		if cluster, ok := clusters[id]; ok {
+			return cluster
+		}
+
+		trustBoundary := parsedModel.TrustBoundaries[id]
+		var parent *diagramCluster
+		if parentId := trustBoundary.ParentTrustBoundaryID(parsedModel); len(parentId) > 0 {
+			parent = addTrustBoundary(parentId)
+		}
+
+		cluster := &diagramCluster{
+			link:        links.trustBoundary(trustBoundary),
+			label:       &diagramText{text: trustBoundary.Title + " (" + trustBoundary.Type.String() + ")", size: 21, bold: true, color: rgbHexColorTwilight()},
+			fillColor:   "#FAFAFA",
+			borderColor: rgbHexColorTwilight(),
+			borderStyle: "dashed",
+			penWidth:    4.5,
+		}
+		if len(trustBoundary.TrustBoundariesNested) > 0 {
+			cluster.penWidth = 5.5
+		}
+		if parent != nil {
+			cluster.fillColor = "#F1F1F1"
+		}
+		if trustBoundary.Type == types.NetworkPolicyNamespaceIsolation {
+			cluster.label.color, cluster.fillColor = "#222222", "#DFF4FF"
+		}
+		if trustBoundary.Type == types.ExecutionEnvironment {
+			cluster.label.color, cluster.fillColor, cluster.borderStyle = "#555555", "#FFFFF0", "dotted"
+		}
+
+		clusters[id] = graph.addCluster(parent, cluster)
+		return clusters[id]
+	}
+	for _, id := range types.SortedKeysOfTrustBoundaries(parsedModel) {
+		trustBoundary := parsedModel.TrustBoundaries[id]
+		if len(trustBoundary.TechnicalAssetsInside) > 0 || len(trustBoundary.TrustBoundariesNested) > 0 {
+			addTrustBoundary(id)
+		}
+	}
+
+	// Shared Runtimes ===============================================================================
+	clusterOf := make(map[string]*diagramCluster)
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		if trustBoundary, ok := parsedModel.DirectContainingTrustBoundaryMappedByTechnicalAssetId[id]; ok {
+			clusterOf[id] = clusters[trustBoundary.Id]
+		}
+	}
+	sharedRuntimeIds := make([]string, 0)
+	for id := range parsedModel.SharedRuntimes {
+		sharedRuntimeIds = append(sharedRuntimeIds, id)
+	}
+	sort.Strings(sharedRuntimeIds)
+	inSharedRuntime := make(map[string]bool)
+	for _, id := range sharedRuntimeIds {
+		sharedRuntime := parsedModel.SharedRuntimes[id]
+		if len(sharedRuntime.TechnicalAssetsRunning) == 0 {
+			continue
+		}
+
+		parent, drawable := clusterOf[sharedRuntime.TechnicalAssetsRunning[0]], true
+		for _, techAssetId := range sharedRuntime.TechnicalAssetsRunning {
+			if clusterOf[techAssetId] != parent || inSharedRuntime[techAssetId] {
+				drawable = false
+			}
+		}
+		if !drawable {
+			continue
+		}
+
+		cluster := graph.addCluster(parent, &diagramCluster{
+			link:        links.sharedRuntime(sharedRuntime),
+			label:       &diagramText{text: sharedRuntime.Title + " (shared runtime)", size: 18, bold: true, color: "#555555"},
+			fillColor:   "#FFFFFF",
+			borderColor: MiddleLightGray,
+			borderStyle: "dashed",
+			penWidth:    2.5,
+		})
+		for _, techAssetId := range sharedRuntime.TechnicalAssetsRunning {
+			clusterOf[techAssetId] = cluster
+			inSharedRuntime[techAssetId] = true
+		}
+	}
+
+	// Technical Assets ===============================================================================
+	techAssets := make([]*types.TechnicalAsset, 0)
+	for _, techAsset := range parsedModel.TechnicalAssets {
+		techAssets = append(techAssets, techAsset)
+	}
+	sort.Sort(types.ByOrderAndIdSort(techAssets))
+	nodes := make(map[string]*diagramNode)
+	for _, techAsset := range techAssets {
+		node := newTechAssetDiagramNode(parsedModel, techAsset, links)
+		if highlight != nil && highlight.technicalAssetIds[techAsset.Id] {
+			node.borderColor, node.penWidth = Red, 8.0
+		}
+		nodes[techAsset.Id] = graph.addNode(clusterOf[techAsset.Id], node)
+	}
+
+	// Data Flows (Technical Communication Links) ===============================================================================
+	for _, techAsset := range techAssets {
+		for _, dataFlow := range techAsset.CommunicationLinksSorted() {
+			target, ok := nodes[dataFlow.TargetId]
+			if !ok {
+				continue
+			}
+
+			edge := &diagramEdge{
+				link:       links.communicationLink(dataFlow),
+				source:     nodes[techAsset.Id],
+				target:     target,
+				color:      determineArrowColor(dataFlow, parsedModel),
+				style:      determineArrowLineStyle(dataFlow),
+				penWidth:   diagramPenWidth(determineArrowPenWidth(dataFlow, parsedModel)),
+				arrowHead:  "normal",
+				arrowTail:  "dot",
+				arrowSize:  14,
+				constraint: dataFlow.DiagramTweakConstraint,
+				weight:     float64(dataFlow.DiagramTweakWeight),
+			}
+			if dataFlow.Readonly {
+				edge.arrowHead, edge.arrowTail = "empty", "odot"
+			}
+			if highlight != nil {
+				if highlight.communicationLinkIds[dataFlow.Id] {
+					edge.color, edge.penWidth = Red, 6.0
+				} else {
+					edge.color = LightGray
+				}
+			}
+			if !parsedModel.DiagramTweakSuppressEdgeLabels {
+				edge.label = &diagramText{text: dataFlow.Protocol.String(), size: 18, color: determineLabelColor(dataFlow, parsedModel)}
+			}
+			graph.addEdge(edge)
+		}
+	}
+
+	// Tweaks ===============================================================================
+	for _, invisibleConnection := range parsedModel.DiagramTweakInvisibleConnectionsBetweenAssets {
+		assetIDs := strings.Split(invisibleConnection, ":")
+		if len(assetIDs) == 2 && nodes[assetIDs[0]] != nil && nodes[assetIDs[1]] != nil {
+			graph.addEdge(&diagramEdge{source: nodes[assetIDs[0]], target: nodes[assetIDs[1]], constraint: true, invisible: true})
+		}
+	}
+	for _, sameRank := range parsedModel.DiagramTweakSameRankAssets {
+		group := make([]*diagramNode, 0)
+		for _, id := range strings.Split(sameRank, ":") {
+			if node, ok := nodes[id]; ok {
+				group = append(group, node)
+			}
+		}
+		graph.sameRank = append(graph.sameRank, group)
+	}
+
+	return graph, nil
+}
+
+// newDataAssetDiagram builds the data asset diagram the way WriteDataAssetDiagramGraphvizDOT does for graphviz
+
+func newDataAssetDiagram(parsedModel *types.Model, links *diagramLinks) *diagramGraph {
+	graph := newDiagramGraph()
+	graph.leftToRight = true
+	graph.nodeSep = 36
+	graph.rankSep = 216
+
+	// Technical Assets ===============================================================================
+	techAssets := make([]*types.TechnicalAsset, 0)
+	for _, techAsset := range parsedModel.TechnicalAssets {
+		techAssets = append(techAssets, techAsset)
+	}
+	sort.Sort(types.ByOrderAndIdSort(techAssets))
+	techAssetNodes := make(map[string]*diagramNode)
+	for _, techAsset := range techAssets {
+		if len(techAsset.DataAssetsStored) > 0 || len(techAsset.DataAssetsProcessed) > 0 {
+			color := rgbHexColorOutOfScope()
+			if !techAsset.OutOfScope {
+				color = "#444444" // since black is too dark here as fill color
+				if severity, ok := highestOpenRiskSeverity(parsedModel, techAsset.GeneratedRisks(parsedModel)); ok {
+					color = diagramSeverityColor(severity)
+				}
+			}
+			techAssetNodes[techAsset.Id] = graph.addNode(nil, newFilledDiagramNode(links.technicalAsset(parsedModel, techAsset), techAsset.Title, color))
+		}
+	}
+
+	// Data Assets ===============================================================================
+	dataAssets := make([]*types.DataAsset, 0)
+	for _, dataAsset := range parsedModel.DataAssets {
+		dataAssets = append(dataAssets, dataAsset)
+	}
+	types.SortByDataAssetDataBreachProbabilityAndTitle(parsedModel, dataAssets)
+	dataAssetNodes := make(map[string]*diagramNode)
+	for _, dataAsset := range dataAssets {
+		color := "#444444" // since black is too dark here as fill color
+		if dataAsset.IsDataBreachPotentialStillAtRisk(parsedModel) {
+			switch dataAsset.IdentifiedDataBreachProbabilityStillAtRisk(parsedModel) {
+			case types.Probable:
+				color = rgbHexColorHighRisk()
+			case types.Possible:
+				color = rgbHexColorMediumRisk()
+			case types.Improbable:
+				color = rgbHexColorLowRisk()
+			}
+		}
+		dataAssetNodes[dataAsset.Id] = graph.addNode(nil, newFilledDiagramNode(links.dataAsset(parsedModel, dataAsset), dataAsset.Title, color))
+	}
+
+	// Data Asset to Tech Asset links ===============================================================================
+	addEdge := func(dataAssetId string, techAssetId string, color string, style string) {
+		if source, ok := dataAssetNodes[dataAssetId]; ok {
+			graph.addEdge(&diagramEdge{
+				link:       links.dataAssetFlow(dataAssetId, techAssetId),
+				source:     source,
+				target:     techAssetNodes[techAssetId],
+				color:      color,
+				style:      style,
+				penWidth:   1.5,
+				arrowHead:  "normal",
+				arrowTail:  "none",
+				arrowSize:  10,
+				constraint: true,
+			})
+		}
+	}
+	for _, techAsset := range techAssets {
+		for _, dataAssetId := range techAsset.DataAssetsStored {
+			addEdge(dataAssetId, techAsset.Id, "#0000FF", "solid")
+		}
+		for _, dataAssetId := range techAsset.DataAssetsProcessed {
+			if !contains(techAsset.DataAssetsStored, dataAssetId) { // here only if not already drawn above
+				addEdge(dataAssetId, techAsset.Id, "#666666", "dashed")
+			}
+		}
+	}
+
+	return graph
+}
+
+func newTechAssetDiagramNode(parsedModel *types.Model, techAsset *types.TechnicalAsset, links *diagramLinks) *diagramNode {
+	node := &diagramNode{
+		link:        links.technicalAsset(parsedModel, techAsset),
+		shape:       "box",
+		fillColor:   determineShapeFillColor(techAsset, parsedModel),
+		borderColor: determineShapeBorderColor(techAsset, parsedModel),
+		borderStyle: determineShapeBorderLineStyle(techAsset),
+		penWidth:    diagramPenWidth(determineShapeBorderPenWidth(techAsset, parsedModel)),
+		peripheries: determineShapePeripheries(techAsset),
+	}
+	switch techAsset.Type {
+	case types.Process:
+		node.shape = "ellipse"
+	case types.Datastore:
+		node.shape = "cylinder"
+	}
+	if techAsset.UsedAsClientByHuman {
+		node.shape = "octagon"
+	}
+
+	attackerAttractiveness := "RAA: out of scope"
+	if !techAsset.OutOfScope {
+		attackerAttractiveness = fmt.Sprintf("RAA: %.0f %%", techAsset.RAA)
+	}
+	node.lines = []*diagramText{
+		{text: techAsset.Technologies.String(), size: 15, color: DarkBlue},
+		{text: techAsset.Size.String(), size: 15, color: LightGray},
+		{text: techAsset.Title, size: 20, bold: true, color: determineTechnicalAssetLabelColor(techAsset, parsedModel)},
+		{text: attackerAttractiveness, size: 15, color: "#603112"},
+	}
+
+	node.width, node.height = textBlockSize(node.lines)
+	node.width += 24
+	node.height += 16
+	switch node.shape {
+	case "ellipse":
+		node.width, node.height = node.width*1.3, node.height*1.4
+	case "cylinder":
+		node.height += 24
+	case "octagon":
+		node.width, node.height = node.width+24, node.height+12
+	}
+	if node.peripheries > 1 {
+		node.width, node.height = node.width+8, node.height+8
+	}
+
+	return node
+}
+
+func newFilledDiagramNode(link diagramLink, title string, color string) *diagramNode {
+	node := &diagramNode{
+		link:        link,
+		shape:       "box",
+		lines:       []*diagramText{{text: title, size: 20, bold: true, color: "#FFFFFF"}},
+		fillColor:   color,
+		borderColor: color,
+		borderStyle: "solid",
+		penWidth:    3.0,
+		peripheries: 1,
+	}
+	node.width, node.height = textBlockSize(node.lines)
+	node.width += 24
+	node.height += 16
+
+	return node
+}
+
+func newDiagramHighlight(attackPaths []*model.AttackPath) *diagramHighlight {
+	highlight := &diagramHighlight{
+		technicalAssetIds:    make(map[string]bool),
+		communicationLinkIds: make(map[string]bool),
+	}
+	for _, attackPath := range attackPaths {
+		for _, id := range attackPath.TechnicalAssetIds() {
+			highlight.technicalAssetIds[id] = true
+		}
+		for _, hop := range attackPath.Hops {
+			highlight.communicationLinkIds[hop.CommunicationLinkId] = true
+		}
+	}
+
+	return highlight
+}
+
+func diagramSeverityColor(severity types.RiskSeverity) string {
+	switch severity {
+	case types.CriticalSeverity:
+		return rgbHexColorCriticalRisk()
+	case types.HighSeverity:
+		return rgbHexColorHighRisk()
+	case types.ElevatedSeverity:
+		return rgbHexColorElevatedRisk()
+	case types.MediumSeverity:
+		return rgbHexColorMediumRisk()
+	default:
+		return rgbHexColorLowRisk()
+	}
+}
+
+// diagramPenWidth parses the pen widths formatted for graphviz
+
+func diagramPenWidth(value string) float64 {
+	var width float64
+	_, err := fmt.Sscanf(value, "%f", &width)
+	if err != nil {
+		return 1
+	}
+
+	return math.Max(width, 0.5)
+}
+
+func textBlockSize(lines []*diagramText) (float64, float64) {
+	width, height := 0.0, 0.0
+	for _, line := range lines {
+		width = math.Max(width, textWidth(line))
+		height += textHeight(line)
+	}
+
+	return width, height
+}
@@ -0,0 +1,202 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// drawIOFile and friends are the subset of the draw.io (mxGraph) file format written by WriteDataFlowDiagramDrawIO
+
+type drawIOFile struct {
+	XMLName xml.Name       `xml:"mxfile"`
+	Host    string         `xml:"host,attr"`
+	Diagram *drawIODiagram `xml:"diagram"`
+}
+
+type drawIODiagram struct {
+	Id    string            `xml:"id,attr"`
+	Name  string            `xml:"name,attr"`
+	Model *drawIOGraphModel `xml:"mxGraphModel"`
+}
+
+type drawIOGraphModel struct {
+	Grid       int           `xml:"grid,attr"`
+	PageWidth  int           `xml:"pageWidth,attr"`
+	PageHeight int           `xml:"pageHeight,attr"`
+	Cells      []*drawIOCell `xml:"root>mxCell"`
+}
+
+type drawIOCell struct {
+	Id       string          `xml:"id,attr"`
+	Value    string          `xml:"value,attr,omitempty"`
+	Style    string          `xml:"style,attr,omitempty"`
+	Vertex   string          `xml:"vertex,attr,omitempty"`
+	Edge     string          `xml:"edge,attr,omitempty"`
+	Parent   string          `xml:"parent,attr,omitempty"`
+	Source   string          `xml:"source,attr,omitempty"`
+	Target   string          `xml:"target,attr,omitempty"`
+	Geometry *drawIOGeometry `xml:"mxGeometry,omitempty"`
+}
+
+type drawIOGeometry struct {
+	X        float64 `xml:"x,attr,omitempty"`
+	Y        float64 `xml:"y,attr,omitempty"`
+	Width    float64 `xml:"width,attr,omitempty"`
+	Height   float64 `xml:"height,attr,omitempty"`
+	Relative string  `xml:"relative,attr,omitempty"`
+	As       string  `xml:"as,attr"`
+}
+
+const drawIORootCell = "1"
+
+// WriteDataFlowDiagramDrawIO writes the data flow diagram as draw.io file, laid out by the built-in diagram layout
+// with the trust boundaries as nested containers and the communication links labeled with their protocol
+
+func WriteDataFlowDiagramDrawIO(parsedModel *types.Model, filename string) error {
+	graph, err := newDataFlowDiagram(parsedModel, false, &diagramLinks{}, nil)
+	if err != nil {
+		return err
+	}
+	graph.layout()
+
+	cells := []*drawIOCell{{Id: "0"}, {Id: drawIORootCell, Parent: "0"}}
+	parentOf := func(cluster *diagramCluster) (string, float64, float64) {
+		if cluster == nil || cluster == graph.root {
+			return drawIORootCell, 0, 0
+		}
+		return cluster.link.id, cluster.x, cluster.y
+	}
+
+	var addClusters func(cluster *diagramCluster)
+	addClusters = func(cluster *diagramCluster) {
+		for _, child := range cluster.children {
+			parent, x, y := parentOf(cluster)
+			style := "swimlane;startSize=" + fmt.Sprintf("%.0f", textHeight(child.label)+diagramClusterPadding/2) +
+				";html=1;whiteSpace=wrap;container=1;collapsible=0;rounded=0" +
+				";fillColor=" + child.fillColor + ";swimlaneFillColor=" + child.fillColor +
+				";strokeColor=" + child.borderColor + ";fontColor=" + child.label.color +
+				";strokeWidth=" + fmt.Sprintf("%.1f", child.penWidth)
+			switch child.borderStyle {
+			case "dashed":
+				style += ";dashed=1"
+			case "dotted":
+				style += ";dashed=1;dashPattern=1 4"
+			}
+			cells = append(cells, &drawIOCell{
+				Id:       child.link.id,
+				Value:    html.EscapeString(child.label.text),
+				Style:    style,
+				Vertex:   "1",
+				Parent:   parent,
+				Geometry: &drawIOGeometry{X: child.x - x, Y: child.y - y, Width: child.width, Height: child.height, As: "geometry"},
+			})
+			addClusters(child)
+		}
+	}
+	addClusters(graph.root)
+
+	for _, node := range graph.nodes {
+		parent, x, y := parentOf(node.cluster)
+		shape := "rounded=0"
+		switch node.shape {
+		case "ellipse":
+			shape = "ellipse"
+		case "cylinder":
+			shape = "shape=cylinder3;boundedLbl=1;size=10"
+		case "octagon":
+			shape = "shape=hexagon;perimeter=hexagonPerimeter2;size=0.1"
+		}
+		style := shape + ";whiteSpace=wrap;html=1;fillColor=" + node.fillColor + ";strokeColor=" + node.borderColor +
+			";strokeWidth=" + fmt.Sprintf("%.1f", node.penWidth)
+		if node.borderStyle != "solid" {
+			style += ";dashed=1"
+		}
+		if node.peripheries > 1 {
+			style += ";double=1"
+		}
+
+		value := ""
+		for n, line := range node.lines {
+			if n > 0 {
+				value += "<br>"
+			}
+			text := html.EscapeString(line.text)
+			if line.bold {
+				text = "<b>" + text + "</b>"
+			}
+			value += `<font style="font-size: ` + fmt.Sprintf("%.0f", line.size) + `px" color="` + line.color + `">` + text + "</font>"
+		}
+
+		cells = append(cells, &drawIOCell{
+			Id:       node.link.id,
+			Value:    value,
+			Style:    style,
+			Vertex:   "1",
+			Parent:   parent,
+			Geometry: &drawIOGeometry{X: node.x - node.width/2 - x, Y: node.y - node.height/2 - y, Width: node.width, Height: node.height, As: "geometry"},
+		})
+	}
+
+	for _, edge := range graph.edges {
+		if edge.invisible {
+			continue
+		}
+
+		style := "html=1;rounded=0;strokeColor=" + edge.color + ";strokeWidth=" + fmt.Sprintf("%.1f", edge.penWidth) +
+			";endArrow=block;endFill=1;startArrow=oval;startFill=1"
+		if graph.orthogonal {
+			style = "edgeStyle=orthogonalEdgeStyle;" + style
+		}
+		if edge.arrowHead == "empty" {
+			style += ";endFill=0;startFill=0"
+		}
+		if edge.style != "solid" {
+			style += ";dashed=1"
+		}
+		value := ""
+		if edge.label != nil {
+			value = html.EscapeString(edge.label.text)
+			style += ";fontColor=" + edge.label.color
+		}
+
+		cells = append(cells, &drawIOCell{
+			Id:       edge.link.id,
+			Value:    value,
+			Style:    style,
+			Edge:     "1",
+			Parent:   drawIORootCell,
+			Source:   edge.source.link.id,
+			Target:   edge.target.link.id,
+			Geometry: &drawIOGeometry{Relative: "1", As: "geometry"},
+		})
+	}
+
+	file := &drawIOFile{
+		Host: "threagile",
+		Diagram: &drawIODiagram{
+			Id:   "data-flow-diagram",
+			Name: parsedModel.Title,
+			Model: &drawIOGraphModel{
+				Grid:       1,
+				PageWidth:  int(graph.width),
+				PageHeight: int(graph.height),
+				Cells:      cells,
+			},
+		},
+	}
+
+	content, err := xml.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding %s: %v", filename, err)
+	}
+	err = os.WriteFile(filepath.Clean(filename), append([]byte(xml.Header), content...), 0600)
+	if err != nil {
+		return fmt.Errorf("error writing %s: %v", filename, err)
+	}
+	return nil
+}
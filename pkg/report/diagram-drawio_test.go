@@ -0,0 +1,73 @@
+package report
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestWriteDataFlowDiagramDrawIO(t *testing.T) {
+	browserToWeb := &types.CommunicationLink{Id: "browser>web", SourceId: "browser", TargetId: "web", Protocol: types.HTTPS}
+	webToDb := &types.CommunicationLink{Id: "web>db", SourceId: "web", TargetId: "db", Protocol: types.JdbcEncrypted}
+	cloud := &types.TrustBoundary{Id: "cloud", Title: "Cloud", Type: types.NetworkCloudProvider, TechnicalAssetsInside: []string{"web"}, TrustBoundariesNested: []string{"cluster"}}
+	cluster := &types.TrustBoundary{Id: "cluster", Title: "Cluster", Type: types.ExecutionEnvironment, TechnicalAssetsInside: []string{"db"}}
+	category := &types.RiskCategory{ID: "something-strange", Title: "Something Strange"}
+	risk := &types.Risk{CategoryId: category.ID, Severity: types.HighSeverity, SyntheticId: "something-strange@web", MostRelevantTechnicalAssetId: "web"}
+	parsedModel := &types.Model{
+		Title: `Shop "A" \ B`,
+		TechnicalAssets: map[string]*types.TechnicalAsset{
+			"browser": {Id: "browser", Title: "Browser", Type: types.ExternalEntity, UsedAsClientByHuman: true, CommunicationLinks: []*types.CommunicationLink{browserToWeb}},
+			"web":     {Id: "web", Title: "Web \"Shop\" \\ <b>Q&A</b>\nFrontend", Type: types.Process, CommunicationLinks: []*types.CommunicationLink{webToDb}},
+			"db":      {Id: "db", Title: "Database", Type: types.Datastore},
+		},
+		CommunicationLinks: map[string]*types.CommunicationLink{browserToWeb.Id: browserToWeb, webToDb.Id: webToDb},
+		TrustBoundaries:    map[string]*types.TrustBoundary{cloud.Id: cloud, cluster.Id: cluster},
+		DirectContainingTrustBoundaryMappedByTechnicalAssetId: map[string]*types.TrustBoundary{"web": cloud, "db": cluster},
+		CustomRiskCategories:     types.RiskCategories{category},
+		GeneratedRisksByCategory: map[string][]*types.Risk{category.ID: {risk}},
+	}
+
+	filename := filepath.Join(t.TempDir(), "data-flow-diagram.drawio")
+	require.NoError(t, WriteDataFlowDiagramDrawIO(parsedModel, filename))
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "\nFrontend", "newlines in attributes need to be encoded")
+
+	file := new(drawIOFile)
+	require.NoError(t, xml.Unmarshal(data, file))
+	assert.Equal(t, parsedModel.Title, file.Diagram.Name)
+
+	cells := make(map[string]*drawIOCell)
+	for _, cell := range file.Diagram.Model.Cells {
+		cells[cell.Id] = cell
+	}
+	for id, parent := range map[string]string{
+		"trust-boundary-cloud":    drawIORootCell,
+		"trust-boundary-cluster":  "trust-boundary-cloud",
+		"technical-asset-browser": drawIORootCell,
+		"technical-asset-web":     "trust-boundary-cloud",
+		"technical-asset-db":      "trust-boundary-cluster",
+	} {
+		require.Contains(t, cells, id)
+		assert.Equal(t, "1", cells[id].Vertex, id)
+		assert.Equal(t, parent, cells[id].Parent, id)
+	}
+	assert.Equal(t, "Cloud (network-cloud-provider)", cells["trust-boundary-cloud"].Value)
+	assert.Contains(t, cells["technical-asset-web"].Value, "<b>Web &#34;Shop&#34; \\ &lt;b&gt;Q&amp;A&lt;/b&gt;\nFrontend</b>")
+
+	for id, expected := range map[string]*drawIOCell{
+		"communication-link-web>db":      {Value: "jdbc-encrypted", Source: "technical-asset-web", Target: "technical-asset-db"},
+		"communication-link-browser>web": {Value: "https", Source: "technical-asset-browser", Target: "technical-asset-web"},
+	} {
+		require.Contains(t, cells, id)
+		assert.Equal(t, "1", cells[id].Edge, id)
+		assert.Equal(t, expected.Value, cells[id].Value, id)
+		assert.Equal(t, expected.Source, cells[id].Source, id)
+		assert.Equal(t, expected.Target, cells[id].Target, id)
+	}
+}
@@ -0,0 +1,65 @@
+package report
+
+import (
+	"sort"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// diagramTrustBoundaries returns the trust boundaries directly inside the given one (or the top level ones for an
+// empty id) which the diagram exports draw as groups, being those with technical assets or nested trust boundaries
+
+func diagramTrustBoundaries(parsedModel *types.Model, parentId string) []*types.TrustBoundary {
+	result := make([]*types.TrustBoundary, 0)
+	for _, id := range types.SortedKeysOfTrustBoundaries(parsedModel) {
+		trustBoundary := parsedModel.TrustBoundaries[id]
+		if len(trustBoundary.TechnicalAssetsInside) == 0 && len(trustBoundary.TrustBoundariesNested) == 0 {
+			continue
+		}
+		if trustBoundary.ParentTrustBoundaryID(parsedModel) == parentId {
+			result = append(result, trustBoundary)
+		}
+	}
+
+	return result
+}
+
+// diagramTechnicalAssets returns the technical assets directly inside the given trust boundary (or those outside of
+// all trust boundaries for an empty id) in diagram order
+
+func diagramTechnicalAssets(parsedModel *types.Model, trustBoundaryId string) []*types.TechnicalAsset {
+	result := make([]*types.TechnicalAsset, 0)
+	for _, techAsset := range parsedModel.TechnicalAssets {
+		id := ""
+		if trustBoundary, ok := parsedModel.DirectContainingTrustBoundaryMappedByTechnicalAssetId[techAsset.Id]; ok {
+			id = trustBoundary.Id
+		}
+		if id == trustBoundaryId {
+			result = append(result, techAsset)
+		}
+	}
+	sort.Sort(types.ByOrderAndIdSort(result))
+
+	return result
+}
+
+// diagramCommunicationLinks returns all communication links in diagram order
+
+func diagramCommunicationLinks(parsedModel *types.Model) []*types.CommunicationLink {
+	techAssets := make([]*types.TechnicalAsset, 0)
+	for _, techAsset := range parsedModel.TechnicalAssets {
+		techAssets = append(techAssets, techAsset)
+	}
+	sort.Sort(types.ByOrderAndIdSort(techAssets))
+
+	result := make([]*types.CommunicationLink, 0)
+	for _, techAsset := range techAssets {
+		for _, link := range techAsset.CommunicationLinksSorted() {
+			if _, ok := parsedModel.TechnicalAssets[link.TargetId]; ok {
+				result = append(result, link)
+			}
+		}
+	}
+
+	return result
+}
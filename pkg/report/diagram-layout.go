@@ -0,0 +1,977 @@
+package report
+
+import (
+	"math"
+	"sort"
+)
+
+// diagramGraph is a diagram to lay out and render without Graphviz: the nodes are put on ranks along the edges (top to
+// bottom, or left to right) and each cluster gets a band of its own across all ranks, so that clusters never overlap and
+// nested clusters stay inside their parents
+
+type diagramGraph struct {
+	title       *diagramText
+	nodes       []*diagramNode
+	edges       []*diagramEdge
+	root        *diagramCluster
+	sameRank    [][]*diagramNode
+	leftToRight bool
+	orthogonal  bool
+	nodeSep     float64
+	rankSep     float64
+	width       float64
+	height      float64
+}
+
+type diagramText struct {
+	text  string
+	size  float64
+	bold  bool
+	color string
+}
+
+type diagramNode struct {
+	link        diagramLink
+	shape       string // box, ellipse, cylinder or octagon
+	lines       []*diagramText
+	fillColor   string
+	borderColor string
+	borderStyle string // solid, dashed or dotted
+	penWidth    float64
+	peripheries int
+	width       float64
+	height      float64
+	cluster     *diagramCluster
+	order       int
+	rank        int
+	barycenter  float64
+	x           float64 // of the center
+	y           float64
+}
+
+type diagramCluster struct {
+	link        diagramLink
+	label       *diagramText
+	fillColor   string
+	borderColor string
+	borderStyle string
+	penWidth    float64
+	parent      *diagramCluster
+	children    []*diagramCluster
+	nodes       []*diagramNode
+	barycenter  float64
+	x           float64 // of the top left corner
+	y           float64
+	width       float64
+	height      float64
+}
+
+// contains tells whether the node is inside the cluster or any of its nested clusters
+
+func (what *diagramCluster) contains(node *diagramNode) bool {
+	for cluster := node.cluster; cluster != nil; cluster = cluster.parent {
+		if cluster == what {
+			return true
+		}
+	}
+	return false
+}
+
+type diagramEdge struct {
+	link       diagramLink
+	source     *diagramNode
+	target     *diagramNode
+	color      string
+	style      string // solid, dashed or dotted
+	penWidth   float64
+	arrowHead  string // normal, empty or none
+	arrowTail  string // dot, odot or none
+	arrowSize  float64
+	label      *diagramText
+	constraint bool
+	invisible  bool
+	weight     float64
+	points     []diagramPoint
+	labelAt    diagramPoint
+}
+
+type diagramPoint struct {
+	x float64
+	y float64
+}
+
+func (what diagramPoint) distance(other diagramPoint) float64 {
+	return math.Hypot(other.x-what.x, other.y-what.y)
+}
+
+// diagramBox is a rectangle by its top left and bottom right corners
+
+type diagramBox struct {
+	x0 float64
+	y0 float64
+	x1 float64
+	y1 float64
+}
+
+// overlap returns the area both boxes cover
+
+func (what diagramBox) overlap(other diagramBox) float64 {
+	width := math.Min(what.x1, other.x1) - math.Max(what.x0, other.x0)
+	height := math.Min(what.y1, other.y1) - math.Max(what.y0, other.y0)
+	if width <= 0 || height <= 0 {
+		return 0
+	}
+	return width * height
+}
+
+// intersects tells whether the horizontal or vertical line between the points runs through the box
+
+func (what diagramBox) intersects(from diagramPoint, to diagramPoint) bool {
+	return math.Max(from.x, to.x) >= what.x0 && math.Min(from.x, to.x) <= what.x1 &&
+		math.Max(from.y, to.y) >= what.y0 && math.Min(from.y, to.y) <= what.y1
+}
+
+const (
+	diagramClusterPadding = 24.0
+	diagramMargin         = 24.0
+	diagramOrderSweeps    = 12
+)
+
+func newDiagramGraph() *diagramGraph {
+	return &diagramGraph{
+		nodes:    make([]*diagramNode, 0),
+		edges:    make([]*diagramEdge, 0),
+		root:     &diagramCluster{children: make([]*diagramCluster, 0), nodes: make([]*diagramNode, 0)},
+		sameRank: make([][]*diagramNode, 0),
+		nodeSep:  36,
+		rankSep:  72,
+	}
+}
+
+func (what *diagramGraph) addCluster(parent *diagramCluster, cluster *diagramCluster) *diagramCluster {
+	if parent == nil {
+		parent = what.root
+	}
+
+	cluster.parent = parent
+	cluster.children = make([]*diagramCluster, 0)
+	cluster.nodes = make([]*diagramNode, 0)
+	parent.children = append(parent.children, cluster)
+	return cluster
+}
+
+func (what *diagramGraph) addNode(cluster *diagramCluster, node *diagramNode) *diagramNode {
+	if cluster == nil {
+		cluster = what.root
+	}
+
+	node.cluster = cluster
+	node.order = len(what.nodes)
+	cluster.nodes = append(cluster.nodes, node)
+	what.nodes = append(what.nodes, node)
+	return node
+}
+
+func (what *diagramGraph) addEdge(edge *diagramEdge) *diagramEdge {
+	what.edges = append(what.edges, edge)
+	return edge
+}
+
+// layout places nodes, clusters and edges; the sizes of the nodes and the labels must be known by now
+
+func (what *diagramGraph) layout() {
+	what.assignRanks()
+
+	for sweep := 0; sweep < diagramOrderSweeps; sweep++ {
+		what.place()
+		what.updateBarycenters()
+	}
+	what.place()
+
+	what.routeEdges()
+	what.placeLabels()
+	what.normalize()
+}
+
+// assignRanks puts each node one rank behind its predecessors, after reversing the edges closing cycles
+
+func (what *diagramGraph) assignRanks() {
+	successors := make(map[*diagramNode][]*diagramNode)
+	for _, edge := range what.edges {
+		if edge.constraint && edge.source != edge.target {
+			successors[edge.source] = append(successors[edge.source], edge.target)
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[*diagramNode]int)
+	acyclic := make(map[*diagramNode][]*diagramNode)
+	var visit func(node *diagramNode)
+	visit = func(node *diagramNode) {
+		state[node] = visiting
+		for _, successor := range successors[node] {
+			switch state[successor] {
+			case visiting:
+				acyclic[successor] = append(acyclic[successor], node)
+			case unvisited:
+				acyclic[node] = append(acyclic[node], successor)
+				visit(successor)
+			default:
+				acyclic[node] = append(acyclic[node], successor)
+			}
+		}
+		state[node] = visited
+	}
+	for _, node := range what.nodes {
+		if state[node] == unvisited {
+			visit(node)
+		}
+	}
+
+	for _, node := range what.nodes {
+		node.rank = 0
+	}
+
+	sameRankGroup := make(map[*diagramNode]int)
+	for n, group := range what.sameRank {
+		for _, node := range group {
+			sameRankGroup[node] = n + 1
+		}
+	}
+
+	// longest path ranking, iterated along with the same-rank groups until stable; the iterations are bounded as
+	// same-rank groups may contradict the edges
+	for iteration := 0; iteration <= len(what.nodes); iteration++ {
+		changed := false
+		for _, node := range what.nodes {
+			for _, successor := range acyclic[node] {
+				if successor.rank < node.rank+1 && (sameRankGroup[node] == 0 || sameRankGroup[node] != sameRankGroup[successor]) {
+					successor.rank = node.rank + 1
+					changed = true
+				}
+			}
+		}
+
+		for _, group := range what.sameRank {
+			rank := 0
+			for _, node := range group {
+				rank = int(math.Max(float64(rank), float64(node.rank)))
+			}
+			for _, node := range group {
+				if node.rank != rank {
+					node.rank = rank
+					changed = true
+				}
+			}
+		}
+
+		if !changed {
+			break
+		}
+	}
+}
+
+// place computes the coordinates of nodes and clusters for the current order; coordinates are computed as breadth
+// (along a rank) and depth (across the ranks) and only turned into x and y at the end
+
+func (what *diagramGraph) place() {
+	rankCount := 0
+	for _, node := range what.nodes {
+		rankCount = int(math.Max(float64(rankCount), float64(node.rank+1)))
+	}
+
+	rankDepth := make([]float64, rankCount)
+	for _, node := range what.nodes {
+		rankDepth[node.rank] = math.Max(rankDepth[node.rank], what.depthOf(node))
+	}
+
+	rankCenter := make([]float64, rankCount)
+	position := 0.0
+	for rank := 0; rank < rankCount; rank++ {
+		rankCenter[rank] = position + rankDepth[rank]/2
+		position += rankDepth[rank] + what.rankSep
+	}
+
+	for _, node := range what.nodes {
+		what.setPosition(node, what.breadthPositionOf(node), rankCenter[node.rank])
+	}
+
+	what.placeCluster(what.root, 0, rankCount)
+}
+
+// placeCluster places the nodes directly inside the cluster as one column and each nested cluster as a band of its
+// own, ordered by their barycenters, and returns the breadth of the cluster
+
+func (what *diagramGraph) placeCluster(cluster *diagramCluster, start float64, rankCount int) float64 {
+	padding, labelBreadth, labelDepth := 0.0, 0.0, 0.0
+	if cluster != what.root {
+		padding = diagramClusterPadding
+		if what.leftToRight {
+			labelBreadth = textHeight(cluster.label) + padding/2
+		} else {
+			labelDepth = textHeight(cluster.label) + padding/2
+		}
+	}
+
+	type item struct {
+		barycenter float64
+		nodes      []*diagramNode
+		cluster    *diagramCluster
+	}
+	items := make([]*item, 0)
+	if len(cluster.nodes) > 0 {
+		barycenter := 0.0
+		for _, node := range cluster.nodes {
+			barycenter += node.barycenter
+		}
+		items = append(items, &item{barycenter: barycenter / float64(len(cluster.nodes)), nodes: cluster.nodes})
+	}
+	for _, child := range cluster.children {
+		items = append(items, &item{barycenter: child.barycenter, cluster: child})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].barycenter < items[j].barycenter
+	})
+
+	position := start + padding + labelBreadth
+	for n, item := range items {
+		if n > 0 {
+			position += what.nodeSep
+		}
+
+		if item.cluster != nil {
+			position += what.placeCluster(item.cluster, position, rankCount)
+			continue
+		}
+
+		ranks := make([][]*diagramNode, rankCount)
+		for _, node := range item.nodes {
+			ranks[node.rank] = append(ranks[node.rank], node)
+		}
+
+		columnBreadth := 0.0
+		for _, nodes := range ranks {
+			columnBreadth = math.Max(columnBreadth, what.rankBreadth(nodes))
+		}
+
+		for _, nodes := range ranks {
+			sort.SliceStable(nodes, func(i, j int) bool {
+				return nodes[i].barycenter < nodes[j].barycenter
+			})
+
+			nodePosition := position + (columnBreadth-what.rankBreadth(nodes))/2
+			for _, node := range nodes {
+				what.setPosition(node, nodePosition+what.breadthOf(node)/2, what.depthPositionOf(node))
+				nodePosition += what.breadthOf(node) + what.nodeSep
+			}
+		}
+
+		position += columnBreadth
+	}
+
+	breadth := position + padding - start
+	if cluster == what.root {
+		return breadth
+	}
+
+	depthStart, depthEnd := math.Inf(1), math.Inf(-1)
+	for _, node := range cluster.nodes {
+		depthStart = math.Min(depthStart, what.depthPositionOf(node)-what.depthOf(node)/2)
+		depthEnd = math.Max(depthEnd, what.depthPositionOf(node)+what.depthOf(node)/2)
+	}
+	for _, child := range cluster.children {
+		childStart, childEnd := what.clusterDepthRange(child)
+		depthStart = math.Min(depthStart, childStart)
+		depthEnd = math.Max(depthEnd, childEnd)
+	}
+	if math.IsInf(depthStart, 1) { // empty cluster
+		depthStart, depthEnd = 0, 0
+	}
+
+	depthStart -= padding + labelDepth
+	depthEnd += padding
+	if what.leftToRight {
+		depthEnd = math.Max(depthEnd, depthStart+textWidth(cluster.label)+2*padding)
+	} else {
+		breadth = math.Max(breadth, textWidth(cluster.label)+2*padding)
+	}
+
+	what.setClusterBounds(cluster, start, breadth, depthStart, depthEnd-depthStart)
+	return breadth
+}
+
+func (what *diagramGraph) rankBreadth(nodes []*diagramNode) float64 {
+	breadth := 0.0
+	for n, node := range nodes {
+		if n > 0 {
+			breadth += what.nodeSep
+		}
+		breadth += what.breadthOf(node)
+	}
+
+	return breadth
+}
+
+// updateBarycenters moves each node towards the mean position of its neighbours weighted by the edges, and each
+// cluster towards the mean of its nodes
+
+func (what *diagramGraph) updateBarycenters() {
+	type neighbour struct {
+		node   *diagramNode
+		weight float64
+	}
+	neighbours := make(map[*diagramNode][]neighbour)
+	for _, edge := range what.edges {
+		if edge.source != edge.target {
+			weight := math.Max(edge.weight, 1)
+			neighbours[edge.source] = append(neighbours[edge.source], neighbour{node: edge.target, weight: weight})
+			neighbours[edge.target] = append(neighbours[edge.target], neighbour{node: edge.source, weight: weight})
+		}
+	}
+
+	for _, node := range what.nodes {
+		if len(neighbours[node]) == 0 {
+			node.barycenter = what.breadthPositionOf(node)
+			continue
+		}
+
+		sum, weights := 0.0, 0.0
+		for _, neighbour := range neighbours[node] {
+			sum += neighbour.weight * what.breadthPositionOf(neighbour.node)
+			weights += neighbour.weight
+		}
+		node.barycenter = sum / weights
+	}
+
+	var update func(cluster *diagramCluster) (float64, int)
+	update = func(cluster *diagramCluster) (float64, int) {
+		sum, count := 0.0, 0
+		for _, node := range cluster.nodes {
+			sum += node.barycenter
+			count++
+		}
+		for _, child := range cluster.children {
+			childSum, childCount := update(child)
+			sum += childSum
+			count += childCount
+		}
+		if count > 0 {
+			cluster.barycenter = sum / float64(count)
+		}
+		return sum, count
+	}
+	update(what.root)
+}
+
+// routeEdges connects the nodes either straight or orthogonally; orthogonal edges leave and enter the nodes at ports
+// spread over their sides and cross between the ranks in channels of their own, edges spanning several ranks running
+// along lanes clear of the nodes and clusters they don't belong to
+
+func (what *diagramGraph) routeEdges() {
+	if !what.orthogonal {
+		for _, edge := range what.visibleEdges() {
+			source, target := edge.source, edge.target
+			from := what.clip(source, diagramPoint{x: target.x, y: target.y})
+			to := what.clip(target, diagramPoint{x: source.x, y: source.y})
+			edge.points = []diagramPoint{from, to}
+			edge.labelAt = diagramPoint{x: (from.x + to.x) / 2, y: (from.y + to.y) / 2}
+		}
+		return
+	}
+
+	type port struct {
+		node *diagramNode
+		side float64
+	}
+	type attachment struct {
+		edge   *diagramEdge
+		other  *diagramNode
+		source bool
+	}
+	ports := make(map[port][]*attachment)
+	sourceChannels := make(map[float64][]*diagramEdge)
+	targetChannels := make(map[float64][]*diagramEdge)
+	sideOf := func(from *diagramNode, to *diagramNode) float64 {
+		if to.rank > from.rank {
+			return 1
+		}
+		return -1
+	}
+	spansRanks := func(edge *diagramEdge) bool {
+		return math.Abs(float64(edge.target.rank-edge.source.rank)) > 1
+	}
+
+	rankDepth := make(map[int]float64)
+	for _, node := range what.nodes {
+		rankDepth[node.rank] = math.Max(rankDepth[node.rank], what.depthOf(node))
+	}
+	channelOf := func(node *diagramNode, side float64) float64 {
+		return what.depthPositionOf(node) + side*rankDepth[node.rank]/2
+	}
+
+	edges := what.visibleEdges()
+	for _, edge := range edges {
+		sourceSide, targetSide := sideOf(edge.source, edge.target), sideOf(edge.target, edge.source)
+		if edge.source.rank == edge.target.rank {
+			sourceSide, targetSide = -1, -1
+		}
+		ports[port{edge.source, sourceSide}] = append(ports[port{edge.source, sourceSide}], &attachment{edge: edge, other: edge.target, source: true})
+		ports[port{edge.target, targetSide}] = append(ports[port{edge.target, targetSide}], &attachment{edge: edge, other: edge.source})
+
+		channel := channelOf(edge.source, sourceSide)
+		sourceChannels[channel] = append(sourceChannels[channel], edge)
+		if spansRanks(edge) {
+			channel = channelOf(edge.target, targetSide)
+			targetChannels[channel] = append(targetChannels[channel], edge)
+		}
+	}
+
+	type ends struct {
+		from           diagramPoint // in breadth and depth
+		to             diagramPoint
+		crossing       float64 // next to the source
+		targetCrossing float64 // next to the target, for edges spanning several ranks
+	}
+	routes := make(map[*diagramEdge]*ends)
+	for _, edge := range edges {
+		routes[edge] = &ends{}
+	}
+
+	for key, attachments := range ports {
+		sort.SliceStable(attachments, func(i, j int) bool {
+			return what.breadthPositionOf(attachments[i].other) < what.breadthPositionOf(attachments[j].other)
+		})
+
+		usable := what.breadthOf(key.node) * 0.6
+		if key.node.shape == "ellipse" {
+			usable = what.breadthOf(key.node) * 0.4
+		}
+		for n, attachment := range attachments {
+			offset := usable * (float64(n+1)/float64(len(attachments)+1) - 0.5)
+			point := diagramPoint{
+				x: what.breadthPositionOf(key.node) + offset,
+				y: what.depthPositionOf(key.node) + key.side*what.depthOf(key.node)/2,
+			}
+			if attachment.source {
+				routes[attachment.edge].from = point
+			} else {
+				routes[attachment.edge].to = point
+			}
+		}
+	}
+
+	// the half of a channel next to a node is used by the edges leaving it, the other half by those entering the nodes
+	// across it from further away
+	for channel, channelEdges := range sourceChannels {
+		sort.SliceStable(channelEdges, func(i, j int) bool {
+			return what.breadthPositionOf(channelEdges[i].target) < what.breadthPositionOf(channelEdges[j].target)
+		})
+
+		for n, edge := range channelEdges {
+			side := sideOf(edge.source, edge.target)
+			if edge.source.rank == edge.target.rank {
+				side = -1
+			}
+			routes[edge].crossing = channel + side*what.rankSep/2*float64(n+1)/float64(len(channelEdges)+1)
+		}
+	}
+
+	for channel, channelEdges := range targetChannels {
+		sort.SliceStable(channelEdges, func(i, j int) bool {
+			return what.breadthPositionOf(channelEdges[i].source) < what.breadthPositionOf(channelEdges[j].source)
+		})
+
+		for n, edge := range channelEdges {
+			routes[edge].targetCrossing = channel + sideOf(edge.target, edge.source)*what.rankSep/2*float64(n+1)/float64(len(channelEdges)+1)
+		}
+	}
+
+	lanes := make([]diagramLane, 0)
+	for _, edge := range edges {
+		route := routes[edge]
+		points := []diagramPoint{
+			route.from,
+			{x: route.from.x, y: route.crossing},
+			{x: route.to.x, y: route.crossing},
+			route.to,
+		}
+		if spansRanks(edge) {
+			lane := what.findLane(edge, route.from.x, route.to.x, route.crossing, route.targetCrossing, lanes)
+			lanes = append(lanes, lane)
+			points = []diagramPoint{
+				route.from,
+				{x: route.from.x, y: route.crossing},
+				{x: lane.breadth, y: route.crossing},
+				{x: lane.breadth, y: route.targetCrossing},
+				{x: route.to.x, y: route.targetCrossing},
+				route.to,
+			}
+		}
+
+		edge.points = make([]diagramPoint, 0, len(points))
+		for _, point := range points {
+			xy := what.toXY(point.x, point.y)
+			if count := len(edge.points); count > 0 && edge.points[count-1] == xy {
+				continue
+			}
+			edge.points = append(edge.points, xy)
+		}
+		last := points[len(points)-2]
+		edge.labelAt = what.toXY(route.to.x, (last.y+route.to.y)/2)
+	}
+}
+
+// diagramLane is where an edge spanning several ranks runs across them, as breadth along a range of depth
+
+type diagramLane struct {
+	breadth    float64
+	depthStart float64
+	depthEnd   float64
+}
+
+// findLane returns the lane closest to the way from one breadth to the other across the given depths that keeps clear
+// of other nodes, of clusters containing neither end of the edge, and of the lanes found before
+
+func (what *diagramGraph) findLane(edge *diagramEdge, fromBreadth float64, toBreadth float64, fromDepth float64, toDepth float64, lanes []diagramLane) diagramLane {
+	depthStart, depthEnd := math.Min(fromDepth, toDepth), math.Max(fromDepth, toDepth)
+	margin, laneSep := what.nodeSep/4, what.nodeSep/6
+
+	type interval struct {
+		start float64
+		end   float64
+	}
+	blocked := make([]interval, 0)
+	for _, node := range what.nodes {
+		nodeDepth := what.depthPositionOf(node)
+		if node == edge.source || node == edge.target || nodeDepth+what.depthOf(node)/2 < depthStart || nodeDepth-what.depthOf(node)/2 > depthEnd {
+			continue
+		}
+
+		breadth := what.breadthPositionOf(node)
+		blocked = append(blocked, interval{start: breadth - what.breadthOf(node)/2 - margin, end: breadth + what.breadthOf(node)/2 + margin})
+	}
+
+	var blockClusters func(cluster *diagramCluster)
+	blockClusters = func(cluster *diagramCluster) {
+		for _, child := range cluster.children {
+			if child.contains(edge.source) || child.contains(edge.target) {
+				label := what.clusterLabelBox(child)
+				labelBreadthStart, labelBreadthEnd, labelDepthStart, labelDepthEnd := label.x0, label.x1, label.y0, label.y1
+				if what.leftToRight {
+					labelBreadthStart, labelBreadthEnd, labelDepthStart, labelDepthEnd = label.y0, label.y1, label.x0, label.x1
+				}
+				if labelDepthEnd >= depthStart && labelDepthStart <= depthEnd {
+					blocked = append(blocked, interval{start: labelBreadthStart - margin, end: labelBreadthEnd + margin})
+				}
+
+				blockClusters(child)
+				continue
+			}
+
+			clusterDepthStart, clusterDepthEnd := what.clusterDepthRange(child)
+			if clusterDepthEnd < depthStart || clusterDepthStart > depthEnd {
+				continue
+			}
+
+			clusterBreadthStart, clusterBreadthEnd := what.clusterBreadthRange(child)
+			blocked = append(blocked, interval{start: clusterBreadthStart - margin, end: clusterBreadthEnd + margin})
+		}
+	}
+	blockClusters(what.root)
+
+	for _, lane := range lanes {
+		if lane.depthEnd >= depthStart && lane.depthStart <= depthEnd {
+			blocked = append(blocked, interval{start: lane.breadth - laneSep, end: lane.breadth + laneSep})
+		}
+	}
+
+	sort.Slice(blocked, func(i, j int) bool { return blocked[i].start < blocked[j].start })
+
+	best, bestCost := toBreadth, math.Inf(1)
+	try := func(start float64, end float64) {
+		if start > end {
+			return
+		}
+
+		breadth := math.Max(start, math.Min(end, toBreadth))
+		cost := math.Abs(breadth-fromBreadth) + math.Abs(breadth-toBreadth)
+		if cost < bestCost || (cost == bestCost && math.Abs(breadth-toBreadth) < math.Abs(best-toBreadth)) {
+			best, bestCost = breadth, cost
+		}
+	}
+
+	free := math.Inf(-1)
+	for _, block := range blocked {
+		try(free, block.start)
+		free = math.Max(free, block.end)
+	}
+	try(free, math.Inf(1))
+
+	return diagramLane{breadth: best, depthStart: depthStart, depthEnd: depthEnd}
+}
+
+// placeLabels moves the label of each edge to the position along its route overlapping the fewest labels placed
+// before, nodes and cluster labels, and crossing the fewest other edges; positions on longer segments come first
+
+func (what *diagramGraph) placeLabels() {
+	type segment struct {
+		edge *diagramEdge
+		from diagramPoint
+		to   diagramPoint
+	}
+	segments := make([]segment, 0)
+	edges := what.visibleEdges()
+	for _, edge := range edges {
+		for n := 1; n < len(edge.points); n++ {
+			segments = append(segments, segment{edge: edge, from: edge.points[n-1], to: edge.points[n]})
+		}
+	}
+
+	obstacles := make([]diagramBox, 0)
+	for _, node := range what.nodes {
+		obstacles = append(obstacles, diagramBox{x0: node.x - node.width/2, y0: node.y - node.height/2, x1: node.x + node.width/2, y1: node.y + node.height/2})
+	}
+	var addClusterLabels func(cluster *diagramCluster)
+	addClusterLabels = func(cluster *diagramCluster) {
+		for _, child := range cluster.children {
+			obstacles = append(obstacles, what.clusterLabelBox(child))
+			addClusterLabels(child)
+		}
+	}
+	addClusterLabels(what.root)
+
+	placed := make([]diagramBox, 0)
+	for _, edge := range edges {
+		if edge.label == nil || len(edge.points) < 2 {
+			continue
+		}
+
+		width, height := textWidth(edge.label)+4, textHeight(edge.label)
+		own := make([]segment, 0)
+		for _, candidate := range segments {
+			if candidate.edge == edge {
+				own = append(own, candidate)
+			}
+		}
+		sort.SliceStable(own, func(i, j int) bool {
+			return own[i].from.distance(own[i].to) > own[j].from.distance(own[j].to)
+		})
+
+		best, bestCost := edge.labelAt, math.Inf(1)
+		index := 0
+		for _, ownSegment := range own {
+			for _, fraction := range []float64{0.5, 0.35, 0.65, 0.2, 0.8} {
+				at := diagramPoint{
+					x: ownSegment.from.x + (ownSegment.to.x-ownSegment.from.x)*fraction,
+					y: ownSegment.from.y + (ownSegment.to.y-ownSegment.from.y)*fraction,
+				}
+
+				var sides []diagramPoint
+				if math.Abs(ownSegment.to.x-ownSegment.from.x) < math.Abs(ownSegment.to.y-ownSegment.from.y) {
+					sides = []diagramPoint{{x: at.x, y: at.y}, {x: at.x - width - 2, y: at.y}} // right or left
+				} else {
+					sides = []diagramPoint{{x: at.x - width/2, y: at.y - height/2 - 1}, {x: at.x - width/2, y: at.y + height/2 + 1}} // above or below
+				}
+
+				for _, labelAt := range sides {
+					box := diagramBox{x0: labelAt.x, y0: labelAt.y - height/2, x1: labelAt.x + width, y1: labelAt.y + height/2}
+					cost := float64(index)
+					for _, other := range placed {
+						cost += 10 * box.overlap(other)
+					}
+					for _, obstacle := range obstacles {
+						cost += 5 * box.overlap(obstacle)
+					}
+					for _, other := range segments {
+						if other.edge != edge && box.intersects(other.from, other.to) {
+							cost += 4 * height
+						}
+					}
+
+					if cost < bestCost {
+						best, bestCost = labelAt, cost
+					}
+					index++
+				}
+			}
+		}
+
+		edge.labelAt = best
+		placed = append(placed, diagramBox{x0: best.x, y0: best.y - height/2, x1: best.x + width, y1: best.y + height/2})
+	}
+}
+
+func (what *diagramGraph) visibleEdges() []*diagramEdge {
+	edges := make([]*diagramEdge, 0)
+	for _, edge := range what.edges {
+		if !edge.invisible && edge.source != edge.target {
+			edges = append(edges, edge)
+		}
+	}
+
+	return edges
+}
+
+// clip returns where the line from the center of the node towards the point leaves the node
+
+func (what *diagramGraph) clip(node *diagramNode, towards diagramPoint) diagramPoint {
+	dx, dy := towards.x-node.x, towards.y-node.y
+	if dx == 0 && dy == 0 {
+		return diagramPoint{x: node.x, y: node.y}
+	}
+
+	halfWidth, halfHeight := node.width/2, node.height/2
+	var scale float64
+	if node.shape == "ellipse" {
+		scale = 1 / math.Sqrt((dx*dx)/(halfWidth*halfWidth)+(dy*dy)/(halfHeight*halfHeight))
+	} else {
+		scale = math.Min(math.Abs(halfWidth/dx), math.Abs(halfHeight/dy))
+	}
+
+	return diagramPoint{x: node.x + dx*scale, y: node.y + dy*scale}
+}
+
+// normalize moves everything into view, below the title if any, and sets the size of the diagram
+
+func (what *diagramGraph) normalize() {
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	extend := func(x0, y0, x1, y1 float64) {
+		minX, minY = math.Min(minX, x0), math.Min(minY, y0)
+		maxX, maxY = math.Max(maxX, x1), math.Max(maxY, y1)
+	}
+
+	for _, node := range what.nodes {
+		extend(node.x-node.width/2, node.y-node.height/2, node.x+node.width/2, node.y+node.height/2)
+	}
+	var extendClusters func(cluster *diagramCluster)
+	extendClusters = func(cluster *diagramCluster) {
+		for _, child := range cluster.children {
+			extend(child.x, child.y, child.x+child.width, child.y+child.height)
+			extendClusters(child)
+		}
+	}
+	extendClusters(what.root)
+	for _, edge := range what.edges {
+		for _, point := range edge.points {
+			extend(point.x, point.y, point.x, point.y)
+		}
+		if edge.label != nil && len(edge.points) > 0 {
+			extend(edge.labelAt.x, edge.labelAt.y-textHeight(edge.label), edge.labelAt.x+textWidth(edge.label)+4, edge.labelAt.y+textHeight(edge.label))
+		}
+	}
+	if math.IsInf(minX, 1) {
+		minX, minY, maxX, maxY = 0, 0, 0, 0
+	}
+
+	titleHeight := 0.0
+	if what.title != nil {
+		titleHeight = textHeight(what.title) + diagramMargin
+		maxX = math.Max(maxX, minX+textWidth(what.title))
+	}
+
+	dx, dy := diagramMargin-minX, diagramMargin+titleHeight-minY
+	for _, node := range what.nodes {
+		node.x += dx
+		node.y += dy
+	}
+	var moveClusters func(cluster *diagramCluster)
+	moveClusters = func(cluster *diagramCluster) {
+		for _, child := range cluster.children {
+			child.x += dx
+			child.y += dy
+			moveClusters(child)
+		}
+	}
+	moveClusters(what.root)
+	for _, edge := range what.edges {
+		for n := range edge.points {
+			edge.points[n].x += dx
+			edge.points[n].y += dy
+		}
+		edge.labelAt.x += dx
+		edge.labelAt.y += dy
+	}
+
+	what.width = maxX - minX + 2*diagramMargin
+	what.height = maxY - minY + 2*diagramMargin + titleHeight
+}
+
+// breadthOf, depthOf and friends map between breadth and depth of the layout and x and y of the diagram
+
+func (what *diagramGraph) breadthOf(node *diagramNode) float64 {
+	if what.leftToRight {
+		return node.height
+	}
+	return node.width
+}
+
+func (what *diagramGraph) depthOf(node *diagramNode) float64 {
+	if what.leftToRight {
+		return node.width
+	}
+	return node.height
+}
+
+func (what *diagramGraph) breadthPositionOf(node *diagramNode) float64 {
+	if what.leftToRight {
+		return node.y
+	}
+	return node.x
+}
+
+func (what *diagramGraph) depthPositionOf(node *diagramNode) float64 {
+	if what.leftToRight {
+		return node.x
+	}
+	return node.y
+}
+
+func (what *diagramGraph) setPosition(node *diagramNode, breadth float64, depth float64) {
+	point := what.toXY(breadth, depth)
+	node.x, node.y = point.x, point.y
+}
+
+func (what *diagramGraph) toXY(breadth float64, depth float64) diagramPoint {
+	if what.leftToRight {
+		return diagramPoint{x: depth, y: breadth}
+	}
+	return diagramPoint{x: breadth, y: depth}
+}
+
+func (what *diagramGraph) setClusterBounds(cluster *diagramCluster, breadthStart float64, breadth float64, depthStart float64, depth float64) {
+	if what.leftToRight {
+		cluster.x, cluster.y, cluster.width, cluster.height = depthStart, breadthStart, depth, breadth
+		return
+	}
+	cluster.x, cluster.y, cluster.width, cluster.height = breadthStart, depthStart, breadth, depth
+}
+
+// clusterLabelBox returns where the label of the cluster is drawn
+
+func (what *diagramGraph) clusterLabelBox(cluster *diagramCluster) diagramBox {
+	top := cluster.y + diagramClusterPadding
+	if what.leftToRight {
+		top = cluster.y + diagramClusterPadding/2
+	}
+
+	width := textWidth(cluster.label)
+	return diagramBox{x0: cluster.x + (cluster.width-width)/2, y0: top, x1: cluster.x + (cluster.width+width)/2, y1: top + textHeight(cluster.label)}
+}
+
+func (what *diagramGraph) clusterBreadthRange(cluster *diagramCluster) (float64, float64) {
+	if what.leftToRight {
+		return cluster.y, cluster.y + cluster.height
+	}
+	return cluster.x, cluster.x + cluster.width
+}
+
+func (what *diagramGraph) clusterDepthRange(cluster *diagramCluster) (float64, float64) {
+	if what.leftToRight {
+		return cluster.x, cluster.x + cluster.width
+	}
+	return cluster.y, cluster.y + cluster.height
+}
@@ -0,0 +1,68 @@
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestDiagramLayoutKeepsLabelsAndEdgesClear(t *testing.T) {
+	data, readError := os.ReadFile("../../test/parsed-model.json")
+	require.NoError(t, readError)
+
+	parsedModel := new(types.Model)
+	require.NoError(t, json.Unmarshal(data, parsedModel))
+
+	for _, leftToRight := range []bool{false, true} {
+		parsedModel.DiagramTweakLayoutLeftToRight = leftToRight
+		graph, graphError := newDataFlowDiagram(parsedModel, true, &diagramLinks{}, nil)
+		require.NoError(t, graphError)
+		graph.layout()
+
+		nodeBox := func(node *diagramNode) diagramBox {
+			return diagramBox{x0: node.x - node.width/2, y0: node.y - node.height/2, x1: node.x + node.width/2, y1: node.y + node.height/2}
+		}
+
+		labels := make([]diagramBox, 0)
+		for _, edge := range graph.visibleEdges() {
+			width, height := textWidth(edge.label)+4, textHeight(edge.label)
+			label := diagramBox{x0: edge.labelAt.x, y0: edge.labelAt.y - height/2, x1: edge.labelAt.x + width, y1: edge.labelAt.y + height/2}
+			for _, other := range labels {
+				assert.Zero(t, label.overlap(other), "label %q overlaps another one (left to right: %v)", edge.label.text, leftToRight)
+			}
+			for _, node := range graph.nodes {
+				assert.Zero(t, label.overlap(nodeBox(node)), "label %q overlaps a node (left to right: %v)", edge.label.text, leftToRight)
+			}
+			labels = append(labels, label)
+
+			for n := 1; n < len(edge.points); n++ {
+				for _, node := range graph.nodes {
+					if node != edge.source && node != edge.target {
+						assert.False(t, nodeBox(node).intersects(edge.points[n-1], edge.points[n]), "edge %q runs through a node (left to right: %v)", edge.label.text, leftToRight)
+					}
+				}
+			}
+		}
+
+		var checkClusters func(cluster *diagramCluster)
+		checkClusters = func(cluster *diagramCluster) {
+			for _, child := range cluster.children {
+				checkClusters(child)
+				box := diagramBox{x0: child.x, y0: child.y, x1: child.x + child.width, y1: child.y + child.height}
+				for _, edge := range graph.visibleEdges() {
+					if child.contains(edge.source) || child.contains(edge.target) {
+						continue
+					}
+					for n := 1; n < len(edge.points); n++ {
+						assert.False(t, box.intersects(edge.points[n-1], edge.points[n]), "edge %q crosses cluster %q (left to right: %v)", edge.label.text, child.label.text, leftToRight)
+					}
+				}
+			}
+		}
+		checkClusters(graph.root)
+	}
+}
@@ -0,0 +1,160 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// diagramLinks adds ids, tooltips and links to the nodes and edges of a diagram; they only show when the diagram is
+// rendered as SVG, where the ids let the interactive viewer find the elements and the links lead to the HTML report
+
+type diagramLinks struct {
+	reportFilename string
+	idPrefix       string
+}
+
+// diagramLink is the id, tooltip and link target of a diagram element
+
+type diagramLink struct {
+	id      string
+	tooltip string
+	url     string
+}
+
+func (what diagramLink) dotAttributes() string {
+	attributes := `id="` + dotEscape(what.id) + `"`
+	if len(what.tooltip) > 0 {
+		attributes += ` tooltip="` + dotEscape(what.tooltip) + `"`
+	}
+	if len(what.url) > 0 {
+		attributes += ` URL="` + dotEscape(what.url) + `" target="_top"`
+	}
+
+	return attributes
+}
+
+func (what *diagramLinks) technicalAsset(parsedModel *types.Model, techAsset *types.TechnicalAsset) diagramLink {
+	tooltip := []string{techAsset.Title}
+	if techAsset.OutOfScope {
+		tooltip = append(tooltip, "out of scope")
+	} else {
+		tooltip = append(tooltip, openRisksText(parsedModel, techAsset.GeneratedRisks(parsedModel)), fmt.Sprintf("RAA: %.0f %%", techAsset.RAA))
+	}
+
+	return what.link(technicalAssetAnchor(techAsset.Id), strings.Join(tooltip, "\n"))
+}
+
+func (what *diagramLinks) dataAsset(parsedModel *types.Model, dataAsset *types.DataAsset) diagramLink {
+	tooltip := []string{dataAsset.Title, dataAsset.Confidentiality.String() + " / " + dataAsset.Integrity.String() + " / " + dataAsset.Availability.String()}
+	openRisks := openRisks(parsedModel, dataAsset.IdentifiedDataBreachProbabilityRisks(parsedModel))
+	if len(openRisks) > 0 {
+		probability := types.Improbable
+		for _, risk := range openRisks {
+			if risk.DataBreachProbability > probability {
+				probability = risk.DataBreachProbability
+			}
+		}
+		tooltip = append(tooltip, fmt.Sprintf("data breach probability: %v (%d open risks)", probability.Title(), len(openRisks)))
+	}
+
+	return what.link(dataAssetAnchor(dataAsset.Id), strings.Join(tooltip, "\n"))
+}
+
+func (what *diagramLinks) communicationLink(link *types.CommunicationLink) diagramLink {
+	return diagramLink{id: what.idPrefix + "communication-link-" + link.Id, tooltip: link.Title + " (" + link.Protocol.String() + ")"}
+}
+
+func (what *diagramLinks) dataAssetFlow(dataAssetId string, techAssetId string) diagramLink {
+	return diagramLink{id: what.idPrefix + "data-asset-" + dataAssetId + "-at-" + techAssetId}
+}
+
+func (what *diagramLinks) trustBoundary(trustBoundary *types.TrustBoundary) diagramLink {
+	return diagramLink{id: what.idPrefix + "trust-boundary-" + trustBoundary.Id, tooltip: trustBoundary.Title}
+}
+
+func (what *diagramLinks) sharedRuntime(sharedRuntime *types.SharedRuntime) diagramLink {
+	return diagramLink{id: what.idPrefix + "shared-runtime-" + sharedRuntime.Id, tooltip: sharedRuntime.Title}
+}
+
+func (what *diagramLinks) technicalAssetNode(parsedModel *types.Model, techAsset *types.TechnicalAsset) string {
+	return "  " + hash(techAsset.Id) + " [ " + what.technicalAsset(parsedModel, techAsset).dotAttributes() + " ];"
+}
+
+func (what *diagramLinks) dataAssetNode(parsedModel *types.Model, dataAsset *types.DataAsset) string {
+	return "  " + hash(dataAsset.Id) + " [ " + what.dataAsset(parsedModel, dataAsset).dotAttributes() + " ];"
+}
+
+func (what *diagramLinks) communicationLinkEdge(link *types.CommunicationLink) string {
+	return what.communicationLink(link).dotAttributes()
+}
+
+func (what *diagramLinks) dataAssetEdge(dataAssetId string, techAssetId string) string {
+	return what.dataAssetFlow(dataAssetId, techAssetId).dotAttributes()
+}
+
+func (what *diagramLinks) link(anchor string, tooltip string) diagramLink {
+	link := diagramLink{id: what.idPrefix + anchor, tooltip: tooltip}
+	if len(what.reportFilename) > 0 {
+		link.url = what.reportFilename + "#" + anchor
+	}
+
+	return link
+}
+
+// technicalAssetAnchor and dataAssetAnchor are the ids of the asset sections in the HTML report
+
+func technicalAssetAnchor(id string) string {
+	return "technical-asset-" + id
+}
+
+func dataAssetAnchor(id string) string {
+	return "data-asset-" + id
+}
+
+// openRisks reduces the risks to those still at risk according to their risk tracking
+
+func openRisks(parsedModel *types.Model, risks []*types.Risk) []*types.Risk {
+	result := make([]*types.Risk, 0)
+	for _, risk := range risks {
+		if risk.GetRiskTrackingWithDefault(parsedModel).Status.IsStillAtRisk() {
+			result = append(result, risk)
+		}
+	}
+
+	return result
+}
+
+// highestOpenRiskSeverity returns the highest severity of the risks still at risk, if any
+
+func highestOpenRiskSeverity(parsedModel *types.Model, risks []*types.Risk) (types.RiskSeverity, bool) {
+	openRisks := openRisks(parsedModel, risks)
+	if len(openRisks) == 0 {
+		return types.LowSeverity, false
+	}
+
+	severity := types.LowSeverity
+	for _, risk := range openRisks {
+		if risk.Severity > severity {
+			severity = risk.Severity
+		}
+	}
+
+	return severity, true
+}
+
+func openRisksText(parsedModel *types.Model, risks []*types.Risk) string {
+	severity, ok := highestOpenRiskSeverity(parsedModel, risks)
+	if !ok {
+		return "no open risks"
+	}
+
+	return fmt.Sprintf("highest open risk: %v (%d open risks)", severity.Title(), len(openRisks(parsedModel, risks)))
+}
+
+func dotEscape(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return strings.ReplaceAll(value, "\n", `\n`)
+}
@@ -0,0 +1,96 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// WriteDataFlowDiagramMermaid writes the data flow diagram as Mermaid flowchart, with the trust boundaries as nested
+// subgraphs and the communication links labeled with their protocol, for wikis rendering Mermaid
+
+func WriteDataFlowDiagramMermaid(parsedModel *types.Model, filename string) error {
+	var content strings.Builder
+	if len(parsedModel.Title) > 0 {
+		content.WriteString("---\ntitle: " + strconv.Quote(parsedModel.Title) + "\n---\n") // Go quoting is valid YAML
+	}
+	direction := "TB"
+	if parsedModel.DiagramTweakLayoutLeftToRight {
+		direction = "LR"
+	}
+	content.WriteString("flowchart " + direction + "\n")
+
+	var styles strings.Builder
+	var writeTrustBoundary func(trustBoundaryId string, indent string)
+	writeTrustBoundary = func(trustBoundaryId string, indent string) {
+		for _, techAsset := range diagramTechnicalAssets(parsedModel, trustBoundaryId) {
+			content.WriteString(indent + mermaidNode(techAsset) + "\n")
+			styles.WriteString(mermaidNodeStyle(parsedModel, techAsset))
+		}
+		for _, trustBoundary := range diagramTrustBoundaries(parsedModel, trustBoundaryId) {
+			content.WriteString(indent + "subgraph n" + hash(trustBoundary.Id) + `["` + mermaidText(trustBoundary.Title+" ("+trustBoundary.Type.String()+")") + `"]` + "\n")
+			writeTrustBoundary(trustBoundary.Id, indent+"  ")
+			content.WriteString(indent + "end\n")
+		}
+	}
+	writeTrustBoundary("", "  ")
+
+	links := diagramCommunicationLinks(parsedModel)
+	for _, link := range links {
+		arrow := "-->"
+		if determineArrowLineStyle(link) != "solid" {
+			arrow = "-.->"
+		}
+		content.WriteString("  n" + hash(link.SourceId) + " " + arrow)
+		if !parsedModel.DiagramTweakSuppressEdgeLabels {
+			content.WriteString(`|"` + mermaidText(link.Protocol.String()) + `"|`)
+		}
+		content.WriteString(" n" + hash(link.TargetId) + "\n")
+	}
+
+	content.WriteString(styles.String())
+	for n, link := range links {
+		content.WriteString(fmt.Sprintf("  linkStyle %d stroke:%v\n", n, determineArrowColor(link, parsedModel)))
+	}
+
+	err := os.WriteFile(filepath.Clean(filename), []byte(content.String()), 0600)
+	if err != nil {
+		return fmt.Errorf("error writing %s: %v", filename, err)
+	}
+	return nil
+}
+
+func mermaidNode(techAsset *types.TechnicalAsset) string {
+	open, closing := `["`, `"]`
+	switch techAsset.Type {
+	case types.Process:
+		open, closing = `(["`, `"])`
+	case types.Datastore:
+		open, closing = `[("`, `")]`
+	}
+	if techAsset.UsedAsClientByHuman {
+		open, closing = `{{"`, `"}}`
+	}
+
+	return "n" + hash(techAsset.Id) + open + mermaidText(techAsset.Title) + closing
+}
+
+func mermaidNodeStyle(parsedModel *types.Model, techAsset *types.TechnicalAsset) string {
+	style := "fill:" + determineShapeFillColor(techAsset, parsedModel) + ",stroke:" + determineShapeBorderColor(techAsset, parsedModel) +
+		",color:" + determineTechnicalAssetLabelColor(techAsset, parsedModel)
+	if determineShapeBorderLineStyle(techAsset) != "solid" {
+		style += ",stroke-dasharray:3 3"
+	}
+
+	return "  style n" + hash(techAsset.Id) + " " + style + "\n"
+}
+
+// mermaidText escapes text for quoted Mermaid labels, which take entity codes and render markup
+
+func mermaidText(value string) string {
+	return strings.NewReplacer("#", "#35;", `"`, "#quot;", "&", "#amp;", "<", "#lt;", ">", "#gt;", "\n", " ").Replace(value)
+}
@@ -0,0 +1,67 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestWriteDataFlowDiagramMermaid(t *testing.T) {
+	browserToWeb := &types.CommunicationLink{Id: "browser>web", SourceId: "browser", TargetId: "web", Protocol: types.HTTPS}
+	webToDb := &types.CommunicationLink{Id: "web>db", SourceId: "web", TargetId: "db", Protocol: types.JdbcEncrypted}
+	cloud := &types.TrustBoundary{Id: "cloud", Title: "Cloud", Type: types.NetworkCloudProvider, TechnicalAssetsInside: []string{"web"}, TrustBoundariesNested: []string{"cluster"}}
+	cluster := &types.TrustBoundary{Id: "cluster", Title: "Cluster", Type: types.ExecutionEnvironment, TechnicalAssetsInside: []string{"db"}}
+	parsedModel := &types.Model{
+		Title: `Shop "A" \ B`,
+		TechnicalAssets: map[string]*types.TechnicalAsset{
+			"browser": {Id: "browser", Title: "Browser", Type: types.ExternalEntity, UsedAsClientByHuman: true, CommunicationLinks: []*types.CommunicationLink{browserToWeb}},
+			"web":     {Id: "web", Title: "Web \"Shop\" \\ <b>Q&A</b>\nFrontend", Type: types.Process, CommunicationLinks: []*types.CommunicationLink{webToDb}},
+			"db":      {Id: "db", Title: "Database", Type: types.Datastore},
+		},
+		CommunicationLinks: map[string]*types.CommunicationLink{browserToWeb.Id: browserToWeb, webToDb.Id: webToDb},
+		TrustBoundaries:    map[string]*types.TrustBoundary{cloud.Id: cloud, cluster.Id: cluster},
+		DirectContainingTrustBoundaryMappedByTechnicalAssetId: map[string]*types.TrustBoundary{"web": cloud, "db": cluster},
+	}
+
+	filename := filepath.Join(t.TempDir(), "data-flow-diagram.mmd")
+	require.NoError(t, WriteDataFlowDiagramMermaid(parsedModel, filename))
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	diagram := string(data)
+
+	assert.Contains(t, diagram, "---\ntitle: \"Shop \\\"A\\\" \\\\ B\"\n---\nflowchart TB\n")
+	assert.Contains(t, diagram, "  n"+hash("browser")+`{{"Browser"}}`+"\n")
+	assert.Contains(t, diagram, "  subgraph n"+hash("cloud")+`["Cloud (network-cloud-provider)"]`+"\n"+
+		"    n"+hash("web")+`(["Web #quot;Shop#quot; \ #lt;b#gt;Q#amp;A#lt;/b#gt; Frontend"])`+"\n"+
+		"    subgraph n"+hash("cluster")+`["Cluster (execution-environment)"]`+"\n"+
+		"      n"+hash("db")+`[("Database")]`+"\n"+
+		"    end\n"+
+		"  end\n")
+	assert.Contains(t, diagram, "  n"+hash("web")+` -.->|"jdbc-encrypted"| n`+hash("db")+"\n")
+	assert.Contains(t, diagram, "  n"+hash("browser")+` -.->|"https"| n`+hash("web")+"\n")
+
+	parsedModel.DiagramTweakSuppressEdgeLabels = true
+	require.NoError(t, WriteDataFlowDiagramMermaid(parsedModel, filename))
+	data, err = os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "  n"+hash("web")+" -.-> n"+hash("db")+"\n")
+	assert.NotContains(t, string(data), "https")
+}
+
+func TestMermaidText(t *testing.T) {
+	for text, expected := range map[string]string{
+		"plain":          "plain",
+		`say "hi"`:       "say #quot;hi#quot;",
+		`C:\temp`:        `C:\temp`,
+		"<b>bold</b>":    "#lt;b#gt;bold#lt;/b#gt;",
+		"Q&A":            "Q#amp;A",
+		"two\nlines":     "two lines",
+		"#quot; is text": "#35;quot; is text",
+	} {
+		assert.Equal(t, expected, mermaidText(text), text)
+	}
+}
@@ -0,0 +1,87 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// WriteDataFlowDiagramPlantUML writes the data flow diagram as PlantUML deployment diagram, with the trust boundaries
+// as nested containers and the communication links labeled with their protocol
+
+func WriteDataFlowDiagramPlantUML(parsedModel *types.Model, filename string) error {
+	var content strings.Builder
+	content.WriteString("@startuml\n")
+	if len(parsedModel.Title) > 0 {
+		content.WriteString("title " + plantUMLText(parsedModel.Title) + "\n")
+	}
+	if parsedModel.DiagramTweakLayoutLeftToRight {
+		content.WriteString("left to right direction\n")
+	}
+
+	var writeTrustBoundary func(trustBoundaryId string, indent string)
+	writeTrustBoundary = func(trustBoundaryId string, indent string) {
+		for _, techAsset := range diagramTechnicalAssets(parsedModel, trustBoundaryId) {
+			content.WriteString(indent + plantUMLElement(parsedModel, techAsset) + "\n")
+		}
+		for _, trustBoundary := range diagramTrustBoundaries(parsedModel, trustBoundaryId) {
+			container := "frame"
+			switch trustBoundary.Type {
+			case types.NetworkCloudProvider:
+				container = "cloud"
+			case types.ExecutionEnvironment:
+				container = "node"
+			}
+			content.WriteString(indent + container + ` "` + plantUMLText(trustBoundary.Title) + `" as n` + hash(trustBoundary.Id) +
+				" <<" + trustBoundary.Type.String() + ">> {\n")
+			writeTrustBoundary(trustBoundary.Id, indent+"  ")
+			content.WriteString(indent + "}\n")
+		}
+	}
+	writeTrustBoundary("", "")
+
+	for _, link := range diagramCommunicationLinks(parsedModel) {
+		style := determineArrowColor(link, parsedModel)
+		if determineArrowLineStyle(link) != "solid" {
+			style += ",dashed"
+		}
+		content.WriteString("n" + hash(link.SourceId) + " -[" + style + "]-> n" + hash(link.TargetId))
+		if !parsedModel.DiagramTweakSuppressEdgeLabels {
+			content.WriteString(" : " + plantUMLText(link.Protocol.String()))
+		}
+		content.WriteString("\n")
+	}
+
+	content.WriteString("@enduml\n")
+
+	err := os.WriteFile(filepath.Clean(filename), []byte(content.String()), 0600)
+	if err != nil {
+		return fmt.Errorf("error writing %s: %v", filename, err)
+	}
+	return nil
+}
+
+func plantUMLElement(parsedModel *types.Model, techAsset *types.TechnicalAsset) string {
+	element := "agent"
+	switch techAsset.Type {
+	case types.Process:
+		element = "component"
+	case types.Datastore:
+		element = "database"
+	}
+	if techAsset.UsedAsClientByHuman {
+		element = "actor"
+	}
+
+	return element + ` "` + plantUMLText(techAsset.Title) + `" as n` + hash(techAsset.Id) +
+		" " + determineShapeFillColor(techAsset, parsedModel) + ";line:" + strings.TrimPrefix(determineShapeBorderColor(techAsset, parsedModel), "#")
+}
+
+// plantUMLText escapes text for quoted PlantUML labels, which take backslash sequences, entity codes and creole markup
+
+func plantUMLText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, "&#34;", "&", "&#38;", "<", "&#60;", "\n", " ").Replace(value)
+}
@@ -0,0 +1,62 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestWriteDataFlowDiagramPlantUML(t *testing.T) {
+	browserToWeb := &types.CommunicationLink{Id: "browser>web", SourceId: "browser", TargetId: "web", Protocol: types.HTTPS}
+	webToDb := &types.CommunicationLink{Id: "web>db", SourceId: "web", TargetId: "db", Protocol: types.JdbcEncrypted}
+	cloud := &types.TrustBoundary{Id: "cloud", Title: "Cloud", Type: types.NetworkCloudProvider, TechnicalAssetsInside: []string{"web"}, TrustBoundariesNested: []string{"cluster"}}
+	cluster := &types.TrustBoundary{Id: "cluster", Title: "Cluster", Type: types.ExecutionEnvironment, TechnicalAssetsInside: []string{"db"}}
+	parsedModel := &types.Model{
+		Title: `Shop "A" \ B`,
+		TechnicalAssets: map[string]*types.TechnicalAsset{
+			"browser": {Id: "browser", Title: "Browser", Type: types.ExternalEntity, UsedAsClientByHuman: true, CommunicationLinks: []*types.CommunicationLink{browserToWeb}},
+			"web":     {Id: "web", Title: "Web \"Shop\" \\ <b>Q&A</b>\nFrontend", Type: types.Process, CommunicationLinks: []*types.CommunicationLink{webToDb}},
+			"db":      {Id: "db", Title: "Database", Type: types.Datastore},
+		},
+		CommunicationLinks: map[string]*types.CommunicationLink{browserToWeb.Id: browserToWeb, webToDb.Id: webToDb},
+		TrustBoundaries:    map[string]*types.TrustBoundary{cloud.Id: cloud, cluster.Id: cluster},
+		DirectContainingTrustBoundaryMappedByTechnicalAssetId: map[string]*types.TrustBoundary{"web": cloud, "db": cluster},
+	}
+
+	filename := filepath.Join(t.TempDir(), "data-flow-diagram.puml")
+	require.NoError(t, WriteDataFlowDiagramPlantUML(parsedModel, filename))
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	diagram := string(data)
+
+	assert.Contains(t, diagram, "@startuml\ntitle Shop &#34;A&#34; \\\\ B\n")
+	assert.Contains(t, diagram, `actor "Browser" as n`+hash("browser")+" ")
+	assert.Contains(t, diagram, `cloud "Cloud" as n`+hash("cloud")+" <<network-cloud-provider>> {\n"+
+		`  component "Web &#34;Shop&#34; \\ &#60;b>Q&#38;A&#60;/b> Frontend" as n`+hash("web")+" #dfc7cf;line:000000\n"+
+		`  node "Cluster" as n`+hash("cluster")+" <<execution-environment>> {\n"+
+		`    database "Database" as n`+hash("db")+" #dfc7cf;line:000000\n"+
+		"  }\n"+
+		"}\n")
+	assert.Contains(t, diagram, "n"+hash("web")+" -[#F987C5,dashed]-> n"+hash("db")+" : jdbc-encrypted\n")
+	assert.Contains(t, diagram, "n"+hash("browser")+" -[#F987C5,dashed]-> n"+hash("web")+" : https\n")
+	assert.True(t, strings.HasSuffix(diagram, "@enduml\n"))
+}
+
+func TestPlantUMLText(t *testing.T) {
+	for text, expected := range map[string]string{
+		"plain":       "plain",
+		`say "hi"`:    "say &#34;hi&#34;",
+		`C:\new`:      `C:\\new`,
+		"<b>bold</b>": "&#60;b>bold&#60;/b>",
+		"Q&A":         "Q&#38;A",
+		"&#34;":       "&#38;#34;",
+		"two\nlines":  "two lines",
+	} {
+		assert.Equal(t, expected, plantUMLText(text), text)
+	}
+}
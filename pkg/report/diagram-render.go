@@ -0,0 +1,537 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// diagramCanvas is what a laid out diagram gets drawn onto; coordinates are in points and colors are hex colors,
+// with an empty color drawing nothing
+
+type diagramCanvas interface {
+	beginElement(link diagramLink, class string)
+	endElement()
+	polygon(points []diagramPoint, fill string, stroke string, penWidth float64, style string)
+	polyline(points []diagramPoint, stroke string, penWidth float64, style string)
+	circle(center diagramPoint, radius float64, fill string, stroke string, penWidth float64)
+	text(at diagramPoint, text *diagramText, anchor string) // at the baseline, anchored at start or middle
+}
+
+func (what *diagramGraph) draw(canvas diagramCanvas) {
+	if what.title != nil {
+		canvas.text(diagramPoint{x: diagramMargin, y: diagramMargin + what.title.size}, what.title, "start")
+	}
+
+	var drawClusters func(cluster *diagramCluster)
+	drawClusters = func(cluster *diagramCluster) {
+		for _, child := range cluster.children {
+			canvas.beginElement(child.link, "cluster")
+			canvas.polygon(rectangle(child.x, child.y, child.width, child.height), child.fillColor, child.borderColor, child.penWidth, child.borderStyle)
+			if what.leftToRight {
+				canvas.text(diagramPoint{x: child.x + child.width/2, y: child.y + diagramClusterPadding/2 + child.label.size}, child.label, "middle")
+			} else {
+				canvas.text(diagramPoint{x: child.x + child.width/2, y: child.y + diagramClusterPadding + child.label.size}, child.label, "middle")
+			}
+			canvas.endElement()
+			drawClusters(child)
+		}
+	}
+	drawClusters(what.root)
+
+	for _, node := range what.nodes {
+		canvas.beginElement(node.link, "node")
+		canvas.polygon(node.outline(0), node.fillColor, node.borderColor, node.penWidth, node.borderStyle)
+		if node.peripheries > 1 {
+			canvas.polygon(node.outline(4), "", node.borderColor, node.penWidth, node.borderStyle)
+		}
+		if node.shape == "cylinder" {
+			canvas.polyline(node.cylinderRim(), node.borderColor, node.penWidth, node.borderStyle)
+		}
+
+		_, height := textBlockSize(node.lines)
+		y := node.y - height/2
+		if node.shape == "cylinder" { // below the rim
+			y += node.rimHeight() / 2
+		}
+		for _, line := range node.lines {
+			canvas.text(diagramPoint{x: node.x, y: y + line.size}, line, "middle")
+			y += textHeight(line)
+		}
+		canvas.endElement()
+	}
+
+	for _, edge := range what.edges {
+		if edge.invisible || len(edge.points) < 2 {
+			continue
+		}
+
+		canvas.beginElement(edge.link, "edge")
+		points := append(make([]diagramPoint, 0, len(edge.points)), edge.points...)
+		last, previous := points[len(points)-1], points[len(points)-2]
+		length := math.Hypot(last.x-previous.x, last.y-previous.y)
+		if edge.arrowHead != "none" && length > 0 {
+			dx, dy := (last.x-previous.x)/length, (last.y-previous.y)/length
+			size := math.Min(edge.arrowSize, length)
+			base := diagramPoint{x: last.x - dx*size, y: last.y - dy*size}
+			points[len(points)-1] = base
+
+			arrow := []diagramPoint{last, {x: base.x - dy*size/3, y: base.y + dx*size/3}, {x: base.x + dy*size/3, y: base.y - dx*size/3}}
+			if edge.arrowHead == "empty" {
+				canvas.polygon(arrow, "#FFFFFF", edge.color, edge.penWidth, "solid")
+			} else {
+				canvas.polygon(arrow, edge.color, edge.color, edge.penWidth, "solid")
+			}
+		}
+		canvas.polyline(points, edge.color, edge.penWidth, edge.style)
+
+		switch edge.arrowTail {
+		case "dot":
+			canvas.circle(edge.points[0], edge.arrowSize/3, edge.color, edge.color, edge.penWidth)
+		case "odot":
+			canvas.circle(edge.points[0], edge.arrowSize/3, "#FFFFFF", edge.color, edge.penWidth)
+		}
+
+		if edge.label != nil {
+			canvas.text(diagramPoint{x: edge.labelAt.x + 4, y: edge.labelAt.y + edge.label.size/3}, edge.label, "start")
+		}
+		canvas.endElement()
+	}
+}
+
+// outline returns the shape of the node as polygon, grown by the given distance
+
+func (what *diagramNode) outline(grow float64) []diagramPoint {
+	x, y := what.x-what.width/2-grow, what.y-what.height/2-grow
+	width, height := what.width+2*grow, what.height+2*grow
+
+	switch what.shape {
+	case "ellipse":
+		return ellipse(what.x, what.y, width/2, height/2, 0, 2*math.Pi)
+	case "octagon":
+		cut := math.Min(width, height) / 4
+		return []diagramPoint{
+			{x: x + cut, y: y}, {x: x + width - cut, y: y},
+			{x: x + width, y: y + cut}, {x: x + width, y: y + height - cut},
+			{x: x + width - cut, y: y + height}, {x: x + cut, y: y + height},
+			{x: x, y: y + height - cut}, {x: x, y: y + cut},
+		}
+	case "cylinder":
+		rim := what.rimHeight()
+		points := ellipse(what.x, y+rim, width/2, rim, math.Pi, 2*math.Pi)
+		return append(points, ellipse(what.x, y+height-rim, width/2, rim, 0, math.Pi)...)
+	default:
+		return rectangle(x, y, width, height)
+	}
+}
+
+// cylinderRim is the front of the top of a cylinder
+
+func (what *diagramNode) cylinderRim() []diagramPoint {
+	return ellipse(what.x, what.y-what.height/2+what.rimHeight(), what.width/2, what.rimHeight(), 0, math.Pi)
+}
+
+func (what *diagramNode) rimHeight() float64 {
+	return math.Min(12, what.height/6)
+}
+
+func rectangle(x float64, y float64, width float64, height float64) []diagramPoint {
+	return []diagramPoint{{x: x, y: y}, {x: x + width, y: y}, {x: x + width, y: y + height}, {x: x, y: y + height}}
+}
+
+// ellipse returns points on the ellipse from one angle to the other, clockwise on screen
+
+func ellipse(x float64, y float64, radiusX float64, radiusY float64, from float64, to float64) []diagramPoint {
+	const steps = 48
+	points := make([]diagramPoint, 0, steps+1)
+	for step := 0; step <= steps; step++ {
+		angle := from + (to-from)*float64(step)/steps
+		points = append(points, diagramPoint{x: x + radiusX*math.Cos(angle), y: y + radiusY*math.Sin(angle)})
+	}
+
+	return points
+}
+
+// dashPattern returns the lengths of dashes and gaps of a line style, or nothing for solid lines
+
+func dashPattern(style string, penWidth float64) []float64 {
+	switch style {
+	case "dashed":
+		return []float64{math.Max(8, 4*penWidth), math.Max(5, 2.5*penWidth)}
+	case "dotted":
+		return []float64{math.Max(1, penWidth), math.Max(4, 2*penWidth)}
+	default:
+		return nil
+	}
+}
+
+// Fonts ===============================================================================
+
+var diagramFonts struct {
+	once    sync.Once
+	regular *opentype.Font
+	bold    *opentype.Font
+	err     error
+	lock    sync.Mutex
+	faces   map[string]font.Face
+}
+
+// diagramFace returns the font face for the text, scaled from points to pixels
+
+func diagramFace(text *diagramText, scale float64) (font.Face, error) {
+	diagramFonts.once.Do(func() {
+		diagramFonts.faces = make(map[string]font.Face)
+		diagramFonts.regular, diagramFonts.err = opentype.Parse(goregular.TTF)
+		if diagramFonts.err == nil {
+			diagramFonts.bold, diagramFonts.err = opentype.Parse(gobold.TTF)
+		}
+	})
+	if diagramFonts.err != nil {
+		return nil, fmt.Errorf("failed to parse diagram font: %w", diagramFonts.err)
+	}
+
+	diagramFonts.lock.Lock()
+	defer diagramFonts.lock.Unlock()
+
+	key := fmt.Sprintf("%v/%v/%v", text.size, text.bold, scale)
+	if face, ok := diagramFonts.faces[key]; ok {
+		return face, nil
+	}
+
+	typeface := diagramFonts.regular
+	if text.bold {
+		typeface = diagramFonts.bold
+	}
+	face, err := opentype.NewFace(typeface, &opentype.FaceOptions{Size: text.size, DPI: 72 * scale, Hinting: font.HintingNone})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create diagram font face: %w", err)
+	}
+	diagramFonts.faces[key] = face
+
+	return face, nil
+}
+
+// textWidth and textHeight return the size of the text in points
+
+func textWidth(text *diagramText) float64 {
+	if text == nil || len(text.text) == 0 {
+		return 0
+	}
+
+	face, err := diagramFace(text, 1)
+	if err != nil { // estimate
+		return float64(len(text.text)) * text.size * 0.6
+	}
+
+	return float64(font.MeasureString(face, text.text)) / 64
+}
+
+func textHeight(text *diagramText) float64 {
+	if text == nil {
+		return 0
+	}
+
+	return text.size * 1.25
+}
+
+// SVG ===============================================================================
+
+// svgCanvas writes the diagram as SVG, with the ids, tooltips and links of the nodes and edges the way graphviz does
+
+type svgCanvas struct {
+	content strings.Builder
+	linked  []bool
+}
+
+func (what *diagramGraph) writeSVG(filename string) error {
+	canvas := new(svgCanvas)
+	canvas.content.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="no"?>` + "\n")
+	canvas.content.WriteString(fmt.Sprintf(`<svg width="%.0fpt" height="%.0fpt" viewBox="0 0 %.2f %.2f" xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink">`+"\n",
+		what.width, what.height, what.width, what.height))
+	canvas.content.WriteString(fmt.Sprintf(`<rect width="%.2f" height="%.2f" fill="#FFFFFF"/>`+"\n", what.width, what.height))
+	what.draw(canvas)
+	canvas.content.WriteString("</svg>\n")
+
+	err := os.WriteFile(filepath.Clean(filename), []byte(canvas.content.String()), 0600)
+	if err != nil {
+		return fmt.Errorf("error writing %s: %v", filename, err)
+	}
+	return nil
+}
+
+func (what *svgCanvas) beginElement(link diagramLink, class string) {
+	what.content.WriteString(`<g class="` + class + `"`)
+	if len(link.id) > 0 {
+		what.content.WriteString(` id="` + html.EscapeString(link.id) + `"`)
+	}
+	what.content.WriteString(">\n")
+	if len(link.tooltip) > 0 {
+		what.content.WriteString("<title>" + html.EscapeString(link.tooltip) + "</title>\n")
+	}
+	if len(link.url) > 0 {
+		what.content.WriteString(`<a xlink:href="` + html.EscapeString(link.url) + `" target="_top">` + "\n")
+	}
+	what.linked = append(what.linked, len(link.url) > 0)
+}
+
+func (what *svgCanvas) endElement() {
+	if what.linked[len(what.linked)-1] {
+		what.content.WriteString("</a>\n")
+	}
+	what.linked = what.linked[:len(what.linked)-1]
+	what.content.WriteString("</g>\n")
+}
+
+func (what *svgCanvas) polygon(points []diagramPoint, fill string, stroke string, penWidth float64, style string) {
+	what.content.WriteString(`<polygon points="` + svgPoints(points) + `"` + svgPaint(fill, stroke, penWidth, style) + "/>\n")
+}
+
+func (what *svgCanvas) polyline(points []diagramPoint, stroke string, penWidth float64, style string) {
+	what.content.WriteString(`<polyline points="` + svgPoints(points) + `"` + svgPaint("", stroke, penWidth, style) + "/>\n")
+}
+
+func (what *svgCanvas) circle(center diagramPoint, radius float64, fill string, stroke string, penWidth float64) {
+	what.content.WriteString(fmt.Sprintf(`<circle cx="%.2f" cy="%.2f" r="%.2f"`, center.x, center.y, radius) + svgPaint(fill, stroke, penWidth, "solid") + "/>\n")
+}
+
+func (what *svgCanvas) text(at diagramPoint, text *diagramText, anchor string) {
+	if len(text.text) == 0 {
+		return
+	}
+
+	weight := ""
+	if text.bold {
+		weight = ` font-weight="bold"`
+	}
+	what.content.WriteString(fmt.Sprintf(`<text x="%.2f" y="%.2f" text-anchor="%v" font-family="Helvetica,Arial,sans-serif" font-size="%.1f"%v fill="%v">%v</text>`+"\n",
+		at.x, at.y, anchor, text.size, weight, text.color, html.EscapeString(text.text)))
+}
+
+func svgPoints(points []diagramPoint) string {
+	values := make([]string, 0, len(points))
+	for _, point := range points {
+		values = append(values, fmt.Sprintf("%.2f,%.2f", point.x, point.y))
+	}
+
+	return strings.Join(values, " ")
+}
+
+func svgPaint(fill string, stroke string, penWidth float64, style string) string {
+	paint := ` fill="none"`
+	if len(fill) > 0 {
+		paint = ` fill="` + fill + `"`
+	}
+	if len(stroke) > 0 {
+		paint += ` stroke="` + stroke + `" stroke-width="` + strconv.FormatFloat(penWidth, 'f', 2, 64) + `"`
+		if pattern := dashPattern(style, penWidth); len(pattern) > 0 {
+			paint += fmt.Sprintf(` stroke-dasharray="%.2f,%.2f"`, pattern[0], pattern[1])
+		}
+	}
+
+	return paint
+}
+
+// PNG ===============================================================================
+
+// pngCanvas rasterizes the diagram, scaled from points to pixels
+
+type pngCanvas struct {
+	image      *image.RGBA
+	scale      float64
+	rasterizer *vector.Rasterizer
+	err        error
+}
+
+func (what *diagramGraph) writePNG(filename string, scale float64) error {
+	width, height := int(math.Ceil(what.width*scale)), int(math.Ceil(what.height*scale))
+	canvas := &pngCanvas{image: image.NewRGBA(image.Rect(0, 0, width, height)), scale: scale, rasterizer: vector.NewRasterizer(0, 0)}
+	draw.Draw(canvas.image, canvas.image.Bounds(), image.White, image.Point{}, draw.Src)
+	what.draw(canvas)
+	if canvas.err != nil {
+		return canvas.err
+	}
+
+	var content bytes.Buffer
+	err := png.Encode(&content, canvas.image)
+	if err != nil {
+		return fmt.Errorf("error encoding %s: %v", filename, err)
+	}
+
+	err = os.WriteFile(filepath.Clean(filename), content.Bytes(), 0600)
+	if err != nil {
+		return fmt.Errorf("error writing %s: %v", filename, err)
+	}
+	return nil
+}
+
+func (what *pngCanvas) beginElement(diagramLink, string) {
+}
+
+func (what *pngCanvas) endElement() {
+}
+
+func (what *pngCanvas) polygon(points []diagramPoint, fill string, stroke string, penWidth float64, style string) {
+	if len(fill) > 0 {
+		what.fill([][]diagramPoint{points}, fill)
+	}
+	if len(stroke) > 0 {
+		what.stroke(append(append(make([]diagramPoint, 0, len(points)+1), points...), points[0]), stroke, penWidth, style)
+	}
+}
+
+func (what *pngCanvas) polyline(points []diagramPoint, stroke string, penWidth float64, style string) {
+	what.stroke(points, stroke, penWidth, style)
+}
+
+func (what *pngCanvas) circle(center diagramPoint, radius float64, fill string, stroke string, penWidth float64) {
+	points := ellipse(center.x, center.y, radius, radius, 0, 2*math.Pi)
+	what.polygon(points[:len(points)-1], fill, stroke, penWidth, "solid")
+}
+
+func (what *pngCanvas) text(at diagramPoint, text *diagramText, anchor string) {
+	if len(text.text) == 0 {
+		return
+	}
+
+	face, err := diagramFace(text, what.scale)
+	if err != nil {
+		what.err = err
+		return
+	}
+
+	x := at.x * what.scale
+	if anchor == "middle" {
+		x -= float64(font.MeasureString(face, text.text)) / 64 / 2
+	}
+	drawer := &font.Drawer{
+		Dst:  what.image,
+		Src:  image.NewUniform(parseHexColor(text.color)),
+		Face: face,
+		Dot:  fixed.Point26_6{X: fixed.Int26_6(x * 64), Y: fixed.Int26_6(at.y * what.scale * 64)},
+	}
+	drawer.DrawString(text.text)
+}
+
+// stroke draws the lines as quads of the pen width, with round joints, leaving out the gaps of dashed lines
+
+func (what *pngCanvas) stroke(points []diagramPoint, stroke string, penWidth float64, style string) {
+	pattern := dashPattern(style, penWidth)
+	halfWidth := penWidth / 2
+	shapes := make([][]diagramPoint, 0)
+	addSegment := func(from diagramPoint, to diagramPoint) {
+		length := math.Hypot(to.x-from.x, to.y-from.y)
+		if length == 0 {
+			return
+		}
+		nx, ny := -(to.y-from.y)/length*halfWidth, (to.x-from.x)/length*halfWidth
+		shapes = append(shapes, []diagramPoint{
+			{x: from.x + nx, y: from.y + ny}, {x: to.x + nx, y: to.y + ny},
+			{x: to.x - nx, y: to.y - ny}, {x: from.x - nx, y: from.y - ny},
+		})
+	}
+
+	dashIndex, dashLeft := 0, 0.0
+	if len(pattern) > 0 {
+		dashLeft = pattern[0]
+	}
+	for n := 1; n < len(points); n++ {
+		from, to := points[n-1], points[n]
+		if len(pattern) == 0 {
+			addSegment(from, to)
+			if n < len(points)-1 && halfWidth >= 1 {
+				joint := ellipse(to.x, to.y, halfWidth, halfWidth, 0, 2*math.Pi)
+				shapes = append(shapes, joint[:len(joint)-1])
+			}
+			continue
+		}
+
+		length := math.Hypot(to.x-from.x, to.y-from.y)
+		position := 0.0
+		for position < length {
+			step := math.Min(dashLeft, length-position)
+			if dashIndex%2 == 0 {
+				start, end := position/length, (position+step)/length
+				addSegment(
+					diagramPoint{x: from.x + (to.x-from.x)*start, y: from.y + (to.y-from.y)*start},
+					diagramPoint{x: from.x + (to.x-from.x)*end, y: from.y + (to.y-from.y)*end})
+			}
+			position += step
+			dashLeft -= step
+			if dashLeft <= 0 {
+				dashIndex++
+				dashLeft = pattern[dashIndex%2]
+			}
+		}
+	}
+
+	for _, shape := range shapes {
+		what.fill([][]diagramPoint{shape}, stroke)
+	}
+}
+
+// fill rasterizes the polygons within their bounding box only, as rasterizing the whole image per shape is slow
+
+func (what *pngCanvas) fill(polygons [][]diagramPoint, fill string) {
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, points := range polygons {
+		for _, point := range points {
+			minX, minY = math.Min(minX, point.x*what.scale), math.Min(minY, point.y*what.scale)
+			maxX, maxY = math.Max(maxX, point.x*what.scale), math.Max(maxY, point.y*what.scale)
+		}
+	}
+
+	bounds := image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX))+1, int(math.Ceil(maxY))+1).Intersect(what.image.Bounds())
+	if bounds.Empty() {
+		return
+	}
+
+	what.rasterizer.Reset(bounds.Dx(), bounds.Dy())
+	what.rasterizer.DrawOp = draw.Over
+	for _, points := range polygons {
+		for n, point := range points {
+			x, y := float32(point.x*what.scale-float64(bounds.Min.X)), float32(point.y*what.scale-float64(bounds.Min.Y))
+			if n == 0 {
+				what.rasterizer.MoveTo(x, y)
+			} else {
+				what.rasterizer.LineTo(x, y)
+			}
+		}
+		what.rasterizer.ClosePath()
+	}
+	what.rasterizer.Draw(what.image, bounds, image.NewUniform(parseHexColor(fill)), image.Point{})
+}
+
+func parseHexColor(value string) color.RGBA {
+	result := color.RGBA{A: 255}
+	value = strings.TrimPrefix(value, "#")
+	if len(value) == 3 {
+		value = string([]byte{value[0], value[0], value[1], value[1], value[2], value[2]})
+	}
+	if len(value) < 6 {
+		return result
+	}
+
+	rgb, err := strconv.ParseUint(value[:6], 16, 32)
+	if err != nil {
+		return result
+	}
+	result.R, result.G, result.B = uint8(rgb>>16), uint8(rgb>>8), uint8(rgb)
+	return result
+}
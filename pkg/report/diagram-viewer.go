@@ -0,0 +1,217 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"sort"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const diagramViewerTemplate = "diagram-viewer.html.tmpl"
+
+// diagramViewer is what the interactive diagram viewer gets to render: the SVG diagrams along with the trust boundaries,
+// tags and severities to filter by and, for the script doing the filtering, the elements of the diagrams
+
+type diagramViewer struct {
+	Title           string
+	ReportFilename  string
+	Diagrams        []*diagramViewerDiagram
+	TrustBoundaries []*diagramViewerOption
+	Tags            []string
+	Severities      []*diagramViewerOption
+	Elements        *diagramViewerElements
+}
+
+type diagramViewerDiagram struct {
+	Title  string
+	Prefix string
+	SVG    htmltemplate.HTML
+}
+
+type diagramViewerOption struct {
+	Id    string
+	Title string
+}
+
+type diagramViewerElements struct {
+	TechnicalAssets []*diagramViewerTechnicalAsset `json:"technical_assets"`
+	DataAssets      []*diagramViewerDataAsset      `json:"data_assets"`
+	Edges           []*diagramViewerEdge           `json:"edges"`
+}
+
+type diagramViewerTechnicalAsset struct {
+	Id              string   `json:"id"`
+	TrustBoundaries []string `json:"trust_boundaries"`
+	Tags            []string `json:"tags"`
+	Severity        int      `json:"severity"` // of the highest open risk, or -1 without open risks
+}
+
+type diagramViewerDataAsset struct {
+	Id              string   `json:"id"`
+	Tags            []string `json:"tags"`
+	TechnicalAssets []string `json:"technical_assets"`
+}
+
+type diagramViewerEdge struct {
+	Id     string `json:"id"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// WriteDiagramViewer writes a standalone HTML page with the data flow and data asset diagrams rendered as SVG, in which
+// the diagram elements can be filtered by trust boundary, tag and open risk severity
+
+func WriteDiagramViewer(config *common.Config, readResult *model.ReadResult, reportFilename string, filename string) error {
+	parsedModel := readResult.ParsedModel
+	viewer := &diagramViewer{
+		Title:           parsedModel.Title,
+		ReportFilename:  reportFilename,
+		Diagrams:        make([]*diagramViewerDiagram, 0),
+		TrustBoundaries: make([]*diagramViewerOption, 0),
+		Tags:            make([]string, 0),
+		Severities:      make([]*diagramViewerOption, 0),
+		Elements:        newDiagramViewerElements(parsedModel),
+	}
+
+	if diagram := readStaticDiagram("Data-Flow Diagram", config.OutputFolder, config.DataFlowDiagramFilenameSVG); diagram != nil {
+		viewer.Diagrams = append(viewer.Diagrams, &diagramViewerDiagram{Title: diagram.Title, Prefix: "data-flow-", SVG: diagram.SVG})
+	}
+	if diagram := readStaticDiagram("Data Mapping", config.OutputFolder, config.DataAssetDiagramFilenameSVG); diagram != nil {
+		viewer.Diagrams = append(viewer.Diagrams, &diagramViewerDiagram{Title: diagram.Title, Prefix: "data-asset-diagram-", SVG: diagram.SVG})
+	}
+	if len(viewer.Diagrams) == 0 {
+		return fmt.Errorf("no diagrams rendered as svg to view")
+	}
+
+	for _, id := range types.SortedKeysOfTrustBoundaries(parsedModel) {
+		viewer.TrustBoundaries = append(viewer.TrustBoundaries, &diagramViewerOption{Id: id, Title: parsedModel.TrustBoundaries[id].Title})
+	}
+	sort.SliceStable(viewer.TrustBoundaries, func(i, j int) bool {
+		return viewer.TrustBoundaries[i].Title < viewer.TrustBoundaries[j].Title
+	})
+
+	tags := make(map[string]bool)
+	for _, techAsset := range viewer.Elements.TechnicalAssets {
+		for _, tag := range techAsset.Tags {
+			tags[tag] = true
+		}
+	}
+	for _, dataAsset := range viewer.Elements.DataAssets {
+		for _, tag := range dataAsset.Tags {
+			tags[tag] = true
+		}
+	}
+	for tag := range tags {
+		viewer.Tags = append(viewer.Tags, tag)
+	}
+	sort.Strings(viewer.Tags)
+
+	values := types.RiskSeverityValues()
+	for n := len(values) - 1; n >= 0; n-- {
+		severity := values[n].(types.RiskSeverity)
+		viewer.Severities = append(viewer.Severities, &diagramViewerOption{Id: fmt.Sprintf("%d", int(severity)), Title: severity.Title()})
+	}
+
+	tmpl, err := htmltemplate.New(diagramViewerTemplate).ParseFS(reportTemplates, "templates/"+diagramViewerTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse diagram viewer template: %w", err)
+	}
+
+	if override := reportTemplateOverride(config, diagramViewerTemplate); len(override) > 0 {
+		tmpl, err = tmpl.ParseFiles(override)
+		if err != nil {
+			return fmt.Errorf("failed to parse diagram viewer template %q: %w", override, err)
+		}
+	}
+
+	var page bytes.Buffer
+	err = tmpl.Execute(&page, viewer)
+	if err != nil {
+		return fmt.Errorf("failed to render diagram viewer: %w", err)
+	}
+
+	err = os.WriteFile(filename, page.Bytes(), 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write diagram viewer: %w", err)
+	}
+	return nil
+}
+
+func newDiagramViewerElements(parsedModel *types.Model) *diagramViewerElements {
+	elements := &diagramViewerElements{
+		TechnicalAssets: make([]*diagramViewerTechnicalAsset, 0),
+		DataAssets:      make([]*diagramViewerDataAsset, 0),
+		Edges:           make([]*diagramViewerEdge, 0),
+	}
+
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		techAsset := parsedModel.TechnicalAssets[id]
+		element := &diagramViewerTechnicalAsset{
+			Id:              technicalAssetAnchor(id),
+			TrustBoundaries: make([]string, 0),
+			Tags:            append(make([]string, 0), techAsset.Tags...),
+			Severity:        -1,
+		}
+		if trustBoundary, ok := parsedModel.DirectContainingTrustBoundaryMappedByTechnicalAssetId[id]; ok {
+			element.TrustBoundaries = trustBoundary.AllParentTrustBoundaryIDs(parsedModel)
+		}
+		if severity, ok := highestOpenRiskSeverity(parsedModel, techAsset.GeneratedRisks(parsedModel)); ok {
+			element.Severity = int(severity)
+		}
+		elements.TechnicalAssets = append(elements.TechnicalAssets, element)
+
+		for _, link := range techAsset.CommunicationLinksSorted() {
+			elements.Edges = append(elements.Edges, &diagramViewerEdge{
+				Id:     "communication-link-" + link.Id,
+				Source: technicalAssetAnchor(link.SourceId),
+				Target: technicalAssetAnchor(link.TargetId),
+			})
+		}
+
+		for _, dataAssetId := range techAsset.DataAssetsProcessed {
+			elements.Edges = append(elements.Edges, &diagramViewerEdge{
+				Id:     "data-asset-" + dataAssetId + "-at-" + id,
+				Source: dataAssetAnchor(dataAssetId),
+				Target: technicalAssetAnchor(id),
+			})
+		}
+		for _, dataAssetId := range techAsset.DataAssetsStored {
+			if !contains(techAsset.DataAssetsProcessed, dataAssetId) {
+				elements.Edges = append(elements.Edges, &diagramViewerEdge{
+					Id:     "data-asset-" + dataAssetId + "-at-" + id,
+					Source: dataAssetAnchor(dataAssetId),
+					Target: technicalAssetAnchor(id),
+				})
+			}
+		}
+	}
+
+	dataAssets := make([]*types.DataAsset, 0)
+	for _, dataAsset := range parsedModel.DataAssets {
+		dataAssets = append(dataAssets, dataAsset)
+	}
+	sort.Sort(types.ByDataAssetTitleSort(dataAssets))
+	for _, dataAsset := range dataAssets {
+		element := &diagramViewerDataAsset{
+			Id:              dataAssetAnchor(dataAsset.Id),
+			Tags:            append(make([]string, 0), dataAsset.Tags...),
+			TechnicalAssets: make([]string, 0),
+		}
+		for _, techAsset := range dataAsset.ProcessedByTechnicalAssetsSorted(parsedModel) {
+			element.TechnicalAssets = append(element.TechnicalAssets, technicalAssetAnchor(techAsset.Id))
+		}
+		for _, techAsset := range dataAsset.StoredByTechnicalAssetsSorted(parsedModel) {
+			if !contains(element.TechnicalAssets, technicalAssetAnchor(techAsset.Id)) {
+				element.TechnicalAssets = append(element.TechnicalAssets, technicalAssetAnchor(techAsset.Id))
+			}
+		}
+		elements.DataAssets = append(elements.DataAssets, element)
+	}
+
+	return elements
+}
@@ -0,0 +1,168 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/model"
+)
+
+const (
+	DiffFormatText     = "text"
+	DiffFormatJSON     = "json"
+	DiffFormatMarkdown = "markdown"
+)
+
+// diffSections are the element kinds of a model diff with their titles, in the order they are written
+
+var diffSections = []struct {
+	title string
+	items func(diff *model.ModelDiff) []*model.ItemDiff
+}{
+	{"Technical assets", func(diff *model.ModelDiff) []*model.ItemDiff { return diff.TechnicalAssets }},
+	{"Communication links", func(diff *model.ModelDiff) []*model.ItemDiff { return diff.CommunicationLinks }},
+	{"Data assets", func(diff *model.ModelDiff) []*model.ItemDiff { return diff.DataAssets }},
+	{"Trust boundaries", func(diff *model.ModelDiff) []*model.ItemDiff { return diff.TrustBoundaries }},
+}
+
+var riskDiffSections = []struct {
+	title  string
+	change string
+}{
+	{"New risks", model.RiskDiffNew},
+	{"Resolved risks", model.RiskDiffResolved},
+	{"Risks of changed severity", model.RiskDiffSeverityChanged},
+}
+
+// FormatDiff writes the model diff in the given format: text, JSON or Markdown (for pull request comments)
+
+func FormatDiff(diff *model.ModelDiff, format string) ([]byte, error) {
+	switch strings.ToLower(format) {
+	case DiffFormatText, "":
+		return []byte(diffText(diff)), nil
+
+	case DiffFormatJSON:
+		return json.MarshalIndent(diff, "", "  ")
+
+	case DiffFormatMarkdown, "md":
+		return []byte(diffMarkdown(diff)), nil
+
+	default:
+		return nil, fmt.Errorf("unknown diff format %q, expected %v, %v or %v", format, DiffFormatText, DiffFormatJSON, DiffFormatMarkdown)
+	}
+}
+
+func diffText(diff *model.ModelDiff) string {
+	var text strings.Builder
+	if diff.IsEmpty() {
+		text.WriteString("No changes\n")
+		return text.String()
+	}
+
+	for _, section := range riskDiffSections {
+		risks := diff.RisksByChange(section.change)
+		if len(risks) == 0 {
+			continue
+		}
+
+		_, _ = fmt.Fprintf(&text, "%v (%d):\n", section.title, len(risks))
+		for _, risk := range risks {
+			_, _ = fmt.Fprintf(&text, "  %-8v %v: %v\n", diffRiskSeverity(risk), risk.SyntheticId, removeFormattingTags(risk.Title))
+		}
+		text.WriteString("\n")
+	}
+
+	for _, section := range diffSections {
+		items := section.items(diff)
+		if len(items) == 0 {
+			continue
+		}
+
+		_, _ = fmt.Fprintf(&text, "%v (%d):\n", section.title, len(items))
+		for _, item := range items {
+			_, _ = fmt.Fprintf(&text, "  %-8v %v (%v)\n", item.Change, item.Title, item.Id)
+			for _, field := range item.Fields {
+				_, _ = fmt.Fprintf(&text, "           %v: %q -> %q\n", field.Field, field.Old, field.New)
+			}
+		}
+		text.WriteString("\n")
+	}
+
+	return strings.TrimSuffix(text.String(), "\n")
+}
+
+func diffMarkdown(diff *model.ModelDiff) string {
+	var text strings.Builder
+	text.WriteString("## Threat model changes\n\n")
+	if diff.IsEmpty() {
+		text.WriteString("No changes to the model or its risks.\n")
+		return text.String()
+	}
+
+	summary := make([]string, 0)
+	for _, section := range riskDiffSections {
+		if count := len(diff.RisksByChange(section.change)); count > 0 {
+			summary = append(summary, fmt.Sprintf("%v: %d", section.title, count))
+		}
+	}
+	for _, section := range diffSections {
+		if count := len(section.items(diff)); count > 0 {
+			summary = append(summary, fmt.Sprintf("%v changed: %d", section.title, count))
+		}
+	}
+	text.WriteString(strings.Join(summary, " · ") + "\n\n")
+
+	for _, section := range riskDiffSections {
+		risks := diff.RisksByChange(section.change)
+		if len(risks) == 0 {
+			continue
+		}
+
+		_, _ = fmt.Fprintf(&text, "### %v\n\n", section.title)
+		text.WriteString("| Severity | Risk | Status | Synthetic ID |\n|---|---|---|---|\n")
+		for _, risk := range risks {
+			_, _ = fmt.Fprintf(&text, "| %v | %v | %v | `%v` |\n", diffRiskSeverity(risk), markdownCell(risk.Title), risk.Status, risk.SyntheticId)
+		}
+		text.WriteString("\n")
+	}
+
+	for _, section := range diffSections {
+		items := section.items(diff)
+		if len(items) == 0 {
+			continue
+		}
+
+		_, _ = fmt.Fprintf(&text, "### %v\n\n", section.title)
+		for _, item := range items {
+			_, _ = fmt.Fprintf(&text, "- **%v** %v (`%v`)\n", item.Change, markdownCell(item.Title), item.Id)
+			for _, field := range item.Fields {
+				_, _ = fmt.Fprintf(&text, "  - `%v`: %v → %v\n", field.Field, markdownValue(field.Old), markdownValue(field.New))
+			}
+		}
+		text.WriteString("\n")
+	}
+
+	return strings.TrimSuffix(text.String(), "\n")
+}
+
+// diffRiskSeverity returns the severity of the risk, or its change like "medium -> high"
+
+func diffRiskSeverity(risk *model.RiskDiff) string {
+	switch {
+	case len(risk.OldSeverity) == 0:
+		return risk.NewSeverity
+	case len(risk.NewSeverity) == 0:
+		return risk.OldSeverity
+	default:
+		return risk.OldSeverity + " -> " + risk.NewSeverity
+	}
+}
+
+func markdownValue(value string) string {
+	if len(value) == 0 {
+		return "_empty_"
+	}
+	value = strings.ReplaceAll(strings.Join(strings.Fields(value), " "), "`", "'")
+	return "`" + value + "`"
+}
@@ -29,6 +29,9 @@ func (what *ExcelColumns) GetColumns() ExcelColumns {
 		"R": {Title: "Date", Width: 18},
 		"S": {Title: "Checked by", Width: 20},
 		"T": {Title: "Ticket", Width: 20},
+		"U": {Title: "Owner", Width: 20},
+		"V": {Title: "Expires", Width: 18},
+		"W": {Title: "Review by", Width: 18},
 	}
 
 	return *what
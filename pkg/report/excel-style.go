@@ -496,10 +496,10 @@ func (what *ExcelStyles) Get(column string, status types.RiskStatus, severity ty
 	case "Q":
 		return what.blackSmall
 
-	case "R", "S":
+	case "R", "S", "V", "W":
 		return what.blackCenter
 
-	case "T":
+	case "T", "U":
 		return what.blackLeft
 	}
 
@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 	"unicode/utf8"
 )
 
@@ -97,11 +98,7 @@ func WriteRisksExcelToFile(parsedModel *types.Model, filename string, config *co
 				commLinkTitle = commLink.Title
 			}
 
-			date := ""
 			riskTracking := risk.GetRiskTrackingWithDefault(parsedModel)
-			if !riskTracking.Date.IsZero() {
-				date = riskTracking.Date.Format("2006-01-02")
-			}
 
 			riskItems = append(riskItems, RiskItem{
 				Columns: []string{
@@ -122,9 +119,12 @@ func WriteRisksExcelToFile(parsedModel *types.Model, filename string, config *co
 					risk.SyntheticId,
 					riskTracking.Status.Title(),
 					riskTracking.Justification,
-					date,
+					excelDate(riskTracking.Date),
 					riskTracking.CheckedBy,
 					riskTracking.Ticket,
+					riskTracking.Owner,
+					excelDate(riskTracking.Expires),
+					excelDate(riskTracking.ReviewBy),
 				},
 				Status:   riskTracking.Status,
 				Severity: risk.Severity,
@@ -145,7 +145,7 @@ func WriteRisksExcelToFile(parsedModel *types.Model, filename string, config *co
 	}
 
 	// set header style
-	setCellStyleError := excel.SetCellStyle(sheetName, "A1", "T1", cellStyles.headCenterBoldItalic)
+	setCellStyleError := excel.SetCellStyle(sheetName, "A1", "W1", cellStyles.headCenterBoldItalic)
 	if setCellStyleError != nil {
 		return fmt.Errorf("unable to set cell style: %w", setCellStyleError)
 	}
@@ -233,6 +233,12 @@ func WriteRisksExcelToFile(parsedModel *types.Model, filename string, config *co
 		return fmt.Errorf("unable to freeze header: %w", freezeError)
 	}
 
+	// list risk tracking expiring or to be reviewed soon
+	dueError := writeRiskTrackingDueSheet(excel, parsedModel, cellStyles, time.Now())
+	if dueError != nil {
+		return fmt.Errorf("unable to write expiring risk tracking: %w", dueError)
+	}
+
 	excel.SetActiveSheet(sheetIndex)
 
 	// save file
@@ -244,6 +250,109 @@ func WriteRisksExcelToFile(parsedModel *types.Model, filename string, config *co
 	return nil
 }
 
+// writeRiskTrackingDueSheet adds a sheet listing the risks whose tracking expires or is to be reviewed within
+// types.RiskTrackingDueSoonDays, or is overdue, if there are any
+
+func writeRiskTrackingDueSheet(excel *excelize.File, parsedModel *types.Model, cellStyles *ExcelStyles, today time.Time) error {
+	risks := parsedModel.RisksWithTrackingDueWithin(today, types.RiskTrackingDueSoonDays)
+	if len(risks) == 0 {
+		return nil
+	}
+
+	sheetName := "Expiring Soon"
+	_, newSheetError := excel.NewSheet(sheetName)
+	if newSheetError != nil {
+		return fmt.Errorf("failed to add sheet: %w", newSheetError)
+	}
+
+	columns := []ExcelColumn{
+		{Title: "Due", Width: 18},
+		{Title: "State", Width: 16},
+		{Title: "Severity", Width: 12},
+		{Title: "Identified Risk", Width: 75},
+		{Title: "ID", Width: 10},
+		{Title: "Status", Width: 18},
+		{Title: "Owner", Width: 20},
+		{Title: "Expires", Width: 18},
+		{Title: "Review by", Width: 18},
+		{Title: "Ticket", Width: 20},
+	}
+	for columnIndex, column := range columns {
+		columnName, columnNameError := excelize.ColumnNumberToName(columnIndex + 1)
+		if columnNameError != nil {
+			return columnNameError
+		}
+
+		setCellValueError := excel.SetCellValue(sheetName, columnName+"1", column.Title)
+		if setCellValueError != nil {
+			return fmt.Errorf("unable to set cell value: %w", setCellValueError)
+		}
+
+		setColWidthError := excel.SetColWidth(sheetName, columnName, columnName, column.Width)
+		if setColWidthError != nil {
+			return setColWidthError
+		}
+	}
+
+	setCellStyleError := excel.SetCellStyle(sheetName, "A1", "J1", cellStyles.headCenterBoldItalic)
+	if setCellStyleError != nil {
+		return fmt.Errorf("unable to set cell style: %w", setCellStyleError)
+	}
+
+	for riskIndex, risk := range risks {
+		tracking := risk.GetRiskTracking(parsedModel)
+		state, stateStyle := "due soon", cellStyles.orangeCenter
+		switch {
+		case tracking.IsExpired(today):
+			state, stateStyle = "expired", cellStyles.redCenter
+		case tracking.IsOverdue(today):
+			state, stateStyle = "review overdue", cellStyles.redCenter
+		}
+
+		values := []string{
+			tracking.DueDate().Format("2006-01-02"),
+			state,
+			risk.Severity.Title(),
+			removeFormattingTags(risk.Title),
+			risk.SyntheticId,
+			tracking.Status.Title(),
+			tracking.Owner,
+			excelDate(tracking.Expires),
+			excelDate(tracking.ReviewBy),
+			tracking.Ticket,
+		}
+		styles := []int{cellStyles.blackCenter, stateStyle, cellStyles.blackCenter, cellStyles.blackSmall, cellStyles.graySmall,
+			cellStyles.blackCenter, cellStyles.blackLeft, cellStyles.blackCenter, cellStyles.blackCenter, cellStyles.blackLeft}
+
+		for columnIndex, value := range values {
+			cellName, coordinateError := excelize.CoordinatesToCellName(columnIndex+1, riskIndex+2)
+			if coordinateError != nil {
+				return coordinateError
+			}
+
+			setCellValueError := excel.SetCellValue(sheetName, cellName, value)
+			if setCellValueError != nil {
+				return fmt.Errorf("unable to set cell value: %w", setCellValueError)
+			}
+
+			setCellStyleError = excel.SetCellStyle(sheetName, cellName, cellName, styles[columnIndex])
+			if setCellStyleError != nil {
+				return fmt.Errorf("unable to set cell style: %w", setCellStyleError)
+			}
+		}
+	}
+
+	return nil
+}
+
+func excelDate(date types.Date) string {
+	if date.IsZero() {
+		return ""
+	}
+
+	return date.Format("2006-01-02")
+}
+
 func WriteTagsExcelToFile(parsedModel *types.Model, filename string) error { // TODO: eventually when len(sortedTagsAvailable) == 0 is: write a hint in the Excel that no tags are used
 	excelRow := 0
 	excel := excelize.NewFile()
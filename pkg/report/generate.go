@@ -16,11 +16,21 @@ type GenerateCommands struct {
 	DataFlowDiagram     bool
 	DataAssetDiagram    bool
 	RisksJSON           bool
+	RisksSARIF          bool
 	TechnicalAssetsJSON bool
 	StatsJSON           bool
+	AttackPathsJSON     bool
+	AttackPathDiagram   bool
+	DiagramSVG          bool
+	DiagramViewer       bool
+	DiagramMermaid      bool
+	DiagramPlantUML     bool
+	DiagramDrawIO       bool
 	RisksExcel          bool
 	TagsExcel           bool
 	ReportPDF           bool
+	ReportMarkdown      bool
+	ReportHTML          bool
 }
 
 func (c *GenerateCommands) Defaults() *GenerateCommands {
@@ -28,11 +38,21 @@ func (c *GenerateCommands) Defaults() *GenerateCommands {
 		DataFlowDiagram:     true,
 		DataAssetDiagram:    true,
 		RisksJSON:           true,
+		RisksSARIF:          false,
 		TechnicalAssetsJSON: true,
 		StatsJSON:           true,
+		AttackPathsJSON:     false,
+		AttackPathDiagram:   false,
+		DiagramSVG:          false,
+		DiagramViewer:       false,
+		DiagramMermaid:      false,
+		DiagramPlantUML:     false,
+		DiagramDrawIO:       false,
 		RisksExcel:          true,
 		TagsExcel:           true,
 		ReportPDF:           true,
+		ReportMarkdown:      false,
+		ReportHTML:          false,
 	}
 	return c
 }
@@ -40,10 +60,29 @@ func (c *GenerateCommands) Defaults() *GenerateCommands {
 func Generate(config *common.Config, readResult *model.ReadResult, commands *GenerateCommands, progressReporter progressReporter) error {
 	generateDataFlowDiagram := commands.DataFlowDiagram
 	generateDataAssetsDiagram := commands.DataAssetDiagram
-	if commands.ReportPDF { // as the PDF report includes both diagrams
+	generateAttackPathDiagram := commands.AttackPathDiagram
+	if commands.ReportPDF || commands.ReportMarkdown || commands.ReportHTML { // as the reports include both diagrams
 		generateDataFlowDiagram = true
 		generateDataAssetsDiagram = true
 	}
+	if commands.DiagramViewer { // as the viewer shows both of them
+		generateDataFlowDiagram = true
+		generateDataAssetsDiagram = true
+	}
+	generateDiagramSVG := commands.DiagramSVG || commands.DiagramViewer || commands.ReportMarkdown || commands.ReportHTML
+	reportLink := ""
+	if commands.ReportHTML { // the diagram nodes link to the asset sections of the HTML report
+		reportLink = config.ReportHTMLFilename
+	}
+
+	if (generateAttackPathDiagram || commands.AttackPathsJSON) && readResult.AttackPaths == nil {
+		progressReporter.Info("Finding attack paths")
+		var complete bool
+		readResult.AttackPaths, complete = model.FindAttackPaths(readResult.ParsedModel, config.AttackPaths)
+		if !complete {
+			progressReporter.Warn(fmt.Errorf("attack path search stopped after %d steps, paths may be missing", config.AttackPaths.StepLimit()))
+		}
+	}
 
 	diagramDPI := config.DiagramDPI
 	if diagramDPI < common.MinGraphvizDPI {
@@ -51,6 +90,16 @@ func Generate(config *common.Config, readResult *model.ReadResult, commands *Gen
 	} else if diagramDPI > common.MaxGraphvizDPI {
 		diagramDPI = common.MaxGraphvizDPI
 	}
+	builtinRenderer, err := UseBuiltinDiagramRenderer(config.DiagramRenderer)
+	if err != nil {
+		return err
+	}
+	svgFilename := func(filename string) string { // of the svg diagram, if any, for the built-in renderer
+		if !generateDiagramSVG {
+			return ""
+		}
+		return filepath.Join(config.OutputFolder, filename)
+	}
 	// Data-flow Diagram rendering
 	if generateDataFlowDiagram {
 		gvFile := filepath.Join(config.OutputFolder, config.DataFlowDiagramFilenameDOT)
@@ -62,15 +111,29 @@ func Generate(config *common.Config, readResult *model.ReadResult, commands *Gen
 			gvFile = tmpFileGV.Name()
 			defer func() { _ = os.Remove(gvFile) }()
 		}
-		dotFile, err := WriteDataFlowDiagramGraphvizDOT(readResult.ParsedModel, gvFile, diagramDPI, config.AddModelTitle, progressReporter)
+		dotFile, err := WriteDataFlowDiagramGraphvizDOT(readResult.ParsedModel, gvFile, diagramDPI, config.AddModelTitle, reportLink, progressReporter)
 		if err != nil {
 			return fmt.Errorf("error while generating data flow diagram: %s", err)
 		}
 
-		err = GenerateDataFlowDiagramGraphvizImage(dotFile, config.OutputFolder,
-			config.TempFolder, config.DataFlowDiagramFilenamePNG, progressReporter, config.KeepDiagramSourceFiles)
-		if err != nil {
-			progressReporter.Warn(err)
+		if builtinRenderer {
+			err = RenderDataFlowDiagram(readResult.ParsedModel, filepath.Join(config.OutputFolder, config.DataFlowDiagramFilenamePNG),
+				svgFilename(config.DataFlowDiagramFilenameSVG), diagramDPI, config.AddModelTitle, reportLink, progressReporter)
+			if err != nil {
+				progressReporter.Warn(err)
+			}
+		} else {
+			err = GenerateDataFlowDiagramGraphvizImage(dotFile, config.OutputFolder,
+				config.TempFolder, config.DataFlowDiagramFilenamePNG, progressReporter, config.KeepDiagramSourceFiles)
+			if err != nil {
+				progressReporter.Warn(err)
+			}
+			if generateDiagramSVG {
+				err = GenerateGraphvizSVG(dotFile, config.OutputFolder, config.DataFlowDiagramFilenameSVG, progressReporter)
+				if err != nil {
+					progressReporter.Warn(err)
+				}
+			}
 		}
 	}
 	// Data Asset Diagram rendering
@@ -84,14 +147,87 @@ func Generate(config *common.Config, readResult *model.ReadResult, commands *Gen
 			gvFile = tmpFile.Name()
 			defer func() { _ = os.Remove(gvFile) }()
 		}
-		dotFile, err := WriteDataAssetDiagramGraphvizDOT(readResult.ParsedModel, gvFile, diagramDPI, progressReporter)
+		dotFile, err := WriteDataAssetDiagramGraphvizDOT(readResult.ParsedModel, gvFile, diagramDPI, reportLink, progressReporter)
 		if err != nil {
 			return fmt.Errorf("error while generating data asset diagram: %s", err)
 		}
-		err = GenerateDataAssetDiagramGraphvizImage(dotFile, config.OutputFolder,
-			config.TempFolder, config.DataAssetDiagramFilenamePNG, progressReporter)
+		if builtinRenderer {
+			err = RenderDataAssetDiagram(readResult.ParsedModel, filepath.Join(config.OutputFolder, config.DataAssetDiagramFilenamePNG),
+				svgFilename(config.DataAssetDiagramFilenameSVG), diagramDPI, reportLink, progressReporter)
+			if err != nil {
+				progressReporter.Warn(err)
+			}
+		} else {
+			err = GenerateDataAssetDiagramGraphvizImage(dotFile, config.OutputFolder,
+				config.TempFolder, config.DataAssetDiagramFilenamePNG, progressReporter)
+			if err != nil {
+				progressReporter.Warn(err)
+			}
+			if generateDiagramSVG {
+				err = GenerateGraphvizSVG(dotFile, config.OutputFolder, config.DataAssetDiagramFilenameSVG, progressReporter)
+				if err != nil {
+					progressReporter.Warn(err)
+				}
+			}
+		}
+	}
+
+	// Attack Path Diagram rendering
+	if generateAttackPathDiagram {
+		gvFile := filepath.Join(config.OutputFolder, config.AttackPathDiagramFilenameDOT)
+		if !config.KeepDiagramSourceFiles {
+			tmpFile, err := os.CreateTemp(config.TempFolder, config.AttackPathDiagramFilenameDOT)
+			if err != nil {
+				return err
+			}
+			gvFile = tmpFile.Name()
+			defer func() { _ = os.Remove(gvFile) }()
+		}
+		dotFile, err := WriteAttackPathDiagramGraphvizDOT(readResult.ParsedModel, readResult.AttackPaths, gvFile, diagramDPI, config.AddModelTitle, reportLink, progressReporter)
 		if err != nil {
-			progressReporter.Warn(err)
+			return fmt.Errorf("error while generating attack path diagram: %s", err)
+		}
+		if builtinRenderer {
+			err = RenderAttackPathDiagram(readResult.ParsedModel, readResult.AttackPaths, filepath.Join(config.OutputFolder, config.AttackPathDiagramFilenamePNG),
+				svgFilename(config.AttackPathDiagramFilenameSVG), diagramDPI, config.AddModelTitle, reportLink, progressReporter)
+			if err != nil {
+				progressReporter.Warn(err)
+			}
+		} else {
+			err = GenerateDataFlowDiagramGraphvizImage(dotFile, config.OutputFolder,
+				config.TempFolder, config.AttackPathDiagramFilenamePNG, progressReporter, config.KeepDiagramSourceFiles)
+			if err != nil {
+				progressReporter.Warn(err)
+			}
+			if generateDiagramSVG {
+				err = GenerateGraphvizSVG(dotFile, config.OutputFolder, config.AttackPathDiagramFilenameSVG, progressReporter)
+				if err != nil {
+					progressReporter.Warn(err)
+				}
+			}
+		}
+	}
+
+	// Data-flow Diagram exports for wikis and diagram editors
+	if commands.DiagramMermaid {
+		progressReporter.Info("Writing data flow diagram mermaid")
+		err := WriteDataFlowDiagramMermaid(readResult.ParsedModel, filepath.Join(config.OutputFolder, config.DataFlowDiagramFilenameMermaid))
+		if err != nil {
+			return fmt.Errorf("error while writing data flow diagram mermaid: %s", err)
+		}
+	}
+	if commands.DiagramPlantUML {
+		progressReporter.Info("Writing data flow diagram plantuml")
+		err := WriteDataFlowDiagramPlantUML(readResult.ParsedModel, filepath.Join(config.OutputFolder, config.DataFlowDiagramFilenamePlantUML))
+		if err != nil {
+			return fmt.Errorf("error while writing data flow diagram plantuml: %s", err)
+		}
+	}
+	if commands.DiagramDrawIO {
+		progressReporter.Info("Writing data flow diagram draw.io")
+		err := WriteDataFlowDiagramDrawIO(readResult.ParsedModel, filepath.Join(config.OutputFolder, config.DataFlowDiagramFilenameDrawIO))
+		if err != nil {
+			return fmt.Errorf("error while writing data flow diagram draw.io: %s", err)
 		}
 	}
 
@@ -104,6 +240,15 @@ func Generate(config *common.Config, readResult *model.ReadResult, commands *Gen
 		}
 	}
 
+	// risks as SARIF for code scanning
+	if commands.RisksSARIF {
+		progressReporter.Info("Writing risks sarif")
+		err := WriteRisksSARIF(readResult.ParsedModel, config.InputFile, filepath.Join(config.OutputFolder, config.SarifRisksFilename))
+		if err != nil {
+			return fmt.Errorf("error while writing risks sarif: %s", err)
+		}
+	}
+
 	// technical assets json
 	if commands.TechnicalAssetsJSON {
 		progressReporter.Info("Writing technical assets json")
@@ -122,6 +267,15 @@ func Generate(config *common.Config, readResult *model.ReadResult, commands *Gen
 		}
 	}
 
+	// attack paths json
+	if commands.AttackPathsJSON {
+		progressReporter.Info("Writing attack paths json")
+		err := WriteAttackPathsJSON(readResult.AttackPaths, filepath.Join(config.OutputFolder, config.JsonAttackPathsFilename))
+		if err != nil {
+			return fmt.Errorf("error while writing attack paths json: %s", err)
+		}
+	}
+
 	// risks Excel
 	if commands.RisksExcel {
 		progressReporter.Info("Writing risks excel")
@@ -155,16 +309,26 @@ func Generate(config *common.Config, readResult *model.ReadResult, commands *Gen
 		// report PDF
 		progressReporter.Info("Writing report pdf")
 
+		raaAlgorithm, raaParameters := "", make([]string, 0)
+		if readResult.RAACalculator != nil {
+			raaAlgorithm, raaParameters = readResult.RAACalculator.Name(), readResult.RAACalculator.Parameters()
+		}
+
 		pdfReporter := pdfReporter{}
 		err = pdfReporter.WriteReportPDF(filepath.Join(config.OutputFolder, config.ReportFilename),
 			filepath.Join(config.AppFolder, config.TemplateFilename),
 			filepath.Join(config.OutputFolder, config.DataFlowDiagramFilenamePNG),
 			filepath.Join(config.OutputFolder, config.DataAssetDiagramFilenamePNG),
+			filepath.Join(config.OutputFolder, config.AttackPathDiagramFilenamePNG),
 			config.InputFile,
 			config.SkipRiskRules,
 			config.BuildTimestamp,
 			modelHash,
 			readResult.IntroTextRAA,
+			raaAlgorithm,
+			raaParameters,
+			readResult.AttackPaths,
+			readResult.BuiltinRiskRules,
 			readResult.CustomRiskRules,
 			config.TempFolder,
 			readResult.ParsedModel)
@@ -173,6 +337,30 @@ func Generate(config *common.Config, readResult *model.ReadResult, commands *Gen
 		}
 	}
 
+	if commands.DiagramViewer {
+		progressReporter.Info("Writing diagram viewer")
+		err := WriteDiagramViewer(config, readResult, reportLink, filepath.Join(config.OutputFolder, config.DiagramViewerFilename))
+		if err != nil {
+			progressReporter.Warn(fmt.Errorf("error while writing diagram viewer: %s", err))
+		}
+	}
+
+	if commands.ReportMarkdown {
+		progressReporter.Info("Writing report markdown")
+		err := WriteReportMarkdown(config, readResult, filepath.Join(config.OutputFolder, config.ReportMarkdownFilename))
+		if err != nil {
+			return fmt.Errorf("error while writing report markdown: %s", err)
+		}
+	}
+
+	if commands.ReportHTML {
+		progressReporter.Info("Writing report html")
+		err := WriteReportHTML(config, readResult, filepath.Join(config.OutputFolder, config.ReportHTMLFilename))
+		if err != nil {
+			return fmt.Errorf("error while writing report html: %s", err)
+		}
+	}
+
 	return nil
 }
 
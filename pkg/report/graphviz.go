@@ -11,14 +11,41 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/threagile/threagile/pkg/model"
 	"github.com/threagile/threagile/pkg/security/types"
 )
 
+// WriteDataFlowDiagramGraphvizDOT writes the data flow diagram; when rendered as SVG, the technical assets link to their
+// sections of the HTML report given by reportFilename (if any) and show their highest open risk severity as tooltip
+
 func WriteDataFlowDiagramGraphvizDOT(parsedModel *types.Model,
-	diagramFilenameDOT string, dpi int, addModelTitle bool,
+	diagramFilenameDOT string, dpi int, addModelTitle bool, reportFilename string,
 	progressReporter progressReporter) (*os.File, error) {
 	progressReporter.Info("Writing data flow diagram input")
+	links := &diagramLinks{reportFilename: reportFilename, idPrefix: "data-flow-"}
+	return writeDataFlowDiagramGraphvizDOT(parsedModel, diagramFilenameDOT, dpi, addModelTitle, links, nil)
+}
+
+// WriteAttackPathDiagramGraphvizDOT writes the data flow diagram with the technical assets and communication links of
+// the attack paths highlighted
+
+func WriteAttackPathDiagramGraphvizDOT(parsedModel *types.Model, attackPaths []*model.AttackPath,
+	diagramFilenameDOT string, dpi int, addModelTitle bool, reportFilename string,
+	progressReporter progressReporter) (*os.File, error) {
+	progressReporter.Info("Writing attack path diagram input")
+	links := &diagramLinks{reportFilename: reportFilename, idPrefix: "attack-path-"}
+	return writeDataFlowDiagramGraphvizDOT(parsedModel, diagramFilenameDOT, dpi, addModelTitle, links, newDiagramHighlight(attackPaths))
+}
+
+// diagramHighlight holds the technical assets and communication links to emphasize in a data flow diagram
 
+type diagramHighlight struct {
+	technicalAssetIds    map[string]bool
+	communicationLinkIds map[string]bool
+}
+
+func writeDataFlowDiagramGraphvizDOT(parsedModel *types.Model,
+	diagramFilenameDOT string, dpi int, addModelTitle bool, links *diagramLinks, highlight *diagramHighlight) (*os.File, error) {
 	var dotContent strings.Builder
 	dotContent.WriteString("digraph generatedModel { concentrate=false \n")
 
@@ -202,6 +229,12 @@ func WriteDataFlowDiagramGraphvizDOT(parsedModel *types.Model,
 	for _, technicalAsset := range techAssets {
 		dotContent.WriteString(makeTechAssetNode(parsedModel, technicalAsset, false))
 		dotContent.WriteString("\n")
+		dotContent.WriteString(links.technicalAssetNode(parsedModel, technicalAsset))
+		dotContent.WriteString("\n")
+		if highlight != nil && highlight.technicalAssetIds[technicalAsset.Id] {
+			dotContent.WriteString("  " + hash(technicalAsset.Id) + ` [ color="` + Red + `" penwidth="8.0" ];`)
+			dotContent.WriteString("\n")
+		}
 	}
 
 	// Data Flows (Technical Communication Links) ===============================================================================
@@ -230,10 +263,18 @@ func WriteDataFlowDiagramGraphvizDOT(parsedModel *types.Model,
 			if dataFlow.DiagramTweakWeight > 0 {
 				tweaks += " weight=\"" + strconv.Itoa(dataFlow.DiagramTweakWeight) + "\" "
 			}
+			if highlight != nil {
+				if highlight.communicationLinkIds[dataFlow.Id] {
+					arrowColor = ` color="` + Red + `"`
+					arrowStyle += ` penwidth="6.0" `
+				} else {
+					arrowColor = ` color="` + LightGray + `"`
+				}
+			}
 
 			dotContent.WriteString("\n")
 			dotContent.WriteString("  " + hash(sourceId) + " -> " + hash(targetId) +
-				` [` + arrowColor + ` ` + arrowStyle + tweaks + ` constraint=` + strconv.FormatBool(dataFlow.DiagramTweakConstraint) + ` `)
+				` [` + arrowColor + ` ` + arrowStyle + tweaks + ` constraint=` + strconv.FormatBool(dataFlow.DiagramTweakConstraint) + ` ` + links.communicationLinkEdge(dataFlow) + ` `)
 			if !parsedModel.DiagramTweakSuppressEdgeLabels {
 				dotContent.WriteString(` xlabel="` + encode(dataFlow.Protocol.String()) + `" fontcolor="` + determineLabelColor(dataFlow, parsedModel) + `" `)
 			}
@@ -444,6 +485,23 @@ func GenerateDataFlowDiagramGraphvizImage(dotFile *os.File, targetDir string,
 	return nil
 }
 
+// GenerateGraphvizSVG renders a DOT file as SVG, which unlike the PNG images scales with the size of the model and can be
+// embedded into the markdown and HTML reports
+
+func GenerateGraphvizSVG(dotFile *os.File, targetDir string, diagramFilenameSVG string, progressReporter progressReporter) error {
+	progressReporter.Info("Rendering diagram as svg: " + diagramFilenameSVG)
+
+	cmd := exec.Command("dot", "-Tsvg", dotFile.Name(), "-o", filepath.Join(targetDir, diagramFilenameSVG)) // #nosec G204
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("graph rendering call failed with error: %v", err)
+	}
+
+	return nil
+}
+
 func makeDiagramSameRankNodeTweaks(parsedModel *types.Model) (string, error) {
 	// see https://stackoverflow.com/questions/25734244/how-do-i-place-nodes-on-the-same-level-in-dot
 	tweak := ""
@@ -493,9 +551,13 @@ func makeDiagramInvisibleConnectionsTweaks(parsedModel *types.Model) (string, er
 	return tweak, nil
 }
 
-func WriteDataAssetDiagramGraphvizDOT(parsedModel *types.Model, diagramFilenameDOT string, dpi int,
+// WriteDataAssetDiagramGraphvizDOT writes the data asset diagram; when rendered as SVG, the technical and data assets
+// link to their sections of the HTML report given by reportFilename (if any) and show their risks as tooltip
+
+func WriteDataAssetDiagramGraphvizDOT(parsedModel *types.Model, diagramFilenameDOT string, dpi int, reportFilename string,
 	progressReporter progressReporter) (*os.File, error) {
 	progressReporter.Info("Writing data asset diagram input")
+	links := &diagramLinks{reportFilename: reportFilename, idPrefix: "data-asset-diagram-"}
 
 	var dotContent strings.Builder
 	dotContent.WriteString("digraph generatedModel { concentrate=true \n")
@@ -534,6 +596,8 @@ func WriteDataAssetDiagramGraphvizDOT(parsedModel *types.Model, diagramFilenameD
 		if len(technicalAsset.DataAssetsStored) > 0 || len(technicalAsset.DataAssetsProcessed) > 0 {
 			dotContent.WriteString(makeTechAssetNode(parsedModel, technicalAsset, true))
 			dotContent.WriteString("\n")
+			dotContent.WriteString(links.technicalAssetNode(parsedModel, technicalAsset))
+			dotContent.WriteString("\n")
 		}
 	}
 
@@ -547,6 +611,8 @@ func WriteDataAssetDiagramGraphvizDOT(parsedModel *types.Model, diagramFilenameD
 	for _, dataAsset := range dataAssets {
 		dotContent.WriteString(makeDataAssetNode(parsedModel, dataAsset))
 		dotContent.WriteString("\n")
+		dotContent.WriteString(links.dataAssetNode(parsedModel, dataAsset))
+		dotContent.WriteString("\n")
 	}
 
 	// Data Asset to Tech Asset links ===============================================================================
@@ -555,7 +621,7 @@ func WriteDataAssetDiagramGraphvizDOT(parsedModel *types.Model, diagramFilenameD
 			targetId := technicalAsset.Id
 			dotContent.WriteString("\n")
 			dotContent.WriteString(hash(sourceId) + " -> " + hash(targetId) +
-				` [ color="blue" style="solid" ];`)
+				` [ color="blue" style="solid" ` + links.dataAssetEdge(sourceId, targetId) + ` ];`)
 			dotContent.WriteString("\n")
 		}
 		for _, sourceId := range technicalAsset.DataAssetsProcessed {
@@ -563,7 +629,7 @@ func WriteDataAssetDiagramGraphvizDOT(parsedModel *types.Model, diagramFilenameD
 				targetId := technicalAsset.Id
 				dotContent.WriteString("\n")
 				dotContent.WriteString(hash(sourceId) + " -> " + hash(targetId) +
-					` [ color="#666666" style="dashed" ];`)
+					` [ color="#666666" style="dashed" ` + links.dataAssetEdge(sourceId, targetId) + ` ];`)
 				dotContent.WriteString("\n")
 			}
 		}
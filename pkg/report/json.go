@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/threagile/threagile/pkg/model"
 	"github.com/threagile/threagile/pkg/security/types"
 )
 
@@ -54,3 +55,15 @@ func WriteStatsJSON(parsedModel *types.Model, filename string) error {
 	}
 	return nil
 }
+
+func WriteAttackPathsJSON(attackPaths []*model.AttackPath, filename string) error {
+	jsonBytes, err := json.Marshal(attackPaths)
+	if err != nil {
+		return fmt.Errorf("failed to marshal attack paths to JSON: %w", err)
+	}
+	err = os.WriteFile(filename, jsonBytes, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write attack paths to JSON file: %w", err)
+	}
+	return nil
+}
@@ -16,7 +16,7 @@ import (
 	"github.com/jung-kurt/gofpdf"
 	"github.com/jung-kurt/gofpdf/contrib/gofpdi"
 	"github.com/threagile/threagile/pkg/docs"
-	"github.com/threagile/threagile/pkg/security/risks"
+	"github.com/threagile/threagile/pkg/model"
 	"github.com/threagile/threagile/pkg/security/types"
 	"github.com/wcharczuk/go-chart"
 	"github.com/wcharczuk/go-chart/drawing"
@@ -52,11 +52,16 @@ func (r *pdfReporter) WriteReportPDF(reportFilename string,
 	templateFilename string,
 	dataFlowDiagramFilenamePNG string,
 	dataAssetDiagramFilenamePNG string,
+	attackPathDiagramFilenamePNG string,
 	modelFilename string,
 	skipRiskRules []string,
 	buildTimestamp string,
 	modelHash string,
 	introTextRAA string,
+	raaAlgorithm string,
+	raaParameters []string,
+	attackPaths []*model.AttackPath,
+	builtinRiskRules types.RiskRules,
 	customRiskRules types.RiskRules,
 	tempFolder string,
 	model *types.Model) error {
@@ -71,7 +76,7 @@ func (r *pdfReporter) WriteReportPDF(reportFilename string,
 	r.createPdfAndInitMetadata(model)
 	r.parseBackgroundTemplate(templateFilename)
 	r.createCover(model)
-	r.createTableOfContents(model)
+	r.createTableOfContents(model, attackPaths)
 	err := r.createManagementSummary(model, tempFolder)
 	if err != nil {
 		return fmt.Errorf("error creating management summary: %w", err)
@@ -92,18 +97,22 @@ func (r *pdfReporter) WriteReportPDF(reportFilename string,
 	r.createTagListing(model)
 	r.createSTRIDE(model)
 	r.createAssignmentByFunction(model)
-	r.createRAA(model, introTextRAA)
+	r.createRAA(model, introTextRAA, raaAlgorithm, raaParameters)
 	r.embedDataRiskMapping(dataAssetDiagramFilenamePNG, tempFolder)
+	if attackPaths != nil {
+		r.createAttackPaths(model, attackPaths, attackPathDiagramFilenamePNG)
+	}
 	//createDataRiskQuickWins()
 	r.createOutOfScopeAssets(model)
 	r.createModelFailures(model)
 	r.createQuestions(model)
+	r.createRiskTrackingDueSoon(model)
 	r.createRiskCategories(model)
 	r.createTechnicalAssets(model)
 	r.createDataAssets(model)
 	r.createTrustBoundaries(model)
 	r.createSharedRuntimes(model)
-	r.createRiskRulesChecked(model, modelFilename, skipRiskRules, buildTimestamp, modelHash, customRiskRules)
+	r.createRiskRulesChecked(model, modelFilename, skipRiskRules, buildTimestamp, modelHash, builtinRiskRules, customRiskRules)
 	r.createDisclaimer(model)
 	err = r.writeReportToFile(reportFilename)
 	if err != nil {
@@ -194,7 +203,7 @@ func (r *pdfReporter) createCover(parsedModel *types.Model) {
 	r.pdf.SetTextColor(0, 0, 0)
 }
 
-func (r *pdfReporter) createTableOfContents(parsedModel *types.Model) {
+func (r *pdfReporter) createTableOfContents(parsedModel *types.Model, attackPaths []*model.AttackPath) {
 	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
 	r.pdf.AddPage()
 	r.currentChapterTitleBreadcrumb = "Table of Contents"
@@ -313,6 +322,14 @@ func (r *pdfReporter) createTableOfContents(parsedModel *types.Model) {
 	r.pdf.Line(15.6, y+1.3, 11+171.5, y+1.3)
 	r.pdf.Link(10, y-5, 172.5, 6.5, r.pdf.AddLink())
 
+	if attackPaths != nil { // as attack paths are only searched on request
+		y += 6
+		r.pdf.Text(11, y, "    "+"Attack Paths")
+		r.pdf.Text(175, y, "{attack-paths}")
+		r.pdf.Line(15.6, y+1.3, 11+171.5, y+1.3)
+		r.pdf.Link(10, y-5, 172.5, 6.5, r.pdf.AddLink())
+	}
+
 	/*
 		y += 6
 		assets := "assets"
@@ -369,6 +386,17 @@ func (r *pdfReporter) createTableOfContents(parsedModel *types.Model) {
 	r.pdf.Line(15.6, y+1.3, 11+171.5, y+1.3)
 	r.pdf.Link(10, y-5, 172.5, 6.5, r.pdf.AddLink())
 
+	y += 6
+	dueRisks := parsedModel.RisksWithTrackingDueWithin(time.Now(), types.RiskTrackingDueSoonDays)
+	risksStr = "Risks"
+	if len(dueRisks) == 1 {
+		risksStr = "Risk"
+	}
+	r.pdf.Text(11, y, "    "+"Risk Tracking Expiring Soon: "+strconv.Itoa(len(dueRisks))+" "+risksStr)
+	r.pdf.Text(175, y, "{risk-tracking-expiring-soon}")
+	r.pdf.Line(15.6, y+1.3, 11+171.5, y+1.3)
+	r.pdf.Link(10, y-5, 172.5, 6.5, r.pdf.AddLink())
+
 	// ===============
 
 	if len(parsedModel.GeneratedRisksByCategory) > 0 {
@@ -1458,13 +1486,62 @@ func (r *pdfReporter) createModelFailures(parsedModel *types.Model) {
 			types.MediumSeverity, true, true, false, true)
 		r.addCategories(parsedModel, types.GetRiskCategories(parsedModel, types.CategoriesOfOnlyLowRisks(parsedModel, modelFailuresByCategory, true)),
 			types.LowSeverity, true, true, false, true)
+		r.addModelFailurePositions(modelFailures)
 	}
 
 	r.pdf.SetDrawColor(0, 0, 0)
 	r.pdf.SetDashPattern([]float64{}, 0)
 }
 
-func (r *pdfReporter) createRAA(parsedModel *types.Model, introTextRAA string) {
+// addModelFailurePositions lists where in the model file or its includes the elements causing the model failures are
+// defined, so that they can be looked up and fixed
+
+func (r *pdfReporter) addModelFailurePositions(modelFailures []*types.Risk) {
+	risks := make([]*types.Risk, 0)
+	for _, risk := range modelFailures {
+		if risk.Position.IsKnown() {
+			risks = append(risks, risk)
+		}
+	}
+	if len(risks) == 0 {
+		return
+	}
+
+	sort.SliceStable(risks, func(i, j int) bool {
+		if risks[i].Position.Filename != risks[j].Position.Filename {
+			return risks[i].Position.Filename < risks[j].Position.Filename
+		}
+		if risks[i].Position.Line != risks[j].Position.Line {
+			return risks[i].Position.Line < risks[j].Position.Line
+		}
+		return risks[i].SyntheticId < risks[j].SyntheticId
+	})
+
+	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
+	html := r.pdf.HTMLBasicNew()
+	if r.pdf.GetY() > 250 {
+		r.pageBreak()
+		r.pdf.SetY(36)
+	} else {
+		html.Write(5, "<br><br>")
+	}
+	r.pdfColorBlack()
+	html.Write(5, "<b>Locations in the model</b><br>")
+	r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+	for _, risk := range risks {
+		if r.pdf.GetY() > 270 {
+			r.pageBreak()
+			r.pdf.SetY(36)
+		}
+		r.pdfColorGray()
+		html.Write(5, uni(risk.Position.String())+": ")
+		r.pdfColorBlack()
+		html.Write(5, uni(risk.Title)+"<br>")
+	}
+	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+}
+
+func (r *pdfReporter) createRAA(parsedModel *types.Model, introTextRAA string, raaAlgorithm string, raaParameters []string) {
 	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
 	r.pdf.SetTextColor(0, 0, 0)
 	chapTitle := "RAA Analysis"
@@ -1480,6 +1557,15 @@ func (r *pdfReporter) createRAA(parsedModel *types.Model, introTextRAA string) {
 	strBuilder.Reset()
 	r.pdf.SetFont("Helvetica", "", fontSizeSmall)
 	r.pdfColorGray()
+	if len(raaAlgorithm) > 0 {
+		strBuilder.WriteString("The RAA values were calculated with the <b>" + uni(raaAlgorithm) + "</b> algorithm")
+		if len(raaParameters) > 0 {
+			strBuilder.WriteString(": " + uni(strings.Join(raaParameters, "; ")))
+		}
+		strBuilder.WriteString(".<br>")
+		html.Write(5, strBuilder.String())
+		strBuilder.Reset()
+	}
 	html.Write(5, "Technical asset paragraphs are clickable and link to the corresponding chapter.")
 	r.pdf.SetFont("Helvetica", "", fontSizeBody)
 
@@ -1535,6 +1621,144 @@ func (r *pdfReporter) createRAA(parsedModel *types.Model, introTextRAA string) {
 	r.pdf.SetDashPattern([]float64{}, 0)
 }
 
+func (r *pdfReporter) createAttackPaths(parsedModel *types.Model, attackPaths []*model.AttackPath, diagramFilenamePNG string) {
+	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
+	r.pdf.SetTextColor(0, 0, 0)
+	chapTitle := "Attack Paths"
+	r.addHeadline(chapTitle, false)
+	r.defineLinkTarget("{attack-paths}")
+	r.currentChapterTitleBreadcrumb = chapTitle
+
+	html := r.pdf.HTMLBasicNew()
+	var strBuilder strings.Builder
+	strBuilder.WriteString("This chapter lists the highest scoring ways for an attacker to get from an entry point, i.e. a technical " +
+		"asset on the internet or an external entity, along the communication links to a technical asset storing " +
+		"<b>strictly-confidential</b> data. Each hop of a path scores the risks still open on its communication link and " +
+		"on the technical asset it leads to, weighted by their severity from 1 (low) to 5 (critical); the score of a path " +
+		"is the sum of the scores of its hops. Paths with a high score should be broken up by mitigating risks along them.<br>")
+	html.Write(5, strBuilder.String())
+	strBuilder.Reset()
+
+	if len(attackPaths) == 0 {
+		r.pdfColorGray()
+		html.Write(5, "<br>No attack paths to strictly-confidential data have been identified.")
+		r.pdfColorBlack()
+		return
+	}
+
+	title := func(techAssetId string) string {
+		if techAsset, ok := parsedModel.TechnicalAssets[techAssetId]; ok {
+			return uni(techAsset.Title)
+		}
+		return uni(techAssetId)
+	}
+
+	for i, attackPath := range attackPaths {
+		if r.pdf.GetY() > 250 {
+			r.pageBreak()
+			r.pdf.SetY(36)
+		} else {
+			strBuilder.WriteString("<br>")
+		}
+
+		pathRisks := make([]*types.Risk, 0)
+		for _, hop := range attackPath.Hops {
+			for _, riskId := range hop.OpenRiskIds {
+				if risk, ok := parsedModel.GeneratedRisksBySyntheticId[strings.ToLower(riskId)]; ok {
+					pathRisks = append(pathRisks, risk)
+				}
+			}
+		}
+		switch types.HighestSeverityStillAtRisk(parsedModel, pathRisks) {
+		case types.CriticalSeverity:
+			colorCriticalRisk(r.pdf)
+		case types.HighSeverity:
+			colorHighRisk(r.pdf)
+		case types.ElevatedSeverity:
+			colorElevatedRisk(r.pdf)
+		case types.MediumSeverity:
+			colorMediumRisk(r.pdf)
+		default:
+			colorLowRisk(r.pdf)
+		}
+		if len(pathRisks) == 0 {
+			r.pdfColorBlack()
+		}
+
+		titles := make([]string, 0)
+		for _, id := range attackPath.TechnicalAssetIds() {
+			titles = append(titles, title(id))
+		}
+		dataTitles := make([]string, 0)
+		for _, id := range attackPath.DataAssetIds {
+			if dataAsset, ok := parsedModel.DataAssets[id]; ok {
+				dataTitles = append(dataTitles, uni(dataAsset.Title))
+			}
+		}
+		strBuilder.WriteString("<b>" + strconv.Itoa(i+1) + ". " + strings.Join(titles, " -> ") + "</b>: score " + strconv.Itoa(attackPath.Score))
+		strBuilder.WriteString("<br>")
+		html.Write(5, strBuilder.String())
+		strBuilder.Reset()
+		r.pdf.SetTextColor(0, 0, 0)
+		html.Write(5, "Strictly-confidential data stored: "+strings.Join(dataTitles, ", ")+"<br>")
+
+		r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+		for _, hop := range attackPath.Hops {
+			linkTitle := uni(hop.CommunicationLinkId)
+			if link, ok := parsedModel.CommunicationLinks[hop.CommunicationLinkId]; ok {
+				linkTitle = uni(link.Title)
+			}
+			strBuilder.WriteString("<b>" + title(hop.SourceId) + " -> " + title(hop.TargetId) + "</b> via " + linkTitle +
+				" (score " + strconv.Itoa(hop.Score) + ")")
+			for _, riskId := range hop.OpenRiskIds {
+				if risk, ok := parsedModel.GeneratedRisksBySyntheticId[strings.ToLower(riskId)]; ok {
+					strBuilder.WriteString("<br>    " + risk.Severity.Title() + ": " + uni(risk.Title))
+				}
+			}
+			strBuilder.WriteString("<br>")
+			html.Write(5, strBuilder.String())
+			strBuilder.Reset()
+		}
+		r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	}
+
+	r.embedAttackPathDiagram(diagramFilenamePNG)
+	r.pdf.SetDrawColor(0, 0, 0)
+	r.pdf.SetDashPattern([]float64{}, 0)
+}
+
+func (r *pdfReporter) embedAttackPathDiagram(diagramFilenamePNG string) {
+	/* #nosec diagramFilenamePNG is not tainted */
+	imagePath, err := os.Open(diagramFilenamePNG)
+	if err != nil {
+		return
+	}
+	defer func() { _ = imagePath.Close() }()
+	srcImage, _, err := image.Decode(imagePath)
+	if err != nil {
+		return
+	}
+	srcDimensions := srcImage.Bounds()
+
+	r.pageBreak()
+	r.pdf.SetY(36)
+	html := r.pdf.HTMLBasicNew()
+	html.Write(5, "The following diagram highlights the technical assets and communication links of the attack paths above. "+
+		"For a full high-resolution version of this diagram please refer to the PNG image file alongside this report.")
+	r.pdf.Ln(10)
+
+	var options gofpdf.ImageOptions
+	options.ImageType = ""
+	r.pdf.RegisterImage(diagramFilenamePNG, "")
+	pinnedWidth, pinnedHeight := 190.0, 195.0
+	if srcDimensions.Dx() > srcDimensions.Dy() {
+		pinnedHeight = 0
+	} else {
+		pinnedWidth = 0
+	}
+	r.pdf.ImageOptions(diagramFilenamePNG, 10, r.pdf.GetY(), pinnedWidth, pinnedHeight, true, options, 0, "")
+}
+
 /*
 func createDataRiskQuickWins() {
 	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
@@ -2196,6 +2420,80 @@ func (r *pdfReporter) createQuestions(parsedModel *types.Model) {
 	}
 }
 
+// createRiskTrackingDueSoon lists the risks whose tracking expires or is to be reviewed soon, or is overdue; expired
+// acceptances have been reverted to unchecked
+
+func (r *pdfReporter) createRiskTrackingDueSoon(parsedModel *types.Model) {
+	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
+	today := time.Now()
+	r.pdf.SetTextColor(0, 0, 0)
+	risks := parsedModel.RisksWithTrackingDueWithin(today, types.RiskTrackingDueSoonDays)
+	risksStr := "Risks"
+	if len(risks) == 1 {
+		risksStr = "Risk"
+	}
+	chapTitle := "Risk Tracking Expiring Soon: " + strconv.Itoa(len(risks)) + " " + risksStr
+	r.addHeadline(chapTitle, false)
+	r.defineLinkTarget("{risk-tracking-expiring-soon}")
+	r.currentChapterTitleBreadcrumb = chapTitle
+	r.pdfColorBlack()
+
+	html := r.pdf.HTMLBasicNew()
+	html.Write(5, "This chapter lists the risks whose tracking expires or is to be reviewed within the next "+
+		strconv.Itoa(types.RiskTrackingDueSoonDays)+" days, or is overdue. Once the acceptance of a risk expired, "+
+		"the risk is unchecked again, so that exceptions granted once do not silently stay valid.")
+
+	if len(risks) == 0 {
+		r.pdfColorLightGray()
+		html.Write(5, "<br><br><br>")
+		html.Write(5, "No risk tracking expires or is to be reviewed soon.")
+	}
+	for _, risk := range risks {
+		tracking := risk.GetRiskTracking(parsedModel)
+		if r.pdf.GetY() > 250 {
+			r.pageBreak()
+			r.pdf.SetY(36)
+		} else {
+			html.Write(5, "<br><br>")
+		}
+
+		state := "due soon"
+		switch {
+		case tracking.IsExpired(today):
+			state = "expired"
+		case tracking.IsOverdue(today):
+			state = "review overdue"
+		}
+		if tracking.IsOverdue(today) {
+			colorModelFailure(r.pdf)
+		} else {
+			r.pdfColorBlack()
+		}
+		html.Write(5, "<b>"+tracking.DueDate().Format("2006-01-02")+" ("+state+")</b>: ")
+		r.pdfColorBlack()
+		html.Write(5, uni(risk.Title)+"<br>")
+
+		details := []string{tracking.Status.Title(), risk.Severity.Title()}
+		if len(tracking.Owner) > 0 {
+			details = append(details, "owner: "+tracking.Owner)
+		}
+		if !tracking.Expires.IsZero() {
+			details = append(details, "expires: "+tracking.Expires.Format("2006-01-02"))
+		}
+		if !tracking.ReviewBy.IsZero() {
+			details = append(details, "review by: "+tracking.ReviewBy.Format("2006-01-02"))
+		}
+		if len(tracking.Ticket) > 0 {
+			details = append(details, "ticket: "+tracking.Ticket)
+		}
+		r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+		r.pdfColorGray()
+		html.Write(5, uni(strings.Join(details, ", "))+"<br>"+uni(risk.SyntheticId))
+		r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	}
+	r.pdfColorBlack()
+}
+
 func sortedKeysOfQuestions(parsedModel *types.Model) []string {
 	keys := make([]string, 0)
 	for k := range parsedModel.Questions {
@@ -4001,7 +4299,7 @@ func (r *pdfReporter) createSharedRuntimes(parsedModel *types.Model) {
 	}
 }
 
-func (r *pdfReporter) createRiskRulesChecked(parsedModel *types.Model, modelFilename string, skipRiskRules []string, buildTimestamp string, modelHash string, customRiskRules types.RiskRules) {
+func (r *pdfReporter) createRiskRulesChecked(parsedModel *types.Model, modelFilename string, skipRiskRules []string, buildTimestamp string, modelHash string, builtinRiskRules types.RiskRules, customRiskRules types.RiskRules) {
 	r.pdf.SetTextColor(0, 0, 0)
 	title := "Risk Rules Checked by Threagile"
 	r.addHeadline(title, false)
@@ -4107,7 +4405,7 @@ func (r *pdfReporter) createRiskRulesChecked(parsedModel *types.Model, modelFile
 		r.pdf.MultiCell(160, 6, individualRiskCategory.RiskAssessment, "0", "0", false)
 	}
 
-	for _, rule := range risks.GetBuiltInRiskRules() {
+	for _, rule := range builtinRiskRules {
 		r.pdf.Ln(-1)
 		r.pdf.SetFont("Helvetica", "B", fontSizeBody)
 		if contains(skipRiskRules, rule.Category().ID) {
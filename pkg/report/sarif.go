@@ -0,0 +1,366 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/docs"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const (
+	sarifSchema        = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion       = "2.1.0"
+	sarifSourceRoot    = "%SRCROOT%"
+	sarifFingerprintId = "threagileSyntheticId/v1"
+)
+
+// the SARIF 2.1.0 subset written by WriteRisksSARIF; see https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                        `json:"tool"`
+	OriginalUriBaseIds map[string]sarifArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	Results            []sarifResult                    `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationUri string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	Id                   string              `json:"id"`
+	Name                 string              `json:"name,omitempty"`
+	ShortDescription     sarifMessage        `json:"shortDescription"`
+	FullDescription      *sarifMessage       `json:"fullDescription,omitempty"`
+	Help                 *sarifMessage       `json:"help,omitempty"`
+	HelpUri              string              `json:"helpUri,omitempty"`
+	DefaultConfiguration sarifRuleDefaults   `json:"defaultConfiguration"`
+	Properties           sarifRuleProperties `json:"properties"`
+}
+
+type sarifRuleDefaults struct {
+	Level string `json:"level"`
+}
+
+type sarifRuleProperties struct {
+	Tags       []string `json:"tags,omitempty"`
+	CWE        string   `json:"cwe,omitempty"`
+	ASVS       string   `json:"asvs,omitempty"`
+	Mitigation string   `json:"mitigation,omitempty"`
+	CheatSheet string   `json:"cheat_sheet,omitempty"`
+	STRIDE     string   `json:"stride,omitempty"`
+	Function   string   `json:"function,omitempty"`
+}
+
+type sarifMessage struct {
+	Text     string `json:"text,omitempty"`
+	Markdown string `json:"markdown,omitempty"`
+}
+
+type sarifResult struct {
+	RuleId              string                `json:"ruleId"`
+	RuleIndex           int                   `json:"ruleIndex"`
+	Level               string                `json:"level"`
+	Message             sarifMessage          `json:"message"`
+	Locations           []sarifLocation       `json:"locations"`
+	PartialFingerprints map[string]string     `json:"partialFingerprints"`
+	Suppressions        []sarifSuppression    `json:"suppressions,omitempty"`
+	Properties          sarifResultProperties `json:"properties"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	Uri       string `json:"uri"`
+	UriBaseId string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName,omitempty"`
+	Kind               string `json:"kind,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Status        string `json:"status"`
+	Justification string `json:"justification,omitempty"`
+}
+
+type sarifResultProperties struct {
+	SecuritySeverity       string   `json:"security-severity"`
+	Severity               string   `json:"severity"`
+	ExploitationLikelihood string   `json:"exploitation_likelihood"`
+	ExploitationImpact     string   `json:"exploitation_impact"`
+	DataBreachProbability  string   `json:"data_breach_probability"`
+	DataBreachAssets       []string `json:"data_breach_technical_assets,omitempty"`
+	RiskStatus             string   `json:"risk_status"`
+	Ticket                 string   `json:"ticket,omitempty"`
+	CheckedBy              string   `json:"checked_by,omitempty"`
+}
+
+// WriteRisksSARIF writes all risks as a SARIF 2.1.0 log: each risk category becomes a rule and each risk a result
+// located at the position of its most relevant element in the model file or its includes, relative to the folder of the
+// model file as source root. Risks tracked as accepted, mitigated or false positive are suppressed, and those in
+// discussion are suppressed pending review.
+
+func WriteRisksSARIF(parsedModel *types.Model, modelFilename string, filename string) error {
+	sourceRoot, err := filepath.Abs(filepath.Dir(modelFilename))
+	if err != nil {
+		return fmt.Errorf("failed to determine the folder of model file %q: %w", modelFilename, err)
+	}
+
+	categories := types.SortedRiskCategories(parsedModel)
+	rules := make([]sarifRule, 0)
+	ruleIndexes := make(map[string]int)
+	for _, category := range categories {
+		ruleIndexes[category.ID] = len(rules)
+		rules = append(rules, newSarifRule(category))
+	}
+
+	results := make([]sarifResult, 0)
+	for _, category := range categories {
+		for _, risk := range types.SortedRisksOfCategory(parsedModel, category) {
+			results = append(results, newSarifResult(parsedModel, sourceRoot, modelFilename, risk, ruleIndexes[category.ID]))
+		}
+	}
+
+	jsonBytes, err := json.MarshalIndent(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "Threagile",
+				Version:        docs.ThreagileVersion,
+				InformationUri: "https://threagile.io",
+				Rules:          rules,
+			}},
+			OriginalUriBaseIds: map[string]sarifArtifactLocation{sarifSourceRoot: {Uri: fileUri(sourceRoot) + "/"}},
+			Results:            results,
+		}},
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal risks to SARIF: %w", err)
+	}
+	err = os.WriteFile(filename, jsonBytes, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write risks to SARIF file: %w", err)
+	}
+	return nil
+}
+
+func newSarifRule(category *types.RiskCategory) sarifRule {
+	tags := []string{"security", "threat-model", category.STRIDE.String()}
+	cwe := ""
+	if category.CWE > 0 {
+		cwe = "CWE-" + strconv.Itoa(category.CWE)
+		tags = append(tags, "external/cwe/cwe-"+strconv.Itoa(category.CWE))
+	}
+
+	var help strings.Builder
+	help.WriteString("**Mitigation** (" + category.Function.Title() + "): " + plainText(category.Mitigation))
+	if len(category.ASVS) > 0 {
+		help.WriteString("\n\n**ASVS**: " + plainText(category.ASVS))
+	}
+	if len(category.CheatSheet) > 0 {
+		help.WriteString("\n\n**Cheat Sheet**: " + category.CheatSheet)
+	}
+	if len(cwe) > 0 {
+		help.WriteString("\n\n**CWE**: " + cwe)
+	}
+
+	helpText := plainText(category.Mitigation)
+	if len(helpText) == 0 {
+		helpText = plainText(category.Title)
+	}
+
+	rule := sarifRule{
+		Id:                   category.ID,
+		Name:                 category.Title,
+		ShortDescription:     sarifMessage{Text: plainText(category.Title)},
+		Help:                 &sarifMessage{Text: helpText, Markdown: help.String()},
+		HelpUri:              category.CheatSheet,
+		DefaultConfiguration: sarifRuleDefaults{Level: "warning"},
+		Properties: sarifRuleProperties{
+			Tags:       tags,
+			CWE:        cwe,
+			ASVS:       category.ASVS,
+			Mitigation: plainText(category.Mitigation),
+			CheatSheet: category.CheatSheet,
+			STRIDE:     category.STRIDE.String(),
+			Function:   category.Function.String(),
+		},
+	}
+	if len(plainText(category.Description)) > 0 {
+		rule.FullDescription = &sarifMessage{Text: plainText(category.Description)}
+	}
+	return rule
+}
+
+func newSarifResult(parsedModel *types.Model, sourceRoot string, modelFilename string, risk *types.Risk, ruleIndex int) sarifResult {
+	tracking := risk.GetRiskTrackingWithDefault(parsedModel)
+	result := sarifResult{
+		RuleId:              risk.CategoryId,
+		RuleIndex:           ruleIndex,
+		Level:               sarifLevel(risk.Severity),
+		Message:             sarifMessage{Text: plainText(risk.Title)},
+		Locations:           []sarifLocation{newSarifLocation(sourceRoot, modelFilename, risk)},
+		PartialFingerprints: map[string]string{sarifFingerprintId: risk.SyntheticId},
+		Properties: sarifResultProperties{
+			SecuritySeverity:       sarifSecuritySeverity(risk.Severity),
+			Severity:               risk.Severity.String(),
+			ExploitationLikelihood: risk.ExploitationLikelihood.String(),
+			ExploitationImpact:     risk.ExploitationImpact.String(),
+			DataBreachProbability:  risk.DataBreachProbability.String(),
+			DataBreachAssets:       risk.DataBreachTechnicalAssetIDs,
+			RiskStatus:             tracking.Status.String(),
+			Ticket:                 tracking.Ticket,
+			CheckedBy:              tracking.CheckedBy,
+		},
+	}
+
+	if status := sarifSuppressionStatus(tracking.Status); len(status) > 0 {
+		justification := tracking.Status.Title()
+		if len(strings.TrimSpace(tracking.Justification)) > 0 {
+			justification += ": " + strings.TrimSpace(tracking.Justification)
+		}
+		result.Suppressions = []sarifSuppression{{Kind: "external", Status: status, Justification: justification}}
+	}
+
+	return result
+}
+
+func sarifLevel(severity types.RiskSeverity) string {
+	switch severity {
+	case types.CriticalSeverity, types.HighSeverity:
+		return "error"
+	case types.ElevatedSeverity, types.MediumSeverity:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// sarifSecuritySeverity maps risk severities to the CVSS-like scores code scanning UIs rank security results by
+
+func sarifSecuritySeverity(severity types.RiskSeverity) string {
+	switch severity {
+	case types.CriticalSeverity:
+		return "9.5"
+	case types.HighSeverity:
+		return "8.0"
+	case types.ElevatedSeverity:
+		return "6.5"
+	case types.MediumSeverity:
+		return "5.0"
+	default:
+		return "2.0"
+	}
+}
+
+// sarifSuppressionStatus returns the suppression status of a risk tracking status, or an empty string for risks that
+// aren't suppressed as they still need to be handled
+
+func sarifSuppressionStatus(status types.RiskStatus) string {
+	switch status {
+	case types.Accepted, types.Mitigated, types.FalsePositive:
+		return "accepted"
+	case types.InDiscussion:
+		return "underReview"
+	default:
+		return ""
+	}
+}
+
+func plainText(text string) string {
+	return strings.TrimSpace(common.StripHTMLTags(text))
+}
+
+// newSarifLocation returns the location of a risk at the position of its most relevant element, or at the model file
+// itself if that position isn't known
+
+func newSarifLocation(sourceRoot string, modelFilename string, risk *types.Risk) sarifLocation {
+	logicalLocations := make([]sarifLogicalLocation, 0)
+	add := func(id string, kind string) {
+		if len(id) > 0 {
+			logicalLocations = append(logicalLocations, sarifLogicalLocation{Name: id, FullyQualifiedName: kind + "/" + id, Kind: kind})
+		}
+	}
+
+	add(risk.MostRelevantCommunicationLinkId, "communication_link")
+	add(risk.MostRelevantTechnicalAssetId, "technical_asset")
+	add(risk.MostRelevantTrustBoundaryId, "trust_boundary")
+	add(risk.MostRelevantSharedRuntimeId, "shared_runtime")
+	add(risk.MostRelevantDataAssetId, "data_asset")
+
+	location := sarifLocation{
+		PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: artifactLocation(sourceRoot, modelFilename)},
+		LogicalLocations: logicalLocations,
+	}
+	if risk.Position.IsKnown() {
+		location.PhysicalLocation.ArtifactLocation = artifactLocation(sourceRoot, risk.Position.Filename)
+		location.PhysicalLocation.Region = &sarifRegion{StartLine: risk.Position.Line, StartColumn: risk.Position.Column}
+	}
+
+	return location
+}
+
+// artifactLocation refers to files within the source root relative to it, as code scanning UIs expect, and to any
+// other files by their file URI
+
+func artifactLocation(sourceRoot string, filename string) sarifArtifactLocation {
+	absFilename, err := filepath.Abs(filename)
+	if err != nil {
+		return sarifArtifactLocation{Uri: (&url.URL{Path: filepath.ToSlash(filename)}).String()}
+	}
+
+	relFilename, err := filepath.Rel(sourceRoot, absFilename)
+	if err != nil || relFilename == ".." || strings.HasPrefix(relFilename, ".."+string(filepath.Separator)) {
+		return sarifArtifactLocation{Uri: fileUri(absFilename)}
+	}
+
+	return sarifArtifactLocation{Uri: (&url.URL{Path: filepath.ToSlash(relFilename)}).String(), UriBaseId: sarifSourceRoot}
+}
+
+// fileUri returns the file URI of an absolute file name, escaping it as needed
+
+func fileUri(filename string) string {
+	path := filepath.ToSlash(filename)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path // windows drive letters
+	}
+
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
@@ -0,0 +1,139 @@
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestWriteRisksSARIF(t *testing.T) {
+	sourceRoot := t.TempDir()
+	modelFilename := filepath.Join(sourceRoot, "threagile.yaml")
+	includeFilename := filepath.Join(sourceRoot, "includes", "assets.yaml")
+
+	injection := &types.RiskCategory{ID: "sql-nosql-injection", Title: "SQL/NoSQL-Injection", Description: "<b>Injection</b> of queries",
+		Mitigation: "Use <i>prepared</i> statements", CWE: 89, STRIDE: types.Tampering, Function: types.Development}
+	hardening := &types.RiskCategory{ID: "missing-hardening", Title: "Missing Hardening", Mitigation: "Harden the assets", STRIDE: types.Tampering, Function: types.Operations}
+	risks := []*types.Risk{
+		{CategoryId: injection.ID, Severity: types.CriticalSeverity, Title: "<b>SQL Injection</b> at <b>Database</b>", SyntheticId: "sql-nosql-injection@db",
+			MostRelevantTechnicalAssetId: "db", Position: input.Position{Filename: modelFilename, Line: 12, Column: 5}},
+		{CategoryId: injection.ID, Severity: types.HighSeverity, SyntheticId: "sql-nosql-injection@search",
+			MostRelevantTechnicalAssetId: "search", Position: input.Position{Filename: includeFilename, Line: 3, Column: 3}},
+		{CategoryId: hardening.ID, Severity: types.ElevatedSeverity, SyntheticId: "missing-hardening@db", MostRelevantTechnicalAssetId: "db"},
+		{CategoryId: hardening.ID, Severity: types.MediumSeverity, SyntheticId: "missing-hardening@web", MostRelevantTechnicalAssetId: "web"},
+		{CategoryId: hardening.ID, Severity: types.LowSeverity, SyntheticId: "missing-hardening@search", MostRelevantTechnicalAssetId: "search"},
+		{CategoryId: hardening.ID, Severity: types.LowSeverity, SyntheticId: "missing-hardening@cache", MostRelevantTechnicalAssetId: "cache"},
+	}
+	parsedModel := &types.Model{
+		CustomRiskCategories: types.RiskCategories{injection, hardening},
+		GeneratedRisksByCategory: map[string][]*types.Risk{
+			injection.ID: risks[:2],
+			hardening.ID: risks[2:],
+		},
+		RiskTracking: map[string]*types.RiskTracking{
+			"sql-nosql-injection@search": {SyntheticRiskId: "sql-nosql-injection@search", Status: types.Accepted, Justification: " Only internal search terms ", CheckedBy: "Alice"},
+			"missing-hardening@db":       {SyntheticRiskId: "missing-hardening@db", Status: types.Mitigated, Ticket: "SEC-1"},
+			"missing-hardening@web":      {SyntheticRiskId: "missing-hardening@web", Status: types.FalsePositive},
+			"missing-hardening@search":   {SyntheticRiskId: "missing-hardening@search", Status: types.InDiscussion},
+		},
+	}
+
+	filename := filepath.Join(t.TempDir(), "risks.sarif")
+	require.NoError(t, WriteRisksSARIF(parsedModel, modelFilename, filename))
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(data, &log))
+	assert.Equal(t, sarifVersion, log.Version)
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+	assert.Equal(t, map[string]sarifArtifactLocation{sarifSourceRoot: {Uri: fileUri(sourceRoot) + "/"}}, run.OriginalUriBaseIds)
+
+	require.Len(t, run.Tool.Driver.Rules, 2)
+	rules := make(map[string]sarifRule)
+	for _, rule := range run.Tool.Driver.Rules {
+		rules[rule.Id] = rule
+	}
+	require.Contains(t, rules, injection.ID)
+	assert.Equal(t, "SQL/NoSQL-Injection", rules[injection.ID].ShortDescription.Text)
+	assert.Equal(t, &sarifMessage{Text: "Injection of queries"}, rules[injection.ID].FullDescription)
+	assert.Equal(t, "Use prepared statements", rules[injection.ID].Help.Text)
+	assert.Equal(t, "CWE-89", rules[injection.ID].Properties.CWE)
+	assert.Contains(t, rules[injection.ID].Properties.Tags, "external/cwe/cwe-89")
+	require.Contains(t, rules, hardening.ID)
+	assert.Nil(t, rules[hardening.ID].FullDescription)
+	assert.Empty(t, rules[hardening.ID].Properties.CWE)
+
+	require.Len(t, run.Results, len(risks))
+	results := make(map[string]sarifResult)
+	for _, result := range run.Results {
+		assert.Equal(t, result.RuleId, run.Tool.Driver.Rules[result.RuleIndex].Id, "rule index of %v", result.PartialFingerprints)
+		results[result.PartialFingerprints[sarifFingerprintId]] = result
+	}
+
+	for syntheticId, expected := range map[string]struct {
+		level            string
+		securitySeverity string
+		suppressions     []sarifSuppression
+	}{
+		"sql-nosql-injection@db":     {level: "error", securitySeverity: "9.5"},
+		"sql-nosql-injection@search": {level: "error", securitySeverity: "8.0", suppressions: []sarifSuppression{{Kind: "external", Status: "accepted", Justification: "Accepted: Only internal search terms"}}},
+		"missing-hardening@db":       {level: "warning", securitySeverity: "6.5", suppressions: []sarifSuppression{{Kind: "external", Status: "accepted", Justification: "Mitigated"}}},
+		"missing-hardening@web":      {level: "warning", securitySeverity: "5.0", suppressions: []sarifSuppression{{Kind: "external", Status: "accepted", Justification: "False Positive"}}},
+		"missing-hardening@search":   {level: "note", securitySeverity: "2.0", suppressions: []sarifSuppression{{Kind: "external", Status: "underReview", Justification: "In Discussion"}}},
+		"missing-hardening@cache":    {level: "note", securitySeverity: "2.0"},
+	} {
+		require.Contains(t, results, syntheticId)
+		result := results[syntheticId]
+		assert.Equal(t, expected.level, result.Level, syntheticId)
+		assert.Equal(t, expected.securitySeverity, result.Properties.SecuritySeverity, syntheticId)
+		assert.Equal(t, expected.suppressions, result.Suppressions, syntheticId)
+	}
+
+	critical := results["sql-nosql-injection@db"]
+	assert.Equal(t, "SQL Injection at Database", critical.Message.Text)
+	assert.Equal(t, "unchecked", critical.Properties.RiskStatus)
+	require.Len(t, critical.Locations, 1)
+	assert.Equal(t, sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{Uri: "threagile.yaml", UriBaseId: sarifSourceRoot},
+		Region:           &sarifRegion{StartLine: 12, StartColumn: 5},
+	}, critical.Locations[0].PhysicalLocation)
+	assert.Equal(t, []sarifLogicalLocation{{Name: "db", FullyQualifiedName: "technical_asset/db", Kind: "technical_asset"}}, critical.Locations[0].LogicalLocations)
+
+	included := results["sql-nosql-injection@search"]
+	assert.Equal(t, sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{Uri: "includes/assets.yaml", UriBaseId: sarifSourceRoot},
+		Region:           &sarifRegion{StartLine: 3, StartColumn: 3},
+	}, included.Locations[0].PhysicalLocation)
+	assert.Equal(t, "accepted", included.Properties.RiskStatus)
+	assert.Equal(t, "Alice", included.Properties.CheckedBy)
+
+	unpositioned := results["missing-hardening@db"]
+	assert.Equal(t, sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{Uri: "threagile.yaml", UriBaseId: sarifSourceRoot},
+	}, unpositioned.Locations[0].PhysicalLocation)
+	assert.Equal(t, "SEC-1", unpositioned.Properties.Ticket)
+}
+
+func TestArtifactLocation(t *testing.T) {
+	sourceRoot := t.TempDir()
+	outside := filepath.Join(filepath.Dir(sourceRoot), "shared models", "base#1.yaml")
+
+	for filename, expected := range map[string]sarifArtifactLocation{
+		filepath.Join(sourceRoot, "threagile.yaml"):                   {Uri: "threagile.yaml", UriBaseId: sarifSourceRoot},
+		filepath.Join(sourceRoot, "includes", "data assets.yaml"):     {Uri: "includes/data%20assets.yaml", UriBaseId: sarifSourceRoot},
+		filepath.Join(sourceRoot, "includes", "..", "threagile.yaml"): {Uri: "threagile.yaml", UriBaseId: sarifSourceRoot},
+		outside: {Uri: fileUri(outside)},
+	} {
+		assert.Equal(t, expected, artifactLocation(sourceRoot, filename), filename)
+	}
+
+	assert.Equal(t, "file:///models/shared%20models/base%231.yaml", fileUri(filepath.FromSlash("/models/shared models/base#1.yaml")))
+}
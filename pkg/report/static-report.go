@@ -0,0 +1,515 @@
+package report
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/docs"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const (
+	markdownReportTemplate = "report.md.tmpl"
+	htmlReportTemplate     = "report.html.tmpl"
+)
+
+// the default report templates; a file of the same name in the report template folder of the config replaces them, and
+// as each chapter is a template definition of its own, such a file may as well just redefine some of the chapters
+
+//go:embed templates/*.tmpl
+var reportTemplates embed.FS
+
+// staticReport is what the markdown and HTML report templates get to render: the parsed model along with the risks,
+// assets and rules of each chapter, already sorted and counted the way the PDF report does
+
+type staticReport struct {
+	Model             *types.Model
+	ModelFilename     string
+	ThreagileVersion  string
+	BuildTimestamp    string
+	GeneratedAt       string
+	Severities        []*staticSeverity
+	Statuses          []*staticStatus
+	RiskCategories    []*staticRiskCategory
+	STRIDE            []*staticRiskGroup
+	Functions         []*staticRiskGroup
+	IntroTextRAA      string
+	RAAAlgorithm      string
+	RAAParameters     []string
+	TechnicalAssets   []*staticTechnicalAsset
+	RAARanking        []*staticTechnicalAsset
+	DataAssets        []*staticDataAsset
+	AttackPaths       []*model.AttackPath
+	RulesChecked      []*staticRule
+	DataFlowDiagram   *staticDiagram
+	DataAssetDiagram  *staticDiagram
+	AttackPathDiagram *staticDiagram
+}
+
+// Diagrams returns the diagrams that have been rendered as SVG
+
+func (what *staticReport) Diagrams() []*staticDiagram {
+	diagrams := make([]*staticDiagram, 0)
+	for _, diagram := range []*staticDiagram{what.DataFlowDiagram, what.DataAssetDiagram, what.AttackPathDiagram} {
+		if diagram != nil {
+			diagrams = append(diagrams, diagram)
+		}
+	}
+
+	return diagrams
+}
+
+// AttackPathsSearched tells whether attack paths have been searched for, which is done on request only
+
+func (what *staticReport) AttackPathsSearched() bool {
+	return what.AttackPaths != nil
+}
+
+type staticSeverity struct {
+	Severity    types.RiskSeverity
+	Total       int
+	StillAtRisk int
+}
+
+type staticStatus struct {
+	Status types.RiskStatus
+	Count  int
+}
+
+type staticRisk struct {
+	*types.Risk
+	Category *types.RiskCategory
+	Tracking types.RiskTracking
+}
+
+func (what *staticRisk) IsStillAtRisk() bool {
+	return what.Tracking.Status.IsStillAtRisk()
+}
+
+type staticRiskCategory struct {
+	*types.RiskCategory
+	Risks           []*staticRisk
+	StillAtRisk     int
+	HighestSeverity types.RiskSeverity
+}
+
+type staticRiskGroup struct {
+	Title       string
+	Explanation string
+	Categories  []*staticRiskCategory
+	Total       int
+	StillAtRisk int
+}
+
+type staticTechnicalAsset struct {
+	*types.TechnicalAsset
+	TrustBoundary       *types.TrustBoundary
+	DataAssetsProcessed []*types.DataAsset
+	DataAssetsStored    []*types.DataAsset
+	CommunicationLinks  []*types.CommunicationLink
+	IncomingLinks       []*types.CommunicationLink
+	Risks               []*staticRisk
+	StillAtRisk         int
+	HighestSeverity     types.RiskSeverity
+}
+
+type staticDataAsset struct {
+	*types.DataAsset
+	ProcessedBy           []*types.TechnicalAsset
+	StoredBy              []*types.TechnicalAsset
+	SentVia               []*types.CommunicationLink
+	ReceivedVia           []*types.CommunicationLink
+	DataBreachProbability types.DataBreachProbability
+	DataBreachRisks       []*staticRisk
+}
+
+type staticRule struct {
+	*types.RiskCategory
+	Kind    string
+	Skipped bool
+	Risks   int
+}
+
+type staticDiagram struct {
+	Title    string
+	Filename string
+	SVG      htmltemplate.HTML
+}
+
+// WriteReportMarkdown writes the report as a markdown file referring to the SVG diagrams next to it
+
+func WriteReportMarkdown(config *common.Config, readResult *model.ReadResult, filename string) error {
+	tmpl, err := texttemplate.New(markdownReportTemplate).Funcs(texttemplate.FuncMap{
+		"markdown": markdownText,
+		"cell":     markdownCell,
+		"join":     strings.Join,
+	}).ParseFS(reportTemplates, "templates/"+markdownReportTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse markdown report template: %w", err)
+	}
+
+	if override := reportTemplateOverride(config, markdownReportTemplate); len(override) > 0 {
+		tmpl, err = tmpl.ParseFiles(override)
+		if err != nil {
+			return fmt.Errorf("failed to parse markdown report template %q: %w", override, err)
+		}
+	}
+
+	var report bytes.Buffer
+	err = tmpl.Execute(&report, newStaticReport(config, readResult))
+	if err != nil {
+		return fmt.Errorf("failed to render markdown report: %w", err)
+	}
+
+	err = os.WriteFile(filename, report.Bytes(), 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write markdown report: %w", err)
+	}
+	return nil
+}
+
+// WriteReportHTML writes the report as a single self-contained HTML file with the SVG diagrams inlined
+
+func WriteReportHTML(config *common.Config, readResult *model.ReadResult, filename string) error {
+	tmpl, err := htmltemplate.New(htmlReportTemplate).Funcs(htmltemplate.FuncMap{
+		"markup": htmlMarkup,
+		"join":   strings.Join,
+	}).ParseFS(reportTemplates, "templates/"+htmlReportTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse html report template: %w", err)
+	}
+
+	if override := reportTemplateOverride(config, htmlReportTemplate); len(override) > 0 {
+		tmpl, err = tmpl.ParseFiles(override)
+		if err != nil {
+			return fmt.Errorf("failed to parse html report template %q: %w", override, err)
+		}
+	}
+
+	var report bytes.Buffer
+	err = tmpl.Execute(&report, newStaticReport(config, readResult))
+	if err != nil {
+		return fmt.Errorf("failed to render html report: %w", err)
+	}
+
+	err = os.WriteFile(filename, report.Bytes(), 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write html report: %w", err)
+	}
+	return nil
+}
+
+func reportTemplateOverride(config *common.Config, name string) string {
+	if len(config.ReportTemplateFolder) == 0 {
+		return ""
+	}
+
+	filename := filepath.Join(config.ReportTemplateFolder, name)
+	if info, statError := os.Stat(filename); statError != nil || info.IsDir() {
+		return ""
+	}
+
+	return filename
+}
+
+func newStaticReport(config *common.Config, readResult *model.ReadResult) *staticReport {
+	parsedModel := readResult.ParsedModel
+	report := &staticReport{
+		Model:            parsedModel,
+		ModelFilename:    config.InputFile,
+		ThreagileVersion: docs.ThreagileVersion,
+		BuildTimestamp:   config.BuildTimestamp,
+		GeneratedAt:      time.Now().Format("2006-01-02 15:04:05"),
+		IntroTextRAA:     readResult.IntroTextRAA,
+		RAAParameters:    make([]string, 0),
+		AttackPaths:      readResult.AttackPaths,
+	}
+	if readResult.RAACalculator != nil {
+		report.RAAAlgorithm, report.RAAParameters = readResult.RAACalculator.Name(), readResult.RAACalculator.Parameters()
+	}
+
+	risksById := make(map[string]*staticRisk)
+	for _, category := range types.SortedRiskCategories(parsedModel) {
+		staticCategory := &staticRiskCategory{RiskCategory: category, Risks: make([]*staticRisk, 0)}
+		for _, risk := range types.SortedRisksOfCategory(parsedModel, category) {
+			staticRisk := &staticRisk{Risk: risk, Category: category, Tracking: risk.GetRiskTrackingWithDefault(parsedModel)}
+			staticCategory.Risks = append(staticCategory.Risks, staticRisk)
+			risksById[risk.SyntheticId] = staticRisk
+			if staticRisk.IsStillAtRisk() {
+				staticCategory.StillAtRisk++
+				if risk.Severity > staticCategory.HighestSeverity {
+					staticCategory.HighestSeverity = risk.Severity
+				}
+			}
+		}
+		report.RiskCategories = append(report.RiskCategories, staticCategory)
+	}
+
+	report.Severities = staticSeverities(report.RiskCategories)
+	report.Statuses = staticStatuses(report.RiskCategories)
+	report.STRIDE = make([]*staticRiskGroup, 0)
+	for _, value := range types.STRIDEValues() {
+		stride := value.(types.STRIDE)
+		report.STRIDE = append(report.STRIDE, newStaticRiskGroup(stride.Title(), stride.Explain(), report.RiskCategories,
+			func(category *staticRiskCategory) bool { return category.STRIDE == stride }))
+	}
+	report.Functions = make([]*staticRiskGroup, 0)
+	for _, value := range types.RiskFunctionValues() {
+		function := value.(types.RiskFunction)
+		report.Functions = append(report.Functions, newStaticRiskGroup(function.Title(), function.Explain(), report.RiskCategories,
+			func(category *staticRiskCategory) bool { return category.Function == function }))
+	}
+
+	report.TechnicalAssets = make([]*staticTechnicalAsset, 0)
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		report.TechnicalAssets = append(report.TechnicalAssets, newStaticTechnicalAsset(parsedModel, parsedModel.TechnicalAssets[id], risksById))
+	}
+	sort.SliceStable(report.TechnicalAssets, func(i, j int) bool {
+		return report.TechnicalAssets[i].Title < report.TechnicalAssets[j].Title
+	})
+
+	report.RAARanking = make([]*staticTechnicalAsset, 0)
+	for _, techAsset := range report.TechnicalAssets {
+		if !techAsset.OutOfScope {
+			report.RAARanking = append(report.RAARanking, techAsset)
+		}
+	}
+	sort.SliceStable(report.RAARanking, func(i, j int) bool {
+		return report.RAARanking[i].RAA > report.RAARanking[j].RAA
+	})
+
+	dataAssets := make([]*types.DataAsset, 0)
+	for _, dataAsset := range parsedModel.DataAssets {
+		dataAssets = append(dataAssets, dataAsset)
+	}
+	sort.Sort(types.ByDataAssetTitleSort(dataAssets))
+	report.DataAssets = make([]*staticDataAsset, 0)
+	for _, dataAsset := range dataAssets {
+		report.DataAssets = append(report.DataAssets, newStaticDataAsset(parsedModel, dataAsset, risksById))
+	}
+
+	report.RulesChecked = staticRulesChecked(parsedModel, config.SkipRiskRules, readResult.BuiltinRiskRules, readResult.CustomRiskRules)
+
+	report.DataFlowDiagram = readStaticDiagram("Data-Flow Diagram", config.OutputFolder, config.DataFlowDiagramFilenameSVG)
+	report.DataAssetDiagram = readStaticDiagram("Data Mapping", config.OutputFolder, config.DataAssetDiagramFilenameSVG)
+	report.AttackPathDiagram = readStaticDiagram("Attack Paths", config.OutputFolder, config.AttackPathDiagramFilenameSVG)
+
+	return report
+}
+
+func staticSeverities(categories []*staticRiskCategory) []*staticSeverity {
+	severities := make([]*staticSeverity, 0)
+	values := types.RiskSeverityValues()
+	for n := len(values) - 1; n >= 0; n-- {
+		severity := &staticSeverity{Severity: values[n].(types.RiskSeverity)}
+		for _, category := range categories {
+			for _, risk := range category.Risks {
+				if risk.Severity != severity.Severity {
+					continue
+				}
+
+				severity.Total++
+				if risk.IsStillAtRisk() {
+					severity.StillAtRisk++
+				}
+			}
+		}
+		severities = append(severities, severity)
+	}
+
+	return severities
+}
+
+func staticStatuses(categories []*staticRiskCategory) []*staticStatus {
+	statuses := make([]*staticStatus, 0)
+	for _, value := range types.RiskStatusValues() {
+		status := &staticStatus{Status: value.(types.RiskStatus)}
+		for _, category := range categories {
+			for _, risk := range category.Risks {
+				if risk.Tracking.Status == status.Status {
+					status.Count++
+				}
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+func newStaticRiskGroup(title string, explanation string, categories []*staticRiskCategory, matches func(category *staticRiskCategory) bool) *staticRiskGroup {
+	group := &staticRiskGroup{Title: title, Explanation: explanation, Categories: make([]*staticRiskCategory, 0)}
+	for _, category := range categories {
+		if matches(category) {
+			group.Categories = append(group.Categories, category)
+			group.Total += len(category.Risks)
+			group.StillAtRisk += category.StillAtRisk
+		}
+	}
+
+	return group
+}
+
+func newStaticTechnicalAsset(parsedModel *types.Model, techAsset *types.TechnicalAsset, risksById map[string]*staticRisk) *staticTechnicalAsset {
+	staticAsset := &staticTechnicalAsset{
+		TechnicalAsset:      techAsset,
+		TrustBoundary:       parsedModel.DirectContainingTrustBoundaryMappedByTechnicalAssetId[techAsset.Id],
+		DataAssetsProcessed: techAsset.DataAssetsProcessedSorted(parsedModel),
+		DataAssetsStored:    techAsset.DataAssetsStoredSorted(parsedModel),
+		CommunicationLinks:  techAsset.CommunicationLinksSorted(),
+		IncomingLinks:       make([]*types.CommunicationLink, 0),
+		Risks:               make([]*staticRisk, 0),
+	}
+
+	staticAsset.IncomingLinks = append(staticAsset.IncomingLinks, parsedModel.IncomingTechnicalCommunicationLinksMappedByTargetId[techAsset.Id]...)
+	sort.SliceStable(staticAsset.IncomingLinks, func(i, j int) bool {
+		return staticAsset.IncomingLinks[i].Id < staticAsset.IncomingLinks[j].Id
+	})
+
+	risks := techAsset.GeneratedRisks(parsedModel)
+	types.SortByRiskSeverity(risks, parsedModel)
+	for _, risk := range risks {
+		if staticRisk, ok := risksById[risk.SyntheticId]; ok {
+			staticAsset.Risks = append(staticAsset.Risks, staticRisk)
+			if staticRisk.IsStillAtRisk() {
+				staticAsset.StillAtRisk++
+				if risk.Severity > staticAsset.HighestSeverity {
+					staticAsset.HighestSeverity = risk.Severity
+				}
+			}
+		}
+	}
+
+	return staticAsset
+}
+
+func newStaticDataAsset(parsedModel *types.Model, dataAsset *types.DataAsset, risksById map[string]*staticRisk) *staticDataAsset {
+	staticAsset := &staticDataAsset{
+		DataAsset:             dataAsset,
+		ProcessedBy:           dataAsset.ProcessedByTechnicalAssetsSorted(parsedModel),
+		StoredBy:              dataAsset.StoredByTechnicalAssetsSorted(parsedModel),
+		SentVia:               dataAsset.SentViaCommLinksSorted(parsedModel),
+		ReceivedVia:           dataAsset.ReceivedViaCommLinksSorted(parsedModel),
+		DataBreachProbability: dataAsset.IdentifiedDataBreachProbability(parsedModel),
+		DataBreachRisks:       make([]*staticRisk, 0),
+	}
+
+	risks := dataAsset.IdentifiedDataBreachProbabilityRisks(parsedModel)
+	types.SortByDataBreachProbability(risks, parsedModel)
+	for _, risk := range risks {
+		if staticRisk, ok := risksById[risk.SyntheticId]; ok {
+			staticAsset.DataBreachRisks = append(staticAsset.DataBreachRisks, staticRisk)
+		}
+	}
+
+	return staticAsset
+}
+
+func staticRulesChecked(parsedModel *types.Model, skipRiskRules []string, builtinRiskRules types.RiskRules, customRiskRules types.RiskRules) []*staticRule {
+	rules := make([]*staticRule, 0)
+	add := func(category *types.RiskCategory, kind string) {
+		rules = append(rules, &staticRule{
+			RiskCategory: category,
+			Kind:         kind,
+			Skipped:      contains(skipRiskRules, category.ID),
+			Risks:        len(parsedModel.GeneratedRisksByCategory[category.ID]),
+		})
+	}
+
+	for _, rule := range customRiskRules {
+		add(rule.Category(), "custom risk rule")
+	}
+	for _, category := range parsedModel.CustomRiskCategories {
+		add(category, "individual risk category")
+	}
+	for _, rule := range builtinRiskRules {
+		add(rule.Category(), "built-in risk rule")
+	}
+
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].Kind != rules[j].Kind {
+			return rules[i].Kind > rules[j].Kind
+		}
+		return rules[i].Title < rules[j].Title
+	})
+
+	return rules
+}
+
+// readStaticDiagram reads a rendered SVG diagram, if any, leaving out the XML declaration and doctype for inlining it
+
+func readStaticDiagram(title string, folder string, filename string) *staticDiagram {
+	if len(filename) == 0 {
+		return nil
+	}
+
+	data, readError := os.ReadFile(filepath.Join(folder, filename))
+	if readError != nil {
+		return nil
+	}
+
+	svg := string(data)
+	if start := strings.Index(svg, "<svg"); start >= 0 {
+		svg = svg[start:]
+	}
+
+	return &staticDiagram{Title: title, Filename: filename, SVG: htmltemplate.HTML(svg)} // #nosec G203 // rendered by graphviz or the built-in renderer
+}
+
+var (
+	htmlMarkupPattern = regexp.MustCompile(`(?i)&lt;(/?[biu]|br\s*/?)&gt;`)
+	htmlLinkPattern   = regexp.MustCompile(`(?i)&lt;a\s+href=&#34;(https?://(?:[^&\s]|&amp;)+)&#34;&gt;(.*?)&lt;/a&gt;`)
+)
+
+// htmlMarkup escapes texts of the model and of risk rules for the HTML report, except for the few tags they may use
+// for formatting: bold, italic, underline, line breaks and links to web pages
+
+func htmlMarkup(text string) htmltemplate.HTML {
+	escaped := htmltemplate.HTMLEscapeString(text)
+	escaped = htmlMarkupPattern.ReplaceAllStringFunc(escaped, func(tag string) string {
+		tag = strings.ToLower(htmlMarkupPattern.FindStringSubmatch(tag)[1])
+		if strings.HasPrefix(tag, "br") {
+			return "<br>"
+		}
+		return "<" + tag + ">"
+	})
+	escaped = htmlLinkPattern.ReplaceAllString(escaped, `<a href="$1">$2</a>`)
+
+	return htmltemplate.HTML(escaped) // #nosec G203 // escaped above
+}
+
+var (
+	markdownBoldPattern      = regexp.MustCompile(`(?i)</?(b|strong)>`)
+	markdownItalicPattern    = regexp.MustCompile(`(?i)</?(i|em)>`)
+	markdownLineBreakPattern = regexp.MustCompile(`(?i)<br\s*/?>|</?p>`)
+	markdownLinkPattern      = regexp.MustCompile(`(?i)<a\s+href="([^"]*)"[^>]*>(.*?)</a>`)
+)
+
+// markdownText turns the HTML snippets risk rules use in their texts into markdown
+
+func markdownText(text string) string {
+	text = markdownLinkPattern.ReplaceAllString(text, "[$2]($1)")
+	text = markdownBoldPattern.ReplaceAllString(text, "**")
+	text = markdownItalicPattern.ReplaceAllString(text, "_")
+	text = markdownLineBreakPattern.ReplaceAllString(text, "\n\n")
+	text = common.StripHTMLTags(text)
+	return strings.TrimSpace(text)
+}
+
+// markdownCell is markdownText squeezed into a single line for table cells
+
+func markdownCell(text string) string {
+	text = strings.Join(strings.Fields(markdownText(text)), " ")
+	return strings.ReplaceAll(text, "|", `\|`)
+}
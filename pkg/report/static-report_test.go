@@ -0,0 +1,75 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestHTMLMarkup(t *testing.T) {
+	for text, expected := range map[string]string{
+		"plain":                                              "plain",
+		"<b>bold</b>, <I>italic</I>, <u>u</u>":               "<b>bold</b>, <i>italic</i>, <u>u</u>",
+		"line<br>break<br/>and<BR />more":                    "line<br>break<br>and<br>more",
+		"<script>alert(1)</script>":                          "&lt;script&gt;alert(1)&lt;/script&gt;",
+		`<b onclick="alert(1)">x</b>`:                        "&lt;b onclick=&#34;alert(1)&#34;&gt;x</b>",
+		`<img src=x onerror=alert(1)>`:                       "&lt;img src=x onerror=alert(1)&gt;",
+		`<a href="https://example.com?a=1&b=2">`:             `&lt;a href=&#34;https://example.com?a=1&amp;b=2&#34;&gt;`,
+		`see <a href="https://example.com?a=1&b=2">here</a>`: `see <a href="https://example.com?a=1&amp;b=2">here</a>`,
+		`<a href="javascript:alert(1)">x</a>`:                `&lt;a href=&#34;javascript:alert(1)&#34;&gt;x&lt;/a&gt;`,
+	} {
+		assert.Equal(t, expected, string(htmlMarkup(text)), text)
+	}
+}
+
+func TestWriteReportHTMLEscapesModelTexts(t *testing.T) {
+	category := &types.RiskCategory{
+		ID:          "something-strange",
+		Title:       "Something Strange",
+		Description: "Found <b>something</b> strange",
+	}
+	risk := &types.Risk{
+		CategoryId:                   category.ID,
+		Severity:                     types.HighSeverity,
+		Title:                        "<b>Something Strange</b> at <b><script>alert('title')</script></b>",
+		SyntheticId:                  "something-strange@web",
+		MostRelevantTechnicalAssetId: "web",
+	}
+	parsedModel := &types.Model{
+		Title: "<script>alert('model')</script>",
+		BusinessOverview: input.Overview{
+			Description: "<i>Shop</i> with <img src=x onerror=alert('description')>",
+		},
+		TechnicalAssets: map[string]*types.TechnicalAsset{
+			"web": {Id: "web", Title: "<script>alert('asset')</script>", Description: "<iframe src=evil></iframe>"},
+		},
+		DataAssets:                  map[string]*types.DataAsset{},
+		CustomRiskCategories:        types.RiskCategories{category},
+		GeneratedRisksByCategory:    map[string][]*types.Risk{category.ID: {risk}},
+		GeneratedRisksBySyntheticId: map[string]*types.Risk{risk.SyntheticId: risk},
+		RiskTracking:                map[string]*types.RiskTracking{},
+	}
+
+	config := new(common.Config).Defaults("")
+	config.OutputFolder = t.TempDir()
+	filename := filepath.Join(config.OutputFolder, "report.html")
+	require.NoError(t, WriteReportHTML(config, &model.ReadResult{ParsedModel: parsedModel}, filename))
+
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	report := string(data)
+
+	assert.NotContains(t, report, "<script>alert")
+	assert.NotContains(t, report, "<img src=x")
+	assert.NotContains(t, report, "<iframe")
+	assert.Contains(t, report, "<b>Something Strange</b> at <b>&lt;script&gt;alert(&#39;title&#39;)&lt;/script&gt;</b>")
+	assert.Contains(t, report, "<i>Shop</i> with &lt;img src=x onerror=alert(&#39;description&#39;)&gt;")
+	assert.Contains(t, report, "Found <b>something</b> strange")
+}
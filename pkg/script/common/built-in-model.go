@@ -0,0 +1,733 @@
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const (
+	technologyHasAttribute               = "technology_has_attribute"
+	technologiesUnknown                  = "technologies_unknown"
+	technologiesString                   = "technologies_string"
+	assetHighest                         = "asset_highest"
+	assetHighestProcessed                = "asset_highest_processed"
+	assetHighestStored                   = "asset_highest_stored"
+	linkHighest                          = "link_highest"
+	trustBoundaryHighest                 = "trust_boundary_highest"
+	sharedRuntimeHighest                 = "shared_runtime_highest"
+	highestConfidentiality               = "highest_confidentiality"
+	highestIntegrity                     = "highest_integrity"
+	highestAvailability                  = "highest_availability"
+	sensitivityScore                     = "sensitivity_score"
+	isTaggedWithAny                      = "is_tagged_with_any"
+	isTaggedWithBaseTag                  = "is_tagged_with_base_tag"
+	isTaggedWithAnyTraversingUp          = "is_tagged_with_any_traversing_up"
+	isAcrossTrustBoundary                = "is_across_trust_boundary"
+	isAcrossTrustBoundaryNetworkOnly     = "is_across_trust_boundary_network_only"
+	isSameTrustBoundary                  = "is_same_trust_boundary"
+	isSameTrustBoundaryNetworkOnly       = "is_same_trust_boundary_network_only"
+	isSameExecutionEnvironment           = "is_same_execution_environment"
+	hasDirectConnection                  = "has_direct_connection"
+	trustBoundaryOf                      = "trust_boundary_of"
+	directTrustBoundaryOf                = "direct_trust_boundary_of"
+	trustBoundaryAssets                  = "trust_boundary_assets"
+	parentTrustBoundaries                = "parent_trust_boundaries"
+	isWithinCloud                        = "is_within_cloud"
+	isNetworkBoundary                    = "is_network_boundary"
+	isProcessLocal                       = "is_process_local"
+	isEncryptedProtocol                  = "is_encrypted_protocol"
+	isPotentialDatabaseAccessProtocol    = "is_potential_database_access_protocol"
+	isPotentialLaxDatabaseAccessProtocol = "is_potential_lax_database_access_protocol"
+	isPotentialWebAccessProtocol         = "is_potential_web_access_protocol"
+	incomingLinks                        = "incoming_links"
+	outgoingLinks                        = "outgoing_links"
+	listFunc                             = "list"
+	appendFunc                           = "append"
+	uniqueFunc                           = "unique"
+	sortFunc                             = "sort"
+	filterFunc                           = "filter"
+	lowerFunc                            = "lower"
+	matchesFunc                          = "matches"
+	startsWithFunc                       = "starts_with"
+	endsWithFunc                         = "ends_with"
+)
+
+// technology_has_attribute(asset, attribute...) tells whether any technology of the asset has any of the attributes
+
+func technologyHasAttributeFunc(scope *Scope, parameters []Value) (any, error) {
+	asset, assetError := toTechnicalAsset(scope, parameters[0])
+	if assetError != nil {
+		return nil, assetError
+	}
+
+	attributes, attributesError := toStrings(parameters[1:])
+	if attributesError != nil {
+		return nil, attributesError
+	}
+
+	return asset.Technologies.GetAttribute(attributes[0], attributes[1:]...), nil
+}
+
+func technologiesUnknownFunc(scope *Scope, parameters []Value) (any, error) {
+	asset, assetError := toTechnicalAsset(scope, parameters[0])
+	if assetError != nil {
+		return nil, assetError
+	}
+
+	return asset.Technologies.IsUnknown(), nil
+}
+
+func technologiesStringFunc(scope *Scope, parameters []Value) (any, error) {
+	asset, assetError := toTechnicalAsset(scope, parameters[0])
+	if assetError != nil {
+		return nil, assetError
+	}
+
+	return asset.Technologies.String(), nil
+}
+
+// asset_highest(asset, aspect) is the highest rating of the asset and the data assets it processes or stores
+
+func assetHighestFunc(scope *Scope, parameters []Value) (any, error) {
+	return highestOfTechnicalAsset(scope, parameters, func(asset *types.TechnicalAsset, model *types.Model) (types.Confidentiality, types.Criticality, types.Criticality) {
+		return asset.HighestConfidentiality(model), asset.HighestIntegrity(model), asset.HighestAvailability(model)
+	})
+}
+
+func assetHighestProcessedFunc(scope *Scope, parameters []Value) (any, error) {
+	return highestOfTechnicalAsset(scope, parameters, func(asset *types.TechnicalAsset, model *types.Model) (types.Confidentiality, types.Criticality, types.Criticality) {
+		return asset.HighestProcessedConfidentiality(model), asset.HighestProcessedIntegrity(model), asset.HighestProcessedAvailability(model)
+	})
+}
+
+func assetHighestStoredFunc(scope *Scope, parameters []Value) (any, error) {
+	return highestOfTechnicalAsset(scope, parameters, func(asset *types.TechnicalAsset, model *types.Model) (types.Confidentiality, types.Criticality, types.Criticality) {
+		return asset.HighestStoredConfidentiality(model), asset.HighestStoredIntegrity(model), asset.HighestStoredAvailability(model)
+	})
+}
+
+func linkHighestFunc(scope *Scope, parameters []Value) (any, error) {
+	link, linkError := toCommunicationLink(scope, parameters[0])
+	if linkError != nil {
+		return nil, linkError
+	}
+
+	model := scope.GetParsedModel()
+	return selectAspect(parameters[1], link.HighestConfidentiality(model), link.HighestIntegrity(model), link.HighestAvailability(model))
+}
+
+func trustBoundaryHighestFunc(scope *Scope, parameters []Value) (any, error) {
+	trustBoundary, trustBoundaryError := toTrustBoundary(scope, parameters[0])
+	if trustBoundaryError != nil {
+		return nil, trustBoundaryError
+	}
+
+	model := scope.GetParsedModel()
+	return selectAspect(parameters[1], trustBoundary.HighestConfidentiality(model), trustBoundary.HighestIntegrity(model), trustBoundary.HighestAvailability(model))
+}
+
+func sharedRuntimeHighestFunc(scope *Scope, parameters []Value) (any, error) {
+	sharedRuntime, sharedRuntimeError := toSharedRuntime(scope, parameters[0])
+	if sharedRuntimeError != nil {
+		return nil, sharedRuntimeError
+	}
+
+	model := scope.GetParsedModel()
+	return selectAspect(parameters[1], sharedRuntime.HighestConfidentiality(model), sharedRuntime.HighestIntegrity(model), sharedRuntime.HighestAvailability(model))
+}
+
+// highest_confidentiality(item) is the highest confidentiality of a technical asset, communication link, trust
+// boundary, shared runtime or data asset, taking into account the data it handles or contains
+
+func highestConfidentialityFunc(scope *Scope, parameters []Value) (any, error) {
+	return highestOfElement(scope, parameters[0], confidentiality)
+}
+
+func highestIntegrityFunc(scope *Scope, parameters []Value) (any, error) {
+	return highestOfElement(scope, parameters[0], integrity)
+}
+
+func highestAvailabilityFunc(scope *Scope, parameters []Value) (any, error) {
+	return highestOfElement(scope, parameters[0], availability)
+}
+
+func sensitivityScoreFunc(scope *Scope, parameters []Value) (any, error) {
+	asset, assetError := toTechnicalAsset(scope, parameters[0])
+	if assetError != nil {
+		return nil, assetError
+	}
+
+	return asset.HighestSensitivityScore(), nil
+}
+
+// is_tagged_with_any(item, tag...) works on any model element with tags
+
+func isTaggedWithAnyFunc(_ *Scope, parameters []Value) (any, error) {
+	tags, tagsError := toStrings(parameters[1:])
+	if tagsError != nil {
+		return nil, tagsError
+	}
+
+	for _, tag := range tagsOf(parameters[0]) {
+		for _, candidate := range tags {
+			if strings.EqualFold(strings.TrimSpace(tag), strings.TrimSpace(candidate)) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func isTaggedWithBaseTagFunc(_ *Scope, parameters []Value) (any, error) {
+	return types.IsTaggedWithBaseTag(tagsOf(parameters[0]), parameters[1].(string)), nil
+}
+
+// is_tagged_with_any_traversing_up(item, tag...) also looks at the trust boundaries containing a technical asset or
+// trust boundary
+
+func isTaggedWithAnyTraversingUpFunc(scope *Scope, parameters []Value) (any, error) {
+	tags, tagsError := toStrings(parameters[1:])
+	if tagsError != nil {
+		return nil, tagsError
+	}
+
+	model, id, idError := modelElementId(scope, parameters[0])
+	if idError != nil {
+		return nil, idError
+	}
+
+	if asset, ok := model.TechnicalAssets[id]; ok {
+		return asset.IsTaggedWithAnyTraversingUp(model, tags...), nil
+	}
+
+	if trustBoundary, ok := model.TrustBoundaries[id]; ok {
+		return trustBoundary.IsTaggedWithAnyTraversingUp(model, tags...), nil
+	}
+
+	return nil, fmt.Errorf("unknown technical asset or trust boundary %q", id)
+}
+
+func isAcrossTrustBoundaryFunc(scope *Scope, parameters []Value) (any, error) {
+	link, linkError := toCommunicationLink(scope, parameters[0])
+	if linkError != nil {
+		return nil, linkError
+	}
+
+	return link.IsAcrossTrustBoundary(scope.GetParsedModel()), nil
+}
+
+func isAcrossTrustBoundaryNetworkOnlyFunc(scope *Scope, parameters []Value) (any, error) {
+	link, linkError := toCommunicationLink(scope, parameters[0])
+	if linkError != nil {
+		return nil, linkError
+	}
+
+	return link.IsAcrossTrustBoundaryNetworkOnly(scope.GetParsedModel()), nil
+}
+
+func isSameTrustBoundaryFunc(scope *Scope, parameters []Value) (any, error) {
+	return compareTechnicalAssets(scope, parameters, (*types.TechnicalAsset).IsSameTrustBoundary)
+}
+
+func isSameTrustBoundaryNetworkOnlyFunc(scope *Scope, parameters []Value) (any, error) {
+	return compareTechnicalAssets(scope, parameters, (*types.TechnicalAsset).IsSameTrustBoundaryNetworkOnly)
+}
+
+func isSameExecutionEnvironmentFunc(scope *Scope, parameters []Value) (any, error) {
+	return compareTechnicalAssets(scope, parameters, (*types.TechnicalAsset).IsSameExecutionEnvironment)
+}
+
+func hasDirectConnectionFunc(scope *Scope, parameters []Value) (any, error) {
+	return compareTechnicalAssets(scope, parameters, (*types.TechnicalAsset).HasDirectConnection)
+}
+
+// trust_boundary_of(asset) is the id of the trust boundary listing the asset, or "" if there is none
+
+func trustBoundaryOfFunc(scope *Scope, parameters []Value) (any, error) {
+	asset, assetError := toTechnicalAsset(scope, parameters[0])
+	if assetError != nil {
+		return nil, assetError
+	}
+
+	return asset.GetTrustBoundaryId(scope.GetParsedModel()), nil
+}
+
+// direct_trust_boundary_of(asset) is the id of the innermost trust boundary containing the asset, or "" if there is none
+
+func directTrustBoundaryOfFunc(scope *Scope, parameters []Value) (any, error) {
+	asset, assetError := toTechnicalAsset(scope, parameters[0])
+	if assetError != nil {
+		return nil, assetError
+	}
+
+	trustBoundary, ok := scope.GetParsedModel().DirectContainingTrustBoundaryMappedByTechnicalAssetId[asset.Id]
+	if !ok || trustBoundary == nil {
+		return "", nil
+	}
+
+	return trustBoundary.Id, nil
+}
+
+func trustBoundaryAssetsFunc(scope *Scope, parameters []Value) (any, error) {
+	trustBoundary, trustBoundaryError := toTrustBoundary(scope, parameters[0])
+	if trustBoundaryError != nil {
+		return nil, trustBoundaryError
+	}
+
+	return toList(trustBoundary.RecursivelyAllTechnicalAssetIDsInside(scope.GetParsedModel())), nil
+}
+
+func parentTrustBoundariesFunc(scope *Scope, parameters []Value) (any, error) {
+	trustBoundary, trustBoundaryError := toTrustBoundary(scope, parameters[0])
+	if trustBoundaryError != nil {
+		return nil, trustBoundaryError
+	}
+
+	return toList(trustBoundary.AllParentTrustBoundaryIDs(scope.GetParsedModel())), nil
+}
+
+func isWithinCloudFunc(scope *Scope, parameters []Value) (any, error) {
+	trustBoundary, trustBoundaryError := toTrustBoundary(scope, parameters[0])
+	if trustBoundaryError != nil {
+		return nil, trustBoundaryError
+	}
+
+	return trustBoundary.Type.IsWithinCloud(), nil
+}
+
+func isNetworkBoundaryFunc(scope *Scope, parameters []Value) (any, error) {
+	trustBoundary, trustBoundaryError := toTrustBoundary(scope, parameters[0])
+	if trustBoundaryError != nil {
+		return nil, trustBoundaryError
+	}
+
+	return trustBoundary.Type.IsNetworkBoundary(), nil
+}
+
+func isProcessLocalFunc(_ *Scope, parameters []Value) (any, error) {
+	return checkProtocol(parameters, types.Protocol.IsProcessLocal)
+}
+
+func isEncryptedProtocolFunc(_ *Scope, parameters []Value) (any, error) {
+	return checkProtocol(parameters, types.Protocol.IsEncrypted)
+}
+
+func isPotentialDatabaseAccessProtocolFunc(_ *Scope, parameters []Value) (any, error) {
+	return checkProtocol(parameters, func(protocol types.Protocol) bool {
+		return protocol.IsPotentialDatabaseAccessProtocol(false)
+	})
+}
+
+func isPotentialLaxDatabaseAccessProtocolFunc(_ *Scope, parameters []Value) (any, error) {
+	return checkProtocol(parameters, func(protocol types.Protocol) bool {
+		return protocol.IsPotentialDatabaseAccessProtocol(true)
+	})
+}
+
+func isPotentialWebAccessProtocolFunc(_ *Scope, parameters []Value) (any, error) {
+	return checkProtocol(parameters, types.Protocol.IsPotentialWebAccessProtocol)
+}
+
+// incoming_links(asset) lists the communication links targeting the asset in the order the model keeps them
+
+func incomingLinksFunc(scope *Scope, parameters []Value) (any, error) {
+	asset, assetError := toTechnicalAsset(scope, parameters[0])
+	if assetError != nil {
+		return nil, assetError
+	}
+
+	return linksOf(scope, scope.GetParsedModel().IncomingTechnicalCommunicationLinksMappedByTargetId[asset.Id])
+}
+
+// outgoing_links(asset) lists the communication links of the asset in the order the reports use
+
+func outgoingLinksFunc(scope *Scope, parameters []Value) (any, error) {
+	asset, assetError := toTechnicalAsset(scope, parameters[0])
+	if assetError != nil {
+		return nil, assetError
+	}
+
+	return linksOf(scope, asset.CommunicationLinksSorted())
+}
+
+func listFuncFunc(_ *Scope, parameters []Value) (any, error) {
+	list := make([]any, 0, len(parameters))
+	for _, parameter := range parameters {
+		list = append(list, parameter)
+	}
+
+	return list, nil
+}
+
+// append(list, item...) returns a new list with the items added; a missing list counts as empty
+
+func appendFuncFunc(_ *Scope, parameters []Value) (any, error) {
+	list, listError := toAnyList(parameters[0])
+	if listError != nil {
+		return nil, listError
+	}
+
+	result := make([]any, 0, len(list)+len(parameters)-1)
+	result = append(result, list...)
+	for _, parameter := range parameters[1:] {
+		result = append(result, parameter)
+	}
+
+	return result, nil
+}
+
+func uniqueFuncFunc(_ *Scope, parameters []Value) (any, error) {
+	list, listError := toAnyList(parameters[0])
+	if listError != nil {
+		return nil, listError
+	}
+
+	seen := make(map[string]bool)
+	result := make([]any, 0, len(list))
+	for _, item := range list {
+		key := fmt.Sprintf("%v", item)
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		result = append(result, item)
+	}
+
+	return result, nil
+}
+
+// sort(list) returns a new list sorted ascending, numbers numerically and texts lexically; a list mixing numbers and
+// texts, or holding anything else, can't be sorted
+
+func sortFuncFunc(_ *Scope, parameters []Value) (any, error) {
+	list, listError := toAnyList(parameters[0])
+	if listError != nil {
+		return nil, listError
+	}
+
+	numbers, texts := 0, 0
+	for _, item := range list {
+		switch {
+		case isNumber(item):
+			numbers++
+
+		case isString(item):
+			texts++
+
+		default:
+			return nil, fmt.Errorf("can't sort a list containing %T", item)
+		}
+	}
+
+	if numbers > 0 && texts > 0 {
+		return nil, fmt.Errorf("can't sort a list mixing numbers and texts")
+	}
+
+	result := append(make([]any, 0, len(list)), list...)
+	var compareError error
+	sort.SliceStable(result, func(i, j int) bool {
+		order, err := Compare(result[i], result[j], "")
+		if err != nil && compareError == nil {
+			compareError = err
+		}
+
+		return order < 0
+	})
+
+	if compareError != nil {
+		return nil, compareError
+	}
+
+	return result, nil
+}
+
+// filter(list, method, parameter...) keeps the items for which the util or built-in returns true; the item is passed
+// as the first parameter, followed by any further parameters
+
+func filterFuncFunc(scope *Scope, parameters []Value) (any, error) {
+	list, listError := toAnyList(parameters[0])
+	if listError != nil {
+		return nil, listError
+	}
+
+	method := strings.ToLower(parameters[1].(string))
+	result := make([]any, 0, len(list))
+	for _, item := range list {
+		value, _, callError := CallMethod(scope, method, append([]Value{item}, parameters[2:]...)...)
+		if callError != nil {
+			return nil, callError
+		}
+
+		keep, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected %q to return a bool, got %T", method, value)
+		}
+
+		if keep {
+			result = append(result, item)
+		}
+	}
+
+	return result, nil
+}
+
+func lowerFuncFunc(_ *Scope, parameters []Value) (any, error) {
+	return strings.ToLower(parameters[0].(string)), nil
+}
+
+// matches(text, regex) tells whether the regular expression matches anywhere in the text
+
+func matchesFuncFunc(scope *Scope, parameters []Value) (any, error) {
+	re, compileError := scope.compiledPattern(parameters[1].(string))
+	if compileError != nil {
+		return nil, fmt.Errorf("invalid regular expression: %v", compileError)
+	}
+
+	return re.MatchString(parameters[0].(string)), nil
+}
+
+func startsWithFuncFunc(_ *Scope, parameters []Value) (any, error) {
+	return strings.HasPrefix(parameters[0].(string), parameters[1].(string)), nil
+}
+
+func endsWithFuncFunc(_ *Scope, parameters []Value) (any, error) {
+	return strings.HasSuffix(parameters[0].(string), parameters[1].(string)), nil
+}
+
+func highestOfTechnicalAsset(scope *Scope, parameters []Value, highest func(asset *types.TechnicalAsset, model *types.Model) (types.Confidentiality, types.Criticality, types.Criticality)) (any, error) {
+	asset, assetError := toTechnicalAsset(scope, parameters[0])
+	if assetError != nil {
+		return nil, assetError
+	}
+
+	confidentialityValue, integrityValue, availabilityValue := highest(asset, scope.GetParsedModel())
+	return selectAspect(parameters[1], confidentialityValue, integrityValue, availabilityValue)
+}
+
+func highestOfElement(scope *Scope, value Value, aspect string) (any, error) {
+	model, id, idError := modelElementId(scope, value)
+	if idError != nil {
+		return nil, idError
+	}
+
+	if asset, ok := model.TechnicalAssets[id]; ok {
+		return selectAspect(aspect, asset.HighestConfidentiality(model), asset.HighestIntegrity(model), asset.HighestAvailability(model))
+	}
+
+	if link, ok := model.CommunicationLinks[id]; ok {
+		return selectAspect(aspect, link.HighestConfidentiality(model), link.HighestIntegrity(model), link.HighestAvailability(model))
+	}
+
+	if trustBoundary, ok := model.TrustBoundaries[id]; ok {
+		return selectAspect(aspect, trustBoundary.HighestConfidentiality(model), trustBoundary.HighestIntegrity(model), trustBoundary.HighestAvailability(model))
+	}
+
+	if sharedRuntime, ok := model.SharedRuntimes[id]; ok {
+		return selectAspect(aspect, sharedRuntime.HighestConfidentiality(model), sharedRuntime.HighestIntegrity(model), sharedRuntime.HighestAvailability(model))
+	}
+
+	if dataAsset, ok := model.DataAssets[id]; ok {
+		return selectAspect(aspect, dataAsset.Confidentiality, dataAsset.Integrity, dataAsset.Availability)
+	}
+
+	return nil, fmt.Errorf("unknown model element %q", id)
+}
+
+func selectAspect(aspect Value, confidentialityValue types.Confidentiality, integrityValue types.Criticality, availabilityValue types.Criticality) (any, error) {
+	switch aspect {
+	case confidentiality:
+		return confidentialityValue.String(), nil
+
+	case integrity:
+		return integrityValue.String(), nil
+
+	case availability:
+		return availabilityValue.String(), nil
+
+	default:
+		return nil, fmt.Errorf("unknown aspect %v, expected %v, %v or %v", aspect, confidentiality, integrity, availability)
+	}
+}
+
+func compareTechnicalAssets(scope *Scope, parameters []Value, compare func(asset *types.TechnicalAsset, model *types.Model, otherAssetId string) bool) (any, error) {
+	asset, assetError := toTechnicalAsset(scope, parameters[0])
+	if assetError != nil {
+		return nil, assetError
+	}
+
+	otherAsset, otherAssetError := toTechnicalAsset(scope, parameters[1])
+	if otherAssetError != nil {
+		return nil, otherAssetError
+	}
+
+	return compare(asset, scope.GetParsedModel(), otherAsset.Id), nil
+}
+
+func checkProtocol(parameters []Value, check func(protocol types.Protocol) bool) (any, error) {
+	value, castError := CastValue(parameters[0], protocol)
+	if castError != nil {
+		return nil, castError
+	}
+
+	return check(types.Protocol(value.(int))), nil
+}
+
+// linksOf looks up the given communication links in the scope's model
+
+func linksOf(scope *Scope, links []*types.CommunicationLink) ([]any, error) {
+	communicationLinks, _ := scope.Model["communication_links"].(map[string]any)
+	result := make([]any, 0, len(links))
+	for _, link := range links {
+		value, ok := communicationLinks[link.Id]
+		if !ok {
+			return nil, fmt.Errorf("unknown communication link %q", link.Id)
+		}
+
+		result = append(result, value)
+	}
+
+	return result, nil
+}
+
+func toTechnicalAsset(scope *Scope, value Value) (*types.TechnicalAsset, error) {
+	model, id, idError := modelElementId(scope, value)
+	if idError != nil {
+		return nil, idError
+	}
+
+	asset, ok := model.TechnicalAssets[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown technical asset %q", id)
+	}
+
+	return asset, nil
+}
+
+func toCommunicationLink(scope *Scope, value Value) (*types.CommunicationLink, error) {
+	model, id, idError := modelElementId(scope, value)
+	if idError != nil {
+		return nil, idError
+	}
+
+	link, ok := model.CommunicationLinks[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown communication link %q", id)
+	}
+
+	return link, nil
+}
+
+func toTrustBoundary(scope *Scope, value Value) (*types.TrustBoundary, error) {
+	model, id, idError := modelElementId(scope, value)
+	if idError != nil {
+		return nil, idError
+	}
+
+	trustBoundary, ok := model.TrustBoundaries[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown trust boundary %q", id)
+	}
+
+	return trustBoundary, nil
+}
+
+func toSharedRuntime(scope *Scope, value Value) (*types.SharedRuntime, error) {
+	model, id, idError := modelElementId(scope, value)
+	if idError != nil {
+		return nil, idError
+	}
+
+	sharedRuntime, ok := model.SharedRuntimes[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown shared runtime %q", id)
+	}
+
+	return sharedRuntime, nil
+}
+
+// modelElementId accepts a model element as found in the scope's model or just its id
+
+func modelElementId(scope *Scope, value Value) (*types.Model, string, error) {
+	model := scope.GetParsedModel()
+	if model == nil {
+		return nil, "", fmt.Errorf("no model in scope")
+	}
+
+	switch castValue := value.(type) {
+	case string:
+		return model, castValue, nil
+
+	case map[string]any:
+		id, ok := castValue["id"].(string)
+		if !ok {
+			return nil, "", fmt.Errorf("model element without id")
+		}
+
+		return model, id, nil
+
+	default:
+		return nil, "", fmt.Errorf("expected model element or id, got %T", value)
+	}
+}
+
+func tagsOf(value Value) []string {
+	item, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	tags, _ := toAnyList(item["tags"])
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if text, isText := tag.(string); isText {
+			result = append(result, text)
+		}
+	}
+
+	return result
+}
+
+func toStrings(values []Value) ([]string, error) {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a string, got %T", value)
+		}
+
+		result = append(result, text)
+	}
+
+	return result, nil
+}
+
+func toAnyList(value Value) ([]any, error) {
+	switch castValue := value.(type) {
+	case []any:
+		return castValue, nil
+
+	case nil:
+		return make([]any, 0), nil
+
+	case string:
+		if len(castValue) == 0 {
+			return make([]any, 0), nil
+		}
+	}
+
+	return nil, fmt.Errorf("expected a list, got %T", value)
+}
+
+func toList(values []string) []any {
+	result := make([]any, 0, len(values))
+	for _, value := range values {
+		result = append(result, value)
+	}
+
+	return result
+}
@@ -0,0 +1,73 @@
+package common
+
+import (
+	"fmt"
+)
+
+var (
+	anyParameter     = parameterType{name: "any value", check: func(Value) bool { return true }}
+	textParameter    = parameterType{name: "a string", check: isText}
+	enumParameter    = parameterType{name: "an enum value", check: isEnum}
+	listParameter    = parameterType{name: "a list", check: isList}
+	elementParameter = parameterType{name: "a model element or id", check: isModelElement}
+	aspectParameter  = parameterType{name: fmt.Sprintf("%v, %v or %v", confidentiality, integrity, availability), check: isAspect}
+)
+
+// parameterType describes what a built-in accepts for a parameter
+
+type parameterType struct {
+	name  string
+	check func(value Value) bool
+}
+
+func parameters(parameterTypes ...parameterType) []parameterType {
+	return parameterTypes
+}
+
+func isText(value Value) bool {
+	_, ok := value.(string)
+	return ok
+}
+
+// isEnum accepts anything CastValue can turn into an enum value; fields holding the zero value are missing
+
+func isEnum(value Value) bool {
+	switch value.(type) {
+	case nil, string, fmt.Stringer, int, int64:
+		return true
+
+	default:
+		return false
+	}
+}
+
+// isList accepts a missing value or an empty string as an empty list
+
+func isList(value Value) bool {
+	_, listError := toAnyList(value)
+	return listError == nil
+}
+
+func isModelElement(value Value) bool {
+	switch castValue := value.(type) {
+	case string:
+		return true
+
+	case map[string]any:
+		_, ok := castValue[ID].(string)
+		return ok
+
+	default:
+		return false
+	}
+}
+
+func isAspect(value Value) bool {
+	switch value {
+	case confidentiality, integrity, availability:
+		return true
+
+	default:
+		return false
+	}
+}
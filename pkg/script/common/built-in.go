@@ -10,41 +10,173 @@ const (
 )
 
 var (
-	callers = map[string]builtInFunc{
-		calculateSeverity: calculateSeverityFunc,
+	callers = map[string]builtIn{
+		calculateSeverity:                    {calculateSeverityFunc, parameters(enumParameter, enumParameter), nil},
+		technologyHasAttribute:               {technologyHasAttributeFunc, parameters(elementParameter, textParameter), &textParameter},
+		technologiesUnknown:                  {technologiesUnknownFunc, parameters(elementParameter), nil},
+		technologiesString:                   {technologiesStringFunc, parameters(elementParameter), nil},
+		assetHighest:                         {assetHighestFunc, parameters(elementParameter, aspectParameter), nil},
+		assetHighestProcessed:                {assetHighestProcessedFunc, parameters(elementParameter, aspectParameter), nil},
+		assetHighestStored:                   {assetHighestStoredFunc, parameters(elementParameter, aspectParameter), nil},
+		linkHighest:                          {linkHighestFunc, parameters(elementParameter, aspectParameter), nil},
+		trustBoundaryHighest:                 {trustBoundaryHighestFunc, parameters(elementParameter, aspectParameter), nil},
+		sharedRuntimeHighest:                 {sharedRuntimeHighestFunc, parameters(elementParameter, aspectParameter), nil},
+		highestConfidentiality:               {highestConfidentialityFunc, parameters(elementParameter), nil},
+		highestIntegrity:                     {highestIntegrityFunc, parameters(elementParameter), nil},
+		highestAvailability:                  {highestAvailabilityFunc, parameters(elementParameter), nil},
+		sensitivityScore:                     {sensitivityScoreFunc, parameters(elementParameter), nil},
+		isTaggedWithAny:                      {isTaggedWithAnyFunc, parameters(anyParameter, textParameter), &textParameter},
+		isTaggedWithBaseTag:                  {isTaggedWithBaseTagFunc, parameters(anyParameter, textParameter), nil},
+		isTaggedWithAnyTraversingUp:          {isTaggedWithAnyTraversingUpFunc, parameters(elementParameter, textParameter), &textParameter},
+		isAcrossTrustBoundary:                {isAcrossTrustBoundaryFunc, parameters(elementParameter), nil},
+		isAcrossTrustBoundaryNetworkOnly:     {isAcrossTrustBoundaryNetworkOnlyFunc, parameters(elementParameter), nil},
+		isSameTrustBoundary:                  {isSameTrustBoundaryFunc, parameters(elementParameter, elementParameter), nil},
+		isSameTrustBoundaryNetworkOnly:       {isSameTrustBoundaryNetworkOnlyFunc, parameters(elementParameter, elementParameter), nil},
+		isSameExecutionEnvironment:           {isSameExecutionEnvironmentFunc, parameters(elementParameter, elementParameter), nil},
+		hasDirectConnection:                  {hasDirectConnectionFunc, parameters(elementParameter, elementParameter), nil},
+		trustBoundaryOf:                      {trustBoundaryOfFunc, parameters(elementParameter), nil},
+		directTrustBoundaryOf:                {directTrustBoundaryOfFunc, parameters(elementParameter), nil},
+		trustBoundaryAssets:                  {trustBoundaryAssetsFunc, parameters(elementParameter), nil},
+		parentTrustBoundaries:                {parentTrustBoundariesFunc, parameters(elementParameter), nil},
+		isWithinCloud:                        {isWithinCloudFunc, parameters(elementParameter), nil},
+		isNetworkBoundary:                    {isNetworkBoundaryFunc, parameters(elementParameter), nil},
+		isProcessLocal:                       {isProcessLocalFunc, parameters(enumParameter), nil},
+		isEncryptedProtocol:                  {isEncryptedProtocolFunc, parameters(enumParameter), nil},
+		isPotentialDatabaseAccessProtocol:    {isPotentialDatabaseAccessProtocolFunc, parameters(enumParameter), nil},
+		isPotentialLaxDatabaseAccessProtocol: {isPotentialLaxDatabaseAccessProtocolFunc, parameters(enumParameter), nil},
+		isPotentialWebAccessProtocol:         {isPotentialWebAccessProtocolFunc, parameters(enumParameter), nil},
+		incomingLinks:                        {incomingLinksFunc, parameters(elementParameter), nil},
+		outgoingLinks:                        {outgoingLinksFunc, parameters(elementParameter), nil},
+		listFunc:                             {listFuncFunc, parameters(), &anyParameter},
+		appendFunc:                           {appendFuncFunc, parameters(listParameter), &anyParameter},
+		uniqueFunc:                           {uniqueFuncFunc, parameters(listParameter), nil},
+		sortFunc:                             {sortFuncFunc, parameters(listParameter), nil},
+		lowerFunc:                            {lowerFuncFunc, parameters(textParameter), nil},
+		matchesFunc:                          {matchesFuncFunc, parameters(textParameter, textParameter), nil},
+		startsWithFunc:                       {startsWithFuncFunc, parameters(textParameter, textParameter), nil},
+		endsWithFunc:                         {endsWithFuncFunc, parameters(textParameter, textParameter), nil},
 	}
 )
 
-type builtInFunc func(parameters []Value) (any, error)
+func init() {
+	// filter calls other built-ins, so it can't be part of the initializer of callers
+	callers[filterFunc] = builtIn{filterFuncFunc, parameters(listParameter, textParameter), &anyParameter}
+}
+
+type builtInFunc func(scope *Scope, parameters []Value) (any, error)
+
+// builtIn is a built-in function with the types of its parameters; a variadic built-in takes any number of
+// additional parameters of the variadic type
+
+type builtIn struct {
+	call       builtInFunc
+	parameters []parameterType
+	variadic   *parameterType
+}
 
 func IsBuiltIn(builtInName string) bool {
 	_, ok := callers[builtInName]
 	return ok
 }
 
-func CallBuiltIn(builtInName string, parameters ...Value) (any, error) {
+func CallBuiltIn(scope *Scope, builtInName string, parameters ...Value) (any, error) {
 	caller, ok := callers[builtInName]
 	if !ok {
 		return nil, fmt.Errorf("unknown built-in %v", builtInName)
 	}
 
-	return caller(parameters)
+	checkError := caller.checkParameters(parameters)
+	if checkError != nil {
+		return nil, checkError
+	}
+
+	return caller.call(scope, parameters)
 }
 
-func calculateSeverityFunc(parameters []Value) (any, error) {
-	if len(parameters) != 2 {
-		return nil, fmt.Errorf("failed to calculate severity: expected 2 parameters, got %d", len(parameters))
+// CallMethod calls a method from the scope's utils or, if there is none by that name, a built-in
+
+func CallMethod(scope *Scope, name string, parameters ...Value) (any, string, error) {
+	method, ok := scope.Methods[name]
+	if ok {
+		newScope, cloneError := scope.Clone()
+		if cloneError != nil {
+			return nil, "", fmt.Errorf("failed to clone scope: %v", cloneError)
+		}
+
+		newScope.Args = parameters
+		errorLiteral, runError := method.Run(newScope)
+		if runError != nil {
+			return nil, errorLiteral, runError
+		}
+
+		return newScope.GetReturnValue(), "", nil
 	}
 
-	likelihoodValue, likelihoodError := toLikelihood(parameters[0])
+	if IsBuiltIn(name) {
+		callValue, callError := CallBuiltIn(scope, name, parameters...)
+		if callError != nil {
+			return callValue, "", fmt.Errorf("failed to call %q: %v", name, callError)
+		}
+
+		return callValue, "", nil
+	}
+
+	return nil, "", fmt.Errorf("no method %q", name)
+}
+
+// CheckBuiltInParameterCount tells whether a built-in can be called with the given number of parameters
+
+func CheckBuiltInParameterCount(builtInName string, count int) error {
+	caller, ok := callers[builtInName]
+	if !ok {
+		return fmt.Errorf("unknown built-in %v", builtInName)
+	}
+
+	return caller.checkParameterCount(count)
+}
+
+func (what builtIn) checkParameterCount(count int) error {
+	if what.variadic == nil && count != len(what.parameters) {
+		return fmt.Errorf("expected %d parameters, got %d", len(what.parameters), count)
+	}
+
+	if count < len(what.parameters) {
+		return fmt.Errorf("expected at least %d parameters, got %d", len(what.parameters), count)
+	}
+
+	return nil
+}
+
+func (what builtIn) checkParameters(values []Value) error {
+	countError := what.checkParameterCount(len(values))
+	if countError != nil {
+		return countError
+	}
+
+	for n, value := range values {
+		parameter := what.variadic
+		if n < len(what.parameters) {
+			parameter = &what.parameters[n]
+		}
+
+		if !parameter.check(value) {
+			return fmt.Errorf("parameter #%d: expected %v, got %T", n+1, parameter.name, value)
+		}
+	}
+
+	return nil
+}
+
+func calculateSeverityFunc(_ *Scope, parameters []Value) (any, error) {
+	likelihoodValue, likelihoodError := CastValue(parameters[0], likelihood)
 	if likelihoodError != nil {
 		return nil, fmt.Errorf("failed to calculate severity: %v", likelihoodError)
 	}
 
-	impactValue, impactError := toImpact(parameters[1])
+	impactValue, impactError := CastValue(parameters[1], impact)
 	if impactError != nil {
 		return nil, fmt.Errorf("failed to calculate severity: %v", impactError)
 	}
 
-	return types.CalculateSeverity(likelihoodValue.(types.RiskExploitationLikelihood), impactValue.(types.RiskExploitationImpact)).String(), nil
+	return types.CalculateSeverity(types.RiskExploitationLikelihood(likelihoodValue.(int)), types.RiskExploitationImpact(impactValue.(int))).String(), nil
 }
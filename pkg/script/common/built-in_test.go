@@ -0,0 +1,312 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// isDbUtil is a util telling whether its parameter is the id "db"
+
+type isDbUtil struct{}
+
+func (what isDbUtil) Run(scope *Scope) (string, error) {
+	scope.SetReturnValue(scope.Args[0] == "db")
+	return "", nil
+}
+
+func (what isDbUtil) Literal() string {
+	return "is_db"
+}
+
+func newBuiltInTestScope(t *testing.T) *Scope {
+	link := &types.CommunicationLink{
+		Id:             "web>db",
+		SourceId:       "web",
+		TargetId:       "db",
+		Protocol:       types.JDBC,
+		DataAssetsSent: []string{"secrets"},
+	}
+	web := &types.TechnicalAsset{
+		Id:                  "web",
+		Confidentiality:     types.Internal,
+		DataAssetsProcessed: []string{"secrets"},
+		Technologies:        types.TechnologyList{{Name: "web-server", Attributes: map[string]bool{"web_application": true}}},
+		CommunicationLinks:  []*types.CommunicationLink{link},
+	}
+	db := &types.TechnicalAsset{
+		Id:              "db",
+		Confidentiality: types.Confidential,
+		Tags:            []string{"database"},
+	}
+	vpc := &types.TrustBoundary{
+		Id:                    "vpc",
+		TechnicalAssetsInside: []string{"web"},
+	}
+	cloud := &types.TrustBoundary{
+		Id:                    "cloud",
+		Type:                  types.NetworkCloudProvider,
+		Tags:                  []string{"aws"},
+		TechnicalAssetsInside: []string{"db"},
+		TrustBoundariesNested: []string{"vpc"},
+	}
+	model := &types.Model{
+		TechnicalAssets:    map[string]*types.TechnicalAsset{"web": web, "db": db},
+		CommunicationLinks: map[string]*types.CommunicationLink{link.Id: link},
+		TrustBoundaries:    map[string]*types.TrustBoundary{"vpc": vpc, "cloud": cloud},
+		DataAssets: map[string]*types.DataAsset{
+			"secrets": {Id: "secrets", Confidentiality: types.StrictlyConfidential, Integrity: types.Critical, Availability: types.Important},
+		},
+		DirectContainingTrustBoundaryMappedByTechnicalAssetId: map[string]*types.TrustBoundary{"web": vpc, "db": cloud},
+		IncomingTechnicalCommunicationLinksMappedByTargetId:   map[string][]*types.CommunicationLink{"db": {link}},
+	}
+
+	scope := new(Scope)
+	assert.NoError(t, scope.Init(nil, map[string]Statement{"is_db": isDbUtil{}}))
+	assert.NoError(t, scope.SetModel(model))
+
+	return scope
+}
+
+func Test_CallBuiltIn_UnknownBuiltInFails(t *testing.T) {
+	_, callError := CallBuiltIn(newBuiltInTestScope(t), "no_such_built_in")
+
+	assert.Error(t, callError)
+}
+
+func Test_CallBuiltIn_WrongParameterCountFails(t *testing.T) {
+	scope := newBuiltInTestScope(t)
+
+	_, callError := CallBuiltIn(scope, lowerFunc, "a", "b")
+	assert.EqualError(t, callError, "expected 1 parameters, got 2")
+
+	_, callError = CallBuiltIn(scope, technologyHasAttribute, "web")
+	assert.EqualError(t, callError, "expected at least 2 parameters, got 1")
+}
+
+func Test_CallBuiltIn_WrongParameterTypeFails(t *testing.T) {
+	scope := newBuiltInTestScope(t)
+
+	_, callError := CallBuiltIn(scope, lowerFunc, true)
+	assert.EqualError(t, callError, "parameter #1: expected a string, got bool")
+
+	_, callError = CallBuiltIn(scope, assetHighest, "web", "secrecy")
+	assert.ErrorContains(t, callError, "parameter #2: expected confidentiality, integrity or availability")
+
+	_, callError = CallBuiltIn(scope, isAcrossTrustBoundary, map[string]any{"title": "no id"})
+	assert.ErrorContains(t, callError, "parameter #1: expected a model element or id")
+
+	_, callError = CallBuiltIn(scope, uniqueFunc, 42)
+	assert.ErrorContains(t, callError, "parameter #1: expected a list")
+
+	_, callError = CallBuiltIn(scope, technologyHasAttribute, "web", "a", 42)
+	assert.ErrorContains(t, callError, "parameter #3: expected a string")
+}
+
+func Test_CallBuiltIn_UnknownModelElementFails(t *testing.T) {
+	_, callError := CallBuiltIn(newBuiltInTestScope(t), incomingLinks, "missing")
+
+	assert.ErrorContains(t, callError, "unknown technical asset")
+}
+
+func Test_CallBuiltIn_CalculateSeverity(t *testing.T) {
+	result, callError := CallBuiltIn(newBuiltInTestScope(t), calculateSeverity, "likely", "high")
+
+	assert.NoError(t, callError)
+	assert.Equal(t, types.CalculateSeverity(types.Likely, types.HighImpact).String(), result)
+}
+
+func Test_CallBuiltIn_IsAcrossTrustBoundary(t *testing.T) {
+	scope := newBuiltInTestScope(t)
+	link := scope.Model["communication_links"].(map[string]any)["web>db"]
+
+	result, callError := CallBuiltIn(scope, isAcrossTrustBoundary, link)
+	assert.NoError(t, callError)
+	assert.Equal(t, true, result)
+
+	result, callError = CallBuiltIn(scope, isSameTrustBoundary, "web", "db")
+	assert.NoError(t, callError)
+	assert.Equal(t, false, result)
+}
+
+func Test_CallBuiltIn_IncomingAndOutgoingLinks(t *testing.T) {
+	scope := newBuiltInTestScope(t)
+
+	result, callError := CallBuiltIn(scope, incomingLinks, "db")
+	assert.NoError(t, callError)
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, "web>db", result.([]any)[0].(map[string]any)["id"])
+	}
+
+	result, callError = CallBuiltIn(scope, incomingLinks, "web")
+	assert.NoError(t, callError)
+	assert.Empty(t, result)
+
+	result, callError = CallBuiltIn(scope, outgoingLinks, "web")
+	assert.NoError(t, callError)
+	assert.Len(t, result, 1)
+}
+
+func Test_CallBuiltIn_TrustBoundaryOf(t *testing.T) {
+	scope := newBuiltInTestScope(t)
+
+	result, callError := CallBuiltIn(scope, trustBoundaryOf, "web")
+	assert.NoError(t, callError)
+	assert.Equal(t, "vpc", result)
+
+	result, callError = CallBuiltIn(scope, parentTrustBoundaries, "vpc")
+	assert.NoError(t, callError)
+	assert.Equal(t, []any{"vpc", "cloud"}, result)
+
+	result, callError = CallBuiltIn(scope, isWithinCloud, "cloud")
+	assert.NoError(t, callError)
+	assert.Equal(t, true, result)
+}
+
+func Test_CallBuiltIn_HighestOfElements(t *testing.T) {
+	scope := newBuiltInTestScope(t)
+
+	result, callError := CallBuiltIn(scope, highestConfidentiality, "web")
+	assert.NoError(t, callError)
+	assert.Equal(t, types.StrictlyConfidential.String(), result)
+
+	result, callError = CallBuiltIn(scope, highestConfidentiality, "db")
+	assert.NoError(t, callError)
+	assert.Equal(t, types.Confidential.String(), result)
+
+	result, callError = CallBuiltIn(scope, highestIntegrity, "web>db")
+	assert.NoError(t, callError)
+	assert.Equal(t, types.Critical.String(), result)
+
+	result, callError = CallBuiltIn(scope, highestAvailability, "secrets")
+	assert.NoError(t, callError)
+	assert.Equal(t, types.Important.String(), result)
+
+	result, callError = CallBuiltIn(scope, highestConfidentiality, "cloud")
+	assert.NoError(t, callError)
+	assert.Equal(t, types.StrictlyConfidential.String(), result)
+
+	_, callError = CallBuiltIn(scope, highestConfidentiality, "missing")
+	assert.Error(t, callError)
+}
+
+func Test_CallBuiltIn_IsTaggedWithAnyTraversingUp(t *testing.T) {
+	scope := newBuiltInTestScope(t)
+
+	result, callError := CallBuiltIn(scope, isTaggedWithAnyTraversingUp, "web", "gcp", "AWS")
+	assert.NoError(t, callError)
+	assert.Equal(t, true, result)
+
+	result, callError = CallBuiltIn(scope, isTaggedWithAnyTraversingUp, "vpc", "gcp")
+	assert.NoError(t, callError)
+	assert.Equal(t, false, result)
+
+	_, callError = CallBuiltIn(scope, isTaggedWithAnyTraversingUp, "web>db", "aws")
+	assert.Error(t, callError)
+}
+
+func Test_CallBuiltIn_TechnologyHasAttribute(t *testing.T) {
+	scope := newBuiltInTestScope(t)
+
+	result, callError := CallBuiltIn(scope, technologyHasAttribute, "web", "database", "web_application")
+	assert.NoError(t, callError)
+	assert.Equal(t, true, result)
+
+	result, callError = CallBuiltIn(scope, technologyHasAttribute, "db", "web_application")
+	assert.NoError(t, callError)
+	assert.Equal(t, false, result)
+}
+
+func Test_CallBuiltIn_StringHelpers(t *testing.T) {
+	scope := newBuiltInTestScope(t)
+
+	result, callError := CallBuiltIn(scope, lowerFunc, "AWS:EC2")
+	assert.NoError(t, callError)
+	assert.Equal(t, "aws:ec2", result)
+
+	result, callError = CallBuiltIn(scope, matchesFunc, "aws:ec2", "^aws:(ec2|s3)$")
+	assert.NoError(t, callError)
+	assert.Equal(t, true, result)
+
+	result, callError = CallBuiltIn(scope, matchesFunc, "gcp", "^aws:(ec2|s3)$")
+	assert.NoError(t, callError)
+	assert.Equal(t, false, result)
+
+	_, callError = CallBuiltIn(scope, matchesFunc, "aws:ec2", "(")
+	assert.ErrorContains(t, callError, "invalid regular expression")
+
+	_, callError = CallBuiltIn(scope, matchesFunc, "aws:ec2", "(")
+	assert.ErrorContains(t, callError, "invalid regular expression")
+
+	clone, cloneError := scope.Clone()
+	assert.NoError(t, cloneError)
+	result, callError = CallBuiltIn(clone, matchesFunc, "gcp", "^gcp$")
+	assert.NoError(t, callError)
+	assert.Equal(t, true, result)
+	assert.Contains(t, scope.patterns, "^gcp$", "clones share the compiled patterns of their scope")
+	assert.NotContains(t, newBuiltInTestScope(t).patterns, "^gcp$", "new scopes compile their patterns anew")
+
+	result, callError = CallBuiltIn(scope, startsWithFunc, "aws:ec2", "aws:")
+	assert.NoError(t, callError)
+	assert.Equal(t, true, result)
+
+	result, callError = CallBuiltIn(scope, endsWithFunc, "aws:ec2", "s3")
+	assert.NoError(t, callError)
+	assert.Equal(t, false, result)
+}
+
+func Test_CallBuiltIn_ListHelpers(t *testing.T) {
+	scope := newBuiltInTestScope(t)
+
+	result, callError := CallBuiltIn(scope, listFunc)
+	assert.NoError(t, callError)
+	assert.Equal(t, []any{}, result)
+
+	result, callError = CallBuiltIn(scope, appendFunc, "", "b", "a")
+	assert.NoError(t, callError)
+	assert.Equal(t, []any{"b", "a"}, result)
+
+	result, callError = CallBuiltIn(scope, uniqueFunc, []any{"b", "a", "b"})
+	assert.NoError(t, callError)
+	assert.Equal(t, []any{"b", "a"}, result)
+
+	result, callError = CallBuiltIn(scope, sortFunc, []any{"b", "c", "a"})
+	assert.NoError(t, callError)
+	assert.Equal(t, []any{"a", "b", "c"}, result)
+
+	result, callError = CallBuiltIn(scope, sortFunc, []any{"10", "9", "a"})
+	assert.NoError(t, callError)
+	assert.Equal(t, []any{"10", "9", "a"}, result)
+
+	result, callError = CallBuiltIn(scope, sortFunc, []any{10, decimal.NewFromFloat(9.5), int64(-1), 2.5})
+	assert.NoError(t, callError)
+	assert.Equal(t, []any{int64(-1), 2.5, decimal.NewFromFloat(9.5), 10}, result)
+
+	_, callError = CallBuiltIn(scope, sortFunc, []any{10, "9"})
+	assert.ErrorContains(t, callError, "mixing numbers and texts")
+
+	_, callError = CallBuiltIn(scope, sortFunc, []any{true, false})
+	assert.ErrorContains(t, callError, "can't sort a list containing bool")
+}
+
+func Test_CallBuiltIn_FilterWithBuiltIn(t *testing.T) {
+	result, callError := CallBuiltIn(newBuiltInTestScope(t), filterFunc, []any{"aws:ec2", "gcp", "aws:s3"}, "starts_with", "aws:")
+
+	assert.NoError(t, callError)
+	assert.Equal(t, []any{"aws:ec2", "aws:s3"}, result)
+}
+
+func Test_CallBuiltIn_FilterWithUtil(t *testing.T) {
+	result, callError := CallBuiltIn(newBuiltInTestScope(t), filterFunc, []any{"web", "db"}, "is_db")
+
+	assert.NoError(t, callError)
+	assert.Equal(t, []any{"db"}, result)
+}
+
+func Test_CallBuiltIn_FilterNeedsBoolResult(t *testing.T) {
+	_, callError := CallBuiltIn(newBuiltInTestScope(t), filterFunc, []any{"WEB"}, "lower")
+
+	assert.ErrorContains(t, callError, "to return a bool")
+}
@@ -3,6 +3,7 @@ package common
 import (
 	"fmt"
 	"github.com/threagile/threagile/pkg/security/types"
+	"reflect"
 )
 
 const (
@@ -18,6 +19,12 @@ const (
 	impact          = "impact"
 	likelihood      = "likelihood"
 	size            = "size"
+	usage           = "usage"
+	protocol        = "protocol"
+	assetType       = "type"
+	machine         = "machine"
+	boundaryType    = "trust-boundary-type"
+	dataFormat      = "data-format"
 )
 
 var (
@@ -34,6 +41,12 @@ var (
 		impact:          toImpact,
 		likelihood:      toLikelihood,
 		size:            toSize,
+		usage:           toUsage,
+		protocol:        toProtocol,
+		assetType:       toTechnicalAssetType,
+		machine:         toMachine,
+		boundaryType:    toTrustBoundaryType,
+		dataFormat:      toDataFormat,
 	}
 )
 
@@ -45,7 +58,41 @@ func CastValue(value any, castType string) (any, error) {
 		return nil, fmt.Errorf("unknown cast type %v", castType)
 	}
 
-	return caster(value)
+	if EmptyToNil(value) == nil {
+		// model fields holding the zero value of an enum are omitted from the scope's model
+		return 0, nil
+	}
+
+	castValue, castError := caster(value)
+	if castError != nil {
+		return nil, castError
+	}
+
+	// compare enums by their order, not by their names
+	reflectValue := reflect.ValueOf(castValue)
+	switch reflectValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(reflectValue.Int()), nil
+	}
+
+	return castValue, nil
+}
+
+// IsCastType tells whether the given name can be used with 'as'
+
+func IsCastType(castType string) bool {
+	_, ok := cast[castType]
+	return ok
+}
+
+// EmptyToNil maps an empty string to nil; a model field that is not set reads as an empty string
+
+func EmptyToNil(value any) any {
+	if text, ok := value.(string); ok && len(text) == 0 {
+		return nil
+	}
+
+	return value
 }
 
 func toConfidentiality(value any) (any, error) {
@@ -207,3 +254,43 @@ func toSize(value any) (any, error) {
 		return nil, fmt.Errorf("toSize: unexpected type %T", value)
 	}
 }
+
+func toUsage(value any) (any, error) {
+	return parseEnum(value, "toUsage", func(text string) (any, error) { return types.ParseUsage(text) })
+}
+
+func toProtocol(value any) (any, error) {
+	return parseEnum(value, "toProtocol", func(text string) (any, error) { return types.ParseProtocol(text) })
+}
+
+func toTechnicalAssetType(value any) (any, error) {
+	return parseEnum(value, "toTechnicalAssetType", func(text string) (any, error) { return types.ParseTechnicalAssetType(text) })
+}
+
+func toMachine(value any) (any, error) {
+	return parseEnum(value, "toMachine", func(text string) (any, error) { return types.ParseTechnicalAssetMachine(text) })
+}
+
+func toTrustBoundaryType(value any) (any, error) {
+	return parseEnum(value, "toTrustBoundaryType", func(text string) (any, error) { return types.ParseTrustBoundary(text) })
+}
+
+func toDataFormat(value any) (any, error) {
+	return parseEnum(value, "toDataFormat", func(text string) (any, error) { return types.ParseDataFormat(text) })
+}
+
+func parseEnum(value any, name string, parse func(text string) (any, error)) (any, error) {
+	switch castValue := value.(type) {
+	case string:
+		return parse(castValue)
+
+	case fmt.Stringer:
+		return parse(castValue.String())
+
+	case int, int64:
+		return castValue, nil
+
+	default:
+		return nil, fmt.Errorf("%v: unexpected type %T", name, value)
+	}
+}
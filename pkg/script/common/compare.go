@@ -22,6 +22,8 @@ func Compare(first any, second any, as string) (int, error) {
 		}
 	}
 
+	firstValue, secondValue = numericStrings(firstValue, secondValue)
+
 	var firstDecimal decimal.Decimal
 	switch castValue := firstValue.(type) {
 	case bool:
@@ -104,3 +106,57 @@ func Compare(first any, second any, as string) (int, error) {
 
 	return firstDecimal.Cmp(secondDecimal), nil
 }
+
+// numericStrings turns a string compared to a number into a number; a model field that is not set reads as an empty string
+
+func numericStrings(first any, second any) (any, any) {
+	if isNumber(first) {
+		second = stringToDecimal(second)
+	}
+
+	if isNumber(second) {
+		first = stringToDecimal(first)
+	}
+
+	return first, second
+}
+
+func isNumber(value any) bool {
+	switch value.(type) {
+	case decimal.Decimal, int, int64, float64:
+		return true
+
+	default:
+		return false
+	}
+}
+
+// isString accepts texts and values with a text representation, like enum values, but not numbers
+
+func isString(value any) bool {
+	switch value.(type) {
+	case string, fmt.Stringer:
+		return !isNumber(value)
+
+	default:
+		return false
+	}
+}
+
+func stringToDecimal(value any) any {
+	text, ok := value.(string)
+	if !ok {
+		return value
+	}
+
+	if len(text) == 0 {
+		return decimal.Zero
+	}
+
+	number, parseError := decimal.NewFromString(text)
+	if parseError != nil {
+		return value
+	}
+
+	return number
+}
@@ -1,11 +1,12 @@
 package common
 
 const (
-	Risk  = "risk"
-	Data  = "data"
-	ID    = "id"
-	Match = "match"
-	Utils = "utils"
+	Risk    = "risk"
+	Data    = "data"
+	ID      = "id"
+	Match   = "match"
+	Utils   = "utils"
+	Iterate = "iterate"
 
 	Assign = "assign"
 	Loop   = "loop"
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"github.com/threagile/threagile/pkg/security/types"
 	"gopkg.in/yaml.v3"
+	"regexp"
 	"strings"
 )
 
@@ -14,8 +15,11 @@ type Scope struct {
 	Model       map[string]any
 	Risk        map[string]any
 	Methods     map[string]Statement
+	parsedModel *types.Model
 	iterator    Value
 	returnValue Value
+	hasReturned bool
+	patterns    map[string]*regexp.Regexp
 }
 
 func (what *Scope) Init(risk *types.RiskCategory, methods map[string]Statement) error {
@@ -32,18 +36,21 @@ func (what *Scope) Init(risk *types.RiskCategory, methods map[string]Statement)
 	}
 
 	what.Methods = methods
+	what.patterns = make(map[string]*regexp.Regexp)
 
 	return nil
 }
 
 func (what *Scope) SetModel(model *types.Model) error {
+	what.parsedModel = model
 	if model != nil {
-		data, marshalError := yaml.Marshal(model)
+		// JSON is a lot faster than YAML here and yields the same field names
+		data, marshalError := json.Marshal(model)
 		if marshalError != nil {
 			return marshalError
 		}
 
-		unmarshalError := yaml.Unmarshal(data, &what.Model)
+		unmarshalError := json.Unmarshal(data, &what.Model)
 		if unmarshalError != nil {
 			return unmarshalError
 		}
@@ -59,10 +66,12 @@ func (what *Scope) Clone() (*Scope, error) {
 	}
 
 	scope := Scope{
-		Parent:  what,
-		Model:   what.Model,
-		Risk:    what.Risk,
-		Methods: what.Methods,
+		Parent:      what,
+		Model:       what.Model,
+		Risk:        what.Risk,
+		Methods:     what.Methods,
+		parsedModel: what.parsedModel,
+		patterns:    what.patterns,
 	}
 
 	unmarshalError := json.Unmarshal(data, &scope.Vars)
@@ -104,6 +113,11 @@ func (what *Scope) Get(name string) (Value, bool) {
 		return value, true
 	}
 
+	if what.hasVar(path) {
+		// a variable of this scope hides the parent's variable of the same name, even if the field is not set
+		return nil, false
+	}
+
 	if what.Parent != nil {
 		return what.Parent.Get(name)
 	}
@@ -125,14 +139,47 @@ func (what *Scope) SwapIterator(value Value) Value {
 	return currentIterator
 }
 
+// GetParsedModel returns the model the scope was set up with, for built-ins that need the typed model
+
+// compiledPattern returns the compiled regular expression of a pattern, compiling it only once for a scope and its
+// clones, i.e. once per run of a risk rule, as scripts match the same patterns against every element
+
+func (what *Scope) compiledPattern(pattern string) (*regexp.Regexp, error) {
+	if compiled, ok := what.patterns[pattern]; ok {
+		return compiled, nil
+	}
+
+	compiled, compileError := regexp.Compile(pattern)
+	if compileError != nil {
+		return nil, compileError
+	}
+
+	if what.patterns == nil {
+		what.patterns = make(map[string]*regexp.Regexp)
+	}
+	what.patterns[pattern] = compiled
+	return compiled, nil
+}
+
+func (what *Scope) GetParsedModel() *types.Model {
+	return what.parsedModel
+}
+
 func (what *Scope) SetReturnValue(value Value) {
 	what.returnValue = value
+	what.hasReturned = true
 }
 
 func (what *Scope) GetReturnValue() Value {
 	return what.returnValue
 }
 
+// HasReturned tells whether a return-statement has been run in this scope; statement lists and loops stop there
+
+func (what *Scope) HasReturned() bool {
+	return what.hasReturned
+}
+
 func (what *Scope) get(path []string, item map[string]any) (Value, bool) {
 	if len(path) == 0 {
 		return nil, false
@@ -159,6 +206,15 @@ func (what *Scope) get(path []string, item map[string]any) (Value, bool) {
 	return what.get(path[1:], value)
 }
 
+func (what *Scope) hasVar(path []string) bool {
+	if len(path) == 0 || len(path[0]) == 0 {
+		return false
+	}
+
+	_, ok := what.Vars[strings.ToLower(path[0])]
+	return ok
+}
+
 func (what *Scope) getVar(path []string) (Value, bool) {
 	if len(path) == 0 {
 		return nil, false
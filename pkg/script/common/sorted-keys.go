@@ -0,0 +1,18 @@
+package common
+
+import (
+	"sort"
+)
+
+// SortedKeys returns the keys of a map in sorted order, so iterating over model items gives reproducible results
+
+func SortedKeys(value map[string]any) []string {
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -17,6 +17,14 @@ func (what *AllExpression) ParseBool(script any) (common.BoolExpression, any, er
 	what.literal = common.ToLiteral(script)
 
 	switch script.(type) {
+	case map[any]any:
+		newMap := make(map[string]any)
+		for key, value := range script.(map[any]any) {
+			newMap[fmt.Sprintf("%v", key)] = value
+		}
+
+		return what.ParseBool(newMap)
+
 	case map[string]any:
 		for key, value := range script.(map[string]any) {
 			switch key {
@@ -83,7 +91,7 @@ func (what *AllExpression) EvalBool(scope *common.Scope) (bool, string, error) {
 		return false, errorEvalLiteral, evalError
 	}
 
-	switch castValue := inValue.(type) {
+	switch castValue := common.EmptyToNil(inValue).(type) {
 	case []any:
 		if what.expression == nil {
 			return true, "", nil
@@ -114,7 +122,8 @@ func (what *AllExpression) EvalBool(scope *common.Scope) (bool, string, error) {
 			return true, "", nil
 		}
 
-		for name, item := range castValue {
+		for _, name := range common.SortedKeys(castValue) {
+			item := castValue[name]
 			if len(what.index) > 0 {
 				scope.Set(what.index, name)
 			}
@@ -134,6 +143,9 @@ func (what *AllExpression) EvalBool(scope *common.Scope) (bool, string, error) {
 			}
 		}
 
+	case nil:
+		return true, "", nil
+
 	default:
 		return false, what.Literal(), fmt.Errorf("failed to eval any-expression: expected iterable type, got %T", inValue)
 	}
@@ -70,7 +70,7 @@ func (what *ContainsExpression) EvalBool(scope *common.Scope) (bool, string, err
 		return false, errorInLiteral, evalError
 	}
 
-	switch castIn := in.(type) {
+	switch castIn := common.EmptyToNil(in).(type) {
 	case []any:
 		for index, value := range castIn {
 			compareValue, compareError := common.Compare(item, value, what.as)
@@ -86,7 +86,8 @@ func (what *ContainsExpression) EvalBool(scope *common.Scope) (bool, string, err
 		return false, "", nil
 
 	case map[string]any:
-		for name, value := range castIn {
+		for _, name := range common.SortedKeys(castIn) {
+			value := castIn[name]
 			compareValue, compareError := common.Compare(item, value, what.as)
 			if compareError != nil {
 				return false, what.Literal(), fmt.Errorf("failed to eval contains-expression: can't compare value to item %q: %v", name, compareError)
@@ -99,6 +100,9 @@ func (what *ContainsExpression) EvalBool(scope *common.Scope) (bool, string, err
 
 		return false, "", nil
 
+	case nil:
+		return false, "", nil
+
 	default:
 		return false, "", fmt.Errorf("failed to eval contains-expression: expected iterable type, got %T", in)
 	}
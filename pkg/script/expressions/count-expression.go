@@ -18,6 +18,14 @@ func (what *CountExpression) ParseDecimal(script any) (common.DecimalExpression,
 	what.literal = common.ToLiteral(script)
 
 	switch script.(type) {
+	case map[any]any:
+		newMap := make(map[string]any)
+		for key, value := range script.(map[any]any) {
+			newMap[fmt.Sprintf("%v", key)] = value
+		}
+
+		return what.ParseDecimal(newMap)
+
 	case map[string]any:
 		for key, value := range script.(map[string]any) {
 			switch key {
@@ -84,7 +92,7 @@ func (what *CountExpression) EvalDecimal(scope *common.Scope) (decimal.Decimal,
 		return decimal.NewFromInt(0), errorEvalLiteral, evalError
 	}
 
-	switch castValue := inValue.(type) {
+	switch castValue := common.EmptyToNil(inValue).(type) {
 	case []any:
 		if what.expression == nil {
 			return decimal.NewFromInt(int64(len(castValue))), "", nil
@@ -119,7 +127,8 @@ func (what *CountExpression) EvalDecimal(scope *common.Scope) (decimal.Decimal,
 		}
 
 		var count int64 = 0
-		for name, item := range castValue {
+		for _, name := range common.SortedKeys(castValue) {
+			item := castValue[name]
 			if len(what.index) > 0 {
 				scope.Set(what.index, name)
 			}
@@ -141,6 +150,9 @@ func (what *CountExpression) EvalDecimal(scope *common.Scope) (decimal.Decimal,
 
 		return decimal.NewFromInt(count), "", nil
 
+	case nil:
+		return decimal.NewFromInt(0), "", nil
+
 	default:
 		return decimal.NewFromInt(0), what.Literal(), fmt.Errorf("failed to eval any-expression: expected iterable type, got %T", inValue)
 	}
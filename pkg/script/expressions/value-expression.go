@@ -9,6 +9,15 @@ import (
 	"strings"
 )
 
+// VarRe and FuncRe match the innermost variable reference and method call of a value; they are resolved in that order
+
+var (
+	VarRe       = regexp.MustCompile(`\{[^{}]+}`)
+	wholeVarRe  = regexp.MustCompile(`^\{[^{}]+}$`)
+	FuncRe      = regexp.MustCompile(`(\w+)\(([^()]*)\)`)
+	wholeFuncRe = regexp.MustCompile(`^(\w+)\(([^()]*)\)$`)
+)
+
 type ValueExpression struct {
 	literal string
 	value   any
@@ -278,6 +287,9 @@ func (what *ValueExpression) EvalAny(scope *common.Scope) (any, string, error) {
 	case float64:
 		return decimal.NewFromFloat(what.value.(float64)), "", nil
 
+	case int:
+		return decimal.NewFromInt(int64(what.value.(int))), "", nil
+
 	case int64:
 		return decimal.NewFromInt(what.value.(int64)), "", nil
 
@@ -316,10 +328,9 @@ func (what *ValueExpression) eval(scope *common.Scope, value any) (any, string,
 }
 
 func (what *ValueExpression) evalString(scope *common.Scope, value string) (any, string, error) {
-	varRe := `\{[^{}]+}`
-	value = what.resolveStringValues(scope, varRe, value)
+	value = what.resolveStringValues(scope, VarRe, value)
 
-	if regexp.MustCompile(`^` + varRe + `$`).MatchString(value) {
+	if wholeVarRe.MatchString(value) {
 		returnValue, ok := scope.Get(value[1 : len(value)-1])
 		if !ok {
 			return "", "", nil
@@ -328,22 +339,21 @@ func (what *ValueExpression) evalString(scope *common.Scope, value string) (any,
 		return returnValue, "", nil
 	}
 
-	funcRe := `(\w+)\(([^()]+)\)`
-	resolvedValue, errorLiteral, evalError := what.resolveMethodCalls(scope, funcRe, value)
+	resolvedValue, errorLiteral, evalError := what.resolveMethodCalls(scope, FuncRe, value)
 	if evalError != nil {
 		return resolvedValue, errorLiteral, evalError
 	}
 
-	if regexp.MustCompile(`^` + funcRe + `$`).MatchString(resolvedValue) {
-		return what.resolveMethodCall(scope, funcRe, resolvedValue)
+	if wholeFuncRe.MatchString(resolvedValue) {
+		return what.resolveMethodCall(scope, FuncRe, resolvedValue)
 	}
 
 	return resolvedValue, "", nil
 }
 
-func (what *ValueExpression) resolveStringValues(scope *common.Scope, reString string, value string) string {
+func (what *ValueExpression) resolveStringValues(scope *common.Scope, re *regexp.Regexp, value string) string {
 	replacements := 0
-	text := regexp.MustCompile(reString).ReplaceAllStringFunc(value, func(name string) string {
+	text := re.ReplaceAllStringFunc(value, func(name string) string {
 		cleanName := name[1 : len(name)-1]
 		item, ok := scope.Get(strings.ToLower(cleanName))
 		if !ok {
@@ -365,17 +375,16 @@ func (what *ValueExpression) resolveStringValues(scope *common.Scope, reString s
 	})
 
 	if replacements > 0 {
-		return what.resolveStringValues(scope, reString, text)
+		return what.resolveStringValues(scope, re, text)
 	}
 
 	return text
 }
 
-func (what *ValueExpression) resolveMethodCalls(scope *common.Scope, reString string, value string) (string, string, error) {
+func (what *ValueExpression) resolveMethodCalls(scope *common.Scope, re *regexp.Regexp, value string) (string, string, error) {
 	replacements := 0
-	re := regexp.MustCompile(reString)
 	text := re.ReplaceAllStringFunc(value, func(name string) string {
-		returnValue, _, callError := what.resolveMethodCall(scope, reString, name)
+		returnValue, _, callError := what.resolveMethodCall(scope, re, name)
 		if callError != nil {
 			return name
 		}
@@ -389,6 +398,10 @@ func (what *ValueExpression) resolveMethodCalls(scope *common.Scope, reString st
 			replacements++
 			return castReturnValue.String()
 
+		case bool:
+			replacements++
+			return strconv.FormatBool(castReturnValue)
+
 		default:
 			return name
 		}
@@ -398,12 +411,10 @@ func (what *ValueExpression) resolveMethodCalls(scope *common.Scope, reString st
 		return text, "", nil
 	}
 
-	return what.resolveMethodCalls(scope, reString, text)
+	return what.resolveMethodCalls(scope, re, text)
 }
 
-func (what *ValueExpression) resolveMethodCall(scope *common.Scope, reString string, value string) (any, string, error) {
-	re := regexp.MustCompile(reString)
-
+func (what *ValueExpression) resolveMethodCall(scope *common.Scope, re *regexp.Regexp, value string) (any, string, error) {
 	match := re.FindStringSubmatch(value)
 	if len(match) != 3 {
 		return value, what.Literal(), fmt.Errorf("method call match failed for %q", value)
@@ -423,32 +434,16 @@ func (what *ValueExpression) resolveMethodCall(scope *common.Scope, reString str
 		}
 	}
 
-	method, ok := scope.Methods[name]
-	if ok {
-		newScope, cloneError := scope.Clone()
-		if cloneError != nil {
-			return value, what.Literal(), fmt.Errorf("failed to clone scope: %v", cloneError)
-		}
-
-		newScope.Args = args
-		errorLiteral, runError := method.Run(newScope)
-		if runError != nil {
-			return value, errorLiteral, runError
-		}
-
-		return newScope.GetReturnValue(), "", nil
-	}
-
-	if common.IsBuiltIn(name) {
-		callValue, callError := common.CallBuiltIn(name, args...)
-		if callError != nil {
-			return callValue, what.Literal(), fmt.Errorf("failed to call %q: %v", name, callError)
+	callValue, errorLiteral, callError := common.CallMethod(scope, name, args...)
+	if callError != nil {
+		if len(errorLiteral) == 0 {
+			errorLiteral = what.Literal()
 		}
 
-		return callValue, "", nil
+		return value, errorLiteral, callError
 	}
 
-	return value, what.Literal(), fmt.Errorf("no method %q", match[1])
+	return callValue, "", nil
 }
 
 func (what *ValueExpression) Literal() string {
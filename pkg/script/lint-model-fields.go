@@ -0,0 +1,118 @@
+package script
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// modelField describes what a variable path may continue with: the fields of a struct, any key of a map, or, for a
+// variable of unknown type, the fields of any of its parts
+
+type modelField struct {
+	fields map[string]*modelField
+	items  *modelField
+	parts  []*modelField
+}
+
+var (
+	jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// newModelFields returns the fields of the scope's model, the fields of the risk category and a field standing for
+// any model element, which is what a script variable usually holds
+
+func newModelFields() (*modelField, *modelField, *modelField) {
+	known := make(map[reflect.Type]*modelField)
+	model := newModelField(reflect.TypeOf(types.Model{}), "json", known)
+
+	elements := make([]*modelField, 0, len(known))
+	for _, field := range known {
+		elements = append(elements, field)
+	}
+
+	risk := newModelField(reflect.TypeOf(types.RiskCategory{}), "yaml", make(map[reflect.Type]*modelField))
+
+	return model, risk, &modelField{parts: elements}
+}
+
+func newModelField(fieldType reflect.Type, tagName string, known map[reflect.Type]*modelField) *modelField {
+	for fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+
+	if fieldType.Implements(jsonMarshaler) || reflect.PointerTo(fieldType).Implements(jsonMarshaler) {
+		return new(modelField)
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Struct:
+		if field, ok := known[fieldType]; ok {
+			return field
+		}
+
+		field := &modelField{fields: make(map[string]*modelField)}
+		known[fieldType] = field
+		for n := 0; n < fieldType.NumField(); n++ {
+			structField := fieldType.Field(n)
+			if !structField.IsExported() {
+				continue
+			}
+
+			name, _, _ := strings.Cut(structField.Tag.Get(tagName), ",")
+			if name == "-" {
+				continue
+			}
+
+			if len(name) == 0 {
+				name = structField.Name
+			}
+
+			field.fields[strings.ToLower(name)] = newModelField(structField.Type, tagName, known)
+		}
+
+		return field
+
+	case reflect.Map:
+		return &modelField{items: newModelField(fieldType.Elem(), tagName, known)}
+
+	case reflect.Slice, reflect.Array:
+		// scripts can't pick list items by path, but may loop over them
+		newModelField(fieldType.Elem(), tagName, known)
+		return new(modelField)
+
+	default:
+		return new(modelField)
+	}
+}
+
+func (what *modelField) child(name string) (*modelField, bool) {
+	if len(what.parts) > 0 {
+		children := make([]*modelField, 0)
+		for _, part := range what.parts {
+			if child, ok := part.child(name); ok {
+				children = append(children, child)
+			}
+		}
+
+		switch len(children) {
+		case 0:
+			return nil, false
+
+		case 1:
+			return children[0], true
+
+		default:
+			return &modelField{parts: children}, true
+		}
+	}
+
+	if what.items != nil {
+		return what.items, true
+	}
+
+	child, ok := what.fields[name]
+	return child, ok
+}
@@ -0,0 +1,717 @@
+package script
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/script/common"
+	"github.com/threagile/threagile/pkg/script/expressions"
+	"github.com/threagile/threagile/pkg/security/types"
+	"gopkg.in/yaml.v3"
+)
+
+// lintPlaceholder replaces variables and method calls the linter has already looked at
+
+const lintPlaceholder = "\x1a"
+
+var (
+	comparisonKeywords = []string{common.Equal, common.NotEqual, common.Greater, common.EqualOrGreater, common.Less, common.EqualOrLess}
+	iterationKeywords  = []string{common.All, common.Any, common.Count}
+)
+
+// LintIssue is a problem found in a risk rule script, at the position of the YAML node it was found in
+
+type LintIssue struct {
+	Filename string
+	Line     int
+	Column   int
+	Message  string
+}
+
+func (what LintIssue) String() string {
+	return fmt.Sprintf("%v:%d:%d: %v", what.Filename, what.Line, what.Column, what.Message)
+}
+
+// Linter checks risk rule scripts without running them against a model
+
+type Linter struct {
+	filename   string
+	issues     []LintIssue
+	utils      map[string]int
+	modelRoot  *modelField
+	riskRoot   *modelField
+	anyElement *modelField
+}
+
+func (what *Linter) Init() *Linter {
+	what.modelRoot, what.riskRoot, what.anyElement = newModelFields()
+	return what
+}
+
+// Lint returns the problems found in the given risk rule file
+
+func (what *Linter) Lint(filename string, text []byte) []LintIssue {
+	if what.modelRoot == nil {
+		what.Init()
+	}
+
+	what.filename = filename
+	what.issues = make([]LintIssue, 0)
+
+	var document yaml.Node
+	parseError := yaml.Unmarshal(text, &document)
+	if parseError != nil {
+		what.issues = append(what.issues, LintIssue{Filename: filename, Message: parseError.Error()})
+		return what.issues
+	}
+
+	if len(document.Content) == 0 {
+		what.issues = append(what.issues, LintIssue{Filename: filename, Message: "empty risk rule"})
+		return what.issues
+	}
+
+	what.lintRule(document.Content[0])
+
+	return what.issues
+}
+
+func (what *Linter) addIssue(node *yaml.Node, format string, args ...any) {
+	what.issues = append(what.issues, LintIssue{
+		Filename: what.filename,
+		Line:     node.Line,
+		Column:   node.Column,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (what *Linter) lintRule(node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		what.addIssue(node, "expected risk rule to be a map")
+		return
+	}
+
+	categoryFields := yamlFieldNames(reflect.TypeOf(types.RiskCategory{}))
+	hasRisk := false
+	forEachPair(node, func(key *yaml.Node, value *yaml.Node) {
+		switch key.Value {
+		case common.Risk:
+			hasRisk = true
+			if value.Kind == yaml.SequenceNode {
+				for _, item := range value.Content {
+					what.lintScript(item)
+				}
+			} else {
+				what.lintScript(value)
+			}
+
+		case "category", "supported-tags":
+
+		default:
+			if !categoryFields[key.Value] {
+				what.addIssue(key, "unknown risk category field %q", key.Value)
+			}
+		}
+	})
+
+	if !hasRisk {
+		what.addIssue(node, "missing %q section", common.Risk)
+	}
+}
+
+func (what *Linter) lintScript(node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		what.addIssue(node, "expected risk script to be a map")
+		return
+	}
+
+	what.utils = make(map[string]int)
+	if utils := findValue(node, common.Utils); utils != nil {
+		what.collectUtils(utils)
+	}
+
+	sections := make(map[string]bool)
+	forEachPair(node, func(key *yaml.Node, value *yaml.Node) {
+		section := strings.ToLower(key.Value)
+		sections[section] = true
+
+		switch section {
+		case common.ID:
+			what.lintIdSection(value)
+
+		case common.Iterate:
+			what.lintIterate(value)
+
+		case common.Data:
+			what.lintData(value)
+
+		case common.Match:
+			what.lintMethod(value)
+
+		case common.Utils:
+			what.lintUtils(value)
+
+		default:
+			what.addIssue(key, "unknown keyword %q in risk script", key.Value)
+		}
+	})
+
+	for _, section := range []string{common.ID, common.Data, common.Match} {
+		if !sections[section] {
+			what.addIssue(node, "missing %q section in risk script", section)
+		}
+	}
+}
+
+func (what *Linter) collectUtils(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		forEachPair(node, func(key *yaml.Node, value *yaml.Node) {
+			if _, ok := what.utils[key.Value]; ok {
+				what.addIssue(key, "method %q redefined", key.Value)
+			}
+
+			what.utils[key.Value] = parameterCount(value)
+		})
+
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			what.collectUtils(item)
+		}
+	}
+}
+
+func (what *Linter) lintIdSection(node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		what.addIssue(node, "expected %q section to be a map", common.ID)
+		return
+	}
+
+	forEachPair(node, func(key *yaml.Node, value *yaml.Node) {
+		switch key.Value {
+		case common.Parameter:
+			what.lintName(key, value)
+
+		case common.ID:
+			what.lintValue(value)
+
+		default:
+			what.addIssue(key, "unknown keyword %q in %q section", key.Value, common.ID)
+		}
+	})
+}
+
+func (what *Linter) lintIterate(node *yaml.Node) {
+	levels := []*yaml.Node{node}
+	if node.Kind == yaml.SequenceNode {
+		levels = node.Content
+	}
+
+	for n, level := range levels {
+		if level.Kind != yaml.MappingNode {
+			what.addIssue(level, "expected level %d of %q to be a map", n+1, common.Iterate)
+			continue
+		}
+
+		keys := what.lintLoopKeys(level, common.Iterate, nil)
+		for _, required := range []string{common.In, common.Item} {
+			if !keys[required] {
+				what.addIssue(level, "level %d of %q needs %q", n+1, common.Iterate, required)
+			}
+		}
+	}
+}
+
+func (what *Linter) lintData(node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		what.addIssue(node, "expected %q section to be a map", common.Data)
+		return
+	}
+
+	riskFields := yamlFieldNames(reflect.TypeOf(types.Risk{}))
+	forEachPair(node, func(key *yaml.Node, value *yaml.Node) {
+		if key.Value == common.Parameter {
+			what.lintName(key, value)
+			return
+		}
+
+		if !riskFields[key.Value] {
+			what.addIssue(key, "unknown risk field %q", key.Value)
+		}
+
+		what.lintValue(value)
+	})
+}
+
+func (what *Linter) lintUtils(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		forEachPair(node, func(_ *yaml.Node, value *yaml.Node) {
+			what.lintMethod(value)
+		})
+
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			what.lintUtils(item)
+		}
+
+	default:
+		what.addIssue(node, "expected %q to be a map or a list", common.Utils)
+	}
+}
+
+func (what *Linter) lintMethod(node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		what.addIssue(node, "expected method to be a map")
+		return
+	}
+
+	forEachPair(node, func(key *yaml.Node, value *yaml.Node) {
+		switch key.Value {
+		case common.Parameter, common.Parameters:
+			if value.Kind == yaml.SequenceNode {
+				for _, item := range value.Content {
+					what.lintName(key, item)
+				}
+			} else {
+				what.lintName(key, value)
+			}
+
+		case common.Do:
+			what.lintStatements(value)
+
+		default:
+			what.addIssue(key, "unknown keyword %q in method", key.Value)
+		}
+	})
+}
+
+// lintStatements checks a statement or a list of statements and tells whether it always returns
+
+func (what *Linter) lintStatements(node *yaml.Node) bool {
+	switch node.Kind {
+	case yaml.MappingNode:
+		if len(node.Content) != 2 {
+			what.addIssue(node, "statement must have a single keyword")
+			return false
+		}
+
+		return what.lintStatement(node.Content[0], node.Content[1])
+
+	case yaml.SequenceNode:
+		returns, reported := false, false
+		for _, item := range node.Content {
+			if returns && !reported {
+				what.addIssue(item, "unreachable statement after return")
+				reported = true
+			}
+
+			if what.lintStatements(item) {
+				returns = true
+			}
+		}
+
+		return returns
+
+	default:
+		what.addIssue(node, "expected statement, got %q", node.Value)
+		return false
+	}
+}
+
+func (what *Linter) lintStatement(key *yaml.Node, value *yaml.Node) bool {
+	switch key.Value {
+	case common.Assign:
+		what.lintAssign(value)
+
+	case common.Loop:
+		if value.Kind != yaml.MappingNode {
+			what.addIssue(value, "expected %q-statement to be a map", common.Loop)
+			return false
+		}
+
+		keys := what.lintLoopKeys(value, common.Loop, func(key *yaml.Node, value *yaml.Node) bool {
+			if key.Value != common.Do {
+				return false
+			}
+
+			// the loop body may not run at all, so a return in it does not make the statements after the loop unreachable
+			what.lintStatements(value)
+			return true
+		})
+
+		if !keys[common.In] {
+			what.addIssue(key, "%q-statement needs %q", common.Loop, common.In)
+		}
+
+	case common.If:
+		return what.lintIf(key, value)
+
+	case common.Return:
+		what.lintExpression(value)
+		return true
+
+	default:
+		what.addIssue(key, "unknown statement %q", key.Value)
+	}
+
+	return false
+}
+
+func (what *Linter) lintAssign(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		forEachPair(node, func(_ *yaml.Node, value *yaml.Node) {
+			what.lintExpression(value)
+		})
+
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			what.lintAssign(item)
+		}
+
+	default:
+		what.addIssue(node, "expected %q-statement to be a map or a list", common.Assign)
+	}
+}
+
+func (what *Linter) lintIf(keyword *yaml.Node, node *yaml.Node) bool {
+	if node.Kind != yaml.MappingNode {
+		what.addIssue(node, "expected %q-statement to be a map", common.If)
+		return false
+	}
+
+	var thenReturns, elseReturns, hasCondition bool
+	forEachPair(node, func(key *yaml.Node, value *yaml.Node) {
+		switch key.Value {
+		case common.Then:
+			thenReturns = what.lintStatements(value)
+
+		case common.Else:
+			elseReturns = what.lintStatements(value)
+
+		default:
+			if hasCondition {
+				what.addIssue(key, "%q-statement has multiple expressions", common.If)
+			}
+
+			hasCondition = true
+			what.lintExpressionKeyword(key, value)
+		}
+	})
+
+	if !hasCondition {
+		what.addIssue(keyword, "%q-statement has no expression", common.If)
+	}
+
+	return thenReturns && elseReturns
+}
+
+func (what *Linter) lintExpression(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		if len(node.Content) != 2 {
+			what.addIssue(node, "expression must have a single keyword")
+		}
+
+		forEachPair(node, what.lintExpressionKeyword)
+
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			what.lintExpression(item)
+		}
+
+	default:
+		what.lintValue(node)
+	}
+}
+
+func (what *Linter) lintExpressionKeyword(key *yaml.Node, value *yaml.Node) {
+	switch {
+	case contains(iterationKeywords, key.Value):
+		if value.Kind != yaml.MappingNode {
+			what.addIssue(value, "expected %q-expression to be a map", key.Value)
+			return
+		}
+
+		hasExpression := false
+		keys := what.lintLoopKeys(value, key.Value, func(innerKey *yaml.Node, innerValue *yaml.Node) bool {
+			if hasExpression {
+				what.addIssue(innerKey, "%q-expression has multiple expressions", key.Value)
+			}
+
+			hasExpression = true
+			what.lintExpressionKeyword(innerKey, innerValue)
+			return true
+		})
+
+		if !keys[common.In] {
+			what.addIssue(key, "%q-expression needs %q", key.Value, common.In)
+		}
+
+	case key.Value == common.Contains:
+		what.lintCastOperands(key, value, common.Item, common.In)
+
+	case contains(comparisonKeywords, key.Value):
+		what.lintCastOperands(key, value, common.First, common.Second)
+
+	case key.Value == common.And, key.Value == common.Or, key.Value == common.True, key.Value == common.False:
+		what.lintExpression(value)
+
+	default:
+		what.addIssue(key, "unknown expression %q", key.Value)
+	}
+}
+
+// lintLoopKeys checks the 'in', 'item' and 'index' keys of a loop; other keys are passed to the given function, which
+// tells whether it knows them
+
+func (what *Linter) lintLoopKeys(node *yaml.Node, keyword string, other func(key *yaml.Node, value *yaml.Node) bool) map[string]bool {
+	keys := make(map[string]bool)
+	forEachPair(node, func(key *yaml.Node, value *yaml.Node) {
+		keys[key.Value] = true
+		switch key.Value {
+		case common.In:
+			what.lintValue(value)
+
+		case common.Item:
+			what.lintName(key, value)
+
+		case common.Index:
+			if keyword == common.Iterate {
+				what.addIssue(key, "unknown keyword %q in %q", key.Value, keyword)
+				return
+			}
+
+			what.lintName(key, value)
+
+		default:
+			if other == nil || !other(key, value) {
+				what.addIssue(key, "unknown keyword %q in %q", key.Value, keyword)
+			}
+		}
+	})
+
+	return keys
+}
+
+// lintCastOperands checks both operands of a comparison and, if there is an 'as' cast, that literal operands are
+// valid values of the cast type
+
+func (what *Linter) lintCastOperands(keyword *yaml.Node, node *yaml.Node, first string, second string) {
+	if node.Kind != yaml.MappingNode {
+		what.addIssue(node, "expected %q-expression to be a map", keyword.Value)
+		return
+	}
+
+	operands := make([]*yaml.Node, 0)
+	var castType *yaml.Node
+	forEachPair(node, func(key *yaml.Node, value *yaml.Node) {
+		switch key.Value {
+		case first, second:
+			what.lintValue(value)
+			operands = append(operands, value)
+
+		case common.As:
+			castType = value
+
+		default:
+			what.addIssue(key, "unknown keyword %q in %q-expression", key.Value, keyword.Value)
+		}
+	})
+
+	if len(operands) != 2 {
+		what.addIssue(keyword, "%q-expression needs %q and %q", keyword.Value, first, second)
+	}
+
+	if castType == nil {
+		return
+	}
+
+	if !common.IsCastType(castType.Value) {
+		what.addIssue(castType, "unknown cast type %q", castType.Value)
+		return
+	}
+
+	for _, operand := range operands {
+		literals := []*yaml.Node{operand}
+		if operand.Kind == yaml.SequenceNode {
+			literals = operand.Content
+		}
+
+		for _, literal := range literals {
+			if literal.Kind != yaml.ScalarNode || strings.ContainsAny(literal.Value, "{(") {
+				continue
+			}
+
+			_, castError := common.CastValue(literal.Value, castType.Value)
+			if castError != nil {
+				what.addIssue(literal, "invalid %v value %q", castType.Value, literal.Value)
+			}
+		}
+	}
+}
+
+func (what *Linter) lintName(key *yaml.Node, value *yaml.Node) {
+	if value.Kind != yaml.ScalarNode || len(value.Value) == 0 {
+		what.addIssue(value, "expected a name for %q", key.Value)
+	}
+}
+
+func (what *Linter) lintValue(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag == "!!str" {
+			what.lintText(node)
+		}
+
+	case yaml.SequenceNode, yaml.MappingNode:
+		for _, item := range node.Content {
+			what.lintValue(item)
+		}
+	}
+}
+
+// lintText checks the variables and method calls of a value in the order they are resolved in
+
+func (what *Linter) lintText(node *yaml.Node) {
+	text := node.Value
+	for {
+		variable := expressions.VarRe.FindStringIndex(text)
+		if variable == nil {
+			break
+		}
+
+		what.lintVariable(node, text[variable[0]+1:variable[1]-1])
+		text = text[:variable[0]] + lintPlaceholder + text[variable[1]:]
+	}
+
+	for {
+		call := expressions.FuncRe.FindStringSubmatchIndex(text)
+		if call == nil {
+			break
+		}
+
+		what.lintCall(node, text[call[2]:call[3]], text[call[4]:call[5]])
+		text = text[:call[0]] + lintPlaceholder + text[call[1]:]
+	}
+}
+
+func (what *Linter) lintVariable(node *yaml.Node, name string) {
+	path := strings.Split(strings.ToLower(name), ".")
+	field := what.anyElement
+	if strings.HasPrefix(path[0], "$") {
+		switch path[0] {
+		case "$model":
+			field = what.modelRoot
+
+		case "$risk":
+			field = what.riskRoot
+
+		default:
+			what.addIssue(node, "unknown variable %q", "{"+name+"}")
+			return
+		}
+	}
+
+	for _, segment := range path[1:] {
+		if strings.Contains(segment, lintPlaceholder) {
+			field = what.anyElement
+			continue
+		}
+
+		child, ok := field.child(segment)
+		if !ok {
+			what.addIssue(node, "unknown model field %q in %q", segment, "{"+strings.ReplaceAll(name, lintPlaceholder, "{...}")+"}")
+			return
+		}
+
+		field = child
+	}
+}
+
+func (what *Linter) lintCall(node *yaml.Node, name string, args string) {
+	count := 0
+	if len(strings.TrimSpace(args)) > 0 {
+		count = len(strings.Split(args, ","))
+	}
+
+	name = strings.ToLower(name)
+	if expected, ok := what.utils[name]; ok {
+		if expected != count {
+			what.addIssue(node, "method %q expects %d parameters, got %d", name, expected, count)
+		}
+
+		return
+	}
+
+	if common.IsBuiltIn(name) {
+		countError := common.CheckBuiltInParameterCount(name, count)
+		if countError != nil {
+			what.addIssue(node, "built-in %q: %v", name, countError)
+		}
+
+		return
+	}
+
+	what.addIssue(node, "unknown method %q", name)
+}
+
+func parameterCount(node *yaml.Node) int {
+	count := 0
+	forEachPair(node, func(key *yaml.Node, value *yaml.Node) {
+		switch key.Value {
+		case common.Parameter, common.Parameters:
+			if value.Kind == yaml.SequenceNode {
+				count += len(value.Content)
+			} else {
+				count++
+			}
+		}
+	})
+
+	return count
+}
+
+func forEachPair(node *yaml.Node, do func(key *yaml.Node, value *yaml.Node)) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+
+	for n := 0; n+1 < len(node.Content); n += 2 {
+		do(node.Content[n], node.Content[n+1])
+	}
+}
+
+func findValue(node *yaml.Node, key string) *yaml.Node {
+	var result *yaml.Node
+	forEachPair(node, func(itemKey *yaml.Node, value *yaml.Node) {
+		if strings.EqualFold(itemKey.Value, key) {
+			result = value
+		}
+	})
+
+	return result
+}
+
+func yamlFieldNames(structType reflect.Type) map[string]bool {
+	names := make(map[string]bool)
+	for n := 0; n < structType.NumField(); n++ {
+		name, _, _ := strings.Cut(structType.Field(n).Tag.Get("yaml"), ",")
+		if len(name) > 0 && name != "-" {
+			names[name] = true
+		}
+	}
+
+	return names
+}
+
+func contains(items []string, item string) bool {
+	for _, candidate := range items {
+		if candidate == item {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,103 @@
+package script
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const lintTestRule = `id: lint-test
+title: Lint Test
+risk:
+  id:
+    parameter: tech_asset
+    id: "{$risk.id}@{tech_asset.id}"
+  data:
+    parameter: tech_asset
+    title: "get_title({tech_asset}) at {$model.technical_assets.{tech_asset.id}.title}"
+    severity: "calculate_severity(unlikely, low)"
+  match:
+    parameter: tech_asset
+    do:
+      - if:
+          any:
+            in: "{tech_asset.communication_links}"
+            item: link
+            equal:
+              first: "{link.protocol}"
+              second: https
+              as: protocol
+          then:
+            return: true
+      - return: false
+  utils:
+    get_title:
+      parameters:
+        - tech_asset
+      do:
+        - return: "lower({tech_asset.title})"
+`
+
+func lintText(text string) []string {
+	messages := make([]string, 0)
+	for _, issue := range new(Linter).Init().Lint("rule.yaml", []byte(text)) {
+		messages = append(messages, issue.Message)
+	}
+
+	return messages
+}
+
+func TestLintAcceptsValidRule(t *testing.T) {
+	assert.Empty(t, lintText(lintTestRule))
+}
+
+func TestLintReportsPosition(t *testing.T) {
+	issues := new(Linter).Init().Lint("rule.yaml", []byte("id: lint-test\ntitle: Lint Test\nrisk:\n  id: {}\n  data: {}\n  match: {}\n  iterat: {}\n"))
+
+	if assert.Len(t, issues, 1) {
+		assert.Equal(t, `rule.yaml:7:3: unknown keyword "iterat" in risk script`, issues[0].String())
+	}
+}
+
+func TestLintReportsUnknownKeywords(t *testing.T) {
+	messages := lintText(replace(lintTestRule, "      - return: false\n", "      - retrun: false\n", "              as: protocol\n", "              as: protocol\n              also: true\n"))
+
+	assert.Contains(t, messages, `unknown statement "retrun"`)
+	assert.Contains(t, messages, `unknown keyword "also" in "equal"-expression`)
+}
+
+func TestLintReportsUndefinedMethodsAndParameterCounts(t *testing.T) {
+	messages := lintText(replace(lintTestRule, "get_title({tech_asset})", "get_title({tech_asset}, extra) get_titel()", "calculate_severity(unlikely, low)", "calculate_severity(unlikely)"))
+
+	assert.Contains(t, messages, `method "get_title" expects 1 parameters, got 2`)
+	assert.Contains(t, messages, `unknown method "get_titel"`)
+	assert.Contains(t, messages, `built-in "calculate_severity": expected 2 parameters, got 1`)
+}
+
+func TestLintReportsUnknownModelPaths(t *testing.T) {
+	messages := lintText(replace(lintTestRule, "{tech_asset.communication_links}", "{tech_asset.comunication_links}", "{$risk.id}", "{$risk.identifier}", ".title}\"", ".titel}\""))
+
+	assert.Contains(t, messages, `unknown model field "comunication_links" in "{tech_asset.comunication_links}"`)
+	assert.Contains(t, messages, `unknown model field "identifier" in "{$risk.identifier}"`)
+	assert.Contains(t, messages, `unknown model field "titel" in "{$model.technical_assets.{...}.titel}"`)
+}
+
+func TestLintReportsInvalidCasts(t *testing.T) {
+	assert.Contains(t, lintText(replace(lintTestRule, "second: https", "second: htps")), `invalid protocol value "htps"`)
+	assert.Contains(t, lintText(replace(lintTestRule, "as: protocol", "as: protocl")), `unknown cast type "protocl"`)
+}
+
+func TestLintReportsUnreachableStatements(t *testing.T) {
+	messages := lintText(replace(lintTestRule, "            return: true\n", "            return: true\n          else:\n            return: false\n"))
+
+	assert.Equal(t, []string{"unreachable statement after return"}, messages)
+}
+
+func replace(text string, pairs ...string) string {
+	for n := 0; n+1 < len(pairs); n += 2 {
+		text = strings.Replace(text, pairs[n], pairs[n+1], 1)
+	}
+
+	return text
+}
@@ -3,18 +3,23 @@ package script
 import (
 	"fmt"
 	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/script/common"
 	"github.com/threagile/threagile/pkg/security/types"
 	"gopkg.in/yaml.v3"
 	"io/fs"
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
+var riskRuleIdPattern = regexp.MustCompile(`^[a-zA-Z0-9\-]+$`)
+
 type RiskRule struct {
 	types.RiskRule
 	category      types.RiskCategory
 	supportedTags []string
-	script        *Script
+	scripts       []*Script
+	source        string
 }
 
 func (what *RiskRule) Init() *RiskRule {
@@ -28,9 +33,9 @@ func (what *RiskRule) ParseFromData(text []byte) (*RiskRule, error) {
 	}
 
 	var rule struct {
-		Category      string         `yaml:"category"`
-		SupportedTags []string       `yaml:"supported-tags"`
-		Script        map[string]any `yaml:"risk"`
+		Category      string   `yaml:"category"`
+		SupportedTags []string `yaml:"supported-tags"`
+		Script        any      `yaml:"risk"`
 	}
 
 	ruleError := yaml.Unmarshal(text, &rule)
@@ -39,88 +44,143 @@ func (what *RiskRule) ParseFromData(text []byte) (*RiskRule, error) {
 	}
 
 	what.supportedTags = rule.SupportedTags
-	script, scriptError := new(Script).ParseScript(rule.Script)
-	if scriptError != nil {
-		return nil, scriptError
+
+	// a risk rule may consist of several scripts, e.g. one for technical assets and one for communication links
+	scripts, ok := rule.Script.([]any)
+	if !ok {
+		scripts = []any{rule.Script}
 	}
 
-	what.script = script
+	what.scripts = make([]*Script, 0)
+	for n, item := range scripts {
+		if item == nil {
+			continue
+		}
+
+		scriptMap, isMap := item.(map[string]any)
+		if !isMap {
+			return nil, fmt.Errorf("unexpected format %T of risk script #%d", item, n+1)
+		}
+
+		script, scriptError := new(Script).ParseScript(scriptMap)
+		if scriptError != nil {
+			return nil, scriptError
+		}
+
+		what.scripts = append(what.scripts, script)
+	}
 
 	return what, nil
 }
 
-func (what *RiskRule) Category() *types.RiskCategory {
-	return &what.category
+// Source returns the file the risk rule was loaded from
+
+func (what *RiskRule) Source() string {
+	return what.source
 }
 
-func (what *RiskRule) SupportedTags() []string {
-	return what.supportedTags
+func (what *RiskRule) SetSource(source string) *RiskRule {
+	what.source = source
+	return what
 }
 
-func (what *RiskRule) GenerateRisks(parsedModel *types.Model) ([]*types.Risk, error) {
-	if what.script == nil {
-		return nil, fmt.Errorf("no script found in risk rule")
+// Validate checks that the risk rule has a usable category and a complete risk script
+
+func (what *RiskRule) Validate() error {
+	if len(what.category.ID) == 0 {
+		return fmt.Errorf("missing risk category id")
 	}
 
-	newScope, scopeError := what.script.NewScope(&what.category)
-	if scopeError != nil {
-		return nil, scopeError
+	if !riskRuleIdPattern.MatchString(what.category.ID) {
+		return fmt.Errorf("invalid risk category id %q: only letters, digits and '-' are allowed", what.category.ID)
 	}
 
-	modelError := newScope.SetModel(parsedModel)
-	if modelError != nil {
-		return nil, modelError
+	if len(what.category.Title) == 0 {
+		return fmt.Errorf("missing title for risk category %q", what.category.ID)
 	}
 
-	newRisks, errorLiteral, riskError := what.script.GenerateRisks(newScope)
-	if riskError != nil {
-		msg := make([]string, 0)
-		msg = append(msg, fmt.Sprintf("error generating risks: %v\n", riskError))
+	if len(what.scripts) == 0 {
+		return fmt.Errorf("missing risk script for risk category %q", what.category.ID)
+	}
 
-		if len(errorLiteral) > 0 {
-			msg = append(msg, fmt.Sprintf("in:\n%v\n", new(input.Strings).IndentPrintf(1, errorLiteral)))
+	for _, script := range what.scripts {
+		if script.id == nil {
+			return fmt.Errorf("missing %q section in risk script for risk category %q", common.ID, what.category.ID)
 		}
 
-		return nil, fmt.Errorf(strings.Join(msg, "\n"))
+		if script.data == nil {
+			return fmt.Errorf("missing %q section in risk script for risk category %q", common.Data, what.category.ID)
+		}
+
+		if script.match == nil {
+			return fmt.Errorf("missing %q section in risk script for risk category %q", common.Match, what.category.ID)
+		}
 	}
 
-	return newRisks, nil
+	return nil
 }
 
-func (what *RiskRule) GetTechnicalAssetsByRiskID(parsedModel *types.Model, riskID string) ([]*types.TechnicalAsset, error) {
-	newScope, scopeError := what.script.NewScope(&what.category)
-	if scopeError != nil {
-		return nil, scopeError
-	}
+func (what *RiskRule) Category() *types.RiskCategory {
+	return &what.category
+}
 
-	modelError := newScope.SetModel(parsedModel)
-	if modelError != nil {
-		return nil, modelError
-	}
+func (what *RiskRule) SupportedTags() []string {
+	return what.supportedTags
+}
 
-	if what.script == nil {
+func (what *RiskRule) GenerateRisks(parsedModel *types.Model) ([]*types.Risk, error) {
+	if len(what.scripts) == 0 {
 		return nil, fmt.Errorf("no script found in risk rule")
 	}
 
-	genericAssets, lookupError := what.script.GetTechnicalAssetsByRiskID(newScope, riskID)
-	if lookupError != nil {
-		return nil, lookupError
+	risks := make([]*types.Risk, 0)
+	for _, script := range what.scripts {
+		newScope, scopeError := script.NewScope(&what.category)
+		if scopeError != nil {
+			return nil, scopeError
+		}
+
+		modelError := newScope.SetModel(parsedModel)
+		if modelError != nil {
+			return nil, modelError
+		}
+
+		newRisks, errorLiteral, riskError := script.GenerateRisks(newScope)
+		if riskError != nil {
+			msg := make([]string, 0)
+			msg = append(msg, fmt.Sprintf("error generating risks: %v\n", riskError))
+
+			if len(errorLiteral) > 0 {
+				msg = append(msg, fmt.Sprintf("in:\n%v\n", new(input.Strings).IndentPrintf(1, errorLiteral)))
+			}
+
+			return nil, fmt.Errorf(strings.Join(msg, "\n"))
+		}
+
+		risks = append(risks, newRisks...)
+	}
+
+	return risks, nil
+}
+
+// GetTechnicalAssetsByRiskID returns the technical assets the risks with the given synthetic id are most relevant for
+
+func (what *RiskRule) GetTechnicalAssetsByRiskID(parsedModel *types.Model, riskID string) ([]*types.TechnicalAsset, error) {
+	risks, riskError := what.GenerateRisks(parsedModel)
+	if riskError != nil {
+		return nil, riskError
 	}
 
 	assets := make([]*types.TechnicalAsset, 0)
-	for _, genericAsset := range genericAssets {
-		asset := new(types.TechnicalAsset)
-		assetData, assetError := yaml.Marshal(genericAsset)
-		if assetError != nil {
-			return nil, assetError
+	for _, risk := range risks {
+		if !strings.EqualFold(risk.SyntheticId, riskID) {
+			continue
 		}
 
-		unmarshalError := yaml.Unmarshal(assetData, asset)
-		if unmarshalError != nil {
-			return nil, unmarshalError
+		asset, ok := parsedModel.TechnicalAssets[risk.MostRelevantTechnicalAssetId]
+		if ok {
+			assets = append(assets, asset)
 		}
-
-		assets = append(assets, asset)
 	}
 
 	return assets, nil
@@ -152,5 +212,12 @@ func (what *RiskRule) loadRiskRule(fileSystem fs.FS, filename string) error {
 		return fmt.Errorf("error parsing scripts from %q: %w\n", scriptFilename, parseError)
 	}
 
+	validateError := what.Validate()
+	if validateError != nil {
+		return fmt.Errorf("invalid risk rule in %q: %w", scriptFilename, validateError)
+	}
+
+	what.source = scriptFilename
+
 	return nil
 }
@@ -12,10 +12,18 @@ import (
 )
 
 type Script struct {
-	id    map[string]any
-	match common.Statement
-	data  map[string]any
-	utils map[string]*statements.MethodStatement
+	id      map[string]any
+	iterate []*iteration
+	match   common.Statement
+	data    map[string]any
+	utils   map[string]*statements.MethodStatement
+}
+
+// iteration is one level of a script's 'iterate' section: the items of 'in' are set as variable 'item' in turn
+
+type iteration struct {
+	in   common.ValueExpression
+	item string
 }
 
 func (what *Script) NewScope(risk *types.RiskCategory) (*common.Scope, error) {
@@ -125,6 +133,14 @@ func (what *Script) ParseScript(script map[string]any) (*Script, error) {
 
 			what.id = stringItem
 
+		case common.Iterate:
+			item, errorScript, itemError := what.parseIterate(value)
+			if itemError != nil {
+				return what, fmt.Errorf("failed to parse %q: %v\nscript:\n%v", key, itemError, new(input.Strings).AddLineNumbers(errorScript))
+			}
+
+			what.iterate = item
+
 		case common.Data:
 			switch castValue := value.(type) {
 			case map[string]any:
@@ -155,86 +171,117 @@ func (what *Script) ParseScript(script map[string]any) (*Script, error) {
 	return what, nil
 }
 
-func (what *Script) GetTechnicalAssetsByRiskID(scope *common.Scope, riskID string) ([]any, error) {
+// GenerateRisks runs the script for each technical asset, or for each combination of items of the 'iterate' section
+
+func (what *Script) GenerateRisks(scope *common.Scope) ([]*types.Risk, string, error) {
+	risks := make([]*types.Risk, 0)
+	if len(what.iterate) > 0 {
+		errorLiteral, iterateError := what.generateRisksByIteration(scope, 0, &risks)
+		if iterateError != nil {
+			return nil, errorLiteral, iterateError
+		}
+
+		return risks, "", nil
+	}
+
 	value, valueOk := what.getItem(scope.Model, "technical_assets")
 	if !valueOk {
-		return nil, fmt.Errorf("no technical assets in scope")
+		return nil, "", fmt.Errorf("no technical assets in scope")
 	}
 
 	techAssets, techAssetsOk := value.(map[string]any)
 	if !techAssetsOk {
-		return nil, fmt.Errorf("unexpected format of technical assets %T", techAssets)
+		return nil, "", fmt.Errorf("unexpected format of technical assets %T", techAssets)
 	}
 
-	matchingTechAssets := make([]any, 0)
-	for techAssetName, techAsset := range techAssets {
-		_ = techAssetName
-
-		isMatch, _, matchError := what.matchRisk(scope, techAsset)
-		if matchError != nil {
-			return nil, matchError
-		}
-
-		if !isMatch {
-			continue
+	for _, techAssetName := range common.SortedKeys(techAssets) {
+		errorLiteral, riskError := what.addRisk(scope, &risks, techAssets[techAssetName])
+		if riskError != nil {
+			return nil, errorLiteral, riskError
 		}
-
-		matchingTechAssets = append(matchingTechAssets, techAsset)
 	}
 
-	return matchingTechAssets, nil
+	return risks, "", nil
 }
 
-func (what *Script) GenerateRisks(scope *common.Scope) ([]*types.Risk, string, error) {
-	value, valueOk := what.getItem(scope.Model, "technical_assets")
-	if !valueOk {
-		return nil, "", fmt.Errorf("no technical assets in scope")
+func (what *Script) generateRisksByIteration(outerScope *common.Scope, level int, risks *[]*types.Risk) (string, error) {
+	if level >= len(what.iterate) {
+		return what.addRisk(outerScope, risks)
 	}
 
-	techAssets, techAssetsOk := value.(map[string]any)
-	if !techAssetsOk {
-		return nil, "", fmt.Errorf("unexpected format of technical assets %T", techAssets)
+	value, errorLiteral, evalError := what.iterate[level].in.EvalAny(outerScope)
+	if evalError != nil {
+		return errorLiteral, fmt.Errorf("failed to eval items of %q level %d: %v", common.Iterate, level+1, evalError)
 	}
 
-	risks := make([]*types.Risk, 0)
-	for techAssetName, techAsset := range techAssets {
-		_ = techAssetName
+	items := make([]any, 0)
+	switch castValue := common.EmptyToNil(value).(type) {
+	case []any:
+		items = castValue
 
-		isMatch, errorMatchLiteral, matchError := what.matchRisk(scope, techAsset)
-		if matchError != nil {
-			return nil, errorMatchLiteral, matchError
+	case map[string]any:
+		for _, name := range common.SortedKeys(castValue) {
+			items = append(items, castValue[name])
 		}
 
-		if !isMatch {
-			continue
-		}
+	case nil:
 
-		risk, errorRiskLiteral, riskError := what.generateRisk(scope, techAsset)
-		if riskError != nil {
-			return nil, errorRiskLiteral, riskError
-		}
+	default:
+		return what.iterate[level].in.Literal(), fmt.Errorf("failed to eval items of %q level %d: expected iterable type, got %T", common.Iterate, level+1, value)
+	}
 
-		if risk == nil {
-			continue
+	for _, item := range items {
+		scope, cloneError := outerScope.Clone()
+		if cloneError != nil {
+			return "", fmt.Errorf("failed to clone scope: %v", cloneError)
 		}
 
-		riskId, errorGetIDLiteral, errorId := what.getRiskID(scope, techAsset)
-		if errorId != nil {
-			return nil, errorGetIDLiteral, errorId
-		}
+		scope.Set(what.iterate[level].item, item)
 
-		risk.SyntheticId = riskId
-		if len(risk.SyntheticId) == 0 {
-			risk.SyntheticId = risk.CategoryId + "@" + risk.MostRelevantDataAssetId
+		errorLiteral, iterateError := what.generateRisksByIteration(scope, level+1, risks)
+		if iterateError != nil {
+			return errorLiteral, iterateError
 		}
+	}
 
-		risks = append(risks, risk)
+	return "", nil
+}
+
+func (what *Script) addRisk(scope *common.Scope, risks *[]*types.Risk, args ...common.Value) (string, error) {
+	isMatch, errorMatchLiteral, matchError := what.matchRisk(scope, args...)
+	if matchError != nil {
+		return errorMatchLiteral, matchError
 	}
 
-	return risks, "", nil
+	if !isMatch {
+		return "", nil
+	}
+
+	risk, errorRiskLiteral, riskError := what.generateRisk(scope, args...)
+	if riskError != nil {
+		return errorRiskLiteral, riskError
+	}
+
+	if risk == nil {
+		return "", nil
+	}
+
+	riskId, errorGetIDLiteral, errorId := what.getRiskID(scope, args...)
+	if errorId != nil {
+		return errorGetIDLiteral, errorId
+	}
+
+	risk.SyntheticId = riskId
+	if len(risk.SyntheticId) == 0 {
+		risk.SyntheticId = risk.CategoryId + "@" + risk.MostRelevantDataAssetId
+	}
+
+	*risks = append(*risks, risk)
+
+	return "", nil
 }
 
-func (what *Script) matchRisk(outerScope *common.Scope, techAsset any) (bool, string, error) {
+func (what *Script) matchRisk(outerScope *common.Scope, args ...common.Value) (bool, string, error) {
 	if what.match == nil {
 		return false, "", nil
 	}
@@ -244,7 +291,7 @@ func (what *Script) matchRisk(outerScope *common.Scope, techAsset any) (bool, st
 		return false, "", fmt.Errorf("failed to clone scope: %v", cloneError)
 	}
 
-	scope.Args = append(scope.Args, techAsset)
+	scope.Args = append(scope.Args, args...)
 
 	errorLiteral, runError := what.match.Run(scope)
 	if runError != nil {
@@ -259,7 +306,7 @@ func (what *Script) matchRisk(outerScope *common.Scope, techAsset any) (bool, st
 	return false, "", nil
 }
 
-func (what *Script) generateRisk(outerScope *common.Scope, techAsset any) (*types.Risk, string, error) {
+func (what *Script) generateRisk(outerScope *common.Scope, args ...common.Value) (*types.Risk, string, error) {
 	if what.data == nil {
 		return nil, "", fmt.Errorf("no data template")
 	}
@@ -269,7 +316,7 @@ func (what *Script) generateRisk(outerScope *common.Scope, techAsset any) (*type
 		return nil, "", fmt.Errorf("failed to clone scope: %v", cloneError)
 	}
 
-	scope.Args = append(scope.Args, techAsset)
+	scope.Args = append(scope.Args, args...)
 
 	parameter, ok := what.data[common.Parameter]
 	if ok {
@@ -321,7 +368,7 @@ func (what *Script) generateRisk(outerScope *common.Scope, techAsset any) (*type
 	return &risk, "", nil
 }
 
-func (what *Script) getRiskID(outerScope *common.Scope, techAsset any) (string, string, error) {
+func (what *Script) getRiskID(outerScope *common.Scope, args ...common.Value) (string, string, error) {
 	if len(what.id) == 0 {
 		return "", "", fmt.Errorf("no ID expression")
 	}
@@ -331,7 +378,7 @@ func (what *Script) getRiskID(outerScope *common.Scope, techAsset any) (string,
 		return "", "", fmt.Errorf("failed to clone scope: %v", cloneError)
 	}
 
-	scope.Args = append(scope.Args, techAsset)
+	scope.Args = append(scope.Args, args...)
 
 	parameter, parameterOk := what.id[common.Parameter]
 	if parameterOk {
@@ -397,7 +444,7 @@ func (what *Script) getItem(value any, path ...string) (any, bool) {
 	for name, item := range object {
 		if strings.EqualFold(path[0], name) {
 			if len(path[1:]) > 0 {
-				return what.getItem(item)
+				return what.getItem(item, path[1:]...)
 			}
 
 			return item, true
@@ -407,6 +454,53 @@ func (what *Script) getItem(value any, path ...string) (any, bool) {
 	return nil, false
 }
 
+func (what *Script) parseIterate(script any) ([]*iteration, any, error) {
+	levels, ok := script.([]any)
+	if !ok {
+		levels = []any{script}
+	}
+
+	iterations := make([]*iteration, 0)
+	for n, level := range levels {
+		levelMap, isMap := level.(map[string]any)
+		if !isMap {
+			return nil, level, fmt.Errorf("unexpected format %T of level %d", level, n+1)
+		}
+
+		newIteration := new(iteration)
+		for key, value := range levelMap {
+			switch strings.ToLower(key) {
+			case common.In:
+				item, errorScript, itemError := new(expressions.ValueExpression).ParseValue(value)
+				if itemError != nil {
+					return nil, errorScript, fmt.Errorf("failed to parse %q of level %d: %v", key, n+1, itemError)
+				}
+
+				newIteration.in = item
+
+			case common.Item:
+				text, isText := value.(string)
+				if !isText {
+					return nil, value, fmt.Errorf("failed to parse %q of level %d: expected string, got %T", key, n+1, value)
+				}
+
+				newIteration.item = strings.ToLower(text)
+
+			default:
+				return nil, level, fmt.Errorf("unexpected key %q in level %d", key, n+1)
+			}
+		}
+
+		if newIteration.in == nil || len(newIteration.item) == 0 {
+			return nil, level, fmt.Errorf("level %d needs both %q and %q", n+1, common.In, common.Item)
+		}
+
+		iterations = append(iterations, newIteration)
+	}
+
+	return iterations, nil, nil
+}
+
 func (what *Script) parseUtils(script any) (map[string]*statements.MethodStatement, any, error) {
 	statementMap := make(map[string]*statements.MethodStatement)
 	switch castScript := script.(type) {
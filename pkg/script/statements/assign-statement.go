@@ -8,16 +8,17 @@ import (
 
 type AssignStatement struct {
 	literal string
-	items   map[string]common.Expression
+	items   []assignItem
+}
+
+type assignItem struct {
+	name       string
+	expression common.Expression
 }
 
 func (what *AssignStatement) Parse(script any) (common.Statement, any, error) {
 	what.literal = common.ToLiteral(script)
 
-	if what.items == nil {
-		what.items = make(map[string]common.Expression)
-	}
-
 	switch castScript := script.(type) {
 	case map[string]any:
 		return what.parse(castScript)
@@ -38,26 +39,27 @@ func (what *AssignStatement) Parse(script any) (common.Statement, any, error) {
 }
 
 func (what *AssignStatement) parse(script map[string]any) (common.Statement, any, error) {
-	for key, value := range script {
+	for _, key := range common.SortedKeys(script) {
+		value := script[key]
 		expression, errorScript, parseError := new(expressions.ExpressionList).ParseAny(value)
 		if parseError != nil {
 			return nil, errorScript, fmt.Errorf("failed to parse %q of assign-statement: %v", key, parseError)
 		}
 
-		what.items[key] = expression
+		what.items = append(what.items, assignItem{name: key, expression: expression})
 	}
 
 	return what, nil, nil
 }
 
 func (what *AssignStatement) Run(scope *common.Scope) (string, error) {
-	for name, item := range what.items {
-		value, errorLiteral, evalError := item.EvalAny(scope)
+	for _, item := range what.items {
+		value, errorLiteral, evalError := item.expression.EvalAny(scope)
 		if evalError != nil {
-			return errorLiteral, fmt.Errorf("failed to eval %q of assign-statement: %v", name, evalError)
+			return errorLiteral, fmt.Errorf("failed to eval %q of assign-statement: %v", item.name, evalError)
 		}
 
-		scope.Set(name, value)
+		scope.Set(item.name, value)
 	}
 
 	return "", nil
@@ -74,7 +74,7 @@ func (what *LoopStatement) Run(scope *common.Scope) (string, error) {
 		return errorEvalLiteral, evalError
 	}
 
-	switch castValue := value.(type) {
+	switch castValue := common.EmptyToNil(value).(type) {
 	case []any:
 		for index, item := range castValue {
 			if len(what.index) > 0 {
@@ -90,10 +90,15 @@ func (what *LoopStatement) Run(scope *common.Scope) (string, error) {
 			if runError != nil {
 				return errorLiteral, fmt.Errorf("failed to run loop-statement for item #%d: %v", index+1, runError)
 			}
+
+			if scope.HasReturned() {
+				break
+			}
 		}
 
 	case map[string]any:
-		for name, item := range castValue {
+		for _, name := range common.SortedKeys(castValue) {
+			item := castValue[name]
 			if len(what.index) > 0 {
 				scope.Set(what.index, name)
 			}
@@ -107,8 +112,14 @@ func (what *LoopStatement) Run(scope *common.Scope) (string, error) {
 			if runError != nil {
 				return errorLiteral, fmt.Errorf("failed to run loop-statement for item %q: %v", name, runError)
 			}
+
+			if scope.HasReturned() {
+				break
+			}
 		}
 
+	case nil:
+
 	default:
 		return what.Literal(), fmt.Errorf("failed to run loop-statement: expected iterable type, got %T", value)
 	}
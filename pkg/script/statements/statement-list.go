@@ -53,6 +53,10 @@ func (what *StatementList) Run(scope *common.Scope) (string, error) {
 		if statementError != nil {
 			return errorLiteral, statementError
 		}
+
+		if scope.HasReturned() {
+			break
+		}
 	}
 
 	return "", nil
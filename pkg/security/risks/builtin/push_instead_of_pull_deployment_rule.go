@@ -43,13 +43,14 @@ func (*PushInsteadPullDeploymentRule) SupportedTags() []string {
 
 func (r *PushInsteadPullDeploymentRule) GenerateRisks(input *types.Model) ([]*types.Risk, error) {
 	risks := make([]*types.Risk, 0)
-	impact := types.LowImpact
-	for _, buildPipeline := range input.TechnicalAssets {
+	for _, id := range input.SortedTechnicalAssetIDs() {
+		buildPipeline := input.TechnicalAssets[id]
 		if buildPipeline.Technologies.GetAttribute(types.BuildPipeline) {
 			for _, deploymentLink := range buildPipeline.CommunicationLinks {
 				targetAsset := input.TechnicalAssets[deploymentLink.TargetId]
 				if !deploymentLink.Readonly && deploymentLink.Usage == types.DevOps &&
 					!targetAsset.OutOfScope && !targetAsset.Technologies.GetAttribute(types.IsDevelopmentRelevant) && targetAsset.Usage == types.Business {
+					impact := types.LowImpact
 					if targetAsset.HighestProcessedConfidentiality(input) >= types.Confidential ||
 						targetAsset.HighestProcessedIntegrity(input) >= types.Critical ||
 						targetAsset.HighestProcessedAvailability(input) >= types.Critical {
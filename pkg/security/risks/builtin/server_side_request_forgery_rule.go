@@ -82,7 +82,8 @@ func (r *ServerSideRequestForgeryRule) createRisk(input *types.Model, technicalA
 		}
 	}
 	// adjust for cloud-based special risks
-	if impact == types.LowImpact && input.TrustBoundaries[technicalAsset.GetTrustBoundaryId(input)].Type.IsWithinCloud() {
+	trustBoundary, hasTrustBoundary := input.TrustBoundaries[technicalAsset.GetTrustBoundaryId(input)]
+	if impact == types.LowImpact && hasTrustBoundary && trustBoundary.Type.IsWithinCloud() {
 		impact = types.MediumImpact
 	}
 	dataBreachTechnicalAssetIDs := make([]string, 0)
@@ -0,0 +1,106 @@
+package risks
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/threagile/threagile/pkg/script"
+)
+
+// LintRiskRules lints the script risk rules in the given files and folders; folders are searched recursively for
+// files containing a risk script. It returns the problems found and the number of files linted.
+
+func LintRiskRules(paths []string) ([]script.LintIssue, int, error) {
+	linter := new(script.Linter).Init()
+	issues := make([]script.LintIssue, 0)
+	files := 0
+	for _, path := range paths {
+		info, statError := os.Stat(path)
+		if statError != nil {
+			return nil, files, statError
+		}
+
+		filenames := []string{path}
+		if info.IsDir() {
+			var findError error
+			filenames, findError = findRiskRuleScripts(path)
+			if findError != nil {
+				return nil, files, findError
+			}
+		}
+
+		for _, filename := range filenames {
+			data, readError := os.ReadFile(filename)
+			if readError != nil {
+				return nil, files, fmt.Errorf("failed to read %q: %v", filename, readError)
+			}
+
+			issues = append(issues, linter.Lint(filename, data)...)
+			files++
+		}
+	}
+
+	return issues, files, nil
+}
+
+// LintEmbeddedRiskRules lints the script risk rules shipped with threagile
+
+func LintEmbeddedRiskRules() ([]script.LintIssue, error) {
+	linter := new(script.Linter).Init()
+	issues := make([]script.LintIssue, 0)
+	walkError := fs.WalkDir(ruleScripts, "scripts", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		isScript, scriptError := isRiskRuleScript(ruleScripts, path)
+		if scriptError != nil {
+			return fmt.Errorf("error parsing %q: %w", path, scriptError)
+		}
+		if !isScript {
+			return nil
+		}
+
+		data, readError := fs.ReadFile(ruleScripts, path)
+		if readError != nil {
+			return readError
+		}
+
+		issues = append(issues, linter.Lint(path, data)...)
+		return nil
+	})
+
+	return issues, walkError
+}
+
+func findRiskRuleScripts(folder string) ([]string, error) {
+	fileSystem := os.DirFS(folder)
+	filenames := make([]string, 0)
+	walkError := fs.WalkDir(fileSystem, ".", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		// files that don't parse are linted as well, to report why
+		isScript, scriptError := isRiskRuleScript(fileSystem, path)
+		if isScript || scriptError != nil {
+			filenames = append(filenames, filepath.Join(folder, path))
+		}
+
+		return nil
+	})
+
+	sort.Strings(filenames)
+	return filenames, walkError
+}
@@ -2,14 +2,75 @@ package risks
 
 import (
 	"embed"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/common"
 	"github.com/threagile/threagile/pkg/script"
+	scriptcommon "github.com/threagile/threagile/pkg/script/common"
 	"github.com/threagile/threagile/pkg/security/risks/builtin"
 	"github.com/threagile/threagile/pkg/security/types"
-	"io/fs"
+	"gopkg.in/yaml.v3"
 )
 
-func GetBuiltInRiskRules() types.RiskRules {
+// GetBuiltInRiskRules returns the built-in risk rules, the embedded script risk rules and, if a config is given,
+// the script risk rules found in the configured script folders and the plugin folder
+
+func GetBuiltInRiskRules(config *common.Config, reporter types.ProgressReporter) types.RiskRules {
+	rules := GetGoRiskRules()
+
+	scriptRules, scriptError := GetScriptRiskRules()
+	if scriptError != nil {
+		reporter.Warnf("error loading script risk rules: %v", scriptError)
+	}
+
+	for id, rule := range scriptRules {
+		builtinRule, ok := rules[id]
+		if ok && builtinRule != nil {
+			reporter.Infof("script risk rule %q shadows built-in risk rule", id)
+		}
+
+		rules[id] = rule
+	}
+
+	if config == nil {
+		return rules
+	}
+
+	loadedSources := make(map[string]string) // of the script risk rules loaded from folders, by their ID
+	for _, folder := range config.RiskRulesScriptFolders {
+		if len(folder) == 0 {
+			continue
+		}
+
+		folderRules, folderError := make(RiskRules).LoadRiskRulesFromFolder(folder, true)
+		if folderError != nil {
+			reporter.Warnf("error loading script risk rules from %q: %v", folder, folderError)
+		}
+
+		mergeScriptRiskRules(rules, folderRules, loadedSources, reporter)
+	}
+
+	if len(config.PluginFolder) > 0 {
+		pluginRules, pluginError := make(RiskRules).LoadRiskRulesFromFolder(config.PluginFolder, false)
+		if pluginError != nil {
+			reporter.Warnf("error loading script risk rules from plugin folder %q: %v", config.PluginFolder, pluginError)
+		}
+
+		mergeScriptRiskRules(rules, pluginRules, loadedSources, reporter)
+	}
+
+	return rules
+}
+
+// GetGoRiskRules returns the risk rules implemented in Go; the embedded script risk rules shadow most of them and
+// are kept in sync with them by a parity test
+
+func GetGoRiskRules() types.RiskRules {
 	rules := make(types.RiskRules)
 	for _, rule := range []types.RiskRule{
 		builtin.NewAccidentalSecretLeakRule(),
@@ -58,22 +119,29 @@ func GetBuiltInRiskRules() types.RiskRules {
 		rules[rule.Category().ID] = rule
 	}
 
-	scriptRules, scriptError := GetScriptRiskRules()
-	if scriptError != nil {
-		fmt.Printf("error loading script risk rules: %v\n", scriptError)
-		return rules
-	}
+	return rules
+}
 
+func mergeScriptRiskRules(rules types.RiskRules, scriptRules RiskRules, loadedSources map[string]string, reporter types.ProgressReporter) {
 	for id, rule := range scriptRules {
-		builtinRule, ok := rules[id]
-		if ok && builtinRule != nil {
-			fmt.Printf("WARNING: script risk rule %q shadows built-in risk rule\n", id)
+		source := id
+		scriptRule, isScriptRule := rule.(*script.RiskRule)
+		if isScriptRule {
+			source = scriptRule.Source()
+		}
+
+		existingRule, ok := rules[id]
+		if loadedSource, isLoaded := loadedSources[id]; isLoaded {
+			reporter.Warnf("script risk rule %q from %q replaces the one from %q", id, source, loadedSource)
+		} else if ok && existingRule != nil {
+			reporter.Warnf("script risk rule %q from %q replaces the built-in one", id, source)
+		} else {
+			reporter.Infof("loaded script risk rule %q from %q", id, source)
 		}
 
 		rules[id] = rule
+		loadedSources[id] = source
 	}
-
-	return rules
 }
 
 //go:embed scripts/*.yaml
@@ -86,16 +154,58 @@ func GetScriptRiskRules() (RiskRules, error) {
 }
 
 func (what RiskRules) LoadRiskRules() (RiskRules, error) {
-	fileSystem := ruleScripts
-	walkError := fs.WalkDir(fileSystem, "scripts", func(path string, entry fs.DirEntry, err error) error {
+	return what.loadRiskRules(ruleScripts, "scripts", "", true)
+}
+
+// LoadRiskRulesFromFolder loads all YAML script risk rules from a folder; files that do not contain a risk script are
+// ignored, while broken risk scripts are skipped and reported in the returned error
+
+func (what RiskRules) LoadRiskRulesFromFolder(folder string, recursive bool) (RiskRules, error) {
+	folderInfo, statError := os.Stat(folder)
+	if statError != nil {
+		return what, statError
+	}
+
+	if !folderInfo.IsDir() {
+		return what, fmt.Errorf("%q is not a folder", folder)
+	}
+
+	return what.loadRiskRules(os.DirFS(folder), ".", folder, recursive)
+}
+
+func (what RiskRules) loadRiskRules(fileSystem fs.FS, root string, folder string, recursive bool) (RiskRules, error) {
+	loadErrors := make([]error, 0)
+	walkError := fs.WalkDir(fileSystem, root, func(path string, entry fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
+		if entry.IsDir() {
+			if path != root && !recursive {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		isScript, scriptError := isRiskRuleScript(fileSystem, path)
+		if scriptError != nil {
+			loadErrors = append(loadErrors, fmt.Errorf("error parsing %q: %w", filepath.Join(folder, path), scriptError))
+			return nil
+		}
+		if !isScript {
+			return nil
+		}
+
 		newRule := new(script.RiskRule).Init()
 		loadError := newRule.Load(fileSystem, path, entry)
 		if loadError != nil {
-			return loadError
+			loadErrors = append(loadErrors, loadError)
+			return nil
+		}
+
+		if len(folder) > 0 {
+			newRule.SetSource(filepath.Join(folder, path))
 		}
 
 		if newRule.Category().ID == "" {
@@ -110,5 +220,33 @@ func (what RiskRules) LoadRiskRules() (RiskRules, error) {
 		return nil, walkError
 	}
 
+	if len(loadErrors) > 0 {
+		return what, errors.Join(loadErrors...)
+	}
+
 	return what, nil
 }
+
+// isRiskRuleScript tells whether the file is a YAML file with a risk script; YAML files that can't be read or parsed
+// can't be told apart from broken risk scripts, so they are reported
+
+func isRiskRuleScript(fileSystem fs.FS, path string) (bool, error) {
+	extension := strings.ToLower(filepath.Ext(path))
+	if extension != ".yaml" && extension != ".yml" {
+		return false, nil
+	}
+
+	data, readError := fs.ReadFile(fileSystem, path)
+	if readError != nil {
+		return false, readError
+	}
+
+	var items map[string]any
+	parseError := yaml.Unmarshal(data, &items)
+	if parseError != nil {
+		return false, parseError
+	}
+
+	_, hasRisk := items[scriptcommon.Risk]
+	return hasRisk, nil
+}
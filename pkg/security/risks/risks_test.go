@@ -0,0 +1,157 @@
+package risks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/script"
+)
+
+func TestLoadRiskRulesFromFolderLoadsScriptRules(t *testing.T) {
+	folder := t.TempDir()
+	writeRiskRuleScript(t, folder, "custom-rule.yaml", "custom-rule")
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, "not-a-rule.yaml"), []byte("title: Something Else\n"), 0600))
+
+	rules, err := make(RiskRules).LoadRiskRulesFromFolder(folder, true)
+
+	assert.NoError(t, err)
+	assert.Len(t, rules, 1)
+	assert.Contains(t, rules, "custom-rule")
+	assert.Equal(t, filepath.Join(folder, "custom-rule.yaml"), rules["custom-rule"].(*script.RiskRule).Source())
+}
+
+func TestLoadRiskRulesFromFolderReportsInvalidRules(t *testing.T) {
+	folder := t.TempDir()
+	writeRiskRuleScript(t, folder, "custom-rule.yaml", "custom-rule")
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, "broken-rule.yaml"), []byte("id: broken-rule\nrisk:\n  id: {}\n"), 0600))
+
+	rules, err := make(RiskRules).LoadRiskRulesFromFolder(folder, true)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "broken-rule.yaml")
+	assert.Contains(t, rules, "custom-rule")
+	assert.NotContains(t, rules, "broken-rule")
+}
+
+func TestLoadRiskRulesFromFolderReportsUnparsableFiles(t *testing.T) {
+	folder := t.TempDir()
+	writeRiskRuleScript(t, folder, "custom-rule.yaml", "custom-rule")
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, "unparsable-rule.yaml"), []byte("id: unparsable-rule\nrisk:\n\t- broken\n"), 0600))
+
+	rules, err := make(RiskRules).LoadRiskRulesFromFolder(folder, true)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), filepath.Join(folder, "unparsable-rule.yaml"))
+	assert.Contains(t, rules, "custom-rule")
+}
+
+func TestLoadRiskRulesFromFolderNonRecursive(t *testing.T) {
+	folder := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(folder, "sub"), 0700))
+	writeRiskRuleScript(t, filepath.Join(folder, "sub"), "custom-rule.yaml", "custom-rule")
+
+	rules, err := make(RiskRules).LoadRiskRulesFromFolder(folder, false)
+
+	assert.NoError(t, err)
+	assert.Empty(t, rules)
+}
+
+func TestGetBuiltInRiskRulesIncludesScriptFolders(t *testing.T) {
+	folder := t.TempDir()
+	writeRiskRuleScript(t, folder, "custom-rule.yml", "custom-rule")
+
+	config := new(common.Config).Defaults("")
+	config.PluginFolder = ""
+	config.RiskRulesScriptFolders = []string{folder}
+
+	rules := GetBuiltInRiskRules(config, &common.DefaultProgressReporter{SuppressError: true})
+
+	assert.Contains(t, rules, "custom-rule")
+	assert.Contains(t, rules, "missing-hardening")
+}
+
+func TestGetBuiltInRiskRulesWarnsAboutReplacedRules(t *testing.T) {
+	folder := t.TempDir()
+	writeRiskRuleScript(t, folder, "custom-rule.yaml", "custom-rule")
+	otherFolder := t.TempDir()
+	writeRiskRuleScript(t, otherFolder, "custom-rule.yaml", "custom-rule")
+	writeRiskRuleScript(t, otherFolder, "accidental-secret-leak.yaml", "accidental-secret-leak")
+
+	config := new(common.Config).Defaults("")
+	config.PluginFolder = ""
+	config.RiskRulesScriptFolders = []string{folder, otherFolder}
+
+	reporter := new(risksTestProgressReporter)
+	rules := GetBuiltInRiskRules(config, reporter)
+
+	assert.Equal(t, filepath.Join(otherFolder, "custom-rule.yaml"), rules["custom-rule"].(*script.RiskRule).Source())
+	assert.ElementsMatch(t, []string{
+		fmt.Sprintf("script risk rule %q from %q replaces the one from %q", "custom-rule",
+			filepath.Join(otherFolder, "custom-rule.yaml"), filepath.Join(folder, "custom-rule.yaml")),
+		fmt.Sprintf("script risk rule %q from %q replaces the built-in one", "accidental-secret-leak",
+			filepath.Join(otherFolder, "accidental-secret-leak.yaml")),
+	}, reporter.warnings)
+}
+
+func TestEmbeddedRiskRulesLintClean(t *testing.T) {
+	issues, err := LintEmbeddedRiskRules()
+
+	assert.NoError(t, err)
+	assert.Empty(t, issues)
+}
+
+func TestLintRiskRulesReportsFileAndLine(t *testing.T) {
+	folder := t.TempDir()
+	writeRiskRuleScript(t, folder, "custom-rule.yaml", "custom-rule")
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, "not-a-rule.yaml"), []byte("title: Something Else\n"), 0600))
+
+	issues, files, err := LintRiskRules([]string{folder})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, files)
+	if assert.Len(t, issues, 1) {
+		assert.Equal(t, filepath.Join(folder, "custom-rule.yaml"), issues[0].Filename)
+		assert.Equal(t, 15, issues[0].Line)
+		assert.Equal(t, `unknown risk field "create-risk"`, issues[0].Message)
+	}
+}
+
+func writeRiskRuleScript(t *testing.T, folder string, filename string, id string) {
+	text := `id: ` + id + `
+title: Custom Rule
+function: architecture
+stride: tampering
+risk:
+  id:
+    parameter: tech_asset
+    id: "{$risk.id}@{tech_asset.id}"
+  match:
+    parameter: tech_asset
+    do:
+      - return: false
+  data:
+    parameter: tech_asset
+    create-risk:
+      title: "Custom Risk at {tech_asset.title}"
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, filename), []byte(text), 0600))
+}
+
+type risksTestProgressReporter struct {
+	warnings []string
+}
+
+func (what *risksTestProgressReporter) Info(_ ...any) {}
+func (what *risksTestProgressReporter) Warn(a ...any) {
+	what.warnings = append(what.warnings, fmt.Sprint(a...))
+}
+func (what *risksTestProgressReporter) Error(_ ...any)            {}
+func (what *risksTestProgressReporter) Infof(_ string, _ ...any)  {}
+func (what *risksTestProgressReporter) Errorf(_ string, _ ...any) {}
+func (what *risksTestProgressReporter) Warnf(format string, a ...any) {
+	what.Warn(fmt.Sprintf(format, a...))
+}
@@ -6,6 +6,8 @@ package types
 
 import (
 	"sort"
+
+	"github.com/threagile/threagile/pkg/input"
 )
 
 type CommunicationLink struct {
@@ -26,6 +28,7 @@ type CommunicationLink struct {
 	DataAssetsReceived     []string       `json:"data_assets_received,omitempty" yaml:"data_assets_received,omitempty"`
 	DiagramTweakWeight     int            `json:"diagram_tweak_weight,omitempty" yaml:"diagram_tweak_weight,omitempty"`
 	DiagramTweakConstraint bool           `json:"diagram_tweak_constraint,omitempty" yaml:"diagram_tweak_constraint,omitempty"`
+	Position               input.Position `json:"position,omitempty" yaml:"position,omitempty"`
 }
 
 func (what CommunicationLink) IsTaggedWithAny(tags ...string) bool {
@@ -6,6 +6,8 @@ package types
 
 import (
 	"sort"
+
+	"github.com/threagile/threagile/pkg/input"
 )
 
 type DataAsset struct {
@@ -21,6 +23,7 @@ type DataAsset struct {
 	Integrity              Criticality     `yaml:"integrity,omitempty" json:"integrity,omitempty"`
 	Availability           Criticality     `yaml:"availability,omitempty" json:"availability,omitempty"`
 	JustificationCiaRating string          `yaml:"justification_cia_rating,omitempty" json:"justification_cia_rating,omitempty"`
+	Position               input.Position  `yaml:"position,omitempty" json:"position,omitempty"`
 }
 
 func (what DataAsset) IsTaggedWithAny(tags ...string) bool {
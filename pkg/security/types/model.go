@@ -10,6 +10,7 @@ import (
 	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/threagile/threagile/pkg/input"
 )
@@ -115,6 +116,10 @@ func (parsedModel *Model) ApplyWildcardRiskTrackingEvaluation(ignoreOrphanedRisk
 					Ticket:          riskTracking.Ticket,
 					Status:          riskTracking.Status,
 					Date:            riskTracking.Date,
+					Expires:         riskTracking.Expires,
+					ReviewBy:        riskTracking.ReviewBy,
+					Owner:           riskTracking.Owner,
+					Position:        riskTracking.Position,
 				}
 			}
 		}
@@ -123,21 +128,31 @@ func (parsedModel *Model) ApplyWildcardRiskTrackingEvaluation(ignoreOrphanedRisk
 			if ignoreOrphanedRiskTracking {
 				progressReporter.Warnf("Wildcard risk tracking does not match any risk id: %v", syntheticRiskIdPattern)
 			} else {
-				return fmt.Errorf("wildcard risk tracking does not match any risk id: %v", syntheticRiskIdPattern)
+				return riskTracking.Position.Errorf("wildcard risk tracking does not match any risk id: %v", syntheticRiskIdPattern)
 			}
 		}
 	}
 	return nil
 }
 
-func (parsedModel *Model) CheckRiskTracking(ignoreOrphanedRiskTracking bool, progressReporter ProgressReporter) error {
+// CheckRiskTracking checks that each risk tracking references a risk, reverts acceptances that expired before the given
+// day to unchecked and assigns the risk status of each risk from its tracking; expiry dates of other statuses, like
+// mitigated, are reminders to review them only
+
+func (parsedModel *Model) CheckRiskTracking(ignoreOrphanedRiskTracking bool, progressReporter ProgressReporter, today time.Time) error {
 	progressReporter.Info("Checking risk tracking")
 	for _, tracking := range parsedModel.RiskTracking {
+		if tracking.Status == Accepted && tracking.IsExpired(today) {
+			progressReporter.Warnf("Risk tracking of %v expired on %v, reverting status %v to %v",
+				tracking.SyntheticRiskId, tracking.Expires.Format("2006-01-02"), tracking.Status, Unchecked)
+			tracking.Status = Unchecked
+		}
+
 		if _, ok := parsedModel.GeneratedRisksBySyntheticId[tracking.SyntheticRiskId]; !ok {
 			if ignoreOrphanedRiskTracking {
 				progressReporter.Infof("Risk tracking references unknown risk (risk id not found): %v", tracking.SyntheticRiskId)
 			} else {
-				return fmt.Errorf("Risk tracking references unknown risk (risk id not found) - you might want to use the option -ignore-orphaned-risk-tracking: %v"+
+				return tracking.Position.Wrap(fmt.Errorf("Risk tracking references unknown risk (risk id not found) - you might want to use the option -ignore-orphaned-risk-tracking: %v"+
 					"\n\nNOTE: For risk tracking each risk-id needs to be defined (the string with the @ sign in it). "+
 					"These unique risk IDs are visible in the PDF report (the small grey string under each risk), "+
 					"the Excel (column \"ID\"), as well as the JSON responses. Some risk IDs have only one @ sign in them, "+
@@ -146,14 +161,45 @@ func (parsedModel *Model) CheckRiskTracking(ignoreOrphanedRiskTracking bool, pro
 					"Using wildcards (the * sign) for parts delimited by @ signs allows to handle groups of certain risks at once. "+
 					"Best is to lookup the IDs to use in the created Excel file. Alternatively a model macro \"seed-risk-tracking\" "+
 					"is available that helps in initially seeding the risk tracking part here based on already identified and not yet handled risks.",
-					tracking.SyntheticRiskId)
+					tracking.SyntheticRiskId))
 			}
 		}
 	}
 
+	for _, risk := range parsedModel.GeneratedRisksBySyntheticId {
+		risk.RiskStatus = risk.GetRiskTrackingWithDefault(parsedModel).Status
+	}
+
 	return nil
 }
 
+// RiskPosition returns where the element most relevant to the risk is defined, preferring the communication link over
+// the technical asset, trust boundary, shared runtime and data asset
+
+func (parsedModel *Model) RiskPosition(risk *Risk) input.Position {
+	if link, ok := parsedModel.CommunicationLinks[risk.MostRelevantCommunicationLinkId]; ok && link.Position.IsKnown() {
+		return link.Position
+	}
+
+	if techAsset, ok := parsedModel.TechnicalAssets[risk.MostRelevantTechnicalAssetId]; ok && techAsset.Position.IsKnown() {
+		return techAsset.Position
+	}
+
+	if trustBoundary, ok := parsedModel.TrustBoundaries[risk.MostRelevantTrustBoundaryId]; ok && trustBoundary.Position.IsKnown() {
+		return trustBoundary.Position
+	}
+
+	if sharedRuntime, ok := parsedModel.SharedRuntimes[risk.MostRelevantSharedRuntimeId]; ok && sharedRuntime.Position.IsKnown() {
+		return sharedRuntime.Position
+	}
+
+	if dataAsset, ok := parsedModel.DataAssets[risk.MostRelevantDataAssetId]; ok && dataAsset.Position.IsKnown() {
+		return dataAsset.Position
+	}
+
+	return input.Position{}
+}
+
 func (parsedModel *Model) CheckTagExists(referencedTag, where string) error {
 	if !slices.Contains(parsedModel.TagsAvailable, referencedTag) {
 		return fmt.Errorf("missing referenced tag in overall tag list at %v: %v", where, referencedTag)
@@ -1,10 +1,113 @@
 package types
 
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/threagile/threagile/pkg/input"
+)
+
+// RiskTrackingDueSoonDays is how many days ahead of its expiry or review date a risk tracking is due soon
+
+const RiskTrackingDueSoonDays = 30
+
 type RiskTracking struct {
-	SyntheticRiskId string     `json:"synthetic_risk_id,omitempty" yaml:"synthetic_risk_id,omitempty"`
-	Justification   string     `json:"justification,omitempty" yaml:"justification,omitempty"`
-	Ticket          string     `json:"ticket,omitempty" yaml:"ticket,omitempty"`
-	CheckedBy       string     `json:"checked_by,omitempty" yaml:"checked_by,omitempty"`
-	Status          RiskStatus `json:"status,omitempty" yaml:"status,omitempty"`
-	Date            Date       `json:"date,omitempty" yaml:"date,omitempty"`
+	SyntheticRiskId string         `json:"synthetic_risk_id,omitempty" yaml:"synthetic_risk_id,omitempty"`
+	Justification   string         `json:"justification,omitempty" yaml:"justification,omitempty"`
+	Ticket          string         `json:"ticket,omitempty" yaml:"ticket,omitempty"`
+	CheckedBy       string         `json:"checked_by,omitempty" yaml:"checked_by,omitempty"`
+	Status          RiskStatus     `json:"status,omitempty" yaml:"status,omitempty"`
+	Date            Date           `json:"date,omitempty" yaml:"date,omitempty"`
+	Expires         Date           `json:"expires,omitempty" yaml:"expires,omitempty"`
+	ReviewBy        Date           `json:"review_by,omitempty" yaml:"review_by,omitempty"`
+	Owner           string         `json:"owner,omitempty" yaml:"owner,omitempty"`
+	Position        input.Position `json:"position,omitempty" yaml:"position,omitempty"`
+}
+
+// IsExpired tells whether the risk tracking expired before the given day; it is valid through its expiry date
+
+func (what *RiskTracking) IsExpired(today time.Time) bool {
+	return !what.Expires.IsZero() && what.Expires.Before(startOfDay(today))
+}
+
+// DueDate returns the earlier of the expiry and review date, or the zero time if there is neither
+
+func (what *RiskTracking) DueDate() time.Time {
+	switch {
+	case what.Expires.IsZero():
+		return what.ReviewBy.Time
+	case what.ReviewBy.IsZero() || what.Expires.Before(what.ReviewBy.Time):
+		return what.Expires.Time
+	default:
+		return what.ReviewBy.Time
+	}
+}
+
+// IsOverdue tells whether the risk tracking expired or should have been reviewed before the given day
+
+func (what *RiskTracking) IsOverdue(today time.Time) bool {
+	due := what.DueDate()
+	return !due.IsZero() && due.Before(startOfDay(today))
+}
+
+// IsDueWithin tells whether the risk tracking expires or is to be reviewed within the given number of days after
+// the given day, or is overdue
+
+func (what *RiskTracking) IsDueWithin(today time.Time, days int) bool {
+	due := what.DueDate()
+	return !due.IsZero() && !due.After(startOfDay(today).AddDate(0, 0, days))
+}
+
+// RisksWithTrackingDueWithin returns the risks whose tracking expires or is to be reviewed within the given number of
+// days after the given day, or is overdue, sorted by due date
+
+func (parsedModel *Model) RisksWithTrackingDueWithin(today time.Time, days int) []*Risk {
+	risks := make([]*Risk, 0)
+	for _, risk := range parsedModel.GeneratedRisksBySyntheticId {
+		if tracking := risk.GetRiskTracking(parsedModel); tracking != nil && tracking.IsDueWithin(today, days) {
+			risks = append(risks, risk)
+		}
+	}
+
+	sort.Slice(risks, func(i, j int) bool {
+		left, right := risks[i].GetRiskTracking(parsedModel).DueDate(), risks[j].GetRiskTracking(parsedModel).DueDate()
+		if !left.Equal(right) {
+			return left.Before(right)
+		}
+		return risks[i].SyntheticId < risks[j].SyntheticId
+	})
+
+	return risks
+}
+
+// RiskTrackingEntries returns the risk tracking entries as written in the model, sorted by synthetic risk id and
+// leaving out those a wildcard entry was applied as
+
+func (parsedModel *Model) RiskTrackingEntries() []*RiskTracking {
+	wildcardPositions := make(map[string]bool)
+	for id, tracking := range parsedModel.RiskTracking {
+		if strings.Contains(id, "*") && tracking.Position.IsKnown() {
+			wildcardPositions[tracking.Position.String()] = true
+		}
+	}
+
+	ids := make([]string, 0)
+	for id, tracking := range parsedModel.RiskTracking {
+		if strings.Contains(id, "*") || !wildcardPositions[tracking.Position.String()] {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	entries := make([]*RiskTracking, 0)
+	for _, id := range ids {
+		entries = append(entries, parsedModel.RiskTracking[id])
+	}
+	return entries
+}
+
+func startOfDay(day time.Time) time.Time {
+	year, month, date := day.Date()
+	return time.Date(year, month, date, 0, 0, 0, 0, time.UTC)
 }
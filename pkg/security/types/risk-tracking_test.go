@@ -0,0 +1,90 @@
+package types
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type riskTrackingTestProgressReporter struct {
+	warnings []string
+}
+
+func (what *riskTrackingTestProgressReporter) Info(_ ...any) {}
+func (what *riskTrackingTestProgressReporter) Warn(a ...any) {
+	what.warnings = append(what.warnings, fmt.Sprint(a...))
+}
+func (what *riskTrackingTestProgressReporter) Error(_ ...any)            {}
+func (what *riskTrackingTestProgressReporter) Infof(_ string, _ ...any)  {}
+func (what *riskTrackingTestProgressReporter) Errorf(_ string, _ ...any) {}
+func (what *riskTrackingTestProgressReporter) Warnf(format string, a ...any) {
+	what.Warn(fmt.Sprintf(format, a...))
+}
+
+func riskTrackingTestDate(value string) Date {
+	date, _ := time.Parse("2006-01-02", value)
+	return Date{Time: date}
+}
+
+func TestRiskTrackingDueDate(t *testing.T) {
+	today := time.Date(2024, 6, 15, 14, 30, 0, 0, time.UTC)
+
+	tracking := &RiskTracking{}
+	assert.True(t, tracking.DueDate().IsZero())
+	assert.False(t, tracking.IsExpired(today))
+	assert.False(t, tracking.IsOverdue(today))
+	assert.False(t, tracking.IsDueWithin(today, 30))
+
+	tracking = &RiskTracking{Expires: riskTrackingTestDate("2024-06-15"), ReviewBy: riskTrackingTestDate("2024-07-01")}
+	assert.Equal(t, tracking.Expires.Time, tracking.DueDate())
+	assert.False(t, tracking.IsExpired(today), "valid through its expiry date")
+	assert.True(t, tracking.IsExpired(today.AddDate(0, 0, 1)))
+	assert.True(t, tracking.IsDueWithin(today, 0))
+
+	tracking = &RiskTracking{Expires: riskTrackingTestDate("2024-12-31"), ReviewBy: riskTrackingTestDate("2024-06-01")}
+	assert.Equal(t, tracking.ReviewBy.Time, tracking.DueDate())
+	assert.False(t, tracking.IsExpired(today))
+	assert.True(t, tracking.IsOverdue(today))
+
+	tracking = &RiskTracking{ReviewBy: riskTrackingTestDate("2024-07-15")}
+	assert.True(t, tracking.IsDueWithin(today, 30))
+	assert.False(t, tracking.IsDueWithin(today, 29))
+}
+
+func TestCheckRiskTrackingRevertsExpired(t *testing.T) {
+	parsedModel := &Model{
+		GeneratedRisksBySyntheticId: map[string]*Risk{
+			"missing-waf@web":        {SyntheticId: "missing-waf@web"},
+			"sql-nosql-injection@db": {SyntheticId: "sql-nosql-injection@db"},
+			"missing-hardening@web":  {SyntheticId: "missing-hardening@web"},
+			"missing-vault@web":      {SyntheticId: "missing-vault@web"},
+		},
+		RiskTracking: map[string]*RiskTracking{
+			"missing-waf@web": {SyntheticRiskId: "missing-waf@web", Status: Accepted,
+				Expires: riskTrackingTestDate("2024-01-31")},
+			"sql-nosql-injection@db": {SyntheticRiskId: "sql-nosql-injection@db", Status: Accepted,
+				Expires: riskTrackingTestDate("2024-12-31"), ReviewBy: riskTrackingTestDate("2024-06-01")},
+			"missing-vault@web": {SyntheticRiskId: "missing-vault@web", Status: Mitigated,
+				Expires: riskTrackingTestDate("2024-03-31")},
+		},
+	}
+
+	progressReporter := new(riskTrackingTestProgressReporter)
+	require.NoError(t, parsedModel.CheckRiskTracking(false, progressReporter, time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)))
+
+	assert.Equal(t, Unchecked, parsedModel.RiskTracking["missing-waf@web"].Status)
+	assert.Equal(t, Unchecked, parsedModel.GeneratedRisksBySyntheticId["missing-waf@web"].RiskStatus)
+	assert.Equal(t, Accepted, parsedModel.GeneratedRisksBySyntheticId["sql-nosql-injection@db"].RiskStatus)
+	assert.Equal(t, Unchecked, parsedModel.GeneratedRisksBySyntheticId["missing-hardening@web"].RiskStatus)
+	assert.Equal(t, Mitigated, parsedModel.GeneratedRisksBySyntheticId["missing-vault@web"].RiskStatus, "only acceptances expire")
+	assert.Equal(t, []string{"Risk tracking of missing-waf@web expired on 2024-01-31, reverting status accepted to unchecked"}, progressReporter.warnings)
+
+	risks := parsedModel.RisksWithTrackingDueWithin(time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC), RiskTrackingDueSoonDays)
+	require.Len(t, risks, 3)
+	assert.Equal(t, "missing-waf@web", risks[0].SyntheticId)
+	assert.Equal(t, "missing-vault@web", risks[1].SyntheticId)
+	assert.Equal(t, "sql-nosql-injection@db", risks[2].SyntheticId)
+}
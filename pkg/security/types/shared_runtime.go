@@ -6,14 +6,17 @@ package types
 
 import (
 	"sort"
+
+	"github.com/threagile/threagile/pkg/input"
 )
 
 type SharedRuntime struct {
-	Id                     string   `json:"id,omitempty" yaml:"id,omitempty"`
-	Title                  string   `json:"title,omitempty" yaml:"title,omitempty"`
-	Description            string   `json:"description,omitempty" yaml:"description,omitempty"`
-	Tags                   []string `json:"tags,omitempty" yaml:"tags,omitempty"`
-	TechnicalAssetsRunning []string `json:"technical_assets_running,omitempty" yaml:"technical_assets_running,omitempty"`
+	Id                     string         `json:"id,omitempty" yaml:"id,omitempty"`
+	Title                  string         `json:"title,omitempty" yaml:"title,omitempty"`
+	Description            string         `json:"description,omitempty" yaml:"description,omitempty"`
+	Tags                   []string       `json:"tags,omitempty" yaml:"tags,omitempty"`
+	TechnicalAssetsRunning []string       `json:"technical_assets_running,omitempty" yaml:"technical_assets_running,omitempty"`
+	Position               input.Position `json:"position,omitempty" yaml:"position,omitempty"`
 }
 
 func (what SharedRuntime) IsTaggedWithAny(tags ...string) bool {
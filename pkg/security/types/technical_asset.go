@@ -7,6 +7,8 @@ package types
 import (
 	"fmt"
 	"sort"
+
+	"github.com/threagile/threagile/pkg/input"
 )
 
 type TechnicalAsset struct {
@@ -38,6 +40,7 @@ type TechnicalAsset struct {
 	CommunicationLinks      []*CommunicationLink  `json:"communication_links,omitempty" yaml:"communication_links,omitempty"`
 	DiagramTweakOrder       int                   `json:"diagram_tweak_order,omitempty" yaml:"diagram_tweak_order,omitempty"`
 	RAA                     float64               `json:"raa,omitempty" yaml:"raa,omitempty"` // will be set by separate calculation step
+	Position                input.Position        `json:"position,omitempty" yaml:"position,omitempty"`
 }
 
 func (what TechnicalAsset) IsTaggedWithAny(tags ...string) bool {
@@ -6,6 +6,8 @@ package types
 
 import (
 	"sort"
+
+	"github.com/threagile/threagile/pkg/input"
 )
 
 type TrustBoundary struct {
@@ -16,6 +18,7 @@ type TrustBoundary struct {
 	Tags                  []string          `json:"tags,omitempty" yaml:"tags,omitempty"`
 	TechnicalAssetsInside []string          `json:"technical_assets_inside,omitempty" yaml:"technical_assets_inside,omitempty"`
 	TrustBoundariesNested []string          `json:"trust_boundaries_nested,omitempty" yaml:"trust_boundaries_nested,omitempty"`
+	Position              input.Position    `json:"position,omitempty" yaml:"position,omitempty"`
 }
 
 func (what TrustBoundary) RecursivelyAllTechnicalAssetIDsInside(model *Model) []string {
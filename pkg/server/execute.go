@@ -5,6 +5,7 @@ Copyright © 2023 NAME HERE <EMAIL ADDRESS>
 package server
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
@@ -127,9 +128,9 @@ func (s *server) execute(ginContext *gin.Context, dryRun bool) (yamlContent []by
 	defer func() { _ = os.Remove(tmpResultFile.Name()) }()
 
 	if dryRun {
-		s.doItViaRuntimeCall(yamlFile, tmpOutputDir, false, false, false, false, false, true, true, true, 40)
+		s.doItViaRuntimeCall(ginContext.Request.Context(), yamlFile, tmpOutputDir, false, false, false, false, false, true, true, true, 40)
 	} else {
-		s.doItViaRuntimeCall(yamlFile, tmpOutputDir, true, true, true, true, true, true, true, true, dpi)
+		s.doItViaRuntimeCall(ginContext.Request.Context(), yamlFile, tmpOutputDir, true, true, true, true, true, true, true, true, dpi)
 	}
 
 	yamlContent, err = os.ReadFile(filepath.Clean(yamlFile))
@@ -174,7 +175,7 @@ func (s *server) execute(ginContext *gin.Context, dryRun bool) (yamlContent []by
 }
 
 // ultimately to avoid any in-process memory and/or data leaks by the used third party libs like PDF generation: exec and quit
-func (s *server) doItViaRuntimeCall(modelFile string, outputDir string,
+func (s *server) doItViaRuntimeCall(ctx context.Context, modelFile string, outputDir string,
 	generateDataFlowDiagram, generateDataAssetDiagram, generateReportPdf, generateRisksExcel, generateTagsExcel, generateRisksJSON, generateTechnicalAssetsJSON, generateStatsJSON bool,
 	dpi int) {
 	// Remember to also add the same args to the exec based sub-process calls!
@@ -215,7 +216,7 @@ func (s *server) doItViaRuntimeCall(modelFile string, outputDir string,
 		panic(nameError)
 	}
 
-	cmd = exec.Command(self, args...) // #nosec G204
+	cmd = exec.CommandContext(ctx, self, args...) // #nosec G204
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		panic(fmt.Errorf(string(out)))
@@ -1182,7 +1182,7 @@ func (s *server) analyzeModelOnServerDirectly(ginContext *gin.Context) {
 
 	err = os.WriteFile(tmpModelFile.Name(), []byte(yamlText), 0400)
 
-	s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, true, true, true, true, true, true, true, true, dpi)
+	s.doItViaRuntimeCall(ginContext.Request.Context(), tmpModelFile.Name(), tmpOutputDir, true, true, true, true, true, true, true, true, dpi)
 	if err != nil {
 		handleErrorInServiceCall(err, ginContext)
 		return
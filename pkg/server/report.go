@@ -104,42 +104,42 @@ func (s *server) streamResponse(ginContext *gin.Context, responseType responseTy
 	defer func() { _ = os.RemoveAll(tmpOutputDir) }()
 	err = os.WriteFile(tmpModelFile.Name(), []byte(yamlText), 0400)
 	if responseType == dataFlowDiagram {
-		s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, true, false, false, false, false, false, false, false, dpi)
+		s.doItViaRuntimeCall(ginContext.Request.Context(), tmpModelFile.Name(), tmpOutputDir, true, false, false, false, false, false, false, false, dpi)
 		if err != nil {
 			handleErrorInServiceCall(err, ginContext)
 			return
 		}
 		ginContext.File(filepath.Clean(filepath.Join(tmpOutputDir, s.config.DataFlowDiagramFilenamePNG)))
 	} else if responseType == dataAssetDiagram {
-		s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, false, true, false, false, false, false, false, false, dpi)
+		s.doItViaRuntimeCall(ginContext.Request.Context(), tmpModelFile.Name(), tmpOutputDir, false, true, false, false, false, false, false, false, dpi)
 		if err != nil {
 			handleErrorInServiceCall(err, ginContext)
 			return
 		}
 		ginContext.File(filepath.Clean(filepath.Join(tmpOutputDir, s.config.DataAssetDiagramFilenamePNG)))
 	} else if responseType == reportPDF {
-		s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, false, false, true, false, false, false, false, false, dpi)
+		s.doItViaRuntimeCall(ginContext.Request.Context(), tmpModelFile.Name(), tmpOutputDir, false, false, true, false, false, false, false, false, dpi)
 		if err != nil {
 			handleErrorInServiceCall(err, ginContext)
 			return
 		}
 		ginContext.FileAttachment(filepath.Clean(filepath.Join(tmpOutputDir, s.config.ReportFilename)), s.config.ReportFilename)
 	} else if responseType == risksExcel {
-		s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, false, false, false, true, false, false, false, false, dpi)
+		s.doItViaRuntimeCall(ginContext.Request.Context(), tmpModelFile.Name(), tmpOutputDir, false, false, false, true, false, false, false, false, dpi)
 		if err != nil {
 			handleErrorInServiceCall(err, ginContext)
 			return
 		}
 		ginContext.FileAttachment(filepath.Clean(filepath.Join(tmpOutputDir, s.config.ExcelRisksFilename)), s.config.ExcelRisksFilename)
 	} else if responseType == tagsExcel {
-		s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, false, false, false, false, true, false, false, false, dpi)
+		s.doItViaRuntimeCall(ginContext.Request.Context(), tmpModelFile.Name(), tmpOutputDir, false, false, false, false, true, false, false, false, dpi)
 		if err != nil {
 			handleErrorInServiceCall(err, ginContext)
 			return
 		}
 		ginContext.FileAttachment(filepath.Clean(filepath.Join(tmpOutputDir, s.config.ExcelTagsFilename)), s.config.ExcelTagsFilename)
 	} else if responseType == risksJSON {
-		s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, false, false, false, false, false, true, false, false, dpi)
+		s.doItViaRuntimeCall(ginContext.Request.Context(), tmpModelFile.Name(), tmpOutputDir, false, false, false, false, false, true, false, false, dpi)
 		if err != nil {
 			handleErrorInServiceCall(err, ginContext)
 			return
@@ -151,7 +151,7 @@ func (s *server) streamResponse(ginContext *gin.Context, responseType responseTy
 		}
 		ginContext.Data(http.StatusOK, "application/json", jsonData) // stream directly with JSON content-type in response instead of file download
 	} else if responseType == technicalAssetsJSON {
-		s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, false, false, false, false, false, true, true, false, dpi)
+		s.doItViaRuntimeCall(ginContext.Request.Context(), tmpModelFile.Name(), tmpOutputDir, false, false, false, false, false, true, true, false, dpi)
 		if err != nil {
 			handleErrorInServiceCall(err, ginContext)
 			return
@@ -163,7 +163,7 @@ func (s *server) streamResponse(ginContext *gin.Context, responseType responseTy
 		}
 		ginContext.Data(http.StatusOK, "application/json", jsonData) // stream directly with JSON content-type in response instead of file download
 	} else if responseType == statsJSON {
-		s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, false, false, false, false, false, false, false, true, dpi)
+		s.doItViaRuntimeCall(ginContext.Request.Context(), tmpModelFile.Name(), tmpOutputDir, false, false, false, false, false, false, false, true, dpi)
 		if err != nil {
 			handleErrorInServiceCall(err, ginContext)
 			return
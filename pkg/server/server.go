@@ -5,6 +5,7 @@ Copyright © 2023 NAME HERE <EMAIL ADDRESS>
 package server
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -176,7 +177,8 @@ func RunServer(config *common.Config) {
 	router.DELETE("/models/:model-id/shared-runtimes/:shared-runtime-id", s.deleteSharedRuntime)
 
 	reporter := common.DefaultProgressReporter{Verbose: s.config.Verbose}
-	s.customRiskRules = model.LoadCustomRiskRules(s.config.RiskRulesPlugins, reporter)
+	s.customRiskRules = model.LoadCustomRiskRules(context.Background(), s.config, reporter)
+	defer model.CloseCustomRiskRules(s.customRiskRules)
 
 	fmt.Println("Threagile s running...")
 	_ = router.Run(":" + strconv.Itoa(s.config.ServerPort)) // listen and serve on 0.0.0.0:8080 or whatever port was specified
@@ -209,7 +211,7 @@ func (s *server) addSupportedTags(input []byte) []byte {
 		}
 	}
 
-	for _, rule := range risks.GetBuiltInRiskRules() {
+	for _, rule := range risks.GetBuiltInRiskRules(s.config, common.DefaultProgressReporter{Verbose: s.config.Verbose}) {
 		for _, tag := range rule.SupportedTags() {
 			supportedTags[strings.ToLower(tag)] = true
 		}
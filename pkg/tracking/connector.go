@@ -0,0 +1,124 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+	"gopkg.in/yaml.v3"
+)
+
+// Ticket is what a connector creates a ticket from, and what the templates of its configuration are executed on; the
+// id is set once the ticket exists
+
+type Ticket struct {
+	Id          string
+	SyntheticId string
+	Title       string
+	CategoryId  string
+	Category    string
+	Severity    string
+	Likelihood  string
+	Impact      string
+	Description string
+	Action      string
+	Mitigation  string
+	Check       string
+	CWE         int
+	Model       string
+}
+
+// Connector creates tickets in an issue tracker and reads back their status
+
+type Connector interface {
+	// CreateTicket creates a ticket and returns its id
+	CreateTicket(ctx context.Context, ticket *Ticket) (string, error)
+
+	// TicketStatus returns the risk status the status of the ticket maps to, and false if it maps to none
+	TicketStatus(ctx context.Context, ticket *Ticket) (types.RiskStatus, bool, error)
+}
+
+// ConnectorFactory creates a connector from its configuration file, the document node of which it is passed
+
+type ConnectorFactory func(config *yaml.Node) (Connector, error)
+
+var connectors = map[string]ConnectorFactory{
+	RESTConnectorName: NewRESTConnector,
+}
+
+// RegisterConnector makes a connector available by the name used as 'connector' in configuration files
+
+func RegisterConnector(name string, factory ConnectorFactory) {
+	connectors[strings.ToLower(name)] = factory
+}
+
+// LoadConnector creates the connector named by 'connector' in the configuration file
+
+func LoadConnector(filename string) (Connector, error) {
+	data, readError := os.ReadFile(filepath.Clean(filename))
+	if readError != nil {
+		return nil, fmt.Errorf("unable to read issue tracker configuration: %v", readError)
+	}
+
+	var config yaml.Node
+	unmarshalError := yaml.Unmarshal(data, &config)
+	if unmarshalError != nil {
+		return nil, fmt.Errorf("unable to parse issue tracker configuration %q: %v", filename, unmarshalError)
+	}
+
+	var header struct {
+		Connector string `yaml:"connector"`
+	}
+	decodeError := config.Decode(&header)
+	if decodeError != nil {
+		return nil, fmt.Errorf("unable to parse issue tracker configuration %q: %v", filename, decodeError)
+	}
+
+	factory, ok := connectors[strings.ToLower(header.Connector)]
+	if !ok {
+		names := make([]string, 0)
+		for name := range connectors {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown connector %q in issue tracker configuration %q, expected one of %v", header.Connector, filename, strings.Join(names, ", "))
+	}
+
+	connector, createError := factory(&config)
+	if createError != nil {
+		return nil, fmt.Errorf("invalid issue tracker configuration %q: %v", filename, createError)
+	}
+
+	return connector, nil
+}
+
+var formattingTags = strings.NewReplacer("<b>", "", "</b>", "", "<i>", "", "</i>", "", "<u>", "", "</u>", "")
+
+// NewTicket returns the ticket data of a risk
+
+func NewTicket(parsedModel *types.Model, risk *types.Risk) *Ticket {
+	ticket := &Ticket{
+		SyntheticId: risk.SyntheticId,
+		Title:       formattingTags.Replace(risk.Title),
+		CategoryId:  risk.CategoryId,
+		Severity:    risk.Severity.String(),
+		Likelihood:  risk.ExploitationLikelihood.String(),
+		Impact:      risk.ExploitationImpact.String(),
+		Model:       parsedModel.Title,
+	}
+
+	if category := types.GetRiskCategory(parsedModel, risk.CategoryId); category != nil {
+		ticket.Category = category.Title
+		ticket.Description = formattingTags.Replace(category.Description)
+		ticket.Action = category.Action
+		ticket.Mitigation = formattingTags.Replace(category.Mitigation)
+		ticket.Check = category.Check
+		ticket.CWE = category.CWE
+	}
+
+	return ticket
+}
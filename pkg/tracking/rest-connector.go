@@ -0,0 +1,326 @@
+package tracking
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/threagile/threagile/pkg/security/types"
+	"gopkg.in/yaml.v3"
+)
+
+const RESTConnectorName = "rest"
+
+// RESTConnectorConfig configures a connector to an issue tracker with a REST/JSON API: paths and string values of the
+// create request body are templates executed on the Ticket, ticket_field and status_field are dot-separated paths
+// into the JSON responses, and status_mapping maps the statuses of the issue tracker to risk statuses
+
+type RESTConnectorConfig struct {
+	Connector string            `yaml:"connector"`
+	BaseURL   string            `yaml:"base_url"`
+	Headers   map[string]string `yaml:"headers"`
+	TokenEnv  string            `yaml:"token_env"`
+	Timeout   int               `yaml:"timeout_seconds"`
+	Create    struct {
+		Method      string         `yaml:"method"`
+		Path        string         `yaml:"path"`
+		Fields      map[string]any `yaml:"fields"`
+		TicketField string         `yaml:"ticket_field"`
+	} `yaml:"create"`
+	Status struct {
+		Method        string            `yaml:"method"`
+		Path          string            `yaml:"path"`
+		StatusField   string            `yaml:"status_field"`
+		StatusMapping map[string]string `yaml:"status_mapping"`
+	} `yaml:"status"`
+}
+
+type RESTConnector struct {
+	config        RESTConnectorConfig
+	client        *http.Client
+	createPath    *template.Template
+	statusPath    *template.Template
+	fields        any
+	statusMapping map[string]types.RiskStatus
+}
+
+func NewRESTConnector(config *yaml.Node) (Connector, error) {
+	what := new(RESTConnector)
+	decodeError := config.Decode(&what.config)
+	if decodeError != nil {
+		return nil, decodeError
+	}
+
+	initError := what.init()
+	if initError != nil {
+		return nil, initError
+	}
+
+	return what, nil
+}
+
+func (what *RESTConnector) init() error {
+	if len(what.config.BaseURL) == 0 {
+		return fmt.Errorf("missing base_url")
+	}
+	if len(what.config.Create.TicketField) == 0 {
+		return fmt.Errorf("missing ticket_field of create")
+	}
+	if len(what.config.Status.Path) == 0 {
+		return fmt.Errorf("missing path of status")
+	}
+	if len(what.config.Status.StatusField) == 0 {
+		return fmt.Errorf("missing status_field of status")
+	}
+	if len(what.config.Create.Method) == 0 {
+		what.config.Create.Method = http.MethodPost
+	}
+	if len(what.config.Status.Method) == 0 {
+		what.config.Status.Method = http.MethodGet
+	}
+
+	var parseError error
+	what.createPath, parseError = template.New("create path").Option("missingkey=error").Parse(what.config.Create.Path)
+	if parseError != nil {
+		return fmt.Errorf("invalid path of create: %v", parseError)
+	}
+	what.statusPath, parseError = template.New("status path").Option("missingkey=error").Parse(what.config.Status.Path)
+	if parseError != nil {
+		return fmt.Errorf("invalid path of status: %v", parseError)
+	}
+	what.fields, parseError = parseFieldTemplates("fields", what.config.Create.Fields)
+	if parseError != nil {
+		return fmt.Errorf("invalid fields of create: %v", parseError)
+	}
+
+	what.statusMapping = make(map[string]types.RiskStatus)
+	for trackerStatus, riskStatus := range what.config.Status.StatusMapping {
+		status, statusError := types.ParseRiskStatus(riskStatus)
+		if statusError != nil {
+			return fmt.Errorf("unknown risk status %q in status_mapping of %q", riskStatus, trackerStatus)
+		}
+		what.statusMapping[strings.ToLower(trackerStatus)] = status
+	}
+
+	timeout := 30 * time.Second
+	if what.config.Timeout > 0 {
+		timeout = time.Duration(what.config.Timeout) * time.Second
+	}
+	what.client = &http.Client{Timeout: timeout}
+
+	return nil
+}
+
+func (what *RESTConnector) CreateTicket(ctx context.Context, ticket *Ticket) (string, error) {
+	body, executeError := executeFieldTemplates(what.fields, ticket)
+	if executeError != nil {
+		return "", fmt.Errorf("unable to fill in ticket fields: %v", executeError)
+	}
+
+	response, requestError := what.request(ctx, what.config.Create.Method, what.createPath, ticket, body)
+	if requestError != nil {
+		return "", fmt.Errorf("unable to create ticket for %v: %v", ticket.SyntheticId, requestError)
+	}
+
+	ticketId, ok := fieldValue(response, what.config.Create.TicketField)
+	if !ok || len(ticketId) == 0 {
+		return "", fmt.Errorf("unable to create ticket for %v: no %q in response", ticket.SyntheticId, what.config.Create.TicketField)
+	}
+
+	return ticketId, nil
+}
+
+func (what *RESTConnector) TicketStatus(ctx context.Context, ticket *Ticket) (types.RiskStatus, bool, error) {
+	response, requestError := what.request(ctx, what.config.Status.Method, what.statusPath, ticket, nil)
+	if requestError != nil {
+		return types.Unchecked, false, fmt.Errorf("unable to get status of ticket %v: %v", ticket.Id, requestError)
+	}
+
+	trackerStatus, ok := fieldValue(response, what.config.Status.StatusField)
+	if !ok {
+		return types.Unchecked, false, fmt.Errorf("unable to get status of ticket %v: no %q in response", ticket.Id, what.config.Status.StatusField)
+	}
+
+	status, ok := what.statusMapping[strings.ToLower(trackerStatus)]
+	return status, ok, nil
+}
+
+func (what *RESTConnector) request(ctx context.Context, method string, path *template.Template, ticket *Ticket, body any) (any, error) {
+	escaped := *ticket
+	escaped.Id = url.PathEscape(ticket.Id)
+	escaped.SyntheticId = url.PathEscape(ticket.SyntheticId)
+
+	var pathBuffer strings.Builder
+	executeError := path.Execute(&pathBuffer, &escaped)
+	if executeError != nil {
+		return nil, executeError
+	}
+
+	var bodyReader io.Reader
+	if body != nil {
+		data, marshalError := json.Marshal(body)
+		if marshalError != nil {
+			return nil, marshalError
+		}
+		bodyReader = bytes.NewReader(data)
+	}
+
+	request, newError := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(what.config.BaseURL, "/")+pathBuffer.String(), bodyReader)
+	if newError != nil {
+		return nil, newError
+	}
+
+	request.Header.Set("Accept", "application/json")
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if len(what.config.TokenEnv) > 0 {
+		if token := os.Getenv(what.config.TokenEnv); len(token) > 0 {
+			request.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+	for name, value := range what.config.Headers {
+		request.Header.Set(name, os.ExpandEnv(value))
+	}
+
+	response, doError := what.client.Do(request)
+	if doError != nil {
+		return nil, doError
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	data, readError := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if readError != nil {
+		return nil, readError
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return nil, fmt.Errorf("%v %v returned %v: %v", method, request.URL.Path, response.Status, strings.TrimSpace(string(data)))
+	}
+
+	var result any
+	if len(bytes.TrimSpace(data)) > 0 {
+		unmarshalError := json.Unmarshal(data, &result)
+		if unmarshalError != nil {
+			return nil, fmt.Errorf("invalid JSON response from %v %v: %v", method, request.URL.Path, unmarshalError)
+		}
+	}
+
+	return result, nil
+}
+
+// parseFieldTemplates parses the string values of the (nested) fields as templates
+
+func parseFieldTemplates(name string, value any) (any, error) {
+	switch typed := value.(type) {
+	case string:
+		return template.New(name).Option("missingkey=error").Parse(typed)
+
+	case map[string]any:
+		result := make(map[string]any)
+		for key, item := range typed {
+			parsed, parseError := parseFieldTemplates(name+"."+key, item)
+			if parseError != nil {
+				return nil, parseError
+			}
+			result[key] = parsed
+		}
+		return result, nil
+
+	case []any:
+		result := make([]any, 0)
+		for index, item := range typed {
+			parsed, parseError := parseFieldTemplates(name+"."+strconv.Itoa(index), item)
+			if parseError != nil {
+				return nil, parseError
+			}
+			result = append(result, parsed)
+		}
+		return result, nil
+
+	default:
+		return value, nil
+	}
+}
+
+func executeFieldTemplates(value any, ticket *Ticket) (any, error) {
+	switch typed := value.(type) {
+	case *template.Template:
+		var buffer strings.Builder
+		executeError := typed.Execute(&buffer, ticket)
+		if executeError != nil {
+			return nil, executeError
+		}
+		return buffer.String(), nil
+
+	case map[string]any:
+		result := make(map[string]any)
+		for key, item := range typed {
+			executed, executeError := executeFieldTemplates(item, ticket)
+			if executeError != nil {
+				return nil, executeError
+			}
+			result[key] = executed
+		}
+		return result, nil
+
+	case []any:
+		result := make([]any, 0)
+		for _, item := range typed {
+			executed, executeError := executeFieldTemplates(item, ticket)
+			if executeError != nil {
+				return nil, executeError
+			}
+			result = append(result, executed)
+		}
+		return result, nil
+
+	default:
+		return value, nil
+	}
+}
+
+// fieldValue returns the value at the dot-separated path into the JSON value, numbers indexing into arrays
+
+func fieldValue(value any, path string) (string, bool) {
+	for _, name := range strings.Split(path, ".") {
+		switch typed := value.(type) {
+		case map[string]any:
+			item, ok := typed[name]
+			if !ok {
+				return "", false
+			}
+			value = item
+
+		case []any:
+			index, parseError := strconv.Atoi(name)
+			if parseError != nil || index < 0 || index >= len(typed) {
+				return "", false
+			}
+			value = typed[index]
+
+		default:
+			return "", false
+		}
+	}
+
+	switch typed := value.(type) {
+	case string:
+		return typed, true
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(typed), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,172 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const restConnectorTestConfig = `
+connector: rest
+base_url: %URL%/api
+token_env: THREAGILE_TEST_TRACKER_TOKEN
+headers:
+  X-Project: SEC
+create:
+  path: /issues
+  fields:
+    title: "{{.Title}}"
+    labels: [threagile, "{{.Severity}}"]
+    custom:
+      risk: "{{.SyntheticId}}"
+  ticket_field: key
+status:
+  path: /issues/{{.Id}}
+  status_field: fields.status.name
+  status_mapping:
+    In Progress: in-progress
+    Done: mitigated
+`
+
+// restConnectorTestServer is a stub issue tracker keeping its issues in memory
+
+type restConnectorTestServer struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	created  []map[string]any
+	statuses map[string]string
+}
+
+func newRESTConnectorTestServer(t *testing.T) *restConnectorTestServer {
+	server := &restConnectorTestServer{statuses: make(map[string]string)}
+
+	handler := func(writer http.ResponseWriter, request *http.Request) {
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+
+		switch {
+		case request.Method == http.MethodPost && request.URL.Path == "/api/issues":
+			assert.Equal(t, "Bearer secret", request.Header.Get("Authorization"))
+			assert.Equal(t, "SEC", request.Header.Get("X-Project"))
+
+			var issue map[string]any
+			assert.NoError(t, json.NewDecoder(request.Body).Decode(&issue))
+			server.created = append(server.created, issue)
+			key := fmt.Sprintf("SEC-%d", len(server.statuses)+1)
+			server.statuses[key] = "Open"
+			_ = json.NewEncoder(writer).Encode(map[string]any{"key": key})
+
+		case request.Method == http.MethodGet && strings.HasPrefix(request.URL.Path, "/api/issues/"):
+			status, ok := server.statuses[strings.TrimPrefix(request.URL.Path, "/api/issues/")]
+			if !ok {
+				http.NotFound(writer, request)
+				return
+			}
+			_ = json.NewEncoder(writer).Encode(map[string]any{"fields": map[string]any{"status": map[string]any{"name": status}}})
+
+		default:
+			http.NotFound(writer, request)
+		}
+	}
+
+	server.Server = httptest.NewServer(http.HandlerFunc(handler))
+	t.Cleanup(server.Close)
+	t.Setenv("THREAGILE_TEST_TRACKER_TOKEN", "secret")
+
+	return server
+}
+
+func (what *restConnectorTestServer) setStatus(key string, status string) {
+	what.mutex.Lock()
+	defer what.mutex.Unlock()
+	what.statuses[key] = status
+}
+
+func loadRESTConnectorTestConnector(t *testing.T, server *restConnectorTestServer) Connector {
+	filename := filepath.Join(t.TempDir(), "threagile-tracker.yaml")
+	config := strings.ReplaceAll(restConnectorTestConfig, "%URL%", server.URL)
+	require.NoError(t, os.WriteFile(filename, []byte(config), 0600))
+
+	connector, err := LoadConnector(filename)
+	require.NoError(t, err)
+	return connector
+}
+
+func TestRESTConnectorCreateAndStatus(t *testing.T) {
+	server := newRESTConnectorTestServer(t)
+	connector := loadRESTConnectorTestConnector(t, server)
+
+	ticketId, err := connector.CreateTicket(context.Background(), &Ticket{SyntheticId: "missing-waf@web", Title: "Missing WAF", Severity: "high"})
+	require.NoError(t, err)
+	assert.Equal(t, "SEC-1", ticketId)
+	require.Len(t, server.created, 1)
+	assert.Equal(t, map[string]any{
+		"title":  "Missing WAF",
+		"labels": []any{"threagile", "high"},
+		"custom": map[string]any{"risk": "missing-waf@web"},
+	}, server.created[0])
+
+	_, ok, err := connector.TicketStatus(context.Background(), &Ticket{Id: ticketId})
+	require.NoError(t, err)
+	assert.False(t, ok, "open is not mapped")
+
+	server.setStatus(ticketId, "done")
+	status, ok, err := connector.TicketStatus(context.Background(), &Ticket{Id: ticketId})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, types.Mitigated, status)
+
+	_, _, err = connector.TicketStatus(context.Background(), &Ticket{Id: "SEC-9"})
+	assert.ErrorContains(t, err, "404")
+}
+
+func TestLoadConnectorErrors(t *testing.T) {
+	for name, config := range map[string]string{
+		"unknown connector":      "connector: carrier-pigeon\n",
+		"missing base url":       "connector: rest\ncreate: {ticket_field: id}\nstatus: {path: /x, status_field: s}\n",
+		"unknown risk status":    "connector: rest\nbase_url: http://localhost\ncreate: {ticket_field: id}\nstatus: {path: /x, status_field: s, status_mapping: {Done: fixed}}\n",
+		"invalid field template": "connector: rest\nbase_url: http://localhost\ncreate: {ticket_field: id, fields: {title: '{{.Nope'}}\nstatus: {path: /x, status_field: s}\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "threagile-tracker.yaml")
+			require.NoError(t, os.WriteFile(filename, []byte(config), 0600))
+
+			_, err := LoadConnector(filename)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestFieldValue(t *testing.T) {
+	var response any
+	require.NoError(t, json.Unmarshal([]byte(`{"id": 42, "issues": [{"key": "SEC-1"}], "done": true}`), &response))
+
+	value, ok := fieldValue(response, "id")
+	assert.True(t, ok)
+	assert.Equal(t, "42", value)
+
+	value, ok = fieldValue(response, "issues.0.key")
+	assert.True(t, ok)
+	assert.Equal(t, "SEC-1", value)
+
+	value, ok = fieldValue(response, "done")
+	assert.True(t, ok)
+	assert.Equal(t, "true", value)
+
+	_, ok = fieldValue(response, "issues.1.key")
+	assert.False(t, ok)
+
+	_, ok = fieldValue(response, "issues")
+	assert.False(t, ok)
+}
@@ -0,0 +1,200 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// Change is what syncing with an issue tracker changes in the risk tracking of a model file: a ticket created for an
+// unchecked risk, or the status of a risk tracking taken over from its ticket
+
+type Change struct {
+	SyntheticRiskId string           `json:"synthetic_risk_id"`
+	Filename        string           `json:"filename"`
+	Ticket          string           `json:"ticket,omitempty"`
+	Created         bool             `json:"created,omitempty"`
+	OldStatus       types.RiskStatus `json:"old_status"`
+	Status          types.RiskStatus `json:"status"`
+}
+
+func (what *Change) String() string {
+	if what.Created {
+		if len(what.Ticket) == 0 {
+			return fmt.Sprintf("%v: would create ticket (%v)", what.SyntheticRiskId, what.Filename)
+		}
+		return fmt.Sprintf("%v: created ticket %v (%v)", what.SyntheticRiskId, what.Ticket, what.Filename)
+	}
+
+	return fmt.Sprintf("%v: %v -> %v from ticket %v (%v)", what.SyntheticRiskId, what.OldStatus, what.Status, what.Ticket, what.Filename)
+}
+
+// Sync pulls the status of the tickets of the risk tracking entries of the analyzed model, and creates tickets for
+// unchecked risks without one; the tickets of entries added by wildcard entries or missing are tracked in new entries
+// of the model file. Expired entries keep their status until re-checked. Unless dryRun is set, each change is made in
+// the editor of the model files right away, which are checked to be writable before creating any ticket; on errors
+// the changes made so far are returned along with the error, to be saved still.
+
+func Sync(ctx context.Context, parsedModel *types.Model, editor *input.ModelEditor, connector Connector, today time.Time, dryRun bool) ([]*Change, error) {
+	changes := make([]*Change, 0)
+	modelFilename := editor.Filename
+
+	if !dryRun {
+		writableError := editor.CheckWritable()
+		if writableError != nil {
+			return changes, writableError
+		}
+	}
+
+	record := func(change *Change) error {
+		changes = append(changes, change)
+		if dryRun {
+			return nil
+		}
+
+		return writeChange(editor, change, today)
+	}
+
+	entries := parsedModel.RiskTrackingEntries()
+	written := make(map[*types.RiskTracking]bool)
+	for _, tracking := range entries {
+		written[tracking] = true
+	}
+
+	statuses := make(map[string]types.RiskStatus)
+	for _, tracking := range entries {
+		if len(tracking.Ticket) == 0 || tracking.IsExpired(today) {
+			continue
+		}
+
+		status, known := statuses[tracking.Ticket]
+		if !known {
+			var ok bool
+			var statusError error
+			status, ok, statusError = connector.TicketStatus(ctx, &Ticket{Id: tracking.Ticket, SyntheticId: tracking.SyntheticRiskId})
+			if statusError != nil {
+				return changes, statusError
+			}
+			if !ok {
+				status = tracking.Status
+			}
+			statuses[tracking.Ticket] = status
+		}
+
+		if status != tracking.Status {
+			recordError := record(&Change{
+				SyntheticRiskId: tracking.SyntheticRiskId,
+				Filename:        trackingFilename(tracking, modelFilename),
+				Ticket:          tracking.Ticket,
+				OldStatus:       tracking.Status,
+				Status:          status,
+			})
+			if recordError != nil {
+				return changes, recordError
+			}
+		}
+	}
+
+	for _, risk := range risksBySeverity(parsedModel) {
+		if risk.RiskStatus != types.Unchecked {
+			continue
+		}
+
+		tracking := risk.GetRiskTracking(parsedModel)
+		if tracking != nil && len(tracking.Ticket) > 0 {
+			continue
+		}
+
+		change := &Change{SyntheticRiskId: risk.SyntheticId, Filename: modelFilename, Created: true, OldStatus: types.Unchecked, Status: types.Unchecked}
+		if tracking != nil && written[tracking] {
+			change.Filename = trackingFilename(tracking, modelFilename)
+		}
+
+		if !dryRun {
+			ticketId, createError := connector.CreateTicket(ctx, NewTicket(parsedModel, risk))
+			if createError != nil {
+				return changes, createError
+			}
+			change.Ticket = ticketId
+		}
+
+		recordError := record(change)
+		if recordError != nil {
+			return changes, fmt.Errorf("%v (tickets created: %v)", recordError, strings.Join(CreatedTickets(changes), ", "))
+		}
+	}
+
+	return changes, nil
+}
+
+// Save writes the changes made in the editor of the model files; as the tickets created would be created again
+// otherwise, the error names them
+
+func Save(editor *input.ModelEditor, changes []*Change) error {
+	saveError := editor.Save()
+	if saveError != nil && len(CreatedTickets(changes)) > 0 {
+		return fmt.Errorf("%v (tickets created: %v)", saveError, strings.Join(CreatedTickets(changes), ", "))
+	}
+
+	return saveError
+}
+
+// CreatedTickets returns the ids of the tickets created by the changes
+
+func CreatedTickets(changes []*Change) []string {
+	tickets := make([]string, 0)
+	for _, change := range changes {
+		if change.Created && len(change.Ticket) > 0 {
+			tickets = append(tickets, change.Ticket)
+		}
+	}
+
+	return tickets
+}
+
+// writeChange makes the change in the risk tracking of the model file, or of the included file holding it, dated the
+// given day
+
+func writeChange(editor *input.ModelEditor, change *Change, today time.Time) error {
+	tracking := input.RiskTracking{Status: change.Status.String(), Ticket: change.Ticket, Date: today.Format("2006-01-02")}
+	if change.Created && editor.HasRiskTracking(change.SyntheticRiskId) {
+		// keep the status of expired entries as written, for when they are re-checked
+		tracking.Status = ""
+	}
+
+	setError := editor.SetRiskTracking(change.SyntheticRiskId, tracking)
+	if setError != nil {
+		return fmt.Errorf("unable to write risk tracking of %v: %v", change.SyntheticRiskId, setError)
+	}
+
+	return nil
+}
+
+func trackingFilename(tracking *types.RiskTracking, modelFilename string) string {
+	if len(tracking.Position.Filename) > 0 {
+		return tracking.Position.Filename
+	}
+
+	return modelFilename
+}
+
+func risksBySeverity(parsedModel *types.Model) []*types.Risk {
+	risks := make([]*types.Risk, 0)
+	for _, risk := range parsedModel.GeneratedRisksBySyntheticId {
+		risks = append(risks, risk)
+	}
+
+	sort.Slice(risks, func(i, j int) bool {
+		if risks[i].Severity != risks[j].Severity {
+			return risks[i].Severity > risks[j].Severity
+		}
+		return risks[i].SyntheticId < risks[j].SyntheticId
+	})
+
+	return risks
+}
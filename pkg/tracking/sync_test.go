@@ -0,0 +1,192 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const syncTestModel = `title: Sync Test
+includes:
+  - database.yaml
+
+# reviewed with the ERP team
+risk_tracking:
+
+  missing-hardening@*: # all of them
+    status: in-progress
+    ticket: SEC-1
+`
+
+const syncTestInclude = `risk_tracking:
+  sql-nosql-injection@db:
+    status: accepted
+    justification: Legacy database, replaced next year
+    expires: 2024-01-31
+`
+
+func TestSync(t *testing.T) {
+	server := newRESTConnectorTestServer(t)
+	connector := loadRESTConnectorTestConnector(t, server)
+	server.setStatus("SEC-1", "Done")
+
+	dir := t.TempDir()
+	filename, includeFilename := filepath.Join(dir, "threagile.yaml"), filepath.Join(dir, "database.yaml")
+	require.NoError(t, os.WriteFile(filename, []byte(syncTestModel), 0600))
+	require.NoError(t, os.WriteFile(includeFilename, []byte(syncTestInclude), 0600))
+	parsedModel := &types.Model{
+		Title: "Sync Test",
+		GeneratedRisksBySyntheticId: map[string]*types.Risk{
+			"missing-hardening@web":  {CategoryId: "missing-hardening", SyntheticId: "missing-hardening@web", Severity: types.MediumSeverity, RiskStatus: types.InProgress},
+			"missing-hardening@db":   {CategoryId: "missing-hardening", SyntheticId: "missing-hardening@db", Severity: types.MediumSeverity, RiskStatus: types.InProgress},
+			"sql-nosql-injection@db": {CategoryId: "sql-nosql-injection", SyntheticId: "sql-nosql-injection@db", Severity: types.HighSeverity, RiskStatus: types.Unchecked},
+			"missing-waf@web":        {CategoryId: "missing-waf", SyntheticId: "missing-waf@web", Title: "<b>Missing WAF</b>", Severity: types.LowSeverity, RiskStatus: types.Unchecked},
+		},
+		RiskTracking: map[string]*types.RiskTracking{
+			"missing-hardening@*": {SyntheticRiskId: "missing-hardening@*", Status: types.InProgress, Ticket: "SEC-1",
+				Position: input.Position{Filename: filename, Line: 8, Column: 3}},
+			"missing-hardening@web": {SyntheticRiskId: "missing-hardening@web", Status: types.InProgress, Ticket: "SEC-1",
+				Position: input.Position{Filename: filename, Line: 8, Column: 3}},
+			"missing-hardening@db": {SyntheticRiskId: "missing-hardening@db", Status: types.InProgress, Ticket: "SEC-1",
+				Position: input.Position{Filename: filename, Line: 8, Column: 3}},
+			"sql-nosql-injection@db": {SyntheticRiskId: "sql-nosql-injection@db", Status: types.Unchecked,
+				Position: input.Position{Filename: includeFilename, Line: 2, Column: 3}, Expires: types.Date{Time: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)}},
+		},
+	}
+	today := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	editor := new(input.ModelEditor)
+	require.NoError(t, editor.Load(filename))
+
+	changes, err := Sync(context.Background(), parsedModel, editor, connector, today, true)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	assert.Empty(t, server.created, "dry run")
+	assert.Empty(t, editor.ModifiedFiles(), "dry run")
+
+	changes, err = Sync(context.Background(), parsedModel, editor, connector, today, false)
+	require.NoError(t, err)
+	assert.Equal(t, []*Change{
+		{SyntheticRiskId: "missing-hardening@*", Filename: filename, Ticket: "SEC-1", OldStatus: types.InProgress, Status: types.Mitigated},
+		{SyntheticRiskId: "sql-nosql-injection@db", Filename: includeFilename, Ticket: "SEC-2", Created: true, OldStatus: types.Unchecked, Status: types.Unchecked},
+		{SyntheticRiskId: "missing-waf@web", Filename: filename, Ticket: "SEC-3", Created: true, OldStatus: types.Unchecked, Status: types.Unchecked},
+	}, changes)
+	require.Len(t, server.created, 2)
+	assert.Equal(t, "Missing WAF", server.created[1]["title"])
+
+	require.NoError(t, Save(editor, changes))
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Equal(t, `title: Sync Test
+includes:
+  - database.yaml
+
+# reviewed with the ERP team
+risk_tracking:
+
+  missing-hardening@*: # all of them
+    status: mitigated
+    ticket: SEC-1
+    date: 2024-06-15
+
+  missing-waf@web:
+    status: unchecked
+    ticket: SEC-3
+    date: 2024-06-15
+`, string(data))
+
+	data, err = os.ReadFile(includeFilename)
+	require.NoError(t, err)
+	assert.Equal(t, `risk_tracking:
+  sql-nosql-injection@db:
+    status: accepted
+    justification: Legacy database, replaced next year
+    expires: 2024-01-31
+    ticket: SEC-2
+    date: 2024-06-15
+`, string(data))
+}
+
+// failingConnector creates tickets until it fails
+
+type failingConnector struct {
+	created int
+	limit   int
+}
+
+func (what *failingConnector) CreateTicket(_ context.Context, _ *Ticket) (string, error) {
+	if what.created >= what.limit {
+		return "", fmt.Errorf("tracker unavailable")
+	}
+
+	what.created++
+	return fmt.Sprintf("SEC-%d", what.created+1), nil
+}
+
+func (what *failingConnector) TicketStatus(_ context.Context, _ *Ticket) (types.RiskStatus, bool, error) {
+	return types.Unchecked, false, nil
+}
+
+func TestSyncKeepsTicketsCreatedBeforeAFailure(t *testing.T) {
+	dir := t.TempDir()
+	filename, includeFilename := filepath.Join(dir, "threagile.yaml"), filepath.Join(dir, "database.yaml")
+	require.NoError(t, os.WriteFile(filename, []byte(syncTestModel), 0600))
+	require.NoError(t, os.WriteFile(includeFilename, []byte(syncTestInclude), 0600))
+	parsedModel := &types.Model{
+		GeneratedRisksBySyntheticId: map[string]*types.Risk{
+			"sql-nosql-injection@db": {CategoryId: "sql-nosql-injection", SyntheticId: "sql-nosql-injection@db", Severity: types.HighSeverity, RiskStatus: types.Unchecked},
+			"missing-waf@web":        {CategoryId: "missing-waf", SyntheticId: "missing-waf@web", Severity: types.LowSeverity, RiskStatus: types.Unchecked},
+		},
+		RiskTracking: map[string]*types.RiskTracking{
+			"sql-nosql-injection@db": {SyntheticRiskId: "sql-nosql-injection@db", Status: types.Unchecked,
+				Position: input.Position{Filename: includeFilename, Line: 2, Column: 3}},
+		},
+	}
+
+	editor := new(input.ModelEditor)
+	require.NoError(t, editor.Load(filename))
+
+	changes, err := Sync(context.Background(), parsedModel, editor, &failingConnector{limit: 1}, time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC), false)
+	assert.ErrorContains(t, err, "tracker unavailable")
+	assert.Equal(t, []string{"SEC-2"}, CreatedTickets(changes))
+	assert.Equal(t, []string{includeFilename}, editor.ModifiedFiles())
+
+	require.NoError(t, Save(editor, changes))
+	data, err := os.ReadFile(includeFilename)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "ticket: SEC-2")
+}
+
+func TestSyncChecksModelFilesBeforeCreatingTickets(t *testing.T) {
+	dir := t.TempDir()
+	filename, includeFilename := filepath.Join(dir, "threagile.yaml"), filepath.Join(dir, "database.yaml")
+	require.NoError(t, os.WriteFile(filename, []byte(syncTestModel), 0600))
+	require.NoError(t, os.WriteFile(includeFilename, []byte(syncTestInclude), 0400))
+	if file, openError := os.OpenFile(includeFilename, os.O_WRONLY, 0); openError == nil {
+		_ = file.Close()
+		t.Skip("running with permissions to write read-only files")
+	}
+
+	editor := new(input.ModelEditor)
+	require.NoError(t, editor.Load(filename))
+
+	parsedModel := &types.Model{
+		GeneratedRisksBySyntheticId: map[string]*types.Risk{
+			"missing-waf@web": {CategoryId: "missing-waf", SyntheticId: "missing-waf@web", Severity: types.LowSeverity, RiskStatus: types.Unchecked},
+		},
+		RiskTracking: map[string]*types.RiskTracking{},
+	}
+
+	connector := &failingConnector{limit: 10}
+	_, err := Sync(context.Background(), parsedModel, editor, connector, time.Now(), false)
+	assert.ErrorContains(t, err, "unable to write model file")
+	assert.Zero(t, connector.created)
+}